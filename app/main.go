@@ -1,25 +1,66 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
+	_ "github.com/lib/pq"
+	"github.com/nats-io/nats.go"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"io"
+	"io/fs"
 	"log"
-	"net/url"
+	_ "modernc.org/sqlite"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
+	esRepo "apismrtbiz/internal/repository/elasticsearch"
+	"apismrtbiz/internal/repository/lru"
+	"apismrtbiz/internal/repository/memory"
+	mongoRepo "apismrtbiz/internal/repository/mongo"
 	mysqlRepo "apismrtbiz/internal/repository/mysql"
+	postgresRepo "apismrtbiz/internal/repository/postgres"
+	redisRepo "apismrtbiz/internal/repository/redis"
+	replicaRepo "apismrtbiz/internal/repository/replica"
+	retryRepo "apismrtbiz/internal/repository/retry"
+	slowlogRepo "apismrtbiz/internal/repository/slowlog"
+	sqliteRepo "apismrtbiz/internal/repository/sqlite"
+
+	mysqlMigrations "apismrtbiz/database/migrations"
+	postgresMigrations "apismrtbiz/database/postgres/migrations"
+	sqliteMigrations "apismrtbiz/database/sqlite/migrations"
 
 	"apismrtbiz/article"
+	"apismrtbiz/category"
+	"apismrtbiz/comment"
+	"apismrtbiz/favorite"
+	"apismrtbiz/internal/config"
+	"apismrtbiz/internal/database"
+	"apismrtbiz/internal/events"
+	natsEvents "apismrtbiz/internal/events/nats"
+	"apismrtbiz/internal/graphql"
 	"apismrtbiz/internal/rest"
+	"apismrtbiz/internal/rest/middleware"
+	"apismrtbiz/internal/rpc"
+	"apismrtbiz/internal/server"
+	"apismrtbiz/internal/webhook"
+	"apismrtbiz/internal/workers"
 	"github.com/joho/godotenv"
-)
-
-const (
-	defaultTimeout = 30
-	defaultAddress = ":9090"
+	"github.com/prometheus/client_golang/prometheus"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
 )
 
 func init() {
@@ -30,50 +71,344 @@ func init() {
 }
 
 func main() {
-	//prepare database
-	dbHost := os.Getenv("DATABASE_HOST")
-	dbPort := os.Getenv("DATABASE_PORT")
-	dbUser := os.Getenv("DATABASE_USER")
-	dbPass := os.Getenv("DATABASE_PASS")
-	dbName := os.Getenv("DATABASE_NAME")
-	connection := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", dbUser, dbPass, dbHost, dbPort, dbName)
-	val := url.Values{}
-	val.Add("parseTime", "1")
-	val.Add("loc", "Asia/Jakarta")
-	dsn := fmt.Sprintf("%s?%s", connection, val.Encode())
-	dbConn, err := sql.Open(`mysql`, dsn)
+	// The settings are read from the CONFIG_FILE YAML file when it is given, the variables override it.
+	// They are described by the config package.
+	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal("failed to open connection to database", err)
+		log.Fatal(err)
 	}
-	err = dbConn.Ping()
+
+	//prepare database
+	dsn, err := cfg.Database.Conn().DSN()
 	if err != nil {
-		log.Fatal("failed to ping database ", err)
+		log.Fatal(err)
 	}
+	dbDriver, dbName := cfg.Database.Driver, cfg.Database.Name
+
+	// closed once the in-flight requests are drained
+	var closers []io.Closer
 
-	defer func() {
-		err := dbConn.Close()
+	var dbConn, replicaConn *sql.DB
+	var mongoDB *mongo.Database
+	if dbDriver == database.DriverMongo {
+		client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(dsn))
 		if err != nil {
-			log.Fatal("got error when closing the DB connection", err)
+			log.Fatal("failed to open connection to database", err)
+		}
+		mongoDB = client.Database(dbName)
+		closers = append(closers, closerFunc(func() error {
+			return client.Disconnect(context.Background())
+		}))
+		if err = client.Ping(context.Background(), nil); err != nil {
+			log.Fatal("failed to ping database ", err)
+		}
+		if err = mongoRepo.CreateIndexes(context.Background(), mongoDB); err != nil {
+			log.Fatal("failed to create the database indexes ", err)
+		}
+	} else {
+		dbConn, err = sql.Open(dbDriver, dsn)
+		if err != nil {
+			log.Fatal("failed to open connection to database", err)
+		}
+		closers = append(closers, dbConn)
+
+		pool := cfg.Database.Pool()
+		pool.Apply(dbConn)
+		// zero is the database/sql default: unlimited open connections, two idle ones and no max lifetime
+		log.Printf("database pool: max open connections %d, max idle connections %d, connection max lifetime %s",
+			pool.MaxOpenConns, pool.MaxIdleConns, pool.ConnMaxLifetime)
+
+		err = dbConn.Ping()
+		if err != nil {
+			log.Fatal("failed to ping database ", err)
+		}
+
+		// Apply the pending migrations unless DATABASE_MIGRATE turns them off, e.g. when production migrates
+		// with the migrate CLI. The embedded SQLite database always gets its schema.
+		if cfg.Database.Migrate || dbDriver == database.DriverSQLite {
+			migrations := map[string]fs.FS{
+				database.DriverMySQL:    mysqlMigrations.FS,
+				database.DriverPostgres: postgresMigrations.FS,
+				database.DriverSQLite:   sqliteMigrations.FS,
+			}
+			if err = database.Migrate(context.Background(), dbConn, dbDriver, migrations[dbDriver]); err != nil {
+				log.Fatal("failed to migrate the database ", err)
+			}
 		}
-	}()
+
+		// Read the articles from a replica once its host is given, it is reached with the credentials of the primary
+		if replicaConfig, ok := cfg.Database.ReplicaConn(); ok {
+			replicaDSN, err := replicaConfig.DSN()
+			if err != nil {
+				log.Fatal(err)
+			}
+			replicaConn, err = sql.Open(dbDriver, replicaDSN)
+			if err != nil {
+				log.Fatal("failed to open connection to the replica database", err)
+			}
+			closers = append(closers, replicaConn)
+			pool.Apply(replicaConn)
+			if err = replicaConn.Ping(); err != nil {
+				log.Fatal("failed to ping the replica database ", err)
+			}
+		}
+	}
 
 	//todo: exchange
-	app := fiber.New()
-	app.Use(cors.New())
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+	logrus.AddHook(middleware.RequestIDHook{})
+
+	// Export the traces once an OTLP endpoint is configured, the exporter reads the OTEL_EXPORTER_OTLP_* variables
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" {
+		exporter, err := otlptracehttp.New(context.Background())
+		if err != nil {
+			log.Fatal("failed to create the trace exporter ", err)
+		}
+		tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+		otel.SetTracerProvider(tp)
+		closers = append(closers, closerFunc(func() error {
+			return tp.Shutdown(context.Background())
+		}))
+	}
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	// Refuse the request bodies over BODY_LIMIT bytes, 1MB by default
+	appConfig := middleware.BodyLimit(cfg.Server.BodyLimit)
+	// /articles/ and /Articles are served like /articles by default. TRAILING_SLASH=redirect sends the
+	// paths ending with a slash to the canonical one instead, TRAILING_SLASH=strict answers them with 404.
+	// CASE_SENSITIVE_ROUTING=true matches the case of the paths.
+	trailingSlash := cfg.Features.TrailingSlash
+	appConfig.StrictRouting = trailingSlash == "redirect" || trailingSlash == "strict"
+	appConfig.CaseSensitive = cfg.Features.CaseSensitiveRouting
+	app := fiber.New(appConfig)
+	if trailingSlash == "redirect" {
+		app.Use(middleware.TrailingSlash())
+	}
+	app.Use(middleware.RequestID())
+	app.Use(middleware.Tracing())
+	app.Use(middleware.NewMetrics(prometheus.DefaultRegisterer).Handler())
+	app.Use(middleware.Logger())
+	app.Use(middleware.Recover())
+	app.Use(middleware.Timeout(cfg.Server.ContextTimeout))
+	app.Use(middleware.Compress(middleware.CompressConfig{MinSize: cfg.Server.CompressMinSize}))
+	// DEBUG_BODY_PATHS=/articles/*,/comments logs the bodies of the requests to the matching paths at debug
+	// level, whatever the log level is. The credentials headers and the DEBUG_BODY_REDACT_FIELDS of the JSON
+	// bodies are redacted, the bodies are cut at DEBUG_BODY_MAX_BYTES.
+	if len(cfg.DebugBody.Paths) > 0 {
+		app.Use(middleware.BodyLog(middleware.BodyLogConfig{
+			Paths:        cfg.DebugBody.Paths,
+			MaxBytes:     cfg.DebugBody.MaxBytes,
+			RedactFields: cfg.DebugBody.RedactFields,
+		}))
+	}
+	corsOrigins := cfg.CORS.AllowOrigins
+	if len(corsOrigins) == 0 {
+		corsOrigins = []string{"*"}
+	}
+	app.Use(middleware.CORS(middleware.CORSConfig{
+		AllowOrigins:     corsOrigins,
+		ExposeHeaders:    []string{"X-Cursor", "X-Prev-Cursor", "X-Total-Count", "X-Total-Pages", fiber.HeaderLink, fiber.HeaderETag, fiber.HeaderXRequestID},
+		AllowCredentials: cfg.CORS.AllowCredentials,
+		MaxAge:           10 * time.Minute,
+	}))
+
+	app.Get("/metrics", middleware.MetricsEndpoint(prometheus.DefaultGatherer))
+
+	// Writes require an api key or a bearer token once either is configured
+	jwtSecret := cfg.Auth.JWTSecret
+	if apiKeys := cfg.Auth.Keys(); len(apiKeys) > 0 {
+		app.Use(middleware.APIKey(middleware.APIKeyConfig{Keys: apiKeys, Optional: jwtSecret != ""}))
+	} else if jwtSecret == "" {
+		log.Println("neither API_KEYS nor JWT_SECRET is set, write endpoints are not authenticated")
+	}
+	if jwtSecret != "" {
+		app.Use(middleware.JWT([]byte(jwtSecret)))
+	}
+
+	// Limit every client once a rate is configured, after the api keys are checked so the buckets are keyed by their label
+	if cfg.RateLimit.RPS > 0 {
+		app.Use(middleware.RateLimit(middleware.RateLimitConfig{Rate: cfg.RateLimit.RPS, Burst: cfg.RateLimit.Burst}))
+	}
 
 	// Prepare Repository
-	authorRepo := mysqlRepo.NewAuthorRepository(dbConn)
-	articleRepo := mysqlRepo.NewArticleRepository(dbConn)
+	var authorRepo article.AuthorRepository
+	var articleRepo article.ArticleRepository
+	var commentRepo comment.CommentRepository
+	var favoriteRepo favorite.FavoriteRepository
+	var categoryRepo category.CategoryRepository
+	switch dbDriver {
+	case database.DriverPostgres:
+		authorRepo = postgresRepo.NewAuthorRepository(dbConn)
+		articleRepo = postgresRepo.NewArticleRepository(dbConn)
+		commentRepo = postgresRepo.NewCommentRepository(dbConn)
+		favoriteRepo = postgresRepo.NewFavoriteRepository(dbConn)
+		categoryRepo = postgresRepo.NewCategoryRepository(dbConn)
+	case database.DriverSQLite:
+		authorRepo = sqliteRepo.NewAuthorRepository(dbConn)
+		articleRepo = sqliteRepo.NewArticleRepository(dbConn)
+		commentRepo = sqliteRepo.NewCommentRepository(dbConn)
+		favoriteRepo = sqliteRepo.NewFavoriteRepository(dbConn)
+		categoryRepo = sqliteRepo.NewCategoryRepository(dbConn)
+	case database.DriverMongo:
+		authorRepo = mongoRepo.NewAuthorRepository(mongoDB)
+		articleRepo = mongoRepo.NewArticleRepository(mongoDB)
+		commentRepo = mongoRepo.NewCommentRepository(mongoDB)
+		favoriteRepo = mongoRepo.NewFavoriteRepository(mongoDB)
+		categoryRepo = mongoRepo.NewCategoryRepository(mongoDB)
+	default:
+		authorRepo = mysqlRepo.NewAuthorRepository(dbConn)
+		articleRepo = mysqlRepo.NewArticleRepository(dbConn)
+		commentRepo = mysqlRepo.NewCommentRepository(dbConn)
+		favoriteRepo = mysqlRepo.NewFavoriteRepository(dbConn)
+		categoryRepo = mysqlRepo.NewCategoryRepository(dbConn)
+	}
+	if replicaConn != nil {
+		var replicaArticleRepo article.ArticleRepository = mysqlRepo.NewArticleRepository(replicaConn)
+		if dbDriver == database.DriverPostgres {
+			replicaArticleRepo = postgresRepo.NewArticleRepository(replicaConn)
+		}
+		articleRepo = replicaRepo.NewArticleRepository(articleRepo, replicaArticleRepo)
+	}
+	// Log the article queries running longer than the threshold once it is given
+	if threshold := cfg.Repository.SlowQueryThreshold; threshold > 0 {
+		articleRepo = slowlogRepo.NewArticleRepository(articleRepo, threshold)
+	}
+	// Retry the writes failing with a transient database error, one attempt turns the retries off
+	articleRepo = retryRepo.NewArticleRepository(articleRepo, retryRepo.Config{
+		MaxAttempts: cfg.Repository.RetryAttempts,
+		Backoff:     cfg.Repository.RetryBackoff,
+	})
 
-	// Build service Layer
-	svc := article.NewService(articleRepo, authorRepo)
-	rest.NewArticleHandler(app, svc)
+	// Cache the articles in redis when it is configured, otherwise in the process memory when a size is given.
+	// The cache is filled from the primary, a lagging replica would keep its stale rows until they expire.
+	if cfg.Cache.RedisAddress != "" {
+		rdb := goredis.NewClient(&goredis.Options{Addr: cfg.Cache.RedisAddress})
+		closers = append(closers, rdb)
+
+		articleRepo = redisRepo.NewArticleRepository(articleRepo, redisRepo.NewClient(rdb), redisRepo.Config{
+			TTL:       cfg.Cache.RedisTTL, // zero falls back to the default ttl
+			KeyPrefix: cfg.Cache.RedisKeyPrefix,
+		})
+	} else if cfg.Cache.Size > 0 {
+		articleRepo = lru.NewArticleRepository(articleRepo, cfg.Cache.Size)
+	}
+
+	// Build service Layer, the content of the articles is sanitized with the basic policy by default
+	sanitizer, err := article.NewSanitizer(cfg.Content.Policy)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts := []article.Option{article.WithSanitizer(sanitizer)}
+
+	// Post the lifecycle events of the articles to the webhooks, the queued events are delivered
+	// once the server stopped, within the drain timeout and before the database is closed
+	if webhookURLs := cfg.Events.WebhookURLs; len(webhookURLs) > 0 {
+		dispatcher := webhook.NewDispatcher(http.DefaultClient, webhook.Config{URLs: webhookURLs})
+		closers = append([]io.Closer{dispatcher}, closers...)
+		opts = append(opts, article.WithNotifier(dispatcher))
+	}
+
+	// Publish the lifecycle events of the articles to NATS as well once it is configured
+	if natsURL := cfg.Events.NATSURL; natsURL != "" {
+		conn, err := nats.Connect(natsURL)
+		if err != nil {
+			log.Fatal("failed to connect to NATS ", err)
+		}
+		broker := natsEvents.NewBroker(conn)
+		closers = append([]io.Closer{broker}, closers...)
+		opts = append(opts, article.WithNotifier(events.NewPublisher(broker)))
+	}
+
+	// Serve the searches from Elasticsearch once it is configured, the changed articles are indexed
+	// in the background and the database is searched while the index is unavailable
+	if esURL := cfg.Search.ElasticsearchURL; esURL != "" {
+		searchRepo := esRepo.NewArticleRepository(http.DefaultClient, esRepo.Config{URL: esURL, Index: cfg.Search.ElasticsearchIndex})
+		if err := searchRepo.EnsureIndex(context.Background()); err != nil {
+			logrus.Warn("failed to create the search index ", err)
+		}
+		indexer := esRepo.NewIndexer(searchRepo, articleRepo, 0)
+		closers = append([]io.Closer{indexer}, closers...)
+		opts = append(opts, article.WithSearchRepository(searchRepo), article.WithNotifier(indexer))
+	}
+	svc := article.NewService(articleRepo, authorRepo, opts...)
+	favoriteSvc := favorite.NewService(favoriteRepo, articleRepo)
+	// Mount the resources under API_BASE_PATH, e.g. /api behind a gateway, at the root by default.
+	// The unversioned routes keep the v1 shape of the articles for the existing clients.
+	basePath := strings.TrimRight(cfg.Server.BasePath, "/")
+	// DEFAULT_PAGE_SIZE and MAX_PAGE_SIZE bound the pages of the articles, REQUIRE_IF_MATCH=true refuses
+	// the PUT and PATCH of an article without the If-Match header
+	articleCfg := rest.HandlerConfig{
+		DefaultPageSize:  cfg.Pagination.DefaultPageSize,
+		MaxPageSize:      cfg.Pagination.MaxPageSize,
+		IdempotencyStore: memory.NewIdempotencyStore(),
+		WordsPerMinute:   cfg.Content.WordsPerMinute, // zero falls back to the default reading speed
+		Favorites:        favoriteSvc,
+		RequireIfMatch:   cfg.Features.RequireIfMatch,
+	}
+	commentSvc := comment.NewService(commentRepo, articleRepo)
+	categorySvc := category.NewService(categoryRepo)
+	for _, version := range []rest.APIVersion{"", rest.APIVersion1, rest.APIVersion2} {
+		versionCfg := articleCfg
+		versionCfg.BasePath, versionCfg.Version = basePath, version
+		if version != "" {
+			versionCfg.BasePath += "/" + string(version)
+		}
+		var resources fiber.Router = app
+		if versionCfg.BasePath != "" {
+			resources = app.Group(versionCfg.BasePath)
+		}
+		rest.NewArticleHandler(app, svc, versionCfg)
+		rest.NewCommentHandler(resources, commentSvc)
+		rest.NewFavoriteHandler(resources, favoriteSvc)
+		rest.NewCategoryHandler(resources, categorySvc)
+	}
+	graphql.NewHandler(app, svc)
+	if err := rest.NewDocsHandler(app); err != nil {
+		log.Fatal("failed to describe the API ", err)
+	}
+
+	rest.NewHealthHandler(app, map[string]rest.HealthChecker{
+		"database": articleRepo,
+	})
+
+	// Serve the articles over gRPC as well once an address is given, the writes are authenticated with the
+	// api keys and the bearer tokens of the REST API. The server stops before the database is closed.
+	if grpcAddress := cfg.Server.GRPCAddress; grpcAddress != "" {
+		ln, err := net.Listen("tcp", grpcAddress)
+		if err != nil {
+			log.Fatal("failed to listen for gRPC ", err)
+		}
+		grpcServer := grpc.NewServer(grpc.UnaryInterceptor(rpc.UnaryAuth(rpc.AuthConfig{
+			Keys:      cfg.Auth.Keys(),
+			JWTSecret: []byte(jwtSecret),
+		})))
+		rpc.NewArticleServer(grpcServer, svc)
+		go func() {
+			if err := grpcServer.Serve(ln); err != nil {
+				logrus.Error(err)
+			}
+		}()
+		closers = append([]io.Closer{closerFunc(func() error {
+			grpcServer.GracefulStop()
+			return nil
+		})}, closers...)
+	}
 
 	// Start Server
-	address := os.Getenv("SERVER_ADDRESS")
-	if address == "" {
-		address = defaultAddress
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Publish the scheduled drafts in the background until the shutdown
+	go workers.NewPublisher(svc, cfg.Worker.PublishInterval).Run(ctx) // zero falls back to the default interval
+
+	if err := server.Run(ctx, app, cfg.Server.Address, cfg.Server.ShutdownTimeout, closers...); err != nil {
+		log.Fatal(err) //nolint
 	}
+}
+
+// closerFunc adapts a function to io.Closer
+type closerFunc func() error
 
-	log.Fatal(app.Listen(address)) //nolint
+func (f closerFunc) Close() error {
+	return f()
 }
@@ -0,0 +1,116 @@
+package article
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"apismrtbiz/domain"
+)
+
+const (
+	// maxFuzzyCandidates bounds how many articles a fuzzy search ranks, the most recently updated first
+	maxFuzzyCandidates = 500
+	fuzzyBatchSize     = 100
+	// minFuzzyScore is the lowest score an article is still matching with, one typo in a five letters word scores 0.8
+	minFuzzyScore = 0.7
+)
+
+// FuzzySearch will list the published articles approximately matching the query, the most relevant first.
+// Every word of the query is matched to the closest word of the title by their Levenshtein distance,
+// so a misspelled query still finds the article it was meant for. The content isn't ranked, its
+// length would make the cost of a search unbounded.
+func (a *Service) FuzzySearch(ctx context.Context, query string, num int64) (res []domain.Article, err error) {
+	ctx, span := tracer.Start(ctx, "Service.FuzzySearch")
+	defer func() { endSpan(span, err) }()
+
+	terms := words(query)
+	if len(terms) == 0 {
+		return []domain.Article{}, nil
+	}
+
+	var cursor domain.Cursor
+	res = make([]domain.Article, 0)
+	for scanned := 0; scanned < maxFuzzyCandidates; {
+		batch, errFetch := a.articleRepo.Fetch(ctx, cursor, fuzzyBatchSize, domain.ArticleFilter{Status: domain.StatusPublished})
+		if errFetch != nil {
+			return nil, errFetch
+		}
+		for _, ar := range batch {
+			if score := fuzzyScore(terms, words(ar.Title)); score >= minFuzzyScore {
+				ar.SearchScore = &score
+				res = append(res, ar)
+			}
+		}
+		scanned += len(batch)
+		if len(batch) < fuzzyBatchSize {
+			break
+		}
+		cursor = domain.NewCursor(domain.SortByUpdatedAt, batch[len(batch)-1])
+	}
+
+	slices.SortStableFunc(res, func(x, y domain.Article) int {
+		return cmp.Compare(*y.SearchScore, *x.SearchScore)
+	})
+	if int64(len(res)) > num {
+		res = res[:num]
+	}
+	return a.fillAuthorDetails(ctx, res)
+}
+
+// words will split the text into its lower cased words
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// fuzzyScore is the mean similarity of every term to its closest word, rounded to three decimals
+func fuzzyScore(terms, words []string) float64 {
+	if len(words) == 0 {
+		return 0
+	}
+	var total float64
+	for _, term := range terms {
+		var best float64
+		for _, word := range words {
+			best = math.Max(best, similarity(term, word))
+		}
+		total += best
+	}
+	return math.Round(total/float64(len(terms))*1000) / 1000
+}
+
+// similarity is one minus the Levenshtein distance of the words relative to the longest one
+func similarity(a, b string) float64 {
+	x, y := []rune(a), []rune(b)
+	longest := max(len(x), len(y))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(x, y))/float64(longest)
+}
+
+// levenshtein is the least number of single rune insertions, deletions or substitutions turning a into b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,22 @@
+package article
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// markdown is the pipeline rendering the content of the articles, the raw HTML
+// of the content is left out by the renderer before it is sanitized
+var markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// RenderHTML will convert the markdown content of an article to HTML, the HTML
+// goes through the sanitizer of the stored content
+func (a *Service) RenderHTML(content string) (string, error) {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(content), &buf); err != nil {
+		return "", err
+	}
+	return a.sanitizer.Sanitize(buf.String()), nil
+}
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	domain "apismrtbiz/domain"
 	mock "github.com/stretchr/testify/mock"
@@ -14,6 +15,34 @@ type ArticleRepository struct {
 	mock.Mock
 }
 
+// Count provides a mock function with given fields: ctx, status
+func (_m *ArticleRepository) Count(ctx context.Context, status domain.Status) (int64, error) {
+	ret := _m.Called(ctx, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Count")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Status) (int64, error)); ok {
+		return rf(ctx, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Status) int64); ok {
+		r0 = rf(ctx, status)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Status) error); ok {
+		r1 = rf(ctx, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx, id
 func (_m *ArticleRepository) Delete(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
@@ -32,41 +61,64 @@ func (_m *ArticleRepository) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *ArticleRepository) Fetch(ctx context.Context, cursor string, num int64) ([]domain.Article, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// Fetch provides a mock function with given fields: ctx, cursor, num, filter
+func (_m *ArticleRepository) Fetch(ctx context.Context, cursor domain.Cursor, num int64, filter domain.ArticleFilter) ([]domain.Article, error) {
+	ret := _m.Called(ctx, cursor, num, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Fetch")
 	}
 
 	var r0 []domain.Article
-	var r1 string
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) ([]domain.Article, string, error)); ok {
-		return rf(ctx, cursor, num)
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Cursor, int64, domain.ArticleFilter) ([]domain.Article, error)); ok {
+		return rf(ctx, cursor, num, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []domain.Article); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Cursor, int64, domain.ArticleFilter) []domain.Article); ok {
+		r0 = rf(ctx, cursor, num, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Article)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Cursor, int64, domain.ArticleFilter) error); ok {
+		r1 = rf(ctx, cursor, num, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchRelated provides a mock function with given fields: ctx, ar, num
+func (_m *ArticleRepository) FetchRelated(ctx context.Context, ar domain.Article, num int64) ([]domain.Article, error) {
+	ret := _m.Called(ctx, ar, num)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchRelated")
+	}
+
+	var r0 []domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Article, int64) ([]domain.Article, error)); ok {
+		return rf(ctx, ar, num)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Article, int64) []domain.Article); ok {
+		r0 = rf(ctx, ar, num)
 	} else {
-		r1 = ret.Get(1).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Article, int64) error); ok {
+		r1 = rf(ctx, ar, num)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
 // GetByID provides a mock function with given fields: ctx, id
@@ -97,6 +149,64 @@ func (_m *ArticleRepository) GetByID(ctx context.Context, id int64) (domain.Arti
 	return r0, r1
 }
 
+// GetByIDs provides a mock function with given fields: ctx, ids
+func (_m *ArticleRepository) GetByIDs(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByIDs")
+	}
+
+	var r0 []domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) ([]domain.Article, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []domain.Article); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBySlug provides a mock function with given fields: ctx, slug
+func (_m *ArticleRepository) GetBySlug(ctx context.Context, slug string) (domain.Article, error) {
+	ret := _m.Called(ctx, slug)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBySlug")
+	}
+
+	var r0 domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Article, error)); ok {
+		return rf(ctx, slug)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Article); ok {
+		r0 = rf(ctx, slug)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByTitle provides a mock function with given fields: ctx, title
 func (_m *ArticleRepository) GetByTitle(ctx context.Context, title string) (domain.Article, error) {
 	ret := _m.Called(ctx, title)
@@ -125,6 +235,226 @@ func (_m *ArticleRepository) GetByTitle(ctx context.Context, title string) (doma
 	return r0, r1
 }
 
+// GetRevision provides a mock function with given fields: ctx, articleID, version
+func (_m *ArticleRepository) GetRevision(ctx context.Context, articleID int64, version int64) (domain.Revision, error) {
+	ret := _m.Called(ctx, articleID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRevision")
+	}
+
+	var r0 domain.Revision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (domain.Revision, error)); ok {
+		return rf(ctx, articleID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) domain.Revision); ok {
+		r0 = rf(ctx, articleID, version)
+	} else {
+		r0 = ret.Get(0).(domain.Revision)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, articleID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IncrementViews provides a mock function with given fields: ctx, id
+func (_m *ArticleRepository) IncrementViews(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementViews")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListRevisions provides a mock function with given fields: ctx, articleID
+func (_m *ArticleRepository) ListRevisions(ctx context.Context, articleID int64) ([]domain.Revision, error) {
+	ret := _m.Called(ctx, articleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRevisions")
+	}
+
+	var r0 []domain.Revision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]domain.Revision, error)); ok {
+		return rf(ctx, articleID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []domain.Revision); ok {
+		r0 = rf(ctx, articleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Revision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, articleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OffsetFetch provides a mock function with given fields: ctx, offset, limit, status
+func (_m *ArticleRepository) OffsetFetch(ctx context.Context, offset int64, limit int64, status domain.Status) ([]domain.Article, error) {
+	ret := _m.Called(ctx, offset, limit, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OffsetFetch")
+	}
+
+	var r0 []domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, domain.Status) ([]domain.Article, error)); ok {
+		return rf(ctx, offset, limit, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, domain.Status) []domain.Article); ok {
+		r0 = rf(ctx, offset, limit, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, domain.Status) error); ok {
+		r1 = rf(ctx, offset, limit, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Ping provides a mock function with given fields: ctx
+func (_m *ArticleRepository) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PublishDue provides a mock function with given fields: ctx, now
+func (_m *ArticleRepository) PublishDue(ctx context.Context, now time.Time) ([]int64, error) {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishDue")
+	}
+
+	var r0 []int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]int64, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []int64); ok {
+		r0 = rf(ctx, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *ArticleRepository) Restore(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: ctx, query, num, cursor, status
+func (_m *ArticleRepository) Search(ctx context.Context, query string, num int64, cursor domain.Cursor, status domain.Status) ([]domain.Article, error) {
+	ret := _m.Called(ctx, query, num, cursor, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 []domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, domain.Cursor, domain.Status) ([]domain.Article, error)); ok {
+		return rf(ctx, query, num, cursor, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, domain.Cursor, domain.Status) []domain.Article); ok {
+		r0 = rf(ctx, query, num, cursor, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, domain.Cursor, domain.Status) error); ok {
+		r1 = rf(ctx, query, num, cursor, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetStatus provides a mock function with given fields: ctx, id, status
+func (_m *ArticleRepository) SetStatus(ctx context.Context, id int64, status domain.Status) error {
+	ret := _m.Called(ctx, id, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.Status) error); ok {
+		r0 = rf(ctx, id, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Store provides a mock function with given fields: ctx, a
 func (_m *ArticleRepository) Store(ctx context.Context, a *domain.Article) error {
 	ret := _m.Called(ctx, a)
@@ -143,6 +473,24 @@ func (_m *ArticleRepository) Store(ctx context.Context, a *domain.Article) error
 	return r0
 }
 
+// StoreRevision provides a mock function with given fields: ctx, rev
+func (_m *ArticleRepository) StoreRevision(ctx context.Context, rev *domain.Revision) error {
+	ret := _m.Called(ctx, rev)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreRevision")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Revision) error); ok {
+		r0 = rf(ctx, rev)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, ar
 func (_m *ArticleRepository) Update(ctx context.Context, ar *domain.Article) error {
 	ret := _m.Called(ctx, ar)
@@ -161,6 +509,24 @@ func (_m *ArticleRepository) Update(ctx context.Context, ar *domain.Article) err
 	return r0
 }
 
+// WithinTransaction provides a mock function with given fields: ctx, fn
+func (_m *ArticleRepository) WithinTransaction(ctx context.Context, fn func(context.Context) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithinTransaction")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewArticleRepository creates a new instance of ArticleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewArticleRepository(t interface {
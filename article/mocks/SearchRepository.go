@@ -0,0 +1,59 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "apismrtbiz/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SearchRepository is an autogenerated mock type for the SearchRepository type
+type SearchRepository struct {
+	mock.Mock
+}
+
+// Search provides a mock function with given fields: ctx, query, num, cursor, status
+func (_m *SearchRepository) Search(ctx context.Context, query string, num int64, cursor domain.Cursor, status domain.Status) ([]domain.Article, error) {
+	ret := _m.Called(ctx, query, num, cursor, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 []domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, domain.Cursor, domain.Status) ([]domain.Article, error)); ok {
+		return rf(ctx, query, num, cursor, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, domain.Cursor, domain.Status) []domain.Article); ok {
+		r0 = rf(ctx, query, num, cursor, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, domain.Cursor, domain.Status) error); ok {
+		r1 = rf(ctx, query, num, cursor, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSearchRepository creates a new instance of SearchRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSearchRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SearchRepository {
+	mock := &SearchRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
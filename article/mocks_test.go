@@ -0,0 +1,61 @@
+package article_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/article"
+	"apismrtbiz/article/mocks"
+	"apismrtbiz/domain"
+)
+
+// TestMocks will fail to compile once a mock falls behind its interface, rerun go generate then
+func TestMocks(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("ArticleRepository", func(t *testing.T) {
+		m := new(mocks.ArticleRepository)
+		var repo article.ArticleRepository = m
+		m.On("Count", mock.Anything, domain.StatusPublished).Return(int64(3), nil).Once()
+
+		total, err := repo.Count(ctx, domain.StatusPublished)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("AuthorRepository", func(t *testing.T) {
+		m := new(mocks.AuthorRepository)
+		var repo article.AuthorRepository = m
+		m.On("GetByID", mock.Anything, int64(1)).Return(domain.Author{ID: 1}, nil).Once()
+
+		author, err := repo.GetByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), author.ID)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("Notifier", func(t *testing.T) {
+		m := new(mocks.Notifier)
+		var notifier article.Notifier = m
+		m.On("Notify", mock.Anything, domain.Event{Type: domain.EventArticleCreated, ArticleID: 1}).Once()
+
+		notifier.Notify(ctx, domain.Event{Type: domain.EventArticleCreated, ArticleID: 1})
+		m.AssertExpectations(t)
+	})
+
+	t.Run("SearchRepository", func(t *testing.T) {
+		m := new(mocks.SearchRepository)
+		var repo article.SearchRepository = m
+		m.On("Search", mock.Anything, "go", int64(10), domain.Cursor{}, domain.StatusPublished).Return([]domain.Article{{ID: 1}}, nil).Once()
+
+		list, err := repo.Search(ctx, "go", 10, domain.Cursor{}, domain.StatusPublished)
+		require.NoError(t, err)
+		assert.Len(t, list, 1)
+		m.AssertExpectations(t)
+	})
+}
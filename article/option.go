@@ -0,0 +1,27 @@
+package article
+
+// Option configure the Service
+type Option func(*Service)
+
+// WithSanitizer will sanitize the content of the stored and updated articles with s
+// instead of the basic policy
+func WithSanitizer(s Sanitizer) Option {
+	return func(a *Service) {
+		a.sanitizer = s
+	}
+}
+
+// WithNotifier will notify n of the articles created, updated and deleted,
+// along the notifiers given before
+func WithNotifier(n Notifier) Option {
+	return func(a *Service) {
+		a.notifiers = append(a.notifiers, n)
+	}
+}
+
+// WithSearchRepository will serve the searches from the index r, ranked by their relevance
+func WithSearchRepository(r SearchRepository) Option {
+	return func(a *Service) {
+		a.searchRepo = r
+	}
+}
@@ -0,0 +1,32 @@
+package article
+
+import (
+	"fmt"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// The policies of the sanitization of the content of the articles
+const (
+	// PolicyStrict strips every tag, leaving the text only
+	PolicyStrict = "strict"
+	// PolicyBasic keeps the safe formatting such as paragraphs, emphasis, lists and links
+	PolicyBasic = "basic"
+)
+
+// Sanitizer will clean the content of an article before it is stored
+type Sanitizer interface {
+	Sanitize(s string) string
+}
+
+// NewSanitizer will create the sanitizer of the given policy, an empty policy is the basic one
+func NewSanitizer(policy string) (Sanitizer, error) {
+	switch policy {
+	case PolicyStrict:
+		return bluemonday.StrictPolicy(), nil
+	case PolicyBasic, "":
+		return bluemonday.UGCPolicy(), nil
+	default:
+		return nil, fmt.Errorf("unknown content policy %q, must be one of %s, %s", policy, PolicyStrict, PolicyBasic)
+	}
+}
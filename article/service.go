@@ -2,24 +2,52 @@ package article
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/microcosm-cc/bluemonday"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 
 	"apismrtbiz/domain"
 )
 
+// Transactor represent the unit of work of the repository, the repository calls given
+// the context of fn run in one transaction which is rolled back when fn returns an error
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 // ArticleRepository represent the article's repository contract
 //
 //go:generate mockery --name ArticleRepository
 type ArticleRepository interface {
-	Fetch(ctx context.Context, cursor string, num int64) (res []domain.Article, nextCursor string, err error)
+	Transactor
+	Fetch(ctx context.Context, cursor domain.Cursor, num int64, filter domain.ArticleFilter) (res []domain.Article, err error)
+	OffsetFetch(ctx context.Context, offset, limit int64, status domain.Status) (res []domain.Article, err error)
+	FetchRelated(ctx context.Context, ar domain.Article, num int64) (res []domain.Article, err error)
+	Count(ctx context.Context, status domain.Status) (int64, error)
 	GetByID(ctx context.Context, id int64) (domain.Article, error)
+	GetByIDs(ctx context.Context, ids []int64) ([]domain.Article, error)
 	GetByTitle(ctx context.Context, title string) (domain.Article, error)
+	GetBySlug(ctx context.Context, slug string) (domain.Article, error)
 	Update(ctx context.Context, ar *domain.Article) error
 	Store(ctx context.Context, a *domain.Article) error
 	Delete(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) error
+	SetStatus(ctx context.Context, id int64, status domain.Status) error
+	PublishDue(ctx context.Context, now time.Time) ([]int64, error)
+	IncrementViews(ctx context.Context, id int64) error
+	Search(ctx context.Context, query string, num int64, cursor domain.Cursor, status domain.Status) (res []domain.Article, err error)
+	StoreRevision(ctx context.Context, rev *domain.Revision) error
+	ListRevisions(ctx context.Context, articleID int64) ([]domain.Revision, error)
+	GetRevision(ctx context.Context, articleID, version int64) (domain.Revision, error)
+	Ping(ctx context.Context) error
 }
 
 // AuthorRepository represent the author's repository contract
@@ -29,16 +57,49 @@ type AuthorRepository interface {
 	GetByID(ctx context.Context, id int64) (domain.Author, error)
 }
 
+// Notifier will be told about the changes of the articles once they are stored,
+// it must not block the usecase
+//
+//go:generate mockery --name Notifier
+type Notifier interface {
+	Notify(ctx context.Context, ev domain.Event)
+}
+
+// SearchRepository represent the full text index of the articles, it serves the searches
+// in place of the article's repository once it is given
+//
+//go:generate mockery --name SearchRepository
+type SearchRepository interface {
+	Search(ctx context.Context, query string, num int64, cursor domain.Cursor, status domain.Status) (res []domain.Article, err error)
+}
+
 type Service struct {
 	articleRepo ArticleRepository
 	authorRepo  AuthorRepository
+	searchRepo  SearchRepository
+	sanitizer   Sanitizer
+	notifiers   []Notifier
 }
 
-// NewService will create a new article service object
-func NewService(a ArticleRepository, ar AuthorRepository) *Service {
-	return &Service{
+// NewService will create a new article service object, the content of the articles
+// is sanitized with the basic policy unless an other sanitizer is given
+func NewService(a ArticleRepository, ar AuthorRepository, opts ...Option) *Service {
+	svc := &Service{
 		articleRepo: a,
 		authorRepo:  ar,
+		sanitizer:   bluemonday.UGCPolicy(),
+	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	return svc
+}
+
+// notify will tell the notifiers about the change of the article
+func (a *Service) notify(ctx context.Context, typ domain.EventType, ar domain.Article) {
+	ev := domain.Event{Type: typ, ArticleID: ar.ID, Version: ar.Version, Timestamp: time.Now().UTC()}
+	for _, n := range a.notifiers {
+		n.Notify(ctx, ev)
 	}
 }
 
@@ -73,7 +134,7 @@ func (a *Service) fillAuthorDetails(ctx context.Context, data []domain.Article)
 		defer close(chanAuthor)
 		err := g.Wait()
 		if err != nil {
-			logrus.Error(err)
+			logrus.WithContext(ctx).Error(err)
 			return
 		}
 
@@ -98,20 +159,155 @@ func (a *Service) fillAuthorDetails(ctx context.Context, data []domain.Article)
 	return data, nil
 }
 
-func (a *Service) Fetch(ctx context.Context, cursor string, num int64) (res []domain.Article, nextCursor string, err error) {
-	res, nextCursor, err = a.articleRepo.Fetch(ctx, cursor, num)
+// decodeCursor will read the opaque cursor given by the client, an empty one starts from the beginning
+func decodeCursor(cursor string) (domain.Cursor, error) {
+	if cursor == "" {
+		return domain.Cursor{}, nil
+	}
+	return domain.DecodeCursor(cursor)
+}
+
+// Fetch will list a page of articles next to the cursor, a backward page is listed
+// in the same order as a forward one. The next and prev cursors point after the last
+// and before the first article of the page, they are left empty when there is no such page.
+func (a *Service) Fetch(ctx context.Context, cursor string, num int64, filter domain.ArticleFilter) (res []domain.Article, nextCursor, prevCursor string, err error) {
+	ctx, span := tracer.Start(ctx, "Service.Fetch")
+	defer func() { endSpan(span, err) }()
+
+	decoded, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	res, err = a.articleRepo.Fetch(ctx, decoded, num, filter)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if filter.Backward {
+		slices.Reverse(res)
+	}
+
+	res, err = a.fillAuthorDetails(ctx, res)
+	if err != nil || len(res) == 0 {
+		return
+	}
+
+	hasNext, hasPrev := len(res) == int(num), cursor != ""
+	if filter.Backward {
+		hasNext, hasPrev = hasPrev, hasNext
+	}
+	if hasNext {
+		nextCursor = domain.NewCursor(filter.Sort.Field, res[len(res)-1]).Encode()
+	}
+	if hasPrev {
+		prevCursor = domain.NewCursor(filter.Sort.Field, res[0]).Encode()
+	}
+	return
+}
+
+// Search will list the articles at the status whose title or content contains the query,
+// the most recently updated first. With a search repository the articles are ranked
+// by their relevance instead, the article's repository is searched when the index fails.
+func (a *Service) Search(ctx context.Context, query string, num int64, cursor string, status domain.Status) (res []domain.Article, nextCursor string, err error) {
+	ctx, span := tracer.Start(ctx, "Service.Search")
+	defer func() { endSpan(span, err) }()
+
+	decoded, err := decodeCursor(cursor)
 	if err != nil {
 		return nil, "", err
 	}
 
+	if a.searchRepo != nil {
+		res, err = a.searchRepo.Search(ctx, query, num, decoded, status)
+		if err == nil {
+			return a.rankedPage(ctx, res, num)
+		}
+		if errors.Is(err, domain.ErrBadParamInput) {
+			return nil, "", err
+		}
+		logrus.WithContext(ctx).Warnf("search index failed, searching the database: %s", err)
+	}
+
+	res, err = a.articleRepo.Search(ctx, query, num, decoded, status)
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, err = a.fillAuthorDetails(ctx, res)
+	if err == nil && len(res) == int(num) {
+		nextCursor = domain.NewCursor(domain.SortByUpdatedAt, res[len(res)-1]).Encode()
+	}
+	return
+}
+
+// rankedPage will fill the articles found by the search repository, the next cursor
+// points after the relevance score of the last one
+func (a *Service) rankedPage(ctx context.Context, list []domain.Article, num int64) (res []domain.Article, nextCursor string, err error) {
+	res, err = a.fillAuthorDetails(ctx, list)
+	if err != nil || len(res) != int(num) {
+		return
+	}
+	last := res[len(res)-1]
+	if last.SearchScore != nil {
+		nextCursor = domain.Cursor{ID: last.ID, Value: strconv.FormatFloat(*last.SearchScore, 'g', -1, 64)}.Encode()
+	}
+	return
+}
+
+// GetRelated will list the published articles sharing tags or the author with the article of the given id,
+// an article with nothing in common with the others gets an empty list
+func (a *Service) GetRelated(ctx context.Context, id int64, num int64) (res []domain.Article, err error) {
+	ctx, span := tracer.Start(ctx, "Service.GetRelated")
+	defer func() { endSpan(span, err) }()
+
+	ar, err := a.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err = a.articleRepo.FetchRelated(ctx, ar, num)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.fillAuthorDetails(ctx, res)
+}
+
+// OffsetFetch will list the page of the articles at the status along with how many there are,
+// an empty status lists every stage
+func (a *Service) OffsetFetch(ctx context.Context, page, perPage int64, status domain.Status) (res []domain.Article, total int64, err error) {
+	ctx, span := tracer.Start(ctx, "Service.OffsetFetch")
+	defer func() { endSpan(span, err) }()
+
+	res, err = a.articleRepo.OffsetFetch(ctx, (page-1)*perPage, perPage, status)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	res, err = a.fillAuthorDetails(ctx, res)
 	if err != nil {
-		nextCursor = ""
+		return nil, 0, err
+	}
+
+	total, err = a.articleRepo.Count(ctx, status)
+	if err != nil {
+		return nil, 0, err
 	}
 	return
 }
 
+// Count will count the articles at the status, an empty status counts every stage
+func (a *Service) Count(ctx context.Context, status domain.Status) (total int64, err error) {
+	ctx, span := tracer.Start(ctx, "Service.Count")
+	defer func() { endSpan(span, err) }()
+
+	return a.articleRepo.Count(ctx, status)
+}
+
 func (a *Service) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
+	ctx, span := tracer.Start(ctx, "Service.GetByID")
+	defer func() { endSpan(span, err) }()
+
 	res, err = a.articleRepo.GetByID(ctx, id)
 	if err != nil {
 		return
@@ -125,12 +321,107 @@ func (a *Service) GetByID(ctx context.Context, id int64) (res domain.Article, er
 	return
 }
 
+// GetByIDs will get the articles of the given ids in the requested order,
+// ids which doesn't exist are left out of the result
+func (a *Service) GetByIDs(ctx context.Context, ids []int64) (res []domain.Article, err error) {
+	ctx, span := tracer.Start(ctx, "Service.GetByIDs")
+	defer func() { endSpan(span, err) }()
+
+	unique := make([]int64, 0, len(ids))
+	seen := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+
+	list, err := a.articleRepo.GetByIDs(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]domain.Article, len(list))
+	for _, item := range list { //nolint
+		byID[item.ID] = item
+	}
+
+	res = make([]domain.Article, 0, len(list))
+	for _, id := range unique {
+		if item, ok := byID[id]; ok {
+			res = append(res, item)
+		}
+	}
+
+	return a.fillAuthorDetails(ctx, res)
+}
+
+// Update will save the given article when its version still matches the stored one,
+// a stale version is reported as a conflict
 func (a *Service) Update(ctx context.Context, ar *domain.Article) (err error) {
-	ar.UpdatedAt = time.Now()
-	return a.articleRepo.Update(ctx, ar)
+	ctx, span := tracer.Start(ctx, "Service.Update")
+	defer func() { endSpan(span, err) }()
+
+	ar.Tags = normalizeTags(ar.Tags)
+	ar.Content = a.sanitizer.Sanitize(ar.Content)
+	// the replaced version is kept as a revision, in the transaction of the update
+	err = a.articleRepo.WithinTransaction(ctx, func(ctx context.Context) error {
+		prev, err := a.articleRepo.GetByID(ctx, ar.ID)
+		if err != nil {
+			return err
+		}
+		// the slug is kept as the article is retitled, so that its URL stays valid
+		ar.Slug = prev.Slug
+		if err = a.articleRepo.Update(ctx, ar); err != nil {
+			return err
+		}
+		return a.articleRepo.StoreRevision(ctx, &domain.Revision{
+			ArticleID: prev.ID,
+			Version:   prev.Version,
+			Title:     prev.Title,
+			Content:   prev.Content,
+			Tags:      prev.Tags,
+		})
+	})
+	if err == nil {
+		a.notify(ctx, domain.EventArticleUpdated, *ar)
+	}
+	return
+}
+
+// ListRevisions will list the revisions of the article of the given id, the newest first
+func (a *Service) ListRevisions(ctx context.Context, id int64) (res []domain.Revision, err error) {
+	ctx, span := tracer.Start(ctx, "Service.ListRevisions")
+	defer func() { endSpan(span, err) }()
+
+	if _, err = a.articleRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return a.articleRepo.ListRevisions(ctx, id)
+}
+
+// DiffRevisions will compare the two stored revisions of the article of the given id,
+// a revision which doesn't exist is reported as ErrNotFound
+func (a *Service) DiffRevisions(ctx context.Context, id, from, to int64) (res domain.RevisionDiff, err error) {
+	ctx, span := tracer.Start(ctx, "Service.DiffRevisions")
+	defer func() { endSpan(span, err) }()
+
+	fromRev, err := a.articleRepo.GetRevision(ctx, id, from)
+	if err != nil {
+		return domain.RevisionDiff{}, err
+	}
+	toRev, err := a.articleRepo.GetRevision(ctx, id, to)
+	if err != nil {
+		return domain.RevisionDiff{}, err
+	}
+	return domain.DiffRevisions(fromRev, toRev), nil
 }
 
 func (a *Service) GetByTitle(ctx context.Context, title string) (res domain.Article, err error) {
+	ctx, span := tracer.Start(ctx, "Service.GetByTitle")
+	defer func() { endSpan(span, err) }()
+
 	res, err = a.articleRepo.GetByTitle(ctx, title)
 	if err != nil {
 		return
@@ -145,23 +436,251 @@ func (a *Service) GetByTitle(ctx context.Context, title string) (res domain.Arti
 	return
 }
 
+// GetBySlug will get the article of the given slug, a soft deleted article is not found
+func (a *Service) GetBySlug(ctx context.Context, slug string) (res domain.Article, err error) {
+	ctx, span := tracer.Start(ctx, "Service.GetBySlug")
+	defer func() { endSpan(span, err) }()
+
+	res, err = a.articleRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return
+	}
+	if res.DeletedAt != nil {
+		return domain.Article{}, domain.ErrNotFound
+	}
+
+	resAuthor, err := a.authorRepo.GetByID(ctx, res.Author.ID)
+	if err != nil {
+		return domain.Article{}, err
+	}
+
+	res.Author = resAuthor
+	return
+}
+
+// maxSlugAttempts bounds the suffixes tried for a slug before the title is reported as a conflict
+const maxSlugAttempts = 100
+
+// uniqueSlug will slugify the title, suffixing the slug with a counter, e.g. hello-world-2,
+// while it is taken by a stored article or by one of the reserved slugs
+func (a *Service) uniqueSlug(ctx context.Context, title string, reserved map[string]struct{}) (string, error) {
+	base := domain.Slugify(title)
+	slug := base
+	for i := 2; i < maxSlugAttempts+2; i++ {
+		if _, ok := reserved[slug]; !ok {
+			_, err := a.articleRepo.GetBySlug(ctx, slug)
+			if errors.Is(err, domain.ErrNotFound) {
+				return slug, nil
+			}
+			if err != nil {
+				return "", err
+			}
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+	return "", domain.ErrConflict
+}
+
+// normalizeTitle will reduce the title to the form used to detect duplicates
+func normalizeTitle(title string) string {
+	return strings.ToLower(strings.TrimSpace(title))
+}
+
+// normalizeTags will lowercase and trim the tags, dropping the duplicates, in the order they are stored
+func normalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	res := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		res = append(res, strings.ToLower(strings.TrimSpace(tag)))
+	}
+	slices.Sort(res)
+	return slices.Compact(res)
+}
+
+// titleExists will report whether an article with the same title, ignoring case
+// and surrounding whitespace, is already stored
+func (a *Service) titleExists(ctx context.Context, title string) bool {
+	existedArticle, err := a.GetByTitle(ctx, strings.TrimSpace(title))
+	if err != nil { // ignore if any error
+		return false
+	}
+	return normalizeTitle(existedArticle.Title) == normalizeTitle(title)
+}
+
 func (a *Service) Store(ctx context.Context, m *domain.Article) (err error) {
-	existedArticle, _ := a.GetByTitle(ctx, m.Title) // ignore if any error
-	if existedArticle != (domain.Article{}) {
+	ctx, span := tracer.Start(ctx, "Service.Store")
+	defer func() { endSpan(span, err) }()
+
+	// the repository hands out the id, only an upsert stores the article under the one it is given
+	m.ID = 0
+	return a.store(ctx, m)
+}
+
+// Upsert will update the article of the id of ar, or store it under that id when there is no such article.
+// created tells which of them happened. The id of a deleted article stays taken, storing under it is
+// reported as a conflict.
+func (a *Service) Upsert(ctx context.Context, ar *domain.Article) (created bool, err error) {
+	ctx, span := tracer.Start(ctx, "Service.Upsert")
+	defer func() { endSpan(span, err) }()
+
+	err = a.Update(ctx, ar)
+	if !errors.Is(err, domain.ErrNotFound) {
+		return false, err
+	}
+	return true, a.store(ctx, ar)
+}
+
+// store will store the article under the id it carries, the next one when zero
+func (a *Service) store(ctx context.Context, m *domain.Article) (err error) {
+	if a.titleExists(ctx, m.Title) {
 		return domain.ErrConflict
 	}
 
-	err = a.articleRepo.Store(ctx, m)
+	if m.Status == "" {
+		m.Status = domain.StatusDraft
+	}
+	m.Tags = normalizeTags(m.Tags)
+	m.Content = a.sanitizer.Sanitize(m.Content)
+	if m.Slug, err = a.uniqueSlug(ctx, m.Title, nil); err != nil {
+		return
+	}
+	if err = a.articleRepo.Store(ctx, m); err != nil {
+		return
+	}
+	a.notify(ctx, domain.EventArticleCreated, *m)
+	return
+}
+
+// StoreBatch will store every given article, the titles are checked for conflict
+// against the stored articles and each other before anything is persisted.
+// The articles are stored in one transaction, so a failure leaves none of them stored.
+func (a *Service) StoreBatch(ctx context.Context, list []*domain.Article) (err error) {
+	ctx, span := tracer.Start(ctx, "Service.StoreBatch")
+	defer func() { endSpan(span, err) }()
+
+	titles := make(map[string]struct{}, len(list))
+	for _, m := range list {
+		title := normalizeTitle(m.Title)
+		if _, ok := titles[title]; ok {
+			return domain.ErrConflict
+		}
+		titles[title] = struct{}{}
+
+		if a.titleExists(ctx, m.Title) {
+			return domain.ErrConflict
+		}
+	}
+
+	err = a.articleRepo.WithinTransaction(ctx, func(ctx context.Context) error {
+		// the slugs already given to the batch are reserved, the transaction may not show the stored ones
+		slugs := make(map[string]struct{}, len(list))
+		for _, m := range list {
+			m.ID = 0
+			if m.Status == "" {
+				m.Status = domain.StatusDraft
+			}
+			m.Tags = normalizeTags(m.Tags)
+			m.Content = a.sanitizer.Sanitize(m.Content)
+			slug, err := a.uniqueSlug(ctx, m.Title, slugs)
+			if err != nil {
+				return err
+			}
+			m.Slug = slug
+			slugs[slug] = struct{}{}
+			if err := a.articleRepo.Store(ctx, m); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	for _, m := range list {
+		a.notify(ctx, domain.EventArticleCreated, *m)
+	}
 	return
 }
 
 func (a *Service) Delete(ctx context.Context, id int64) (err error) {
+	ctx, span := tracer.Start(ctx, "Service.Delete")
+	defer func() { endSpan(span, err) }()
+
 	existedArticle, err := a.articleRepo.GetByID(ctx, id)
 	if err != nil {
 		return
 	}
-	if existedArticle == (domain.Article{}) {
+	if reflect.DeepEqual(existedArticle, domain.Article{}) {
 		return domain.ErrNotFound
 	}
-	return a.articleRepo.Delete(ctx, id)
+	if err = a.articleRepo.Delete(ctx, id); err != nil {
+		return
+	}
+	a.notify(ctx, domain.EventArticleDeleted, existedArticle)
+	return
+}
+
+// Restore will bring back the soft deleted article of the given id,
+// an article which is still active is reported as a conflict
+func (a *Service) Restore(ctx context.Context, id int64) (err error) {
+	ctx, span := tracer.Start(ctx, "Service.Restore")
+	defer func() { endSpan(span, err) }()
+
+	_, err = a.articleRepo.GetByID(ctx, id)
+	if err == nil {
+		return domain.ErrConflict
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return
+	}
+	return a.articleRepo.Restore(ctx, id)
+}
+
+// Publish will make the draft article of the given id public,
+// an article which is already published is reported as a conflict
+func (a *Service) Publish(ctx context.Context, id int64) (err error) {
+	ctx, span := tracer.Start(ctx, "Service.Publish")
+	defer func() { endSpan(span, err) }()
+
+	return a.transition(ctx, id, domain.StatusDraft, domain.StatusPublished)
+}
+
+// Unpublish will take the published article of the given id back to draft,
+// an article which is still a draft is reported as a conflict
+func (a *Service) Unpublish(ctx context.Context, id int64) (err error) {
+	ctx, span := tracer.Start(ctx, "Service.Unpublish")
+	defer func() { endSpan(span, err) }()
+
+	return a.transition(ctx, id, domain.StatusPublished, domain.StatusDraft)
+}
+
+// IncrementViews will count one more view of the article of the given id. The version is left as it is,
+// a view isn't a write the updates could conflict with, the REST ETag carries the count instead.
+func (a *Service) IncrementViews(ctx context.Context, id int64) (err error) {
+	ctx, span := tracer.Start(ctx, "Service.IncrementViews")
+	defer func() { endSpan(span, err) }()
+
+	return a.articleRepo.IncrementViews(ctx, id)
+}
+
+// PublishDue will publish the drafts whose publish_at has passed and report how many were published
+func (a *Service) PublishDue(ctx context.Context) (published int, err error) {
+	ctx, span := tracer.Start(ctx, "Service.PublishDue")
+	defer func() { endSpan(span, err) }()
+
+	ids, err := a.articleRepo.PublishDue(ctx, time.Now())
+	return len(ids), err
+}
+
+func (a *Service) transition(ctx context.Context, id int64, from, to domain.Status) error {
+	existedArticle, err := a.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existedArticle.Status != from {
+		return domain.ErrConflict
+	}
+	return a.articleRepo.SetStatus(ctx, id, to)
 }
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"apismrtbiz/article"
 	"apismrtbiz/article/mocks"
@@ -16,16 +18,18 @@ import (
 func TestFetchArticle(t *testing.T) {
 	mockArticleRepo := new(mocks.ArticleRepository)
 	mockArticle := domain.Article{
-		Title:   "Hello",
-		Content: "Content",
+		ID:        10,
+		Title:     "Hello",
+		Content:   "Content",
+		UpdatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 	}
 
 	mockListArtilce := make([]domain.Article, 0)
 	mockListArtilce = append(mockListArtilce, mockArticle)
 
 	t.Run("success", func(t *testing.T) {
-		mockArticleRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"),
-			mock.AnythingOfType("int64")).Return(mockListArtilce, "next-cursor", nil).Once()
+		mockArticleRepo.On("Fetch", mock.Anything, mock.AnythingOfType("domain.Cursor"),
+			mock.AnythingOfType("int64"), mock.Anything).Return(mockListArtilce, nil).Once()
 		mockAuthor := domain.Author{
 			ID:   1,
 			Name: "Iman Tumorang",
@@ -34,9 +38,9 @@ func TestFetchArticle(t *testing.T) {
 		mockAuthorrepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockAuthor, nil)
 		u := article.NewService(mockArticleRepo, mockAuthorrepo)
 		num := int64(1)
-		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
-		cursorExpected := "next-cursor"
+		cursor := domain.Cursor{ID: 12, Value: "2024-01-02T00:00:00Z"}.Encode()
+		list, nextCursor, _, err := u.Fetch(context.TODO(), cursor, num, domain.ArticleFilter{})
+		cursorExpected := domain.NewCursor(domain.SortByUpdatedAt, mockArticle).Encode()
 		assert.Equal(t, cursorExpected, nextCursor)
 		assert.NotEmpty(t, nextCursor)
 		assert.NoError(t, err)
@@ -47,14 +51,13 @@ func TestFetchArticle(t *testing.T) {
 	})
 
 	t.Run("error-failed", func(t *testing.T) {
-		mockArticleRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"),
-			mock.AnythingOfType("int64")).Return(nil, "", errors.New("Unexpexted Error")).Once()
+		mockArticleRepo.On("Fetch", mock.Anything, mock.AnythingOfType("domain.Cursor"),
+			mock.AnythingOfType("int64"), mock.Anything).Return(nil, errors.New("Unexpexted Error")).Once()
 
 		mockAuthorrepo := new(mocks.AuthorRepository)
 		u := article.NewService(mockArticleRepo, mockAuthorrepo)
 		num := int64(1)
-		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
+		list, nextCursor, _, err := u.Fetch(context.TODO(), "", num, domain.ArticleFilter{})
 
 		assert.Empty(t, nextCursor)
 		assert.Error(t, err)
@@ -64,6 +67,285 @@ func TestFetchArticle(t *testing.T) {
 	})
 }
 
+func TestFetchArticleCursor(t *testing.T) {
+	t.Run("round-trip", func(t *testing.T) {
+		last := domain.Article{ID: 7, Title: "b", Author: domain.Author{ID: 1}}
+		filter := domain.ArticleFilter{Sort: domain.ArticleSort{Field: domain.SortByTitle, Order: domain.SortAsc}}
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Fetch", mock.Anything, domain.Cursor{}, int64(1), filter).Return([]domain.Article{last}, nil).Once()
+		mockArticleRepo.On("Fetch", mock.Anything, domain.Cursor{ID: 7, Value: "b"}, int64(1), filter).Return([]domain.Article{}, nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Author{ID: 1}, nil)
+		u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+		_, nextCursor, _, err := u.Fetch(context.TODO(), "", 1, filter)
+		require.NoError(t, err)
+		decoded, err := domain.DecodeCursor(nextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Cursor{ID: 7, Value: "b"}, decoded)
+
+		list, nextCursor, _, err := u.Fetch(context.TODO(), nextCursor, 1, filter)
+		assert.NoError(t, err)
+		assert.Empty(t, list)
+		assert.Empty(t, nextCursor)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("garbage", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		for _, cursor := range []string{"not a cursor", "MjAyNC0wMS0wMVQwMDowMDowMFo", domain.Cursor{Value: "b"}.Encode()} {
+			_, _, _, err := u.Fetch(context.TODO(), cursor, 1, domain.ArticleFilter{})
+			assert.ErrorIs(t, err, domain.ErrBadParamInput, cursor)
+		}
+		mockArticleRepo.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFetchArticleBackward(t *testing.T) {
+	// the dataset, most recently updated first: 1, 2, 3, 4
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dataset := make([]domain.Article, 0, 4)
+	for id := int64(1); id <= 4; id++ {
+		dataset = append(dataset, domain.Article{ID: id, Author: domain.Author{ID: 1}, UpdatedAt: base.Add(-time.Duration(id) * time.Hour)})
+	}
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockAuthorrepo := new(mocks.AuthorRepository)
+	mockAuthorrepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Author{ID: 1}, nil)
+	u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+	// forward from the end of the first page lists 3, 4
+	mockArticleRepo.On("Fetch", mock.Anything, domain.NewCursor(domain.SortByUpdatedAt, dataset[1]), int64(2), domain.ArticleFilter{}).
+		Return([]domain.Article{dataset[2], dataset[3]}, nil).Once()
+	list, nextCursor, prevCursor, err := u.Fetch(context.TODO(), domain.NewCursor(domain.SortByUpdatedAt, dataset[1]).Encode(), 2, domain.ArticleFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 4}, []int64{list[0].ID, list[1].ID})
+	assert.Equal(t, domain.NewCursor(domain.SortByUpdatedAt, dataset[3]).Encode(), nextCursor)
+	assert.Equal(t, domain.NewCursor(domain.SortByUpdatedAt, dataset[2]).Encode(), prevCursor)
+
+	// backward from the start of that page, the repository lists 2, 1 closest to the cursor first
+	backward := domain.ArticleFilter{Backward: true}
+	mockArticleRepo.On("Fetch", mock.Anything, domain.NewCursor(domain.SortByUpdatedAt, dataset[2]), int64(2), backward).
+		Return([]domain.Article{dataset[1], dataset[0]}, nil).Once()
+	list, nextCursor, prevCursor, err = u.Fetch(context.TODO(), prevCursor, 2, backward)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, []int64{list[0].ID, list[1].ID})
+	assert.Equal(t, domain.NewCursor(domain.SortByUpdatedAt, dataset[1]).Encode(), nextCursor)
+	assert.Equal(t, domain.NewCursor(domain.SortByUpdatedAt, dataset[0]).Encode(), prevCursor)
+	mockArticleRepo.AssertExpectations(t)
+}
+
+func TestSearchIndex(t *testing.T) {
+	mockAuthor := domain.Author{ID: 2, Name: "Iman Tumorang"}
+	score := 1.5
+
+	t.Run("ranked", func(t *testing.T) {
+		mockSearchRepo := new(mocks.SearchRepository)
+		mockSearchRepo.On("Search", mock.Anything, "generics", int64(1), domain.Cursor{}, domain.StatusPublished).
+			Return([]domain.Article{{ID: 3, Author: domain.Author{ID: 2}, SearchScore: &score}}, nil).Once()
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(2)).Return(mockAuthor, nil)
+		u := article.NewService(mockArticleRepo, mockAuthorrepo, article.WithSearchRepository(mockSearchRepo))
+
+		list, nextCursor, err := u.Search(context.TODO(), "generics", 1, "", domain.StatusPublished)
+
+		assert.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, mockAuthor, list[0].Author)
+		assert.Equal(t, domain.Cursor{ID: 3, Value: "1.5"}.Encode(), nextCursor)
+		mockArticleRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockSearchRepo.AssertExpectations(t)
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		mockSearchRepo := new(mocks.SearchRepository)
+		mockSearchRepo.On("Search", mock.Anything, "generics", int64(5), domain.Cursor{}, domain.StatusPublished).Return(nil, errors.New("connection refused")).Once()
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Search", mock.Anything, "generics", int64(5), domain.Cursor{}, domain.StatusPublished).
+			Return([]domain.Article{{ID: 3, Author: domain.Author{ID: 2}}}, nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(2)).Return(mockAuthor, nil)
+		u := article.NewService(mockArticleRepo, mockAuthorrepo, article.WithSearchRepository(mockSearchRepo))
+
+		list, _, err := u.Search(context.TODO(), "generics", 5, "", domain.StatusPublished)
+
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("bad-cursor", func(t *testing.T) {
+		cursor := domain.Cursor{ID: 3, Value: "2024-01-01T00:00:00Z"}
+		mockSearchRepo := new(mocks.SearchRepository)
+		mockSearchRepo.On("Search", mock.Anything, "generics", int64(5), cursor, domain.StatusPublished).Return(nil, domain.ErrBadParamInput).Once()
+		mockArticleRepo := new(mocks.ArticleRepository)
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithSearchRepository(mockSearchRepo))
+
+		_, _, err := u.Search(context.TODO(), "generics", 5, cursor.Encode(), domain.StatusPublished)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		mockArticleRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetRelated(t *testing.T) {
+	mockArticle := domain.Article{ID: 1, Author: domain.Author{ID: 2}, Tags: []string{"golang"}}
+	mockAuthor := domain.Author{ID: 2, Name: "Iman Tumorang"}
+
+	t.Run("related", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(mockArticle, nil).Once()
+		mockArticleRepo.On("FetchRelated", mock.Anything, mockArticle, int64(5)).
+			Return([]domain.Article{{ID: 3, Author: domain.Author{ID: 2}}}, nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(2)).Return(mockAuthor, nil)
+		u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+		list, err := u.GetRelated(context.TODO(), 1, 5)
+
+		assert.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, mockAuthor, list[0].Author)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("nothing-related", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(mockArticle, nil).Once()
+		mockArticleRepo.On("FetchRelated", mock.Anything, mockArticle, int64(5)).Return([]domain.Article{}, nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		list, err := u.GetRelated(context.TODO(), 1, 5)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, list)
+		assert.Empty(t, list)
+	})
+
+	t.Run("not-exist", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{}, domain.ErrNotFound).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		_, err := u.GetRelated(context.TODO(), 1, 5)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockArticleRepo.AssertNotCalled(t, "FetchRelated", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFuzzySearch(t *testing.T) {
+	mockAuthor := domain.Author{ID: 2, Name: "Iman Tumorang"}
+	candidates := []domain.Article{
+		{ID: 1, Title: "Cooking pasta", Content: "Boil the water", Author: domain.Author{ID: 2}},
+		{ID: 2, Title: "Hello world", Content: "The first program", Author: domain.Author{ID: 2}},
+		{ID: 3, Title: "Help wanted", Content: "Looking for a hand", Author: domain.Author{ID: 2}},
+	}
+
+	t.Run("typo", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Fetch", mock.Anything, domain.Cursor{}, int64(100), domain.ArticleFilter{Status: domain.StatusPublished}).Return(candidates, nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(2)).Return(mockAuthor, nil)
+		u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+		list, err := u.FuzzySearch(context.TODO(), "Helo", 10)
+
+		assert.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, int64(2), list[0].ID)
+		require.NotNil(t, list[0].SearchScore)
+		assert.Equal(t, 0.8, *list[0].SearchScore)
+		assert.Equal(t, int64(3), list[1].ID)
+		assert.Less(t, *list[1].SearchScore, *list[0].SearchScore)
+		assert.Equal(t, mockAuthor, list[0].Author)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("no-match", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Fetch", mock.Anything, domain.Cursor{}, int64(100), domain.ArticleFilter{Status: domain.StatusPublished}).Return(candidates, nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		list, err := u.FuzzySearch(context.TODO(), "kubernetes", 10)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, list)
+		assert.Empty(t, list)
+	})
+
+	t.Run("title-only", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Fetch", mock.Anything, domain.Cursor{}, int64(100), domain.ArticleFilter{Status: domain.StatusPublished}).Return(candidates, nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		list, err := u.FuzzySearch(context.TODO(), "program", 10)
+
+		assert.NoError(t, err)
+		assert.Empty(t, list, "the content isn't ranked")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("Unexpected")).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		_, err := u.FuzzySearch(context.TODO(), "Helo", 10)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestOffsetFetchArticle(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockListArticle := []domain.Article{{Title: "Hello", Content: "Content", Author: domain.Author{ID: 1}}}
+
+	t.Run("success", func(t *testing.T) {
+		mockArticleRepo.On("OffsetFetch", mock.Anything, int64(20), int64(10), domain.StatusPublished).Return(mockListArticle, nil).Once()
+		mockArticleRepo.On("Count", mock.Anything, domain.StatusPublished).Return(int64(21), nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Author{ID: 1, Name: "Iman Tumorang"}, nil)
+		u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+		list, total, err := u.OffsetFetch(context.TODO(), 3, 10, domain.StatusPublished)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(21), total)
+		assert.Len(t, list, 1)
+		assert.Equal(t, "Iman Tumorang", list[0].Author.Name)
+		mockArticleRepo.AssertExpectations(t)
+		mockAuthorrepo.AssertExpectations(t)
+	})
+
+	t.Run("error-failed", func(t *testing.T) {
+		mockArticleRepo.On("OffsetFetch", mock.Anything, int64(0), int64(10), domain.StatusPublished).Return(nil, errors.New("Unexpected Error")).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+		list, total, err := u.OffsetFetch(context.TODO(), 1, 10, domain.StatusPublished)
+
+		assert.Error(t, err)
+		assert.Zero(t, total)
+		assert.Len(t, list, 0)
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
+
+func TestCount(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockArticleRepo.On("Count", mock.Anything, domain.StatusPublished).Return(int64(3), nil).Once()
+
+	u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+	total, err := u.Count(context.TODO(), domain.StatusPublished)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	mockArticleRepo.AssertExpectations(t)
+}
+
 func TestGetByID(t *testing.T) {
 	mockArticleRepo := new(mocks.ArticleRepository)
 	mockArticle := domain.Article{
@@ -105,6 +387,78 @@ func TestGetByID(t *testing.T) {
 	})
 }
 
+func TestGetBySlug(t *testing.T) {
+	mockAuthor := domain.Author{ID: 1, Name: "Iman Tumorang"}
+
+	t.Run("success", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello").Return(domain.Article{ID: 1, Slug: "hello", Author: domain.Author{ID: 1}}, nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(1)).Return(mockAuthor, nil).Once()
+		u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+		a, err := u.GetBySlug(context.TODO(), "hello")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), a.ID)
+		assert.Equal(t, mockAuthor, a.Author)
+		mockArticleRepo.AssertExpectations(t)
+		mockAuthorrepo.AssertExpectations(t)
+	})
+	t.Run("soft-deleted", func(t *testing.T) {
+		deletedAt := time.Now()
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello").Return(domain.Article{ID: 1, Slug: "hello", DeletedAt: &deletedAt}, nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		_, err := u.GetBySlug(context.TODO(), "hello")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
+
+func TestGetByIDs(t *testing.T) {
+	mockAuthor := domain.Author{ID: 1, Name: "Iman Tumorang"}
+
+	t.Run("preserve-order-and-skip-duplicates", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByIDs", mock.Anything, []int64{3, 1, 2}).Return([]domain.Article{
+			{ID: 1, Author: domain.Author{ID: 1}},
+			{ID: 2, Author: domain.Author{ID: 1}},
+			{ID: 3, Author: domain.Author{ID: 1}},
+		}, nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(1)).Return(mockAuthor, nil)
+		u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+		list, err := u.GetByIDs(context.TODO(), []int64{3, 1, 3, 2})
+
+		assert.NoError(t, err)
+		require.Len(t, list, 3)
+		assert.Equal(t, []int64{3, 1, 2}, []int64{list[0].ID, list[1].ID, list[2].ID})
+		assert.Equal(t, mockAuthor, list[0].Author)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown-ids", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByIDs", mock.Anything, []int64{1, 99}).Return([]domain.Article{
+			{ID: 1, Author: domain.Author{ID: 1}},
+		}, nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(1)).Return(mockAuthor, nil)
+		u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+		list, err := u.GetByIDs(context.TODO(), []int64{1, 99})
+
+		assert.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, int64(1), list[0].ID)
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
+
 func TestStore(t *testing.T) {
 	mockArticleRepo := new(mocks.ArticleRepository)
 	mockArticle := domain.Article{
@@ -116,6 +470,7 @@ func TestStore(t *testing.T) {
 		tempMockArticle := mockArticle
 		tempMockArticle.ID = 0
 		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, mock.Anything).Return(domain.Article{}, domain.ErrNotFound)
 		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
 
 		mockAuthorrepo := new(mocks.AuthorRepository)
@@ -125,6 +480,109 @@ func TestStore(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.Equal(t, mockArticle.Title, tempMockArticle.Title)
+		assert.Equal(t, domain.StatusDraft, tempMockArticle.Status)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("normalized-tags", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Tagged").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, mock.Anything).Return(domain.Article{}, domain.ErrNotFound)
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		ar := &domain.Article{Title: "Tagged", Content: "Content", Tags: []string{" Web", "golang", "web"}}
+		err := u.Store(context.TODO(), ar)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"golang", "web"}, ar.Tags)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("sanitized-content", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Unsafe").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, mock.Anything).Return(domain.Article{}, domain.ErrNotFound)
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		ar := &domain.Article{
+			Title:   "Unsafe",
+			Content: `<p onclick="steal()">Hello <b>world</b></p><script>alert(1)</script><img src="x.png" onerror="steal()">`,
+		}
+		err := u.Store(context.TODO(), ar)
+
+		assert.NoError(t, err)
+		assert.Equal(t, `<p>Hello <b>world</b></p><img src="x.png">`, ar.Content)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("strict-policy", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Unsafe").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, mock.Anything).Return(domain.Article{}, domain.ErrNotFound)
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		sanitizer, err := article.NewSanitizer(article.PolicyStrict)
+		require.NoError(t, err)
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithSanitizer(sanitizer))
+
+		ar := &domain.Article{Title: "Unsafe", Content: `<p onclick="steal()">Hello <b>world</b></p><script>alert(1)</script>`}
+		err = u.Store(context.TODO(), ar)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello world", ar.Content)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("slug", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello-world-go-1-23-is-out").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		ar := &domain.Article{Title: "  Hello, World!  Go 1.23 is out... ", Content: "Content"}
+		err := u.Store(context.TODO(), ar)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "hello-world-go-1-23-is-out", ar.Slug)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("slug-collision", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "cafe-au-lait").Return(domain.Article{ID: 1, Title: "Cafe au lait"}, nil).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "cafe-au-lait-2").Return(domain.Article{ID: 2, Title: "Café au lait"}, nil).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "cafe-au-lait-3").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		ar := &domain.Article{Title: "Café au lait!", Content: "Content"}
+		err := u.Store(context.TODO(), ar)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "cafe-au-lait-3", ar.Slug)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("case-only-difference", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "hello").Return(domain.Article{ID: 1, Title: "Hello", Author: domain.Author{ID: 1}}, nil).Once()
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		mockAuthorrepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Author{ID: 1}, nil)
+		u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+		err := u.Store(context.TODO(), &domain.Article{Title: "  hello ", Content: "Content"})
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		mockArticleRepo.AssertExpectations(t)
+		mockArticleRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+	t.Run("new-title", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Brand New").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, mock.Anything).Return(domain.Article{}, domain.ErrNotFound)
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		err := u.Store(context.TODO(), &domain.Article{Title: "Brand New", Content: "Content"})
+
+		assert.NoError(t, err)
 		mockArticleRepo.AssertExpectations(t)
 	})
 	t.Run("existing-title", func(t *testing.T) {
@@ -147,6 +605,152 @@ func TestStore(t *testing.T) {
 	})
 }
 
+// runTransaction stands for the transaction of the mocked repository, it runs the unit of work as given
+func runTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestNewSanitizer(t *testing.T) {
+	for _, policy := range []string{"", article.PolicyBasic, article.PolicyStrict} {
+		_, err := article.NewSanitizer(policy)
+		assert.NoError(t, err, policy)
+	}
+	_, err := article.NewSanitizer("lenient")
+	assert.Error(t, err)
+}
+
+func TestRenderHTML(t *testing.T) {
+	u := article.NewService(new(mocks.ArticleRepository), new(mocks.AuthorRepository))
+
+	res, err := u.RenderHTML("# Hello\n\n*world* and [a link](https://example.com)")
+	require.NoError(t, err)
+	assert.Equal(t, "<h1>Hello</h1>\n<p><em>world</em> and <a href=\"https://example.com\" rel=\"nofollow\">a link</a></p>\n", res)
+
+	res, err = u.RenderHTML("<script>alert(1)</script>\n\n[click](javascript:alert(1))")
+	require.NoError(t, err)
+	assert.NotContains(t, res, "<script")
+	assert.NotContains(t, res, "javascript:")
+}
+
+func TestNotify(t *testing.T) {
+	isEvent := func(typ domain.EventType, id int64) interface{} {
+		return mock.MatchedBy(func(ev domain.Event) bool {
+			return ev.Type == typ && ev.ArticleID == id && !ev.Timestamp.IsZero()
+		})
+	}
+
+	t.Run("store", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, mock.Anything).Return(domain.Article{}, domain.ErrNotFound)
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).
+			Run(func(args mock.Arguments) { args.Get(1).(*domain.Article).ID = 7 }).Return(nil).Once()
+		mockNotifier := new(mocks.Notifier)
+		mockNotifier.On("Notify", mock.Anything, isEvent(domain.EventArticleCreated, 7)).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithNotifier(mockNotifier))
+
+		err := u.Store(context.TODO(), &domain.Article{Title: "Hello", Content: "Content"})
+		assert.NoError(t, err)
+		mockNotifier.AssertExpectations(t)
+	})
+	t.Run("update", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("WithinTransaction", mock.Anything, mock.Anything).Return(runTransaction).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7, Version: 1}, nil).Once()
+		mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		mockArticleRepo.On("StoreRevision", mock.Anything, mock.AnythingOfType("*domain.Revision")).Return(nil).Once()
+		mockNotifier := new(mocks.Notifier)
+		mockNotifier.On("Notify", mock.Anything, isEvent(domain.EventArticleUpdated, 7)).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithNotifier(mockNotifier))
+
+		err := u.Update(context.TODO(), &domain.Article{ID: 7, Title: "Hello", Content: "Content", Version: 1})
+		assert.NoError(t, err)
+		mockNotifier.AssertExpectations(t)
+	})
+	t.Run("delete", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7}, nil).Once()
+		mockArticleRepo.On("Delete", mock.Anything, int64(7)).Return(nil).Once()
+		mockNotifier := new(mocks.Notifier)
+		mockNotifier.On("Notify", mock.Anything, isEvent(domain.EventArticleDeleted, 7)).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithNotifier(mockNotifier))
+
+		err := u.Delete(context.TODO(), 7)
+		assert.NoError(t, err)
+		mockNotifier.AssertExpectations(t)
+	})
+	t.Run("failed-write", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7}, nil).Once()
+		mockArticleRepo.On("Delete", mock.Anything, int64(7)).Return(errors.New("unexpected error")).Once()
+		mockNotifier := new(mocks.Notifier)
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithNotifier(mockNotifier))
+
+		err := u.Delete(context.TODO(), 7)
+		assert.Error(t, err)
+		mockNotifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+	})
+}
+
+func TestStoreBatch(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(domain.Article{}, domain.ErrNotFound).Twice()
+		mockArticleRepo.On("WithinTransaction", mock.Anything, mock.Anything).Return(runTransaction).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, mock.Anything).Return(domain.Article{}, domain.ErrNotFound)
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Twice()
+
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+		err := u.StoreBatch(context.TODO(), []*domain.Article{
+			{Title: "One", Content: "Content"},
+			{Title: "Two", Content: "Content"},
+		})
+
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("store-fails", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(domain.Article{}, domain.ErrNotFound).Twice()
+		mockArticleRepo.On("WithinTransaction", mock.Anything, mock.Anything).Return(runTransaction).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, mock.Anything).Return(domain.Article{}, domain.ErrNotFound)
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, mock.Anything).Return(domain.Article{}, domain.ErrNotFound)
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(errors.New("Unexpected Error")).Once()
+
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+		err := u.StoreBatch(context.TODO(), []*domain.Article{
+			{Title: "One", Content: "Content"},
+			{Title: "Two", Content: "Content"},
+		})
+
+		// the error of the second store is handed to the transaction, which rolls back the first one
+		assert.EqualError(t, err, "Unexpected Error")
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("duplicate-in-batch", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "One").Return(domain.Article{}, domain.ErrNotFound).Once()
+
+		mockAuthorrepo := new(mocks.AuthorRepository)
+		u := article.NewService(mockArticleRepo, mockAuthorrepo)
+
+		err := u.StoreBatch(context.TODO(), []*domain.Article{
+			{Title: "One", Content: "Content"},
+			{Title: "One", Content: "Content"},
+		})
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		mockArticleRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
 func TestDelete(t *testing.T) {
 	mockArticleRepo := new(mocks.ArticleRepository)
 	mockArticle := domain.Article{
@@ -194,16 +798,155 @@ func TestDelete(t *testing.T) {
 	})
 }
 
+func TestRestore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Restore", mock.Anything, int64(7)).Return(nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		err := u.Restore(context.TODO(), 7)
+
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("already-active", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7}, nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		err := u.Restore(context.TODO(), 7)
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		mockArticleRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+	})
+
+	t.Run("not-exist", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Restore", mock.Anything, int64(7)).Return(domain.ErrNotFound).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		err := u.Restore(context.TODO(), 7)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
+
+func TestPublish(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7, Status: domain.StatusDraft}, nil).Once()
+		mockArticleRepo.On("SetStatus", mock.Anything, int64(7), domain.StatusPublished).Return(nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		err := u.Publish(context.TODO(), 7)
+
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("already-published", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7, Status: domain.StatusPublished}, nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		err := u.Publish(context.TODO(), 7)
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		mockArticleRepo.AssertNotCalled(t, "SetStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("not-exist", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{}, domain.ErrNotFound).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		err := u.Publish(context.TODO(), 7)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockArticleRepo.AssertNotCalled(t, "SetStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUnpublish(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7, Status: domain.StatusPublished}, nil).Once()
+		mockArticleRepo.On("SetStatus", mock.Anything, int64(7), domain.StatusDraft).Return(nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		err := u.Unpublish(context.TODO(), 7)
+
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("already-draft", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7, Status: domain.StatusDraft}, nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		err := u.Unpublish(context.TODO(), 7)
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		mockArticleRepo.AssertNotCalled(t, "SetStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestIncrementViews(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("IncrementViews", mock.Anything, int64(7)).Return(nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		err := u.IncrementViews(context.TODO(), 7)
+
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("not-exist", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("IncrementViews", mock.Anything, int64(7)).Return(domain.ErrNotFound).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		err := u.IncrementViews(context.TODO(), 7)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestPublishDue(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockArticleRepo.On("PublishDue", mock.Anything, mock.AnythingOfType("time.Time")).Return([]int64{3, 4}, nil).Once()
+	u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+	published, err := u.PublishDue(context.TODO())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+	mockArticleRepo.AssertExpectations(t)
+}
+
 func TestUpdate(t *testing.T) {
 	mockArticleRepo := new(mocks.ArticleRepository)
 	mockArticle := domain.Article{
 		Title:   "Hello",
 		Content: "Content",
 		ID:      23,
+		Version: 1,
 	}
 
 	t.Run("success", func(t *testing.T) {
+		mockArticleRepo.On("WithinTransaction", mock.Anything, mock.Anything).Return(runTransaction).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).
+			Return(domain.Article{ID: 23, Title: "Old", Content: "Old content", Version: 1, Tags: []string{"go"}}, nil).Once()
 		mockArticleRepo.On("Update", mock.Anything, &mockArticle).Once().Return(nil)
+		mockArticleRepo.On("StoreRevision", mock.Anything, &domain.Revision{ArticleID: 23, Version: 1, Title: "Old", Content: "Old content", Tags: []string{"go"}}).
+			Return(nil).Once()
 
 		mockAuthorrepo := new(mocks.AuthorRepository)
 		u := article.NewService(mockArticleRepo, mockAuthorrepo)
@@ -212,4 +955,148 @@ func TestUpdate(t *testing.T) {
 		assert.NoError(t, err)
 		mockArticleRepo.AssertExpectations(t)
 	})
+	t.Run("sanitized-content", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("WithinTransaction", mock.Anything, mock.Anything).Return(runTransaction).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(domain.Article{ID: 23, Version: 1}, nil).Once()
+		mockArticleRepo.On("Update", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool {
+			return ar.Content == `<a href="https://example.com" rel="nofollow">link</a>`
+		})).Return(nil).Once()
+		mockArticleRepo.On("StoreRevision", mock.Anything, mock.AnythingOfType("*domain.Revision")).Return(nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		err := u.Update(context.TODO(), &domain.Article{
+			ID: 23, Title: "Hello", Version: 1,
+			Content: `<a href="https://example.com" onmouseover="steal()">link</a><script src="https://evil.example"></script>`,
+		})
+		assert.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("stale-version", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("WithinTransaction", mock.Anything, mock.Anything).Return(runTransaction).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(domain.Article{ID: 23, Version: 2}, nil).Once()
+		mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(domain.ErrConflict).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		err := u.Update(context.TODO(), &domain.Article{ID: 23, Title: "Hello", Content: "Content", Version: 1})
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		mockArticleRepo.AssertExpectations(t)
+		mockArticleRepo.AssertNotCalled(t, "StoreRevision", mock.Anything, mock.Anything)
+	})
+	t.Run("not-exist", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("WithinTransaction", mock.Anything, mock.Anything).Return(runTransaction).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(domain.Article{}, domain.ErrNotFound).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		err := u.Update(context.TODO(), &domain.Article{ID: 23, Title: "Hello", Content: "Content", Version: 1})
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockArticleRepo.AssertExpectations(t)
+		mockArticleRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpsert(t *testing.T) {
+	t.Run("existing", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("WithinTransaction", mock.Anything, mock.Anything).Return(runTransaction).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(domain.Article{ID: 23, Slug: "old", Version: 1}, nil).Once()
+		mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		mockArticleRepo.On("StoreRevision", mock.Anything, mock.AnythingOfType("*domain.Revision")).Return(nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		ar := &domain.Article{ID: 23, Title: "Hello", Content: "Content", Version: 1}
+		created, err := u.Upsert(context.TODO(), ar)
+
+		assert.NoError(t, err)
+		assert.False(t, created)
+		assert.Equal(t, "old", ar.Slug)
+		mockArticleRepo.AssertExpectations(t)
+		mockArticleRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+	t.Run("missing", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("WithinTransaction", mock.Anything, mock.Anything).Return(runTransaction).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("GetBySlug", mock.Anything, "hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool {
+			return ar.ID == 23
+		})).Return(nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		ar := &domain.Article{ID: 23, Title: "Hello", Content: "Content"}
+		created, err := u.Upsert(context.TODO(), ar)
+
+		assert.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, domain.StatusDraft, ar.Status)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("stale-version", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("WithinTransaction", mock.Anything, mock.Anything).Return(runTransaction).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(domain.Article{ID: 23, Version: 2}, nil).Once()
+		mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(domain.ErrConflict).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		created, err := u.Upsert(context.TODO(), &domain.Article{ID: 23, Title: "Hello", Content: "Content"})
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.False(t, created)
+		mockArticleRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
+func TestListRevisions(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		revisions := []domain.Revision{{ArticleID: 23, Version: 2}, {ArticleID: 23, Version: 1}}
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(domain.Article{ID: 23, Version: 3}, nil).Once()
+		mockArticleRepo.On("ListRevisions", mock.Anything, int64(23)).Return(revisions, nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		list, err := u.ListRevisions(context.TODO(), 23)
+		require.NoError(t, err)
+		assert.Equal(t, revisions, list)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("not-exist", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(domain.Article{}, domain.ErrNotFound).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		_, err := u.ListRevisions(context.TODO(), 23)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
+
+func TestDiffRevisions(t *testing.T) {
+	t.Run("single-changed-field", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetRevision", mock.Anything, int64(23), int64(3)).
+			Return(domain.Revision{ArticleID: 23, Version: 3, Title: "Old", Content: "Content", Tags: []string{"go"}}, nil).Once()
+		mockArticleRepo.On("GetRevision", mock.Anything, int64(23), int64(5)).
+			Return(domain.Revision{ArticleID: 23, Version: 5, Title: "New", Content: "Content", Tags: []string{"go"}}, nil).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		diff, err := u.DiffRevisions(context.TODO(), 23, 3, 5)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), diff.From)
+		assert.Equal(t, int64(5), diff.To)
+		assert.Equal(t, []domain.FieldChange{{Field: "title", Op: domain.ChangeChanged, From: "Old", To: "New"}}, diff.Changes)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("not-exist", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetRevision", mock.Anything, int64(23), int64(3)).Return(domain.Revision{ArticleID: 23, Version: 3}, nil).Once()
+		mockArticleRepo.On("GetRevision", mock.Anything, int64(23), int64(9)).Return(domain.Revision{}, domain.ErrNotFound).Once()
+		u := article.NewService(mockArticleRepo, new(mocks.AuthorRepository))
+
+		_, err := u.DiffRevisions(context.TODO(), 23, 3, 9)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockArticleRepo.AssertExpectations(t)
+	})
 }
@@ -0,0 +1,77 @@
+package category
+
+import (
+	"context"
+
+	"apismrtbiz/domain"
+)
+
+// CategoryRepository represent the category's repository contract
+//
+//go:generate mockery --name CategoryRepository
+type CategoryRepository interface {
+	Fetch(ctx context.Context) ([]domain.Category, error)
+	GetByID(ctx context.Context, id int64) (domain.Category, error)
+	Store(ctx context.Context, c *domain.Category) error
+	Update(ctx context.Context, c *domain.Category) error
+	Delete(ctx context.Context, id int64) error
+	// HasArticles will report whether any article, a soft deleted one included, is filed under the category
+	HasArticles(ctx context.Context, id int64) (bool, error)
+}
+
+type Service struct {
+	categoryRepo CategoryRepository
+}
+
+// NewService will create a new category service object
+func NewService(c CategoryRepository) *Service {
+	return &Service{
+		categoryRepo: c,
+	}
+}
+
+// Fetch will list every category by name, there are few enough of them to skip the paging
+func (s *Service) Fetch(ctx context.Context) (res []domain.Category, err error) {
+	ctx, span := tracer.Start(ctx, "Service.Fetch")
+	defer func() { endSpan(span, err) }()
+
+	return s.categoryRepo.Fetch(ctx)
+}
+
+func (s *Service) GetByID(ctx context.Context, id int64) (res domain.Category, err error) {
+	ctx, span := tracer.Start(ctx, "Service.GetByID")
+	defer func() { endSpan(span, err) }()
+
+	return s.categoryRepo.GetByID(ctx, id)
+}
+
+func (s *Service) Store(ctx context.Context, c *domain.Category) (err error) {
+	ctx, span := tracer.Start(ctx, "Service.Store")
+	defer func() { endSpan(span, err) }()
+
+	return s.categoryRepo.Store(ctx, c)
+}
+
+// Update will rename the category, a category which doesn't exist is reported as ErrNotFound
+func (s *Service) Update(ctx context.Context, c *domain.Category) (err error) {
+	ctx, span := tracer.Start(ctx, "Service.Update")
+	defer func() { endSpan(span, err) }()
+
+	return s.categoryRepo.Update(ctx, c)
+}
+
+// Delete will remove the category, it is refused with ErrInUse while articles are still filed under it
+// so that none of them points at a missing category
+func (s *Service) Delete(ctx context.Context, id int64) (err error) {
+	ctx, span := tracer.Start(ctx, "Service.Delete")
+	defer func() { endSpan(span, err) }()
+
+	inUse, err := s.categoryRepo.HasArticles(ctx, id)
+	if err != nil {
+		return
+	}
+	if inUse {
+		return domain.ErrInUse
+	}
+	return s.categoryRepo.Delete(ctx, id)
+}
@@ -0,0 +1,39 @@
+package category_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/category"
+	"apismrtbiz/category/mocks"
+	"apismrtbiz/domain"
+)
+
+func TestDelete(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("HasArticles", mock.Anything, int64(4)).Return(false, nil).Once()
+		mockCategoryRepo.On("Delete", mock.Anything, int64(4)).Return(nil).Once()
+		s := category.NewService(mockCategoryRepo)
+
+		err := s.Delete(context.TODO(), 4)
+
+		require.NoError(t, err)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("has-articles", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.CategoryRepository)
+		mockCategoryRepo.On("HasArticles", mock.Anything, int64(4)).Return(true, nil).Once()
+		s := category.NewService(mockCategoryRepo)
+
+		err := s.Delete(context.TODO(), 4)
+
+		assert.ErrorIs(t, err, domain.ErrInUse)
+		mockCategoryRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
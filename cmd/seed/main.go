@@ -0,0 +1,88 @@
+// Command seed fills the database of the settings of the app with sample articles for development,
+// running it again only adds the articles missing from the database.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	_ "modernc.org/sqlite"
+
+	mysqlMigrations "apismrtbiz/database/migrations"
+	postgresMigrations "apismrtbiz/database/postgres/migrations"
+	sqliteMigrations "apismrtbiz/database/sqlite/migrations"
+
+	"apismrtbiz/internal/config"
+	"apismrtbiz/internal/database"
+	mongoRepo "apismrtbiz/internal/repository/mongo"
+	mysqlRepo "apismrtbiz/internal/repository/mysql"
+	postgresRepo "apismrtbiz/internal/repository/postgres"
+	sqliteRepo "apismrtbiz/internal/repository/sqlite"
+	"apismrtbiz/internal/seed"
+)
+
+func main() {
+	num := flag.Int("n", 50, "number of sample articles")
+	flag.Parse()
+
+	// the variables may come from the environment alone
+	_ = godotenv.Load()
+
+	// the database is the one of the app, from the CONFIG_FILE YAML file and the variables
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	dbConfig := cfg.Database.Conn()
+	dsn, err := dbConfig.DSN()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var repo seed.Repository
+	if dbConfig.Driver == database.DriverMongo {
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(dsn))
+		if err != nil {
+			log.Fatal("failed to open connection to database ", err)
+		}
+		defer client.Disconnect(ctx)
+		repo = mongoRepo.NewArticleRepository(client.Database(dbConfig.Name))
+	} else {
+		dbConn, err := sql.Open(dbConfig.Driver, dsn)
+		if err != nil {
+			log.Fatal("failed to open connection to database ", err)
+		}
+		defer dbConn.Close()
+
+		// the sample articles need the schema of the running version
+		switch dbConfig.Driver {
+		case database.DriverPostgres:
+			err = database.Migrate(ctx, dbConn, dbConfig.Driver, postgresMigrations.FS)
+			repo = postgresRepo.NewArticleRepository(dbConn)
+		case database.DriverSQLite:
+			err = database.Migrate(ctx, dbConn, dbConfig.Driver, sqliteMigrations.FS)
+			repo = sqliteRepo.NewArticleRepository(dbConn)
+		default:
+			err = database.Migrate(ctx, dbConn, dbConfig.Driver, mysqlMigrations.FS)
+			repo = mysqlRepo.NewArticleRepository(dbConn)
+		}
+		if err != nil {
+			log.Fatal("failed to migrate the database ", err)
+		}
+	}
+
+	stored, err := seed.Run(ctx, repo, *num)
+	if err != nil {
+		log.Fatalf("stored %d sample articles before failing: %v", stored, err)
+	}
+	log.Printf("stored %d sample articles, %d were already there", stored, *num-stored)
+}
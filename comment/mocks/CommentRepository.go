@@ -0,0 +1,77 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "apismrtbiz/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CommentRepository is an autogenerated mock type for the CommentRepository type
+type CommentRepository struct {
+	mock.Mock
+}
+
+// Fetch provides a mock function with given fields: ctx, articleID, cursor, num
+func (_m *CommentRepository) Fetch(ctx context.Context, articleID int64, cursor domain.Cursor, num int64) ([]domain.Comment, error) {
+	ret := _m.Called(ctx, articleID, cursor, num)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fetch")
+	}
+
+	var r0 []domain.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.Cursor, int64) ([]domain.Comment, error)); ok {
+		return rf(ctx, articleID, cursor, num)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, domain.Cursor, int64) []domain.Comment); ok {
+		r0 = rf(ctx, articleID, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, domain.Cursor, int64) error); ok {
+		r1 = rf(ctx, articleID, cursor, num)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store provides a mock function with given fields: ctx, c
+func (_m *CommentRepository) Store(ctx context.Context, c *domain.Comment) error {
+	ret := _m.Called(ctx, c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Store")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Comment) error); ok {
+		r0 = rf(ctx, c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCommentRepository creates a new instance of CommentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCommentRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CommentRepository {
+	mock := &CommentRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,74 @@
+package comment
+
+import (
+	"context"
+
+	"apismrtbiz/domain"
+)
+
+// CommentRepository represent the comment's repository contract
+//
+//go:generate mockery --name CommentRepository
+type CommentRepository interface {
+	Fetch(ctx context.Context, articleID int64, cursor domain.Cursor, num int64) ([]domain.Comment, error)
+	Store(ctx context.Context, c *domain.Comment) error
+}
+
+// ArticleRepository represent the part of the article's repository contract the comments rely on
+//
+//go:generate mockery --name ArticleRepository
+type ArticleRepository interface {
+	GetByID(ctx context.Context, id int64) (domain.Article, error)
+}
+
+type Service struct {
+	commentRepo CommentRepository
+	articleRepo ArticleRepository
+}
+
+// NewService will create a new comment service object
+func NewService(c CommentRepository, a ArticleRepository) *Service {
+	return &Service{
+		commentRepo: c,
+		articleRepo: a,
+	}
+}
+
+// Fetch will list a page of the comments of the article next to the cursor, the oldest first.
+// The next cursor points after the last comment of the page, it is left empty on the last page.
+func (s *Service) Fetch(ctx context.Context, articleID int64, cursor string, num int64) (res []domain.Comment, nextCursor string, err error) {
+	ctx, span := tracer.Start(ctx, "Service.Fetch")
+	defer func() { endSpan(span, err) }()
+
+	var decoded domain.Cursor
+	if cursor != "" {
+		if decoded, err = domain.DecodeCursor(cursor); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if _, err = s.articleRepo.GetByID(ctx, articleID); err != nil {
+		return nil, "", err
+	}
+
+	res, err = s.commentRepo.Fetch(ctx, articleID, decoded, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(res) == int(num) {
+		nextCursor = domain.NewCommentCursor(res[len(res)-1]).Encode()
+	}
+	return
+}
+
+// Store will post the comment on its article, an article which doesn't exist is reported as ErrNotFound
+func (s *Service) Store(ctx context.Context, c *domain.Comment) (err error) {
+	ctx, span := tracer.Start(ctx, "Service.Store")
+	defer func() { endSpan(span, err) }()
+
+	if _, err = s.articleRepo.GetByID(ctx, c.ArticleID); err != nil {
+		return
+	}
+	return s.commentRepo.Store(ctx, c)
+}
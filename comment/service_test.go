@@ -0,0 +1,108 @@
+package comment_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/comment"
+	"apismrtbiz/comment/mocks"
+	"apismrtbiz/domain"
+)
+
+func TestFetch(t *testing.T) {
+	postedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	list := []domain.Comment{
+		{ID: 1, ArticleID: 7, Author: "Iman", Content: "First", CreatedAt: postedAt},
+		{ID: 2, ArticleID: 7, Author: "Bxcodec", Content: "Second", CreatedAt: postedAt.Add(time.Minute)},
+	}
+
+	t.Run("full-page", func(t *testing.T) {
+		mockCommentRepo := new(mocks.CommentRepository)
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7}, nil).Once()
+		mockCommentRepo.On("Fetch", mock.Anything, int64(7), domain.Cursor{}, int64(2)).Return(list, nil).Once()
+		s := comment.NewService(mockCommentRepo, mockArticleRepo)
+
+		res, nextCursor, err := s.Fetch(context.TODO(), 7, "", 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, list, res)
+		assert.Equal(t, domain.NewCommentCursor(list[1]).Encode(), nextCursor)
+		mockCommentRepo.AssertExpectations(t)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("last-page", func(t *testing.T) {
+		cursor := domain.NewCommentCursor(list[1])
+		mockCommentRepo := new(mocks.CommentRepository)
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7}, nil).Once()
+		mockCommentRepo.On("Fetch", mock.Anything, int64(7), cursor, int64(2)).Return(list[:1], nil).Once()
+		s := comment.NewService(mockCommentRepo, mockArticleRepo)
+
+		res, nextCursor, err := s.Fetch(context.TODO(), 7, cursor.Encode(), 2)
+
+		require.NoError(t, err)
+		assert.Len(t, res, 1)
+		assert.Empty(t, nextCursor)
+		mockCommentRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid-cursor", func(t *testing.T) {
+		mockCommentRepo := new(mocks.CommentRepository)
+		s := comment.NewService(mockCommentRepo, new(mocks.ArticleRepository))
+
+		_, _, err := s.Fetch(context.TODO(), 7, "not a cursor", 2)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		mockCommentRepo.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("article-not-found", func(t *testing.T) {
+		mockCommentRepo := new(mocks.CommentRepository)
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(404)).Return(domain.Article{}, domain.ErrNotFound).Once()
+		s := comment.NewService(mockCommentRepo, mockArticleRepo)
+
+		_, _, err := s.Fetch(context.TODO(), 404, "", 2)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockCommentRepo.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockCommentRepo := new(mocks.CommentRepository)
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7}, nil).Once()
+		mockCommentRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Comment")).
+			Run(func(args mock.Arguments) { args.Get(1).(*domain.Comment).ID = 12 }).Return(nil).Once()
+		s := comment.NewService(mockCommentRepo, mockArticleRepo)
+
+		c := &domain.Comment{ArticleID: 7, Author: "Iman", Content: "Nice"}
+		err := s.Store(context.TODO(), c)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(12), c.ID)
+		mockCommentRepo.AssertExpectations(t)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("article-not-found", func(t *testing.T) {
+		mockCommentRepo := new(mocks.CommentRepository)
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(404)).Return(domain.Article{}, domain.ErrNotFound).Once()
+		s := comment.NewService(mockCommentRepo, mockArticleRepo)
+
+		err := s.Store(context.TODO(), &domain.Comment{ArticleID: 404, Author: "Iman", Content: "Nice"})
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockCommentRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
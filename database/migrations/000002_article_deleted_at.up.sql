@@ -0,0 +1,3 @@
+USE `ctfhr`;
+
+ALTER TABLE `article` ADD COLUMN `deleted_at` datetime DEFAULT NULL;
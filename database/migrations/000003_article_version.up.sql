@@ -0,0 +1,3 @@
+USE `ctfhr`;
+
+ALTER TABLE `article` ADD COLUMN `version` int(11) NOT NULL DEFAULT 1;
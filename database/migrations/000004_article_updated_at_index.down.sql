@@ -0,0 +1,3 @@
+USE `ctfhr`;
+
+ALTER TABLE `article` DROP INDEX `idx_article_updated_at`;
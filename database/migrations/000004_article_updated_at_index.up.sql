@@ -0,0 +1,3 @@
+USE `ctfhr`;
+
+ALTER TABLE `article` ADD INDEX `idx_article_updated_at` (`updated_at`);
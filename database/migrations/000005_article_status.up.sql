@@ -0,0 +1,5 @@
+USE `ctfhr`;
+
+ALTER TABLE `article` ADD COLUMN `status` varchar(16) NOT NULL DEFAULT 'draft';
+-- the articles written before the workflow existed were already public
+UPDATE `article` SET `status` = 'published';
@@ -0,0 +1,4 @@
+USE `ctfhr`;
+
+ALTER TABLE `article` DROP INDEX `idx_article_status_publish_at`;
+ALTER TABLE `article` DROP COLUMN `publish_at`;
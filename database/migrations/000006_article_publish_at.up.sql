@@ -0,0 +1,4 @@
+USE `ctfhr`;
+
+ALTER TABLE `article` ADD COLUMN `publish_at` datetime NULL DEFAULT NULL;
+ALTER TABLE `article` ADD INDEX `idx_article_status_publish_at` (`status`, `publish_at`);
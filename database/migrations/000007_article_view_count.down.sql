@@ -0,0 +1,3 @@
+USE `ctfhr`;
+
+ALTER TABLE `article` DROP COLUMN `view_count`;
@@ -0,0 +1,3 @@
+USE `ctfhr`;
+
+ALTER TABLE `article` ADD COLUMN `view_count` bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+USE `ctfhr`;
+
+CREATE TABLE `article_tag` (
+  `article_id` int(11) NOT NULL,
+  `tag` varchar(32) COLLATE utf8_unicode_ci NOT NULL,
+  PRIMARY KEY (`article_id`, `tag`),
+  KEY `idx_article_tag_tag` (`tag`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
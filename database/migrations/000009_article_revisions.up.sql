@@ -0,0 +1,12 @@
+USE `ctfhr`;
+
+-- the tags are kept comma separated, a tag never contains a comma
+CREATE TABLE `article_revisions` (
+  `article_id` int(11) NOT NULL,
+  `version` int(11) NOT NULL,
+  `title` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  `content` longtext COLLATE utf8_unicode_ci NOT NULL,
+  `tags` text COLLATE utf8_unicode_ci NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`article_id`, `version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
@@ -0,0 +1,4 @@
+USE `ctfhr`;
+
+DROP INDEX `idx_article_slug` ON `article`;
+ALTER TABLE `article` DROP COLUMN `slug`;
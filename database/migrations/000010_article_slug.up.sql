@@ -0,0 +1,6 @@
+USE `ctfhr`;
+
+ALTER TABLE `article` ADD COLUMN `slug` varchar(191) NOT NULL DEFAULT '';
+-- the articles written before the slugs existed are reachable by their id
+UPDATE `article` SET `slug` = CONCAT('article-', `id`);
+CREATE UNIQUE INDEX `idx_article_slug` ON `article` (`slug`);
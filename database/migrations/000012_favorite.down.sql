@@ -0,0 +1,3 @@
+USE `ctfhr`;
+
+DROP TABLE `favorite`;
@@ -0,0 +1,10 @@
+USE `ctfhr`;
+
+-- the user id is the subject of the bearer token
+CREATE TABLE `favorite` (
+  `user_id` varchar(191) COLLATE utf8_unicode_ci NOT NULL,
+  `article_id` int(11) NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`user_id`, `article_id`),
+  KEY `idx_favorite_article` (`article_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
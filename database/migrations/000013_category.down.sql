@@ -0,0 +1,8 @@
+USE `ctfhr`;
+
+DROP INDEX `idx_article_category` ON `article`;
+ALTER TABLE `article` DROP COLUMN `category_id`;
+
+ALTER TABLE `category`
+  MODIFY `name` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  MODIFY `tag` varchar(45) COLLATE utf8_unicode_ci NOT NULL;
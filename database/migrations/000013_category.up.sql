@@ -0,0 +1,11 @@
+USE `ctfhr`;
+
+-- the category table of the baseline is reused, its names are widened to the length the API accepts
+-- and its tag, which the API doesn't manage, is left empty on the categories it creates
+ALTER TABLE `category`
+  MODIFY `name` varchar(100) COLLATE utf8_unicode_ci NOT NULL,
+  MODIFY `tag` varchar(45) COLLATE utf8_unicode_ci NOT NULL DEFAULT '';
+
+-- the articles written before the categories existed are left uncategorized
+ALTER TABLE `article` ADD COLUMN `category_id` int(11) NULL;
+CREATE INDEX `idx_article_category` ON `article` (`category_id`);
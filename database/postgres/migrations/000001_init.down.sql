@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS article_category;
+DROP TABLE IF EXISTS category;
+DROP TABLE IF EXISTS article_tag;
+DROP TABLE IF EXISTS article;
+DROP TABLE IF EXISTS author;
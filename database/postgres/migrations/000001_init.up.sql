@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS author (
+    id         SERIAL PRIMARY KEY,
+    name       varchar(200) NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL DEFAULT now(),
+    updated_at timestamp NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS article (
+    id         SERIAL PRIMARY KEY,
+    title      varchar(45) NOT NULL,
+    content    text NOT NULL,
+    author_id  integer NOT NULL DEFAULT 0,
+    updated_at timestamp NULL,
+    created_at timestamp NULL,
+    deleted_at timestamp NULL,
+    version    integer NOT NULL DEFAULT 1,
+    status     varchar(16) NOT NULL DEFAULT 'draft',
+    publish_at timestamp NULL,
+    view_count bigint NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_updated_at ON article (updated_at);
+CREATE INDEX IF NOT EXISTS idx_article_status_publish_at ON article (status, publish_at);
+
+CREATE TABLE IF NOT EXISTS article_tag (
+    article_id integer NOT NULL,
+    tag        varchar(32) NOT NULL,
+    PRIMARY KEY (article_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_tag_tag ON article_tag (tag);
+
+CREATE TABLE IF NOT EXISTS category (
+    id         SERIAL PRIMARY KEY,
+    name       varchar(45) NOT NULL,
+    tag        varchar(45) NOT NULL,
+    created_at timestamp NULL,
+    updated_at timestamp NULL
+);
+
+CREATE TABLE IF NOT EXISTS article_category (
+    id          SERIAL PRIMARY KEY,
+    article_id  integer NOT NULL,
+    category_id integer NOT NULL,
+    UNIQUE (article_id, category_id)
+);
@@ -0,0 +1,10 @@
+-- the tags are kept comma separated, a tag never contains a comma
+CREATE TABLE IF NOT EXISTS article_revisions (
+    article_id integer NOT NULL,
+    version    integer NOT NULL,
+    title      varchar(45) NOT NULL,
+    content    text NOT NULL,
+    tags       text NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL,
+    PRIMARY KEY (article_id, version)
+);
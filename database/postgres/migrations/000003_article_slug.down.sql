@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_article_slug;
+ALTER TABLE article DROP COLUMN slug;
@@ -0,0 +1,4 @@
+ALTER TABLE article ADD COLUMN slug TEXT NOT NULL DEFAULT '';
+-- the articles written before the slugs existed are reachable by their id
+UPDATE article SET slug = 'article-' || id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_article_slug ON article (slug);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS comment (
+    id         SERIAL PRIMARY KEY,
+    article_id integer NOT NULL,
+    author     varchar(100) NOT NULL,
+    content    text NOT NULL,
+    created_at timestamp NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_article ON comment (article_id, created_at, id);
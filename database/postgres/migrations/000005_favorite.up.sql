@@ -0,0 +1,9 @@
+-- the user id is the subject of the bearer token
+CREATE TABLE IF NOT EXISTS favorite (
+    user_id    varchar(191) NOT NULL,
+    article_id integer NOT NULL,
+    created_at timestamp NOT NULL,
+    PRIMARY KEY (user_id, article_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_favorite_article ON favorite (article_id);
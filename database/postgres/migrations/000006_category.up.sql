@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS category (
+    id         SERIAL PRIMARY KEY,
+    name       varchar(100) NOT NULL,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+);
+
+-- the articles written before the categories existed are left uncategorized
+ALTER TABLE article ADD COLUMN category_id integer NULL;
+CREATE INDEX IF NOT EXISTS idx_article_category ON article (category_id);
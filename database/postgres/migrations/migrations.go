@@ -0,0 +1,9 @@
+// Package migrations holds the Postgres schema migrations, applied in order of their version
+package migrations
+
+import "embed"
+
+// FS holds the migrations embedded in the binary, so they are applied without the source tree
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS author (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    name       TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS article (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    title      TEXT NOT NULL,
+    content    TEXT NOT NULL,
+    author_id  INTEGER NOT NULL DEFAULT 0,
+    updated_at DATETIME NULL,
+    created_at DATETIME NULL,
+    deleted_at DATETIME NULL,
+    version    INTEGER NOT NULL DEFAULT 1,
+    status     TEXT NOT NULL DEFAULT 'draft',
+    publish_at DATETIME NULL,
+    view_count INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_updated_at ON article (updated_at);
+CREATE INDEX IF NOT EXISTS idx_article_status_publish_at ON article (status, publish_at);
+
+CREATE TABLE IF NOT EXISTS article_tag (
+    article_id INTEGER NOT NULL,
+    tag        TEXT NOT NULL,
+    PRIMARY KEY (article_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_tag_tag ON article_tag (tag);
@@ -0,0 +1,10 @@
+-- the tags are kept comma separated, a tag never contains a comma
+CREATE TABLE IF NOT EXISTS article_revisions (
+    article_id INTEGER NOT NULL,
+    version    INTEGER NOT NULL,
+    title      TEXT NOT NULL,
+    content    TEXT NOT NULL,
+    tags       TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (article_id, version)
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS comment (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    article_id INTEGER NOT NULL,
+    author     TEXT NOT NULL,
+    content    TEXT NOT NULL,
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_article ON comment (article_id, created_at, id);
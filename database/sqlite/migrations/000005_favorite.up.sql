@@ -0,0 +1,9 @@
+-- the user id is the subject of the bearer token
+CREATE TABLE IF NOT EXISTS favorite (
+    user_id    TEXT NOT NULL,
+    article_id INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, article_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_favorite_article ON favorite (article_id);
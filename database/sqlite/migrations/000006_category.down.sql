@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_article_category;
+ALTER TABLE article DROP COLUMN category_id;
+DROP TABLE IF EXISTS category;
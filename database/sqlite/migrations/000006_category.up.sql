@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS category (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    name       TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+-- the articles written before the categories existed are left uncategorized
+ALTER TABLE article ADD COLUMN category_id INTEGER NULL;
+CREATE INDEX IF NOT EXISTS idx_article_category ON article (category_id);
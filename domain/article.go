@@ -1,15 +1,118 @@
 package domain
 
 import (
+	"encoding/xml"
 	"time"
 )
 
 // Article is representing the Article data struct
 type Article struct {
-	ID        int64     `json:"id"`
-	Title     string    `json:"title" validate:"required"`
-	Content   string    `json:"content" validate:"required"`
-	Author    Author    `json:"author"`
-	UpdatedAt time.Time `json:"updated_at"`
-	CreatedAt time.Time `json:"created_at"`
+	XMLName   xml.Name   `json:"-" xml:"article"`
+	ID        int64      `json:"id" xml:"id"`
+	Title     string     `json:"title" xml:"title" validate:"required,max=45"`
+	Slug      string     `json:"slug" xml:"slug"`
+	Content   string     `json:"content" xml:"content" validate:"required,max=100000"`
+	Author    Author     `json:"author" xml:"author"`
+	UpdatedAt time.Time  `json:"updated_at" xml:"updated_at"`
+	CreatedAt time.Time  `json:"created_at" xml:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" xml:"deleted_at,omitempty"`
+	Version   int64      `json:"version" xml:"version"`
+	Status    Status     `json:"status" xml:"status"`
+	// PublishAt schedules a draft to be published once the time has passed
+	PublishAt *time.Time `json:"publish_at,omitempty" xml:"publish_at,omitempty"`
+	ViewCount int64      `json:"view_count" xml:"view_count"`
+	Tags      []string   `json:"tags,omitempty" xml:"tags>tag,omitempty" validate:"dive,required,max=32,excludesall=0x2C"`
+	// CategoryID is the category the article is filed under, zero leaves it uncategorized
+	CategoryID int64 `json:"category_id,omitempty" xml:"category_id,omitempty"`
+
+	// ReadingTimeMinutes is estimated from the content when the article is read, it isn't stored
+	ReadingTimeMinutes int `json:"reading_time_minutes,omitempty" xml:"reading_time_minutes,omitempty"`
+
+	// Favorited and FavoritesCount are filled in when an authenticated user reads the article
+	Favorited      *bool  `json:"favorited,omitempty" xml:"favorited,omitempty"`
+	FavoritesCount *int64 `json:"favorites_count,omitempty" xml:"favorites_count,omitempty"`
+
+	// SearchScore is the relevance of the article to a fuzzy search, from 0 to 1,
+	// or to a search served by the search index
+	SearchScore *float64 `json:"search_score,omitempty" xml:"search_score,omitempty"`
+	// Highlight is the fragment of the title or the content matching a search served by the search index,
+	// the matching terms are wrapped in <em>
+	Highlight string `json:"highlight,omitempty" xml:"highlight,omitempty"`
+}
+
+// Status is the stage of an article in the publishing workflow
+type Status string
+
+// The stages of the publishing workflow
+const (
+	StatusDraft     Status = "draft"
+	StatusPublished Status = "published"
+)
+
+// Valid will report whether the status is a known one
+func (s Status) Valid() bool {
+	return s == StatusDraft || s == StatusPublished
+}
+
+// ArticleFilter represent the criteria used to narrow down the fetched articles
+type ArticleFilter struct {
+	// IncludeDeleted will include the soft deleted articles
+	IncludeDeleted bool
+	// Sort is the ordering of the articles, the zero value lists the most recently updated first
+	Sort ArticleSort
+	// AuthorID will keep only the articles of the author, zero keeps every author
+	AuthorID int64
+	// CreatedFrom and CreatedTo bound the creation time of the articles inclusively,
+	// a zero time leaves that side of the range open
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// Backward will page towards the start of the ordering, listing the articles before the cursor
+	Backward bool
+	// Status will keep only the articles at that stage, empty keeps every stage
+	Status Status
+	// Tag will keep only the articles having the tag, empty keeps every article
+	Tag string
+	// CategoryID will keep only the articles of the category, zero keeps every category
+	CategoryID int64
+}
+
+// SortField is a field the articles can be ordered by
+type SortField string
+
+// The fields the articles can be ordered by
+const (
+	SortByUpdatedAt SortField = "updated_at"
+	SortByCreatedAt SortField = "created_at"
+	SortByTitle     SortField = "title"
+)
+
+// Valid will report whether the articles can be ordered by the field
+func (f SortField) Valid() bool {
+	switch f {
+	case SortByUpdatedAt, SortByCreatedAt, SortByTitle:
+		return true
+	}
+	return false
+}
+
+// SortOrder is the direction of the ordering
+type SortOrder string
+
+// The directions of the ordering
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// Valid will report whether the value is a known direction
+func (o SortOrder) Valid() bool {
+	return o == SortAsc || o == SortDesc
+}
+
+// ArticleSort represent the ordering of the fetched articles
+type ArticleSort struct {
+	// Field defaults to SortByUpdatedAt
+	Field SortField
+	// Order defaults to SortDesc
+	Order SortOrder
 }
@@ -2,8 +2,8 @@ package domain
 
 // Author representing the Author data struct
 type Author struct {
-	ID        int64  `json:"id"`
-	Name      string `json:"name"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID        int64  `json:"id" xml:"id"`
+	Name      string `json:"name" xml:"name"`
+	CreatedAt string `json:"created_at" xml:"created_at"`
+	UpdatedAt string `json:"updated_at" xml:"updated_at"`
 }
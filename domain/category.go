@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Category is representing a category the articles are organized into
+type Category struct {
+	XMLName   xml.Name  `json:"-" xml:"category"`
+	ID        int64     `json:"id" xml:"id"`
+	Name      string    `json:"name" xml:"name" validate:"required,max=100"`
+	CreatedAt time.Time `json:"created_at" xml:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" xml:"updated_at"`
+}
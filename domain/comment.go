@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Comment is representing the comment of a reader on an article
+type Comment struct {
+	XMLName   xml.Name  `json:"-" xml:"comment"`
+	ID        int64     `json:"id" xml:"id"`
+	ArticleID int64     `json:"article_id" xml:"article_id"`
+	Author    string    `json:"author" xml:"author" validate:"required,max=100"`
+	Content   string    `json:"content" xml:"content" validate:"required,max=5000"`
+	CreatedAt time.Time `json:"created_at" xml:"created_at"`
+}
+
+// NewCommentCursor will build the cursor pointing at the comment, the comments are listed
+// in the order they were posted
+func NewCommentCursor(c Comment) Cursor {
+	return Cursor{ID: c.ID, Value: c.CreatedAt.Format(time.RFC3339Nano)}
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Cursor represent the position of the last article seen in a listing,
+// it is handed to the clients as an opaque token
+type Cursor struct {
+	// ID is the id of the last article seen
+	ID int64 `json:"id"`
+	// Value is the value of the sort field of the last article seen, times are formatted as RFC3339Nano
+	Value string `json:"v"`
+}
+
+// NewCursor will build the cursor pointing at the article in the ordering of the sort field
+func NewCursor(field SortField, ar Article) Cursor {
+	cursor := Cursor{ID: ar.ID}
+	switch field {
+	case SortByTitle:
+		cursor.Value = ar.Title
+	case SortByCreatedAt:
+		cursor.Value = ar.CreatedAt.Format(time.RFC3339Nano)
+	default:
+		cursor.Value = ar.UpdatedAt.Format(time.RFC3339Nano)
+	}
+	return cursor
+}
+
+// DecodeCursor will read back the token of Encode, anything else is reported as ErrBadParamInput
+func DecodeCursor(token string) (Cursor, error) {
+	var cursor Cursor
+	byt, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, ErrBadParamInput
+	}
+	if err = json.Unmarshal(byt, &cursor); err != nil || cursor.ID <= 0 || cursor.Value == "" {
+		return Cursor{}, ErrBadParamInput
+	}
+	return cursor, nil
+}
+
+// Encode will build the opaque token of the cursor
+func (c Cursor) Encode() string {
+	byt, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(byt)
+}
+
+// IsZero will report whether the cursor is unset, which starts the listing from the beginning
+func (c Cursor) IsZero() bool {
+	return c == Cursor{}
+}
+
+// Time will parse the value of a cursor on a time sort field
+func (c Cursor) Time() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, c.Value)
+	if err != nil {
+		return time.Time{}, ErrBadParamInput
+	}
+	return t, nil
+}
+
+// Float will parse the value of a cursor on a relevance score
+func (c Cursor) Float() (float64, error) {
+	f, err := strconv.ParseFloat(c.Value, 64)
+	if err != nil {
+		return 0, ErrBadParamInput
+	}
+	return f, nil
+}
@@ -1,14 +1,28 @@
 package domain
 
-import "errors"
+import "net/http"
+
+// AppError represent an error of the domain, the code is stable so the clients can branch on it
+// and the status is the HTTP status it is answered with
+type AppError struct {
+	Code    string
+	Status  int
+	Message string
+}
+
+func (e *AppError) Error() string {
+	return e.Message
+}
 
 var (
 	// ErrInternalServerError will throw if any the Internal Server Error happen
-	ErrInternalServerError = errors.New("internal Server Error")
+	ErrInternalServerError = &AppError{Code: "INTERNAL_ERROR", Status: http.StatusInternalServerError, Message: "internal Server Error"}
 	// ErrNotFound will throw if the requested item is not exists
-	ErrNotFound = errors.New("your requested Item is not found")
+	ErrNotFound = &AppError{Code: "NOT_FOUND", Status: http.StatusNotFound, Message: "your requested Item is not found"}
 	// ErrConflict will throw if the current action already exists
-	ErrConflict = errors.New("your Item already exist")
+	ErrConflict = &AppError{Code: "CONFLICT", Status: http.StatusConflict, Message: "your Item already exist"}
+	// ErrInUse will throw if the item is still referenced and can't be removed
+	ErrInUse = &AppError{Code: "IN_USE", Status: http.StatusConflict, Message: "your Item is still in use"}
 	// ErrBadParamInput will throw if the given request-body or params is not valid
-	ErrBadParamInput = errors.New("given Param is not valid")
+	ErrBadParamInput = &AppError{Code: "BAD_PARAM_INPUT", Status: http.StatusBadRequest, Message: "given Param is not valid"}
 )
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// EventType is the kind of change in the lifecycle of an article
+type EventType string
+
+// The lifecycle events of the articles
+const (
+	EventArticleCreated EventType = "article.created"
+	EventArticleUpdated EventType = "article.updated"
+	EventArticleDeleted EventType = "article.deleted"
+)
+
+// Event represent a change of an article, notified once the change is stored
+type Event struct {
+	Type      EventType `json:"type"`
+	ArticleID int64     `json:"article_id"`
+	Version   int64     `json:"version"`
+	Timestamp time.Time `json:"timestamp"`
+}
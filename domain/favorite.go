@@ -0,0 +1,7 @@
+package domain
+
+// FavoriteStatus is how many users favorited an article, and whether the user asking is one of them
+type FavoriteStatus struct {
+	Favorited bool
+	Count     int64
+}
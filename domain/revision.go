@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"encoding/xml"
+	"slices"
+	"time"
+)
+
+// Revision is the snapshot of an article as it was before an update replaced it
+type Revision struct {
+	ArticleID int64 `json:"article_id" xml:"article_id"`
+	// Version is the version of the article the snapshot was taken of
+	Version int64    `json:"version" xml:"version"`
+	Title   string   `json:"title" xml:"title"`
+	Content string   `json:"content" xml:"content"`
+	Tags    []string `json:"tags,omitempty" xml:"tags>tag,omitempty"`
+	// CreatedAt is when the snapshot was replaced
+	CreatedAt time.Time `json:"created_at" xml:"created_at"`
+}
+
+// ChangeOp tells how a field differs between two revisions
+type ChangeOp string
+
+// The ways a field differs between two revisions
+const (
+	ChangeAdded   ChangeOp = "added"
+	ChangeRemoved ChangeOp = "removed"
+	ChangeChanged ChangeOp = "changed"
+)
+
+// FieldChange is a field differing between two revisions, an added field has no From
+// and a removed one no To
+type FieldChange struct {
+	Field string      `json:"field" xml:"field"`
+	Op    ChangeOp    `json:"op" xml:"op"`
+	From  interface{} `json:"from,omitempty" xml:"from,omitempty"`
+	To    interface{} `json:"to,omitempty" xml:"to,omitempty"`
+}
+
+// RevisionDiff represent the fields differing between two revisions of an article
+type RevisionDiff struct {
+	XMLName   xml.Name      `json:"-" xml:"diff"`
+	ArticleID int64         `json:"article_id" xml:"article_id"`
+	From      int64         `json:"from" xml:"from"`
+	To        int64         `json:"to" xml:"to"`
+	Changes   []FieldChange `json:"changes" xml:"changes>change"`
+}
+
+// DiffRevisions will list the fields differing from one revision to the other, in the order
+// title, content, tags. The fields left unchanged are not listed.
+func DiffRevisions(from, to Revision) RevisionDiff {
+	diff := RevisionDiff{ArticleID: to.ArticleID, From: from.Version, To: to.Version, Changes: []FieldChange{}}
+	fields := []struct {
+		name           string
+		from, to       interface{}
+		fromSet, toSet bool
+		equal          bool
+	}{
+		{"title", from.Title, to.Title, from.Title != "", to.Title != "", from.Title == to.Title},
+		{"content", from.Content, to.Content, from.Content != "", to.Content != "", from.Content == to.Content},
+		{"tags", from.Tags, to.Tags, len(from.Tags) > 0, len(to.Tags) > 0, slices.Equal(from.Tags, to.Tags)},
+	}
+	for _, f := range fields {
+		switch {
+		case f.equal:
+			continue
+		case !f.fromSet:
+			diff.Changes = append(diff.Changes, FieldChange{Field: f.name, Op: ChangeAdded, To: f.to})
+		case !f.toSet:
+			diff.Changes = append(diff.Changes, FieldChange{Field: f.name, Op: ChangeRemoved, From: f.from})
+		default:
+			diff.Changes = append(diff.Changes, FieldChange{Field: f.name, Op: ChangeChanged, From: f.from, To: f.to})
+		}
+	}
+	return diff
+}
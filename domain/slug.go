@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxSlugLength keeps the slugs short enough for the URLs and the unique index of the slug column
+const maxSlugLength = 80
+
+// defaultSlug is given to the titles without any letter or digit
+const defaultSlug = "article"
+
+// Slugify will turn the title into a URL-safe slug, made of lowercase ASCII letters and digits
+// separated by single dashes. The accents are dropped, e.g. "Café au lait!" becomes "cafe-au-lait".
+func Slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range norm.NFKD.String(title) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// the accent of the previous letter
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			dash = true
+		}
+		if b.Len() >= maxSlugLength {
+			break
+		}
+	}
+
+	slug := strings.TrimSuffix(b.String()[:min(b.Len(), maxSlugLength)], "-")
+	if slug == "" {
+		return defaultSlug
+	}
+	return slug
+}
@@ -0,0 +1,72 @@
+package favorite
+
+import (
+	"context"
+
+	"apismrtbiz/domain"
+)
+
+// FavoriteRepository represent the favorite's repository contract, a user favorites an article at most once
+//
+//go:generate mockery --name FavoriteRepository
+type FavoriteRepository interface {
+	Add(ctx context.Context, userID string, articleID int64) error
+	Remove(ctx context.Context, userID string, articleID int64) error
+	Status(ctx context.Context, userID string, articleIDs []int64) (map[int64]domain.FavoriteStatus, error)
+}
+
+// ArticleRepository represent the part of the article's repository contract the favorites rely on
+//
+//go:generate mockery --name ArticleRepository
+type ArticleRepository interface {
+	GetByID(ctx context.Context, id int64) (domain.Article, error)
+}
+
+type Service struct {
+	favoriteRepo FavoriteRepository
+	articleRepo  ArticleRepository
+}
+
+// NewService will create a new favorite service object
+func NewService(f FavoriteRepository, a ArticleRepository) *Service {
+	return &Service{
+		favoriteRepo: f,
+		articleRepo:  a,
+	}
+}
+
+// Favorite will bookmark the article for the user, favoriting it again changes nothing.
+// An article which doesn't exist is reported as ErrNotFound.
+func (s *Service) Favorite(ctx context.Context, userID string, articleID int64) (err error) {
+	ctx, span := tracer.Start(ctx, "Service.Favorite")
+	defer func() { endSpan(span, err) }()
+
+	if _, err = s.articleRepo.GetByID(ctx, articleID); err != nil {
+		return
+	}
+	return s.favoriteRepo.Add(ctx, userID, articleID)
+}
+
+// Unfavorite will remove the bookmark of the article for the user, an article which isn't favorited is left as is.
+// An article which doesn't exist is reported as ErrNotFound.
+func (s *Service) Unfavorite(ctx context.Context, userID string, articleID int64) (err error) {
+	ctx, span := tracer.Start(ctx, "Service.Unfavorite")
+	defer func() { endSpan(span, err) }()
+
+	if _, err = s.articleRepo.GetByID(ctx, articleID); err != nil {
+		return
+	}
+	return s.favoriteRepo.Remove(ctx, userID, articleID)
+}
+
+// Status will tell for each of the articles whether the user favorited it and by how many users it is,
+// the articles nobody favorited are given the zero status
+func (s *Service) Status(ctx context.Context, userID string, articleIDs []int64) (res map[int64]domain.FavoriteStatus, err error) {
+	ctx, span := tracer.Start(ctx, "Service.Status")
+	defer func() { endSpan(span, err) }()
+
+	if len(articleIDs) == 0 {
+		return map[int64]domain.FavoriteStatus{}, nil
+	}
+	return s.favoriteRepo.Status(ctx, userID, articleIDs)
+}
@@ -0,0 +1,81 @@
+package favorite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/domain"
+	"apismrtbiz/favorite"
+	"apismrtbiz/favorite/mocks"
+)
+
+func TestFavorite(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockFavoriteRepo := new(mocks.FavoriteRepository)
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7}, nil).Once()
+		mockFavoriteRepo.On("Add", mock.Anything, "42", int64(7)).Return(nil).Once()
+		s := favorite.NewService(mockFavoriteRepo, mockArticleRepo)
+
+		err := s.Favorite(context.TODO(), "42", 7)
+
+		require.NoError(t, err)
+		mockFavoriteRepo.AssertExpectations(t)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("article-not-found", func(t *testing.T) {
+		mockFavoriteRepo := new(mocks.FavoriteRepository)
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(404)).Return(domain.Article{}, domain.ErrNotFound).Once()
+		s := favorite.NewService(mockFavoriteRepo, mockArticleRepo)
+
+		err := s.Favorite(context.TODO(), "42", 404)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockFavoriteRepo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUnfavorite(t *testing.T) {
+	mockFavoriteRepo := new(mocks.FavoriteRepository)
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7}, nil).Once()
+	mockFavoriteRepo.On("Remove", mock.Anything, "42", int64(7)).Return(nil).Once()
+	s := favorite.NewService(mockFavoriteRepo, mockArticleRepo)
+
+	err := s.Unfavorite(context.TODO(), "42", 7)
+
+	require.NoError(t, err)
+	mockFavoriteRepo.AssertExpectations(t)
+}
+
+func TestStatus(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		want := map[int64]domain.FavoriteStatus{7: {Favorited: true, Count: 2}}
+		mockFavoriteRepo := new(mocks.FavoriteRepository)
+		mockFavoriteRepo.On("Status", mock.Anything, "42", []int64{7, 8}).Return(want, nil).Once()
+		s := favorite.NewService(mockFavoriteRepo, new(mocks.ArticleRepository))
+
+		res, err := s.Status(context.TODO(), "42", []int64{7, 8})
+
+		require.NoError(t, err)
+		assert.Equal(t, want, res)
+		mockFavoriteRepo.AssertExpectations(t)
+	})
+
+	t.Run("no-articles", func(t *testing.T) {
+		mockFavoriteRepo := new(mocks.FavoriteRepository)
+		s := favorite.NewService(mockFavoriteRepo, new(mocks.ArticleRepository))
+
+		res, err := s.Status(context.TODO(), "42", nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, res)
+		mockFavoriteRepo.AssertNotCalled(t, "Status", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
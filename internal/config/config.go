@@ -0,0 +1,506 @@
+// Package config loads the settings of the API from an optional YAML file and the environment
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"apismrtbiz/article"
+	"apismrtbiz/internal/database"
+)
+
+// The defaults of the settings neither the file nor the environment give
+const (
+	defaultAddress         = ":9090"
+	defaultContextTimeout  = 30 * time.Second
+	defaultShutdownTimeout = 10 * time.Second
+)
+
+// Config represent the settings of the API
+type Config struct {
+	Database   Database   `yaml:"database"`
+	Server     Server     `yaml:"server"`
+	Pagination Pagination `yaml:"pagination"`
+	Features   Features   `yaml:"features"`
+	Auth       Auth       `yaml:"auth"`
+	CORS       CORS       `yaml:"cors"`
+	RateLimit  RateLimit  `yaml:"rate_limit"`
+	DebugBody  DebugBody  `yaml:"debug_body"`
+	Repository Repository `yaml:"repository"`
+	Cache      Cache      `yaml:"cache"`
+	Content    Content    `yaml:"content"`
+	Events     Events     `yaml:"events"`
+	Search     Search     `yaml:"search"`
+	Worker     Worker     `yaml:"worker"`
+}
+
+// Database represent the connection to the database and its pool
+type Database struct {
+	// Driver is one of the database drivers, MySQL by default
+	Driver string `yaml:"driver"`
+	Host   string `yaml:"host"`
+	Port   string `yaml:"port"`
+	User   string `yaml:"user"`
+	Pass   string `yaml:"pass"`
+	// Name is the database, for SQLite the path of its file
+	Name string `yaml:"name"`
+	// SSLMode is the sslmode of Postgres, disable when empty
+	SSLMode string `yaml:"sslmode"`
+	// ReplicaHost is the host the articles are read from, reached with the credentials of the primary,
+	// the primary serves the reads when empty
+	ReplicaHost string `yaml:"replica_host"`
+	// ReplicaPort is the port of the replica, the one of the primary when empty
+	ReplicaPort     string        `yaml:"replica_port"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	// Migrate applies the pending migrations on start, the embedded SQLite database always gets its schema
+	Migrate bool `yaml:"migrate"`
+}
+
+// Server represent the settings of the HTTP server
+type Server struct {
+	Address string `yaml:"address"`
+	// ContextTimeout bounds the handling of a request
+	ContextTimeout time.Duration `yaml:"context_timeout"`
+	// ShutdownTimeout bounds the draining of the in-flight requests on shutdown
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// BodyLimit is the size of the largest request body accepted in bytes, the middleware default when zero
+	BodyLimit int `yaml:"body_limit"`
+	// CompressMinSize is the size of the smallest response body compressed in bytes, the middleware default when zero
+	CompressMinSize int `yaml:"compress_min_size"`
+	// BasePath prefixes the resources, e.g. /api behind a gateway, they are mounted at the root when empty
+	BasePath string `yaml:"base_path"`
+	// GRPCAddress is the address the articles are served over gRPC at, there is no gRPC server when empty
+	GRPCAddress string `yaml:"grpc_address"`
+}
+
+// Pagination represent the page sizes of the article lists, the defaults of the handler when zero
+type Pagination struct {
+	DefaultPageSize int `yaml:"default_page_size"`
+	MaxPageSize     int `yaml:"max_page_size"`
+}
+
+// Features represent the behaviours of the API turned on by the operators
+type Features struct {
+	// TrailingSlash is how the paths ending with a slash are served: like the others when empty,
+	// redirected to the canonical path with redirect, answered with 404 with strict
+	TrailingSlash string `yaml:"trailing_slash"`
+	// CaseSensitiveRouting matches the case of the paths
+	CaseSensitiveRouting bool `yaml:"case_sensitive_routing"`
+	// RequireIfMatch refuses the updates of an article without an If-Match header
+	RequireIfMatch bool `yaml:"require_if_match"`
+}
+
+// Auth represent the credentials of the writes, they aren't authenticated when neither is given
+type Auth struct {
+	// APIKeys are the label:key pairs of the api keys, a label may be repeated to keep
+	// the previous key valid while rotating
+	APIKeys []string `yaml:"api_keys"`
+	// JWTSecret is the secret the bearer tokens are signed with
+	JWTSecret string `yaml:"jwt_secret"`
+}
+
+// CORS represent the cross origin requests allowed
+type CORS struct {
+	// AllowOrigins are the origins allowed, every origin when empty
+	AllowOrigins     []string `yaml:"allow_origins"`
+	AllowCredentials bool     `yaml:"allow_credentials"`
+}
+
+// RateLimit represent the limit of the requests of every client, there is none when the rate is zero
+type RateLimit struct {
+	// RPS is the number of requests a client regains per second
+	RPS float64 `yaml:"rps"`
+	// Burst is the number of requests a client can send at once, one when zero
+	Burst int `yaml:"burst"`
+}
+
+// DebugBody represent the logging of the bodies of the requests, they aren't logged when no path is given
+type DebugBody struct {
+	// Paths are the patterns of the paths whose bodies are logged, e.g. /articles/*
+	Paths []string `yaml:"paths"`
+	// MaxBytes cuts the bodies logged, the middleware default when zero
+	MaxBytes int `yaml:"max_bytes"`
+	// RedactFields are the fields of the JSON bodies redacted from the log
+	RedactFields []string `yaml:"redact_fields"`
+}
+
+// Repository represent the decorators of the article repository
+type Repository struct {
+	// SlowQueryThreshold logs the article queries running longer, they aren't logged when zero
+	SlowQueryThreshold time.Duration `yaml:"slow_query_threshold"`
+	// RetryAttempts bounds the attempts of a write failing with a transient error, the default when zero
+	RetryAttempts int `yaml:"retry_attempts"`
+	// RetryBackoff is the wait before the first retry, the default when zero
+	RetryBackoff time.Duration `yaml:"retry_backoff"`
+}
+
+// Cache represent the cache of the articles, in redis when its address is given, otherwise
+// in the process memory when a size is given
+type Cache struct {
+	// Size is the number of articles cached in the process memory
+	Size         int    `yaml:"size"`
+	RedisAddress string `yaml:"redis_address"`
+	// RedisTTL is how long an article is cached in redis, the default when zero
+	RedisTTL       time.Duration `yaml:"redis_ttl"`
+	RedisKeyPrefix string        `yaml:"redis_key_prefix"`
+}
+
+// Content represent the rendering of the content of the articles
+type Content struct {
+	// Policy is the sanitization policy of the written content, basic or strict, basic when empty
+	Policy string `yaml:"policy"`
+	// WordsPerMinute is the reading speed the reading time is estimated with, the default when zero
+	WordsPerMinute int `yaml:"words_per_minute"`
+}
+
+// Events represent the destinations of the lifecycle events of the articles
+type Events struct {
+	// WebhookURLs are posted every event
+	WebhookURLs []string `yaml:"webhook_urls"`
+	// NATSURL is the server the events are published to, they aren't when empty
+	NATSURL string `yaml:"nats_url"`
+}
+
+// Search represent the Elasticsearch index serving the searches, the database serves them when the url is empty
+type Search struct {
+	ElasticsearchURL   string `yaml:"elasticsearch_url"`
+	ElasticsearchIndex string `yaml:"elasticsearch_index"`
+}
+
+// Worker represent the background jobs
+type Worker struct {
+	// PublishInterval is how often the scheduled drafts are published, the default when zero
+	PublishInterval time.Duration `yaml:"publish_interval"`
+}
+
+// Keys will return the labels of the api keys by key, the pairs without a key are dropped
+func (a Auth) Keys() map[string]string {
+	keys := map[string]string{}
+	for _, pair := range a.APIKeys {
+		label, key, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || key == "" {
+			continue
+		}
+		keys[key] = label
+	}
+	return keys
+}
+
+// Conn will return the connection to the primary database
+func (d Database) Conn() database.ConnConfig {
+	return database.ConnConfig{
+		Driver:  d.Driver,
+		Host:    d.Host,
+		Port:    d.Port,
+		User:    d.User,
+		Pass:    d.Pass,
+		Name:    d.Name,
+		SSLMode: d.SSLMode,
+	}
+}
+
+// ReplicaConn will return the connection to the replica, ok is false when there is none
+func (d Database) ReplicaConn() (conn database.ConnConfig, ok bool) {
+	if d.ReplicaHost == "" || d.Driver == database.DriverSQLite {
+		return database.ConnConfig{}, false
+	}
+	conn = d.Conn()
+	conn.Host = d.ReplicaHost
+	if d.ReplicaPort != "" {
+		conn.Port = d.ReplicaPort
+	}
+	return conn, true
+}
+
+// Pool will return the connection pool of the database
+func (d Database) Pool() database.PoolConfig {
+	return database.PoolConfig{MaxOpenConns: d.MaxOpenConns, MaxIdleConns: d.MaxIdleConns, ConnMaxLifetime: d.ConnMaxLifetime}
+}
+
+// Load will read the settings from the YAML file of CONFIG_FILE when it is set, then from the environment
+// whose variables override the file. The settings neither gives keep their default. Every invalid or missing
+// setting is reported in the one error, so that they can be fixed at once.
+func Load() (Config, error) {
+	cfg := Config{
+		Database: Database{Driver: database.DriverMySQL, Migrate: true},
+		Server:   Server{Address: defaultAddress, ContextTimeout: defaultContextTimeout, ShutdownTimeout: defaultShutdownTimeout},
+	}
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := readFile(path, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+
+	var env envReader
+	env.str(&cfg.Database.Driver, "DATABASE_DRIVER")
+	env.str(&cfg.Database.Host, "DATABASE_HOST")
+	env.str(&cfg.Database.Port, "DATABASE_PORT")
+	env.str(&cfg.Database.User, "DATABASE_USER")
+	env.str(&cfg.Database.Pass, "DATABASE_PASS")
+	env.str(&cfg.Database.Name, "DATABASE_NAME")
+	env.str(&cfg.Database.SSLMode, "DATABASE_SSLMODE")
+	env.str(&cfg.Database.ReplicaHost, "DATABASE_REPLICA_HOST")
+	env.str(&cfg.Database.ReplicaPort, "DATABASE_REPLICA_PORT")
+	env.int(&cfg.Database.MaxOpenConns, "DATABASE_MAX_OPEN_CONNS")
+	env.int(&cfg.Database.MaxIdleConns, "DATABASE_MAX_IDLE_CONNS")
+	env.duration(&cfg.Database.ConnMaxLifetime, "DATABASE_CONN_MAX_LIFETIME")
+	env.bool(&cfg.Database.Migrate, "DATABASE_MIGRATE")
+	env.str(&cfg.Server.Address, "SERVER_ADDRESS")
+	env.seconds(&cfg.Server.ContextTimeout, "CONTEXT_TIMEOUT")
+	env.duration(&cfg.Server.ShutdownTimeout, "SHUTDOWN_TIMEOUT")
+	env.int(&cfg.Server.BodyLimit, "BODY_LIMIT")
+	env.int(&cfg.Pagination.DefaultPageSize, "DEFAULT_PAGE_SIZE")
+	env.int(&cfg.Pagination.MaxPageSize, "MAX_PAGE_SIZE")
+	env.str(&cfg.Features.TrailingSlash, "TRAILING_SLASH")
+	env.bool(&cfg.Features.CaseSensitiveRouting, "CASE_SENSITIVE_ROUTING")
+	env.bool(&cfg.Features.RequireIfMatch, "REQUIRE_IF_MATCH")
+	env.int(&cfg.Server.CompressMinSize, "COMPRESS_MIN_SIZE")
+	env.str(&cfg.Server.BasePath, "API_BASE_PATH")
+	env.str(&cfg.Server.GRPCAddress, "GRPC_ADDRESS")
+	env.list(&cfg.Auth.APIKeys, "API_KEYS")
+	env.str(&cfg.Auth.JWTSecret, "JWT_SECRET")
+	env.list(&cfg.CORS.AllowOrigins, "CORS_ALLOW_ORIGINS")
+	env.bool(&cfg.CORS.AllowCredentials, "CORS_ALLOW_CREDENTIALS")
+	env.float(&cfg.RateLimit.RPS, "RATE_LIMIT_RPS")
+	env.int(&cfg.RateLimit.Burst, "RATE_LIMIT_BURST")
+	env.list(&cfg.DebugBody.Paths, "DEBUG_BODY_PATHS")
+	env.int(&cfg.DebugBody.MaxBytes, "DEBUG_BODY_MAX_BYTES")
+	env.list(&cfg.DebugBody.RedactFields, "DEBUG_BODY_REDACT_FIELDS")
+	env.duration(&cfg.Repository.SlowQueryThreshold, "SLOW_QUERY_THRESHOLD")
+	env.int(&cfg.Repository.RetryAttempts, "REPOSITORY_RETRY_ATTEMPTS")
+	env.duration(&cfg.Repository.RetryBackoff, "REPOSITORY_RETRY_BACKOFF")
+	env.int(&cfg.Cache.Size, "ARTICLE_CACHE_SIZE")
+	env.str(&cfg.Cache.RedisAddress, "REDIS_ADDRESS")
+	env.duration(&cfg.Cache.RedisTTL, "REDIS_CACHE_TTL")
+	env.str(&cfg.Cache.RedisKeyPrefix, "REDIS_KEY_PREFIX")
+	env.str(&cfg.Content.Policy, "CONTENT_POLICY")
+	env.int(&cfg.Content.WordsPerMinute, "READING_WORDS_PER_MINUTE")
+	env.list(&cfg.Events.WebhookURLs, "WEBHOOK_URLS")
+	env.str(&cfg.Events.NATSURL, "NATS_URL")
+	env.str(&cfg.Search.ElasticsearchURL, "ELASTICSEARCH_URL")
+	env.str(&cfg.Search.ElasticsearchIndex, "ELASTICSEARCH_INDEX")
+	env.duration(&cfg.Worker.PublishInterval, "PUBLISH_INTERVAL")
+
+	errs := append(env.errs, cfg.validate()...)
+	if len(errs) > 0 {
+		return Config{}, fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return cfg, nil
+}
+
+// readFile will decode the YAML file over the defaults, a key the settings don't have is an error
+// so that a typo isn't silently ignored
+func readFile(path string, cfg *Config) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read the configuration file: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err = dec.Decode(cfg); err != nil {
+		return fmt.Errorf("failed to parse the configuration file %s: %w", path, err)
+	}
+	return nil
+}
+
+// validate will list the problems of the settings, naming the variable and the key of the file of each
+func (c Config) validate() []error {
+	var errs []error
+	if _, err := c.Database.Conn().DSN(); err != nil {
+		errs = append(errs, err)
+	}
+	host := setting{"DATABASE_HOST", "database.host", c.Database.Host}
+	port := setting{"DATABASE_PORT", "database.port", c.Database.Port}
+	name := setting{"DATABASE_NAME", "database.name", c.Database.Name}
+	required := map[string][]setting{
+		database.DriverMySQL:    {host, port, name},
+		database.DriverPostgres: {host, port, name},
+		database.DriverSQLite:   {name},
+		database.DriverMongo:    {host, port, name},
+	}
+	for _, s := range required[c.Database.Driver] {
+		if s.value == "" {
+			errs = append(errs, fmt.Errorf("%s (%s) is required by the %s driver", s.env, s.key, c.Database.Driver))
+		}
+	}
+	if err := c.Database.Pool().Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid database pool: %w", err))
+	}
+
+	if c.Server.ContextTimeout <= 0 {
+		errs = append(errs, errors.New("CONTEXT_TIMEOUT (server.context_timeout) must be positive"))
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("SHUTDOWN_TIMEOUT (server.shutdown_timeout) must be positive"))
+	}
+	if c.Server.BodyLimit < 0 {
+		errs = append(errs, errors.New("BODY_LIMIT (server.body_limit) must not be negative"))
+	}
+
+	if c.Pagination.DefaultPageSize < 0 || c.Pagination.MaxPageSize < 0 {
+		errs = append(errs, errors.New("the page sizes (pagination) must not be negative"))
+	} else if c.Pagination.MaxPageSize > 0 && c.Pagination.DefaultPageSize > c.Pagination.MaxPageSize {
+		errs = append(errs, fmt.Errorf("DEFAULT_PAGE_SIZE (pagination.default_page_size) %d is over MAX_PAGE_SIZE (pagination.max_page_size) %d",
+			c.Pagination.DefaultPageSize, c.Pagination.MaxPageSize))
+	}
+
+	switch c.Features.TrailingSlash {
+	case "", "redirect", "strict":
+	default:
+		errs = append(errs, fmt.Errorf("TRAILING_SLASH (features.trailing_slash) %q must be redirect or strict", c.Features.TrailingSlash))
+	}
+
+	if c.RateLimit.RPS < 0 {
+		errs = append(errs, errors.New("RATE_LIMIT_RPS (rate_limit.rps) must not be negative"))
+	}
+	if c.Content.WordsPerMinute < 0 {
+		errs = append(errs, errors.New("READING_WORDS_PER_MINUTE (content.words_per_minute) must not be negative"))
+	}
+	switch c.Content.Policy {
+	case "", article.PolicyBasic, article.PolicyStrict:
+	default:
+		errs = append(errs, fmt.Errorf("CONTENT_POLICY (content.policy) %q must be %s or %s", c.Content.Policy, article.PolicyBasic, article.PolicyStrict))
+	}
+	for _, s := range []intSetting{
+		{"COMPRESS_MIN_SIZE", "server.compress_min_size", c.Server.CompressMinSize},
+		{"RATE_LIMIT_BURST", "rate_limit.burst", c.RateLimit.Burst},
+		{"DEBUG_BODY_MAX_BYTES", "debug_body.max_bytes", c.DebugBody.MaxBytes},
+		{"REPOSITORY_RETRY_ATTEMPTS", "repository.retry_attempts", c.Repository.RetryAttempts},
+		{"ARTICLE_CACHE_SIZE", "cache.size", c.Cache.Size},
+	} {
+		if s.value < 0 {
+			errs = append(errs, fmt.Errorf("%s (%s) must not be negative", s.env, s.key))
+		}
+	}
+	for _, s := range []durationSetting{
+		{"SLOW_QUERY_THRESHOLD", "repository.slow_query_threshold", c.Repository.SlowQueryThreshold},
+		{"REPOSITORY_RETRY_BACKOFF", "repository.retry_backoff", c.Repository.RetryBackoff},
+		{"REDIS_CACHE_TTL", "cache.redis_ttl", c.Cache.RedisTTL},
+		{"PUBLISH_INTERVAL", "worker.publish_interval", c.Worker.PublishInterval},
+	} {
+		if s.value < 0 {
+			errs = append(errs, fmt.Errorf("%s (%s) must not be negative", s.env, s.key))
+		}
+	}
+	return errs
+}
+
+// setting names a required setting by its variable and its key in the file
+type setting struct {
+	env, key, value string
+}
+
+// intSetting and durationSetting name a setting which can't be negative
+type intSetting struct {
+	env, key string
+	value    int
+}
+
+type durationSetting struct {
+	env, key string
+	value    time.Duration
+}
+
+// envReader will override the settings with the variables which are set and not empty,
+// collecting the values which can't be parsed
+type envReader struct {
+	errs []error
+}
+
+func (r *envReader) str(dst *string, key string) {
+	if v := os.Getenv(key); v != "" {
+		*dst = v
+	}
+}
+
+func (r *envReader) int(dst *int, key string) {
+	v := os.Getenv(key)
+	if v == "" {
+		return
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s must be an integer, got %q", key, v))
+		return
+	}
+	*dst = n
+}
+
+func (r *envReader) float(dst *float64, key string) {
+	v := os.Getenv(key)
+	if v == "" {
+		return
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s must be a number, got %q", key, v))
+		return
+	}
+	*dst = f
+}
+
+// list will read a comma separated list, the blank items are dropped
+func (r *envReader) list(dst *[]string, key string) {
+	v := os.Getenv(key)
+	if v == "" {
+		return
+	}
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	*dst = list
+}
+
+func (r *envReader) bool(dst *bool, key string) {
+	v := os.Getenv(key)
+	if v == "" {
+		return
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s must be true or false, got %q", key, v))
+		return
+	}
+	*dst = b
+}
+
+func (r *envReader) duration(dst *time.Duration, key string) {
+	v := os.Getenv(key)
+	if v == "" {
+		return
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s must be a duration such as 30s, got %q", key, v))
+		return
+	}
+	*dst = d
+}
+
+// seconds will read a duration given as a number of seconds, as the variable has always been,
+// or as a duration such as 30s
+func (r *envReader) seconds(dst *time.Duration, key string) {
+	v := os.Getenv(key)
+	if v == "" {
+		return
+	}
+	if n, err := strconv.Atoi(v); err == nil {
+		*dst = time.Duration(n) * time.Second
+		return
+	}
+	r.duration(dst, key)
+}
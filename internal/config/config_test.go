@@ -0,0 +1,208 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/internal/config"
+)
+
+// clearEnv will unset the variables of the settings for the test, an empty variable is ignored
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{
+		"CONFIG_FILE", "DATABASE_DRIVER", "DATABASE_HOST", "DATABASE_PORT", "DATABASE_USER", "DATABASE_PASS",
+		"DATABASE_NAME", "DATABASE_SSLMODE", "DATABASE_REPLICA_HOST", "DATABASE_REPLICA_PORT",
+		"DATABASE_MAX_OPEN_CONNS", "DATABASE_MAX_IDLE_CONNS", "DATABASE_CONN_MAX_LIFETIME", "DATABASE_MIGRATE",
+		"SERVER_ADDRESS", "CONTEXT_TIMEOUT", "SHUTDOWN_TIMEOUT", "BODY_LIMIT", "DEFAULT_PAGE_SIZE", "MAX_PAGE_SIZE",
+		"TRAILING_SLASH", "CASE_SENSITIVE_ROUTING", "REQUIRE_IF_MATCH", "COMPRESS_MIN_SIZE", "API_BASE_PATH", "GRPC_ADDRESS",
+		"API_KEYS", "JWT_SECRET", "CORS_ALLOW_ORIGINS", "CORS_ALLOW_CREDENTIALS", "RATE_LIMIT_RPS", "RATE_LIMIT_BURST",
+		"DEBUG_BODY_PATHS", "DEBUG_BODY_MAX_BYTES", "DEBUG_BODY_REDACT_FIELDS", "SLOW_QUERY_THRESHOLD",
+		"REPOSITORY_RETRY_ATTEMPTS", "REPOSITORY_RETRY_BACKOFF", "ARTICLE_CACHE_SIZE", "REDIS_ADDRESS", "REDIS_CACHE_TTL",
+		"REDIS_KEY_PREFIX", "CONTENT_POLICY", "READING_WORDS_PER_MINUTE", "WEBHOOK_URLS", "NATS_URL",
+		"ELASTICSEARCH_URL", "ELASTICSEARCH_INDEX", "PUBLISH_INTERVAL",
+	} {
+		t.Setenv(key, "")
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv("DATABASE_DRIVER", "sqlite")
+		t.Setenv("DATABASE_NAME", "article.db")
+
+		cfg, err := config.Load()
+		require.NoError(t, err)
+		assert.True(t, cfg.Database.Migrate)
+		assert.Equal(t, ":9090", cfg.Server.Address)
+		assert.Equal(t, 30*time.Second, cfg.Server.ContextTimeout)
+		assert.Equal(t, 10*time.Second, cfg.Server.ShutdownTimeout)
+		_, ok := cfg.Database.ReplicaConn()
+		assert.False(t, ok)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv("CONFIG_FILE", writeFile(t, `
+database:
+  driver: postgres
+  host: db
+  port: "5432"
+  name: article
+  conn_max_lifetime: 5m
+  replica_host: replica
+server:
+  context_timeout: 5s
+pagination:
+  default_page_size: 20
+  max_page_size: 50
+features:
+  require_if_match: true
+rate_limit:
+  rps: 2.5
+  burst: 10
+cors:
+  allow_origins: [https://example.com]
+debug_body:
+  paths: [/articles/*]
+cache:
+  redis_address: redis:6379
+  redis_ttl: 1m
+content:
+  policy: strict
+worker:
+  publish_interval: 30s
+`))
+
+		cfg, err := config.Load()
+		require.NoError(t, err)
+		assert.Equal(t, "postgres", cfg.Database.Driver)
+		assert.Equal(t, config.RateLimit{RPS: 2.5, Burst: 10}, cfg.RateLimit)
+		assert.Equal(t, []string{"https://example.com"}, cfg.CORS.AllowOrigins)
+		assert.Equal(t, []string{"/articles/*"}, cfg.DebugBody.Paths)
+		assert.Equal(t, config.Cache{RedisAddress: "redis:6379", RedisTTL: time.Minute}, cfg.Cache)
+		assert.Equal(t, "strict", cfg.Content.Policy)
+		assert.Equal(t, 30*time.Second, cfg.Worker.PublishInterval)
+		assert.Equal(t, 5*time.Minute, cfg.Database.ConnMaxLifetime)
+		assert.Equal(t, 5*time.Second, cfg.Server.ContextTimeout)
+		assert.Equal(t, config.Pagination{DefaultPageSize: 20, MaxPageSize: 50}, cfg.Pagination)
+		assert.True(t, cfg.Features.RequireIfMatch)
+
+		replica, ok := cfg.Database.ReplicaConn()
+		require.True(t, ok)
+		assert.Equal(t, "replica", replica.Host)
+		assert.Equal(t, "5432", replica.Port, "the replica is on the port of the primary")
+	})
+
+	t.Run("env-overrides-file", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv("CONFIG_FILE", writeFile(t, `
+database:
+  driver: mysql
+  host: db
+  port: "3306"
+  name: article
+  migrate: true
+server:
+  context_timeout: 5s
+pagination:
+  max_page_size: 50
+`))
+		t.Setenv("DATABASE_HOST", "primary")
+		t.Setenv("DATABASE_MIGRATE", "false")
+		t.Setenv("CONTEXT_TIMEOUT", "12")
+		t.Setenv("MAX_PAGE_SIZE", "80")
+		t.Setenv("COMPRESS_MIN_SIZE", "2048")
+		t.Setenv("API_KEYS", "client-1:key-1, client-1:key-2,broken")
+		t.Setenv("WEBHOOK_URLS", "https://a.example, ,https://b.example")
+		t.Setenv("RATE_LIMIT_RPS", "0.5")
+
+		cfg, err := config.Load()
+		require.NoError(t, err)
+		assert.Equal(t, 2048, cfg.Server.CompressMinSize)
+		assert.Equal(t, map[string]string{"key-1": "client-1", "key-2": "client-1"}, cfg.Auth.Keys())
+		assert.Equal(t, []string{"https://a.example", "https://b.example"}, cfg.Events.WebhookURLs)
+		assert.Equal(t, 0.5, cfg.RateLimit.RPS)
+		assert.Equal(t, "primary", cfg.Database.Host)
+		assert.Equal(t, "3306", cfg.Database.Port, "the settings the environment doesn't give are read from the file")
+		assert.False(t, cfg.Database.Migrate)
+		assert.Equal(t, 12*time.Second, cfg.Server.ContextTimeout, "the timeout is given in seconds")
+		assert.Equal(t, 80, cfg.Pagination.MaxPageSize)
+	})
+
+	t.Run("missing-required", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv("DATABASE_HOST", "db")
+
+		_, err := config.Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "DATABASE_PORT (database.port) is required by the mysql driver")
+		assert.Contains(t, err.Error(), "DATABASE_NAME (database.name) is required by the mysql driver")
+		assert.NotContains(t, err.Error(), "DATABASE_HOST")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv("DATABASE_DRIVER", "sqlite")
+		t.Setenv("DATABASE_NAME", "article.db")
+		t.Setenv("DATABASE_MAX_OPEN_CONNS", "many")
+		t.Setenv("SHUTDOWN_TIMEOUT", "10")
+		t.Setenv("DEFAULT_PAGE_SIZE", "100")
+		t.Setenv("MAX_PAGE_SIZE", "50")
+		t.Setenv("TRAILING_SLASH", "drop")
+		t.Setenv("RATE_LIMIT_RPS", "fast")
+		t.Setenv("RATE_LIMIT_BURST", "-1")
+		t.Setenv("PUBLISH_INTERVAL", "-1m")
+		t.Setenv("CONTENT_POLICY", "lenient")
+
+		_, err := config.Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `RATE_LIMIT_RPS must be a number, got "fast"`)
+		assert.Contains(t, err.Error(), "RATE_LIMIT_BURST (rate_limit.burst) must not be negative")
+		assert.Contains(t, err.Error(), "PUBLISH_INTERVAL (worker.publish_interval) must not be negative")
+		assert.Contains(t, err.Error(), `CONTENT_POLICY (content.policy) "lenient" must be basic or strict`)
+		assert.Contains(t, err.Error(), `DATABASE_MAX_OPEN_CONNS must be an integer, got "many"`)
+		assert.Contains(t, err.Error(), `SHUTDOWN_TIMEOUT must be a duration such as 30s, got "10"`)
+		assert.Contains(t, err.Error(), "DEFAULT_PAGE_SIZE (pagination.default_page_size) 100 is over MAX_PAGE_SIZE")
+		assert.Contains(t, err.Error(), `TRAILING_SLASH (features.trailing_slash) "drop" must be redirect or strict`)
+	})
+
+	t.Run("unknown-driver", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv("DATABASE_DRIVER", "oracle")
+
+		_, err := config.Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown DATABASE_DRIVER "oracle"`)
+	})
+
+	t.Run("unknown-key", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv("CONFIG_FILE", writeFile(t, "database:\n  hots: db\n"))
+
+		_, err := config.Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "field hots not found")
+	})
+
+	t.Run("missing-file", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+
+		_, err := config.Load()
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+
+	sqliteRepo "apismrtbiz/internal/repository/sqlite"
+)
+
+// The database drivers selected by DATABASE_DRIVER, named after their database/sql driver
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMongo    = "mongodb"
+)
+
+// ConnConfig represent the connection to the database
+type ConnConfig struct {
+	Driver string
+	Host   string
+	Port   string
+	User   string
+	Pass   string
+	// Name is the database, for SQLite the path of its file
+	Name string
+	// SSLMode is the sslmode of Postgres, disable by default
+	SSLMode string
+}
+
+// DSN will build the data source name of the driver, an unknown driver is reported as an error
+func (c ConnConfig) DSN() (string, error) {
+	switch c.Driver {
+	case DriverMySQL:
+		connection := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", c.User, c.Pass, c.Host, c.Port, c.Name)
+		val := url.Values{}
+		val.Add("parseTime", "1")
+		val.Add("loc", "Asia/Jakarta")
+		// the migrations hold several statements
+		val.Add("multiStatements", "true")
+		return fmt.Sprintf("%s?%s", connection, val.Encode()), nil
+	case DriverPostgres:
+		sslMode := c.SSLMode
+		if sslMode == "" {
+			sslMode = "disable"
+		}
+		return (&url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(c.User, c.Pass),
+			Host:     net.JoinHostPort(c.Host, c.Port),
+			Path:     c.Name,
+			RawQuery: url.Values{"sslmode": {sslMode}}.Encode(),
+		}).String(), nil
+	case DriverSQLite:
+		// the other settings don't apply to the file
+		return sqliteRepo.DSN(c.Name), nil
+	case DriverMongo:
+		mongoURL := &url.URL{Scheme: "mongodb", Host: net.JoinHostPort(c.Host, c.Port)}
+		if c.User != "" {
+			mongoURL.User = url.UserPassword(c.User, c.Pass)
+		}
+		return mongoURL.String(), nil
+	}
+	return "", fmt.Errorf("unknown DATABASE_DRIVER %q, use %s, %s, %s or %s", c.Driver, DriverMySQL, DriverPostgres, DriverSQLite, DriverMongo)
+}
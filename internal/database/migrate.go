@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratedb "github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/mysql"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// Migrate will apply the pending migrations to the database of the database/sql driver, in order of their version.
+// The applied version is recorded in schema_migrations the way the migrate CLI does, so the migrations applied
+// by hand or on a previous start are skipped and running it again changes nothing.
+// A MySQL database must be opened with multiStatements, the migrations hold several statements.
+func Migrate(ctx context.Context, db *sql.DB, driver string, migrations fs.FS) (err error) {
+	src, err := iofs.New(migrations, ".")
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	var target migratedb.Driver
+	switch driver {
+	case DriverMySQL, DriverPostgres:
+		// the migrations run on a connection of the pool, handed back once they are done
+		conn, errConn := db.Conn(ctx)
+		if errConn != nil {
+			return errConn
+		}
+		if driver == DriverMySQL {
+			target, err = mysql.WithConnection(ctx, conn, &mysql.Config{})
+		} else {
+			target, err = postgres.WithConnection(ctx, conn, &postgres.Config{})
+		}
+		if err != nil {
+			conn.Close()
+			return err
+		}
+		defer target.Close()
+	case DriverSQLite:
+		// closing the driver would close the database, it holds no connection of its own
+		target, err = sqlite.WithInstance(db, &sqlite.Config{})
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("no migrations for the database driver %q", driver)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, driver, target)
+	if err != nil {
+		return err
+	}
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sqliteMigrations "apismrtbiz/database/sqlite/migrations"
+	"apismrtbiz/internal/database"
+	sqliteRepo "apismrtbiz/internal/repository/sqlite"
+)
+
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", sqliteRepo.DSN(":memory:"))
+	require.NoError(t, err)
+	// every connection would open its own in-memory database
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestMigrate(t *testing.T) {
+	db := openSQLite(t)
+	ctx := context.TODO()
+
+	require.NoError(t, database.Migrate(ctx, db, "sqlite", sqliteMigrations.FS))
+
+	var name string
+	err := db.QueryRow(`SELECT name FROM sqlite_master WHERE type = 'table' AND name = 'article'`).Scan(&name)
+	require.NoError(t, err)
+	assert.Equal(t, "article", name)
+
+	// the migrations are numbered from one, so the latest is their count
+	ups, err := fs.Glob(sqliteMigrations.FS, "*.up.sql")
+	require.NoError(t, err)
+	var version int
+	require.NoError(t, db.QueryRow(`SELECT version FROM schema_migrations`).Scan(&version))
+	assert.Equal(t, len(ups), version)
+
+	// the applied migrations are skipped on the next start
+	_, err = db.Exec(`INSERT INTO article (title, content) VALUES ('Hello', 'Content')`)
+	require.NoError(t, err)
+	require.NoError(t, database.Migrate(ctx, db, "sqlite", sqliteMigrations.FS))
+	var total int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM article`).Scan(&total))
+	assert.Equal(t, 1, total)
+}
+
+func TestMigratePending(t *testing.T) {
+	db := openSQLite(t)
+	ctx := context.TODO()
+
+	migrations := fstest.MapFS{
+		"000001_note.up.sql":   {Data: []byte(`CREATE TABLE note (id INTEGER PRIMARY KEY);`)},
+		"000001_note.down.sql": {Data: []byte(`DROP TABLE note;`)},
+	}
+	require.NoError(t, database.Migrate(ctx, db, "sqlite", migrations))
+
+	migrations["000002_note_body.up.sql"] = &fstest.MapFile{Data: []byte(`ALTER TABLE note ADD COLUMN body TEXT;`)}
+	migrations["000002_note_body.down.sql"] = &fstest.MapFile{Data: []byte(`ALTER TABLE note DROP COLUMN body;`)}
+	require.NoError(t, database.Migrate(ctx, db, "sqlite", migrations))
+
+	_, err := db.Exec(`INSERT INTO note (body) VALUES ('only the second migration was pending')`)
+	assert.NoError(t, err)
+}
+
+func TestMigrateFailure(t *testing.T) {
+	db := openSQLite(t)
+
+	migrations := fstest.MapFS{
+		"000001_broken.up.sql": {Data: []byte(`CREATE TABLE;`)},
+	}
+	assert.Error(t, database.Migrate(context.TODO(), db, "sqlite", migrations))
+	assert.Error(t, database.Migrate(context.TODO(), db, "oracle", migrations))
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// PoolConfig represent the connection pool of the database handle, a zero value keeps the database/sql default
+type PoolConfig struct {
+	// MaxOpenConns caps the connections open at once, in use or idle
+	MaxOpenConns int
+	// MaxIdleConns caps the idle connections kept for reuse
+	MaxIdleConns int
+	// ConnMaxLifetime closes the connections once they are that old, so the stale ones are replaced
+	ConnMaxLifetime time.Duration
+}
+
+// Validate will check the settings are non-negative
+func (c PoolConfig) Validate() error {
+	if c.MaxOpenConns < 0 {
+		return errors.New("max open connections must not be negative")
+	}
+	if c.MaxIdleConns < 0 {
+		return errors.New("max idle connections must not be negative")
+	}
+	if c.ConnMaxLifetime < 0 {
+		return errors.New("connection max lifetime must not be negative")
+	}
+	return nil
+}
+
+// Apply will set the pool of the database handle, leaving the default of the unset settings
+func (c PoolConfig) Apply(db *sql.DB) {
+	if c.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(c.MaxOpenConns)
+	}
+	if c.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(c.MaxIdleConns)
+	}
+	if c.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(c.ConnMaxLifetime)
+	}
+}
@@ -0,0 +1,56 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"apismrtbiz/internal/database"
+)
+
+func TestPoolConfigValidate(t *testing.T) {
+	assert.NoError(t, database.PoolConfig{}.Validate())
+	assert.NoError(t, database.PoolConfig{MaxOpenConns: 10, MaxIdleConns: 5, ConnMaxLifetime: time.Minute}.Validate())
+	assert.Error(t, database.PoolConfig{MaxOpenConns: -1}.Validate())
+	assert.Error(t, database.PoolConfig{MaxIdleConns: -1}.Validate())
+	assert.Error(t, database.PoolConfig{ConnMaxLifetime: -time.Second}.Validate())
+}
+
+func TestPoolConfigApply(t *testing.T) {
+	db, err := sql.Open("sqlite", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	database.PoolConfig{MaxOpenConns: 3, MaxIdleConns: 1, ConnMaxLifetime: time.Hour}.Apply(db)
+	assert.Equal(t, 3, db.Stats().MaxOpenConnections)
+
+	// hold every connection of the pool, then hand them back: only one is kept idle
+	ctx := context.TODO()
+	conns := make([]*sql.Conn, 3)
+	for i := range conns {
+		conns[i], err = db.Conn(ctx)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, db.Stats().OpenConnections)
+	for _, conn := range conns {
+		require.NoError(t, conn.Close())
+	}
+
+	stats := db.Stats()
+	assert.Equal(t, 1, stats.Idle)
+	assert.Equal(t, int64(2), stats.MaxIdleClosed)
+}
+
+func TestPoolConfigApplyDefaults(t *testing.T) {
+	db, err := sql.Open("sqlite", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	database.PoolConfig{}.Apply(db)
+	assert.Zero(t, db.Stats().MaxOpenConnections)
+}
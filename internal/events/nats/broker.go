@@ -0,0 +1,29 @@
+// Package nats publishes the events to a NATS server
+package nats
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Broker represent the NATS driver of the events
+type Broker struct {
+	conn *nats.Conn
+}
+
+// NewBroker will create a broker publishing on the connection
+func NewBroker(conn *nats.Conn) *Broker {
+	return &Broker{conn: conn}
+}
+
+// Publish will send the data on the subject, the message is buffered by the connection
+// and flushed in the background so that the write isn't held by the server
+func (b *Broker) Publish(_ context.Context, subject string, data []byte) error {
+	return b.conn.Publish(subject, data)
+}
+
+// Close will flush the buffered messages and close the connection
+func (b *Broker) Close() error {
+	return b.conn.Drain()
+}
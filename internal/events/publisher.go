@@ -0,0 +1,45 @@
+// Package events publishes the domain events of the articles to a message broker
+package events
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/sirupsen/logrus"
+
+	"apismrtbiz/domain"
+)
+
+// Broker represent the message queue the events are published to, see the nats package for a driver
+type Broker interface {
+	Publish(ctx context.Context, subject string, data []byte) error
+}
+
+// Publisher publishes every event as JSON on the subject named after its type, e.g. article.created
+type Publisher struct {
+	broker Broker
+}
+
+// NewPublisher will create a publisher of the events to the broker
+func NewPublisher(b Broker) *Publisher {
+	return &Publisher{broker: b}
+}
+
+// Notify will publish the event, publishing is best-effort so that a failure is logged
+// without failing the write which is already stored
+func (p *Publisher) Notify(ctx context.Context, ev domain.Event) {
+	data, err := json.Marshal(ev)
+	if err == nil {
+		err = p.broker.Publish(ctx, string(ev.Type), data)
+	}
+	if err != nil {
+		logrus.WithContext(ctx).Errorf("failed to publish the %s event of article %d: %s", ev.Type, ev.ArticleID, err)
+	}
+}
+
+// NopBroker drops every event, for the tests and when no broker is configured
+type NopBroker struct{}
+
+func (NopBroker) Publish(context.Context, string, []byte) error {
+	return nil
+}
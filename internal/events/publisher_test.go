@@ -0,0 +1,80 @@
+package events_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/article"
+	"apismrtbiz/article/mocks"
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/events"
+)
+
+type message struct {
+	subject string
+	data    []byte
+}
+
+// recordingBroker keeps the published messages, failing with err when given
+type recordingBroker struct {
+	messages []message
+	err      error
+}
+
+func (b *recordingBroker) Publish(_ context.Context, subject string, data []byte) error {
+	b.messages = append(b.messages, message{subject: subject, data: data})
+	return b.err
+}
+
+func newService(t *testing.T, broker events.Broker) *article.Service {
+	t.Helper()
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(domain.Article{}, domain.ErrNotFound).Once()
+	mockArticleRepo.On("GetBySlug", mock.Anything, mock.Anything).Return(domain.Article{}, domain.ErrNotFound)
+	mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).
+		Run(func(args mock.Arguments) {
+			ar := args.Get(1).(*domain.Article)
+			ar.ID = 7
+			ar.Version = 1
+		}).Return(nil).Once()
+	return article.NewService(mockArticleRepo, new(mocks.AuthorRepository), article.WithNotifier(events.NewPublisher(broker)))
+}
+
+func TestPublishAfterStore(t *testing.T) {
+	broker := new(recordingBroker)
+	svc := newService(t, broker)
+
+	err := svc.Store(context.TODO(), &domain.Article{Title: "Hello", Content: "Content"})
+	require.NoError(t, err)
+
+	require.Len(t, broker.messages, 1)
+	assert.Equal(t, "article.created", broker.messages[0].subject)
+	var ev domain.Event
+	require.NoError(t, json.Unmarshal(broker.messages[0].data, &ev))
+	assert.Equal(t, domain.EventArticleCreated, ev.Type)
+	assert.Equal(t, int64(7), ev.ArticleID)
+	assert.Equal(t, int64(1), ev.Version)
+	assert.False(t, ev.Timestamp.IsZero())
+}
+
+func TestPublishBestEffort(t *testing.T) {
+	broker := &recordingBroker{err: errors.New("nats: connection closed")}
+	svc := newService(t, broker)
+
+	err := svc.Store(context.TODO(), &domain.Article{Title: "Hello", Content: "Content"})
+	assert.NoError(t, err)
+	assert.Len(t, broker.messages, 1)
+}
+
+func TestNopBroker(t *testing.T) {
+	svc := newService(t, events.NopBroker{})
+
+	err := svc.Store(context.TODO(), &domain.Article{Title: "Hello", Content: "Content"})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,124 @@
+// Package graphql serves the article usecases on the /graphql endpoint, see schema.graphql
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	"github.com/sirupsen/logrus"
+
+	"apismrtbiz/domain"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// ArticleService represent the article's usecases served over GraphQL
+//
+//go:generate mockery --name ArticleService
+type ArticleService interface {
+	Fetch(ctx context.Context, cursor string, num int64, filter domain.ArticleFilter) ([]domain.Article, string, string, error)
+	GetByID(ctx context.Context, id int64) (domain.Article, error)
+	Store(context.Context, *domain.Article) error
+	Update(ctx context.Context, ar *domain.Article) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// Handler represent the httphandler of the GraphQL endpoint
+type Handler struct {
+	schema *graphqlgo.Schema
+}
+
+// request represent a GraphQL request, sent as the JSON body of a POST or the params of a GET
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// NewHandler will initialize the /graphql endpoint, the queries are served on GET and POST
+// while the mutations need a POST so that they go through the authentication of the writes
+func NewHandler(e *fiber.App, svc ArticleService) {
+	handler := &Handler{
+		schema: graphqlgo.MustParseSchema(schema, &resolver{svc: svc}),
+	}
+	e.Get("/graphql", handler.Serve)
+	e.Post("/graphql", handler.Serve)
+}
+
+// Serve will execute the GraphQL request, errors of the query are reported in the response body
+func (h *Handler) Serve(c *fiber.Ctx) error {
+	var req request
+	ctx := c.UserContext()
+	if c.Method() == http.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if raw := c.Query("variables"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &req.Variables); err != nil {
+				return c.Status(http.StatusBadRequest).JSON(errResponse("variables must be a JSON object"))
+			}
+		}
+		ctx = context.WithValue(ctx, readOnlyKey{}, true)
+	} else if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(errResponse(err.Error()))
+	}
+	if req.Query == "" {
+		return c.Status(http.StatusBadRequest).JSON(errResponse("query is required"))
+	}
+
+	return c.JSON(h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
+
+func errResponse(message string) fiber.Map {
+	return fiber.Map{"errors": []fiber.Map{{"message": message}}}
+}
+
+type readOnlyKey struct{}
+
+// requireMutation will refuse the mutations sent with a GET
+func requireMutation(ctx context.Context) error {
+	if readOnly, _ := ctx.Value(readOnlyKey{}).(bool); readOnly {
+		return &Error{Message: "mutations must be sent with POST", Code: "BAD_REQUEST"}
+	}
+	return nil
+}
+
+// Error represent an error of a resolver, the code is reported in the extensions of the error
+type Error struct {
+	Message string
+	Code    string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Extensions will be rendered along the message of the error
+func (e *Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.Code}
+}
+
+func badInput(message string) error {
+	return &Error{Message: message, Code: "BAD_USER_INPUT"}
+}
+
+// toError will translate the domain errors to an Error, the unexpected ones are logged
+// and reported as internal without leaking their message
+func toError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return &Error{Message: domain.ErrNotFound.Error(), Code: "NOT_FOUND"}
+	case errors.Is(err, domain.ErrConflict):
+		return &Error{Message: domain.ErrConflict.Error(), Code: "CONFLICT"}
+	case errors.Is(err, domain.ErrBadParamInput):
+		return badInput(domain.ErrBadParamInput.Error())
+	default:
+		logrus.WithContext(ctx).Error(err)
+		return &Error{Message: domain.ErrInternalServerError.Error(), Code: "INTERNAL"}
+	}
+}
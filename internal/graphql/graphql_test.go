@@ -0,0 +1,210 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/graphql"
+	"apismrtbiz/internal/graphql/mocks"
+	"apismrtbiz/internal/rest/middleware"
+)
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string            `json:"message"`
+		Extensions map[string]string `json:"extensions"`
+	} `json:"errors"`
+}
+
+func query(t *testing.T, app *fiber.App, body string) response {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	res, err := app.Test(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var out response
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&out))
+	return out
+}
+
+func TestArticle(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	app := fiber.New()
+	graphql.NewHandler(app, mockUCase)
+
+	t.Run("success", func(t *testing.T) {
+		mockUCase.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{
+			ID: 1, Title: "Hello", Content: "Content", Author: domain.Author{ID: 3, Name: "Iman"}, Status: domain.StatusPublished,
+		}, nil).Once()
+
+		res := query(t, app, `{"query": "{ article(id: 1) { id title author { name } tags } }"}`)
+		require.Empty(t, res.Errors)
+		assert.JSONEq(t, `{"article": {"id": "1", "title": "Hello", "author": {"name": "Iman"}, "tags": []}}`, string(res.Data))
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mockUCase.On("GetByID", mock.Anything, int64(404)).Return(domain.Article{}, domain.ErrNotFound).Once()
+
+		res := query(t, app, `{"query": "query($id: ID!) { article(id: $id) { title } }", "variables": {"id": "404"}}`)
+		require.Empty(t, res.Errors)
+		assert.JSONEq(t, `{"article": null}`, string(res.Data))
+	})
+
+	t.Run("invalid-id", func(t *testing.T) {
+		res := query(t, app, `{"query": "{ article(id: \"abc\") { title } }"}`)
+		require.Len(t, res.Errors, 1)
+		assert.Equal(t, "BAD_USER_INPUT", res.Errors[0].Extensions["code"])
+	})
+	mockUCase.AssertExpectations(t)
+}
+
+func TestArticleDraft(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("GetByID", mock.Anything, int64(2)).Return(domain.Article{ID: 2, Title: "Draft", Status: domain.StatusDraft}, nil).Twice()
+	app := fiber.New()
+	app.Use(middleware.APIKey(middleware.APIKeyConfig{Keys: map[string]string{"secret": "editor"}, Optional: true}))
+	graphql.NewHandler(app, mockUCase)
+
+	// the drafts are hidden from the public
+	res := query(t, app, `{"query": "{ article(id: 2) { title } }"}`)
+	require.Empty(t, res.Errors)
+	assert.JSONEq(t, `{"article": null}`, string(res.Data))
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query": "{ article(id: 2) { title } }"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(middleware.HeaderAPIKey, "secret")
+	raw, err := app.Test(req)
+	require.NoError(t, err)
+	var out response
+	require.NoError(t, json.NewDecoder(raw.Body).Decode(&out))
+	require.Empty(t, out.Errors)
+	assert.JSONEq(t, `{"article": {"title": "Draft"}}`, string(out.Data))
+	mockUCase.AssertExpectations(t)
+}
+
+func TestArticlesConnection(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	app := fiber.New()
+	graphql.NewHandler(app, mockUCase)
+
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	first := []domain.Article{{ID: 3, Title: "third", UpdatedAt: now}, {ID: 2, Title: "second", UpdatedAt: now.Add(-time.Minute)}}
+	last := []domain.Article{{ID: 1, Title: "first", UpdatedAt: now.Add(-2 * time.Minute)}}
+	filter := domain.ArticleFilter{Status: domain.StatusPublished}
+	endCursor := domain.NewCursor(domain.SortByUpdatedAt, first[1]).Encode()
+	mockUCase.On("Fetch", mock.Anything, "", int64(2), filter).Return(first, endCursor, "", nil).Once()
+	mockUCase.On("Fetch", mock.Anything, endCursor, int64(2), filter).Return(last, "", "prev", nil).Once()
+
+	const page = `query($cursor: String) { articles(cursor: $cursor, num: 2) {
+		edges { cursor node { title } }
+		pageInfo { hasNextPage hasPreviousPage endCursor }
+	} }`
+	type connection struct {
+		Articles struct {
+			Edges []struct {
+				Cursor string `json:"cursor"`
+				Node   struct {
+					Title string `json:"title"`
+				} `json:"node"`
+			} `json:"edges"`
+			PageInfo struct {
+				HasNextPage     bool    `json:"hasNextPage"`
+				HasPreviousPage bool    `json:"hasPreviousPage"`
+				EndCursor       *string `json:"endCursor"`
+			} `json:"pageInfo"`
+		} `json:"articles"`
+	}
+	fetch := func(cursor *string) connection {
+		body, err := json.Marshal(map[string]interface{}{"query": page, "variables": map[string]interface{}{"cursor": cursor}})
+		require.NoError(t, err)
+		res := query(t, app, string(body))
+		require.Empty(t, res.Errors)
+		var conn connection
+		require.NoError(t, json.Unmarshal(res.Data, &conn))
+		return conn
+	}
+
+	conn := fetch(nil)
+	require.Len(t, conn.Articles.Edges, 2)
+	assert.Equal(t, "third", conn.Articles.Edges[0].Node.Title)
+	assert.Equal(t, endCursor, conn.Articles.Edges[1].Cursor)
+	assert.True(t, conn.Articles.PageInfo.HasNextPage)
+	assert.False(t, conn.Articles.PageInfo.HasPreviousPage)
+	require.NotNil(t, conn.Articles.PageInfo.EndCursor)
+
+	conn = fetch(conn.Articles.PageInfo.EndCursor)
+	require.Len(t, conn.Articles.Edges, 1)
+	assert.Equal(t, "first", conn.Articles.Edges[0].Node.Title)
+	assert.False(t, conn.Articles.PageInfo.HasNextPage)
+	assert.True(t, conn.Articles.PageInfo.HasPreviousPage)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestMutations(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	app := fiber.New()
+	graphql.NewHandler(app, mockUCase)
+
+	t.Run("create", func(t *testing.T) {
+		mockUCase.On("Store", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool { return ar.Title == "Hello" })).
+			Run(func(args mock.Arguments) { args.Get(1).(*domain.Article).ID = 7 }).Return(nil).Once()
+
+		res := query(t, app, `{"query": "mutation { createArticle(input: {title: \"Hello\", content: \"Content\"}) { id } }"}`)
+		require.Empty(t, res.Errors)
+		assert.JSONEq(t, `{"createArticle": {"id": "7"}}`, string(res.Data))
+	})
+
+	t.Run("create-conflict", func(t *testing.T) {
+		mockUCase.On("Store", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool { return ar.Title == "Taken" })).
+			Return(domain.ErrConflict).Once()
+
+		res := query(t, app, `{"query": "mutation { createArticle(input: {title: \"Taken\", content: \"Content\"}) { id } }"}`)
+		require.Len(t, res.Errors, 1)
+		assert.Equal(t, "CONFLICT", res.Errors[0].Extensions["code"])
+	})
+
+	t.Run("update", func(t *testing.T) {
+		mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool { return ar.ID == 1 && ar.Version == 1 })).
+			Run(func(args mock.Arguments) { args.Get(1).(*domain.Article).Version++ }).Return(nil).Once()
+
+		res := query(t, app, `{"query": "mutation { updateArticle(id: 1, input: {title: \"Hello\", content: \"Content\", version: 1}) { version } }"}`)
+		require.Empty(t, res.Errors)
+		assert.JSONEq(t, `{"updateArticle": {"version": 2}}`, string(res.Data))
+	})
+
+	t.Run("delete-not-found", func(t *testing.T) {
+		mockUCase.On("Delete", mock.Anything, int64(404)).Return(domain.ErrNotFound).Once()
+
+		res := query(t, app, `{"query": "mutation { deleteArticle(id: 404) }"}`)
+		require.Len(t, res.Errors, 1)
+		assert.Equal(t, "NOT_FOUND", res.Errors[0].Extensions["code"])
+	})
+
+	t.Run("refused-on-get", func(t *testing.T) {
+		target := "/graphql?query=" + url.QueryEscape("mutation { deleteArticle(id: 1) }")
+		res, err := app.Test(httptest.NewRequest(http.MethodGet, target, nil))
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		var out response
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&out))
+		require.Len(t, out.Errors, 1)
+		assert.Equal(t, "BAD_REQUEST", out.Errors[0].Extensions["code"])
+	})
+	mockUCase.AssertExpectations(t)
+}
@@ -0,0 +1,311 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/rest/middleware"
+)
+
+const (
+	defaultNum = 10
+	maxNum     = 100
+)
+
+var validate = validator.New()
+
+// resolver is the root of the queries and the mutations
+type resolver struct {
+	svc ArticleService
+}
+
+func (r *resolver) Article(ctx context.Context, args struct{ ID graphqlgo.ID }) (*articleResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	ar, err := r.svc.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+	// the drafts are hidden from the public like over REST
+	if ar.Status != domain.StatusPublished && !isEditor(ctx) {
+		return nil, nil
+	}
+	return &articleResolver{ar}, nil
+}
+
+// isEditor will report whether the request is authenticated, by a bearer token or an api key
+func isEditor(ctx context.Context) bool {
+	if _, ok := middleware.UserIDFromContext(ctx); ok {
+		return true
+	}
+	_, ok := middleware.APIKeyLabelFromContext(ctx)
+	return ok
+}
+
+func (r *resolver) Articles(ctx context.Context, args struct {
+	Cursor *string
+	Num    *int32
+}) (*connectionResolver, error) {
+	num := int64(defaultNum)
+	if args.Num != nil {
+		num = int64(*args.Num)
+	}
+	switch {
+	case num <= 0:
+		return nil, badInput("num must be a positive integer")
+	case num > maxNum:
+		num = maxNum
+	}
+	var cursor string
+	if args.Cursor != nil {
+		cursor = *args.Cursor
+	}
+
+	filter := domain.ArticleFilter{Status: domain.StatusPublished}
+	list, nextCursor, prevCursor, err := r.svc.Fetch(ctx, cursor, num, filter)
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+	return &connectionResolver{list: list, sort: filter.Sort.Field, nextCursor: nextCursor, prevCursor: prevCursor}, nil
+}
+
+type createArticleInput struct {
+	Title     string
+	Content   string
+	AuthorID  *graphqlgo.ID
+	Status    *string
+	PublishAt *graphqlgo.Time
+	Tags      *[]string
+}
+
+func (r *resolver) CreateArticle(ctx context.Context, args struct{ Input createArticleInput }) (*articleResolver, error) {
+	if err := requireMutation(ctx); err != nil {
+		return nil, err
+	}
+	in := args.Input
+	ar := domain.Article{Title: in.Title, Content: in.Content}
+	if in.AuthorID != nil {
+		id, err := parseID(*in.AuthorID)
+		if err != nil {
+			return nil, err
+		}
+		ar.Author.ID = id
+	}
+	if in.Status != nil {
+		ar.Status = domain.Status(*in.Status)
+		if !ar.Status.Valid() {
+			return nil, badInput("status must be one of draft, published")
+		}
+	}
+	if in.PublishAt != nil {
+		ar.PublishAt = &in.PublishAt.Time
+	}
+	if in.Tags != nil {
+		ar.Tags = *in.Tags
+	}
+	if err := validate.Struct(ar); err != nil {
+		return nil, badInput(err.Error())
+	}
+
+	if err := r.svc.Store(ctx, &ar); err != nil {
+		return nil, toError(ctx, err)
+	}
+	return &articleResolver{ar}, nil
+}
+
+type updateArticleInput struct {
+	Title   string
+	Content string
+	Tags    *[]string
+	Version int32
+}
+
+func (r *resolver) UpdateArticle(ctx context.Context, args struct {
+	ID    graphqlgo.ID
+	Input updateArticleInput
+}) (*articleResolver, error) {
+	if err := requireMutation(ctx); err != nil {
+		return nil, err
+	}
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	ar := domain.Article{ID: id, Title: args.Input.Title, Content: args.Input.Content, Version: int64(args.Input.Version)}
+	if args.Input.Tags != nil {
+		ar.Tags = *args.Input.Tags
+	}
+	if err = validate.Struct(ar); err != nil {
+		return nil, badInput(err.Error())
+	}
+	if ar.Version <= 0 {
+		return nil, badInput("version must be a positive integer")
+	}
+
+	if err = r.svc.Update(ctx, &ar); err != nil {
+		return nil, toError(ctx, err)
+	}
+	return &articleResolver{ar}, nil
+}
+
+func (r *resolver) DeleteArticle(ctx context.Context, args struct{ ID graphqlgo.ID }) (bool, error) {
+	if err := requireMutation(ctx); err != nil {
+		return false, err
+	}
+	id, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+	if err = r.svc.Delete(ctx, id); err != nil {
+		return false, toError(ctx, err)
+	}
+	return true, nil
+}
+
+type articleResolver struct {
+	ar domain.Article
+}
+
+func (r *articleResolver) ID() graphqlgo.ID {
+	return formatID(r.ar.ID)
+}
+
+func (r *articleResolver) Title() string {
+	return r.ar.Title
+}
+
+func (r *articleResolver) Content() string {
+	return r.ar.Content
+}
+
+func (r *articleResolver) Author() *authorResolver {
+	return &authorResolver{r.ar.Author}
+}
+
+func (r *articleResolver) Status() string {
+	return string(r.ar.Status)
+}
+
+func (r *articleResolver) Tags() []string {
+	if r.ar.Tags == nil {
+		return []string{}
+	}
+	return r.ar.Tags
+}
+
+func (r *articleResolver) Version() int32 {
+	return int32(r.ar.Version)
+}
+
+func (r *articleResolver) ViewCount() int32 {
+	return int32(r.ar.ViewCount)
+}
+
+func (r *articleResolver) CreatedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: r.ar.CreatedAt}
+}
+
+func (r *articleResolver) UpdatedAt() graphqlgo.Time {
+	return graphqlgo.Time{Time: r.ar.UpdatedAt}
+}
+
+func (r *articleResolver) PublishAt() *graphqlgo.Time {
+	if r.ar.PublishAt == nil {
+		return nil
+	}
+	return &graphqlgo.Time{Time: *r.ar.PublishAt}
+}
+
+type authorResolver struct {
+	author domain.Author
+}
+
+func (r *authorResolver) ID() graphqlgo.ID {
+	return formatID(r.author.ID)
+}
+
+func (r *authorResolver) Name() string {
+	return r.author.Name
+}
+
+// connectionResolver represent a page of articles as a Relay connection
+type connectionResolver struct {
+	list       []domain.Article
+	sort       domain.SortField
+	nextCursor string
+	prevCursor string
+}
+
+func (r *connectionResolver) Edges() []*edgeResolver {
+	edges := make([]*edgeResolver, len(r.list))
+	for i, ar := range r.list {
+		edges[i] = &edgeResolver{cursor: domain.NewCursor(r.sort, ar).Encode(), ar: ar}
+	}
+	return edges
+}
+
+func (r *connectionResolver) PageInfo() *pageInfoResolver {
+	info := &pageInfoResolver{hasNext: r.nextCursor != "", hasPrev: r.prevCursor != ""}
+	if len(r.list) > 0 {
+		start := domain.NewCursor(r.sort, r.list[0]).Encode()
+		end := domain.NewCursor(r.sort, r.list[len(r.list)-1]).Encode()
+		info.start, info.end = &start, &end
+	}
+	return info
+}
+
+type edgeResolver struct {
+	cursor string
+	ar     domain.Article
+}
+
+func (r *edgeResolver) Cursor() string {
+	return r.cursor
+}
+
+func (r *edgeResolver) Node() *articleResolver {
+	return &articleResolver{r.ar}
+}
+
+type pageInfoResolver struct {
+	hasNext, hasPrev bool
+	start, end       *string
+}
+
+func (r *pageInfoResolver) HasNextPage() bool {
+	return r.hasNext
+}
+
+func (r *pageInfoResolver) HasPreviousPage() bool {
+	return r.hasPrev
+}
+
+func (r *pageInfoResolver) StartCursor() *string {
+	return r.start
+}
+
+func (r *pageInfoResolver) EndCursor() *string {
+	return r.end
+}
+
+func parseID(id graphqlgo.ID) (int64, error) {
+	n, err := strconv.ParseInt(string(id), 10, 64)
+	if err != nil || n <= 0 {
+		return 0, badInput("id must be a positive integer")
+	}
+	return n, nil
+}
+
+func formatID(id int64) graphqlgo.ID {
+	return graphqlgo.ID(strconv.FormatInt(id, 10))
+}
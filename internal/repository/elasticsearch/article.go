@@ -0,0 +1,268 @@
+// Package elasticsearch indexes the articles in Elasticsearch and serves their full text search
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"apismrtbiz/domain"
+)
+
+const defaultIndex = "articles"
+
+// Config represent the settings of the search repository
+type Config struct {
+	// URL is the address of the Elasticsearch node, e.g. http://127.0.0.1:9200
+	URL string
+	// Index is the name of the index of the articles, defaults to "articles"
+	Index string
+}
+
+// ArticleRepository talks to the Elasticsearch REST API, it represent the article.SearchRepository interface
+type ArticleRepository struct {
+	client *http.Client
+	url    string
+	index  string
+}
+
+// NewArticleRepository will create an object that represent the article.SearchRepository interface
+func NewArticleRepository(client *http.Client, cfg Config) *ArticleRepository {
+	if cfg.Index == "" {
+		cfg.Index = defaultIndex
+	}
+	return &ArticleRepository{
+		client: client,
+		url:    strings.TrimRight(cfg.URL, "/"),
+		index:  cfg.Index,
+	}
+}
+
+// document is an article as it is indexed, the author is kept by its id only
+type document struct {
+	ID         int64         `json:"id"`
+	Title      string        `json:"title"`
+	Slug       string        `json:"slug"`
+	Content    string        `json:"content"`
+	AuthorID   int64         `json:"author_id"`
+	Status     domain.Status `json:"status"`
+	Tags       []string      `json:"tags,omitempty"`
+	CategoryID int64         `json:"category_id,omitempty"`
+	Version    int64         `json:"version"`
+	ViewCount  int64         `json:"view_count"`
+	PublishAt  *time.Time    `json:"publish_at,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+func newDocument(ar domain.Article) document {
+	return document{
+		ID:         ar.ID,
+		Title:      ar.Title,
+		Slug:       ar.Slug,
+		Content:    ar.Content,
+		AuthorID:   ar.Author.ID,
+		Status:     ar.Status,
+		Tags:       ar.Tags,
+		CategoryID: ar.CategoryID,
+		Version:    ar.Version,
+		ViewCount:  ar.ViewCount,
+		PublishAt:  ar.PublishAt,
+		CreatedAt:  ar.CreatedAt,
+		UpdatedAt:  ar.UpdatedAt,
+	}
+}
+
+func (d document) article() domain.Article {
+	return domain.Article{
+		ID:         d.ID,
+		Title:      d.Title,
+		Slug:       d.Slug,
+		Content:    d.Content,
+		Author:     domain.Author{ID: d.AuthorID},
+		Status:     d.Status,
+		Tags:       d.Tags,
+		CategoryID: d.CategoryID,
+		Version:    d.Version,
+		ViewCount:  d.ViewCount,
+		PublishAt:  d.PublishAt,
+		CreatedAt:  d.CreatedAt,
+		UpdatedAt:  d.UpdatedAt,
+	}
+}
+
+// mapping analyzes the title and the content as full text, the other fields are kept as they are
+var mapping = map[string]interface{}{
+	"mappings": map[string]interface{}{
+		"properties": map[string]interface{}{
+			"id":          map[string]string{"type": "long"},
+			"title":       map[string]string{"type": "text"},
+			"slug":        map[string]string{"type": "keyword"},
+			"content":     map[string]string{"type": "text"},
+			"author_id":   map[string]string{"type": "long"},
+			"status":      map[string]string{"type": "keyword"},
+			"tags":        map[string]string{"type": "keyword"},
+			"category_id": map[string]string{"type": "long"},
+			"version":     map[string]string{"type": "long"},
+			"view_count":  map[string]string{"type": "long"},
+			"publish_at":  map[string]string{"type": "date"},
+			"created_at":  map[string]string{"type": "date"},
+			"updated_at":  map[string]string{"type": "date"},
+		},
+	},
+}
+
+// EnsureIndex will create the index of the articles with its mapping unless it exists
+func (m *ArticleRepository) EnsureIndex(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.EnsureIndex", "indices.create")
+	defer func() { endSpan(span, err) }()
+
+	status, err := m.do(ctx, http.MethodHead, "/"+m.index, nil, nil)
+	if status != http.StatusNotFound {
+		return err
+	}
+	_, err = m.do(ctx, http.MethodPut, "/"+m.index, mapping, nil)
+	return err
+}
+
+// Index will store the article in the index, replacing its previous version
+func (m *ArticleRepository) Index(ctx context.Context, ar domain.Article) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Index", "index")
+	defer func() { endSpan(span, err) }()
+
+	_, err = m.do(ctx, http.MethodPut, m.docPath(ar.ID), newDocument(ar), nil)
+	return err
+}
+
+// Remove will drop the article from the index, an article which isn't indexed is ignored
+func (m *ArticleRepository) Remove(ctx context.Context, id int64) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Remove", "delete")
+	defer func() { endSpan(span, err) }()
+
+	status, err := m.do(ctx, http.MethodDelete, m.docPath(id), nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+type searchResponse struct {
+	Hits struct {
+		Hits []struct {
+			Score     *float64            `json:"_score"`
+			Source    document            `json:"_source"`
+			Highlight map[string][]string `json:"highlight"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// Search will list the articles at the status matching the query, the most relevant first, starting after the cursor.
+// The value of the cursor is the relevance score of the last article of the previous page, an empty status keeps every stage.
+func (m *ArticleRepository) Search(ctx context.Context, query string, num int64, cursor domain.Cursor, status domain.Status) (res []domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Search", "search")
+	defer func() { endSpan(span, err) }()
+
+	match := map[string]interface{}{
+		"must": map[string]interface{}{
+			"multi_match": map[string]interface{}{"query": query, "fields": []string{"title^2", "content"}},
+		},
+	}
+	if status != "" {
+		// a filter doesn't count in the score, so the ranking is the same whatever the status
+		match["filter"] = map[string]interface{}{"term": map[string]interface{}{"status": status}}
+	}
+	body := map[string]interface{}{
+		"size":  num,
+		"query": map[string]interface{}{"bool": match},
+		"sort": []interface{}{
+			map[string]string{"_score": "desc"},
+			map[string]string{"id": "desc"},
+		},
+		"highlight": map[string]interface{}{
+			"fields": map[string]interface{}{
+				"title":   map[string]int{"number_of_fragments": 0},
+				"content": map[string]int{"fragment_size": 150, "number_of_fragments": 1},
+			},
+		},
+	}
+	if !cursor.IsZero() {
+		score, errCursor := cursor.Float()
+		if errCursor != nil {
+			return nil, errCursor
+		}
+		body["search_after"] = []interface{}{score, cursor.ID}
+	}
+
+	var found searchResponse
+	if _, err = m.do(ctx, http.MethodPost, "/"+m.index+"/_search", body, &found); err != nil {
+		return nil, err
+	}
+
+	res = make([]domain.Article, 0, len(found.Hits.Hits))
+	for _, hit := range found.Hits.Hits {
+		ar := hit.Source.article()
+		ar.SearchScore = hit.Score
+		for _, field := range []string{"title", "content"} {
+			if fragments := hit.Highlight[field]; len(fragments) > 0 {
+				ar.Highlight = fragments[0]
+				break
+			}
+		}
+		res = append(res, ar)
+	}
+	return res, nil
+}
+
+// Ping will check that the Elasticsearch node answers
+func (m *ArticleRepository) Ping(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Ping", "ping")
+	defer func() { endSpan(span, err) }()
+
+	_, err = m.do(ctx, http.MethodGet, "/", nil, nil)
+	return err
+}
+
+func (m *ArticleRepository) docPath(id int64) string {
+	return "/" + m.index + "/_doc/" + strconv.FormatInt(id, 10)
+}
+
+// do will send the request with the JSON body and decode the response into out,
+// the status is zero when no response was received and an error status is returned as an error
+func (m *ArticleRepository) do(ctx context.Context, method, path string, body, out interface{}) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		byt, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(byt)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, m.url+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := m.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusMultipleChoices {
+		reason, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return res.StatusCode, fmt.Errorf("elasticsearch %s %s: unexpected status %d %s", method, path, res.StatusCode, bytes.TrimSpace(reason))
+	}
+	if out == nil {
+		return res.StatusCode, nil
+	}
+	return res.StatusCode, json.NewDecoder(res.Body).Decode(out)
+}
@@ -0,0 +1,84 @@
+//go:build integration
+
+package elasticsearch_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/domain"
+	esRepo "apismrtbiz/internal/repository/elasticsearch"
+)
+
+// openTestIndex will create an empty index on the Elasticsearch node of ELASTICSEARCH_TEST_URL, e.g.
+// ELASTICSEARCH_TEST_URL=http://127.0.0.1:9200 go test -tags integration ./internal/repository/elasticsearch/...
+func openTestIndex(t *testing.T) (*esRepo.ArticleRepository, func()) {
+	t.Helper()
+	url := os.Getenv("ELASTICSEARCH_TEST_URL")
+	if url == "" {
+		t.Skip("ELASTICSEARCH_TEST_URL is not set")
+	}
+
+	index := "articles_test_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	repo := esRepo.NewArticleRepository(http.DefaultClient, esRepo.Config{URL: url, Index: index})
+	require.NoError(t, repo.EnsureIndex(context.TODO()))
+	t.Cleanup(func() { send(t, http.MethodDelete, url+"/"+index) })
+
+	// the indexed documents are searchable once the index is refreshed
+	return repo, func() { send(t, http.MethodPost, url+"/"+index+"/_refresh") }
+}
+
+func send(t *testing.T, method, url string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, nil)
+	require.NoError(t, err)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+}
+
+func TestIntegrationSearch(t *testing.T) {
+	repo, refresh := openTestIndex(t)
+	ctx := context.TODO()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, repo.Ping(ctx))
+	for _, ar := range []domain.Article{
+		{ID: 1, Title: "Cooking pasta", Content: "Boil the water and add the generics", Author: domain.Author{ID: 2}, UpdatedAt: now, CreatedAt: now},
+		{ID: 2, Title: "Go generics", Content: "Type parameters in Go", Author: domain.Author{ID: 2}, UpdatedAt: now, CreatedAt: now},
+		{ID: 3, Title: "Gardening", Content: "Tomatoes", Author: domain.Author{ID: 2}, UpdatedAt: now, CreatedAt: now},
+	} {
+		require.NoError(t, repo.Index(ctx, ar))
+	}
+	refresh()
+
+	list, err := repo.Search(ctx, "generics", 10, domain.Cursor{}, "")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, int64(2), list[0].ID, "a match in the title ranks first")
+	assert.Equal(t, "Go <em>generics</em>", list[0].Highlight)
+	assert.Equal(t, now, list[0].UpdatedAt)
+	require.NotNil(t, list[0].SearchScore)
+
+	next := domain.Cursor{ID: list[0].ID, Value: strconv.FormatFloat(*list[0].SearchScore, 'g', -1, 64)}
+	page, err := repo.Search(ctx, "generics", 10, next, "")
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, int64(1), page[0].ID)
+
+	require.NoError(t, repo.Remove(ctx, 2))
+	require.NoError(t, repo.Remove(ctx, 2))
+	refresh()
+
+	list, err = repo.Search(ctx, "generics", 10, domain.Cursor{}, "")
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, int64(1), list[0].ID)
+}
@@ -0,0 +1,176 @@
+package elasticsearch_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/domain"
+	esRepo "apismrtbiz/internal/repository/elasticsearch"
+)
+
+// request is what the fake node received
+type request struct {
+	Method string
+	Path   string
+	Body   map[string]interface{}
+}
+
+// fakeNode will answer every request with the status and the body of respond, recording the requests
+func fakeNode(t *testing.T, respond func(r *http.Request) (int, string)) (*esRepo.ArticleRepository, func() []request) {
+	t.Helper()
+	var (
+		mu       sync.Mutex
+		received []request
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{Method: r.Method, Path: r.URL.Path}
+		if r.ContentLength > 0 {
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req.Body))
+		}
+		mu.Lock()
+		received = append(received, req)
+		mu.Unlock()
+
+		status, body := respond(r)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	repo := esRepo.NewArticleRepository(srv.Client(), esRepo.Config{URL: srv.URL + "/"})
+	return repo, func() []request {
+		mu.Lock()
+		defer mu.Unlock()
+		return received
+	}
+}
+
+func TestSearch(t *testing.T) {
+	const hits = `{"hits": {"hits": [
+		{"_score": 2.5, "_source": {"id": 3, "title": "Go generics", "content": "Type parameters", "author_id": 2, "status": "published"},
+		 "highlight": {"content": ["Type <em>parameters</em>"]}},
+		{"_score": 1.25, "_source": {"id": 1, "title": "Generics in Go", "content": "Content", "author_id": 2},
+		 "highlight": {"title": ["<em>Generics</em> in Go"], "content": ["Content"]}}
+	]}}`
+
+	t.Run("ranked", func(t *testing.T) {
+		repo, received := fakeNode(t, func(*http.Request) (int, string) { return http.StatusOK, hits })
+
+		list, err := repo.Search(context.TODO(), "generics", 2, domain.Cursor{}, domain.StatusPublished)
+
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, int64(3), list[0].ID)
+		assert.Equal(t, domain.Author{ID: 2}, list[0].Author)
+		assert.Equal(t, domain.StatusPublished, list[0].Status)
+		require.NotNil(t, list[0].SearchScore)
+		assert.Equal(t, 2.5, *list[0].SearchScore)
+		assert.Equal(t, "Type <em>parameters</em>", list[0].Highlight)
+		assert.Equal(t, "<em>Generics</em> in Go", list[1].Highlight)
+
+		reqs := received()
+		require.Len(t, reqs, 1)
+		assert.Equal(t, http.MethodPost, reqs[0].Method)
+		assert.Equal(t, "/articles/_search", reqs[0].Path)
+		assert.Equal(t, float64(2), reqs[0].Body["size"])
+		match := reqs[0].Body["query"].(map[string]interface{})["bool"].(map[string]interface{})
+		assert.Equal(t, "generics", match["must"].(map[string]interface{})["multi_match"].(map[string]interface{})["query"])
+		assert.Equal(t, map[string]interface{}{"term": map[string]interface{}{"status": "published"}}, match["filter"])
+		assert.NotContains(t, reqs[0].Body, "search_after")
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		repo, received := fakeNode(t, func(*http.Request) (int, string) { return http.StatusOK, `{"hits": {"hits": []}}` })
+
+		list, err := repo.Search(context.TODO(), "generics", 2, domain.Cursor{ID: 1, Value: "1.25"}, "")
+
+		require.NoError(t, err)
+		assert.NotNil(t, list)
+		assert.Empty(t, list)
+		assert.Equal(t, []interface{}{1.25, float64(1)}, received()[0].Body["search_after"])
+	})
+
+	t.Run("bad-cursor", func(t *testing.T) {
+		repo, received := fakeNode(t, func(*http.Request) (int, string) { return http.StatusOK, hits })
+
+		_, err := repo.Search(context.TODO(), "generics", 2, domain.Cursor{ID: 1, Value: "2024-01-01T00:00:00Z"}, "")
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Empty(t, received())
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		repo, _ := fakeNode(t, func(*http.Request) (int, string) {
+			return http.StatusServiceUnavailable, `{"error": "cluster_block_exception"}`
+		})
+
+		_, err := repo.Search(context.TODO(), "generics", 2, domain.Cursor{}, "")
+
+		assert.ErrorContains(t, err, "unexpected status 503")
+	})
+}
+
+func TestIndex(t *testing.T) {
+	repo, received := fakeNode(t, func(*http.Request) (int, string) { return http.StatusCreated, `{"result": "created"}` })
+
+	err := repo.Index(context.TODO(), domain.Article{ID: 7, Title: "Hello", Content: "Content", Author: domain.Author{ID: 2, Name: "Iman"}, Tags: []string{"go"}})
+
+	require.NoError(t, err)
+	reqs := received()
+	require.Len(t, reqs, 1)
+	assert.Equal(t, http.MethodPut, reqs[0].Method)
+	assert.Equal(t, "/articles/_doc/7", reqs[0].Path)
+	assert.Equal(t, "Hello", reqs[0].Body["title"])
+	assert.Equal(t, float64(2), reqs[0].Body["author_id"])
+	assert.Equal(t, []interface{}{"go"}, reqs[0].Body["tags"])
+	assert.NotContains(t, reqs[0].Body, "author")
+}
+
+func TestRemove(t *testing.T) {
+	t.Run("removed", func(t *testing.T) {
+		repo, received := fakeNode(t, func(*http.Request) (int, string) { return http.StatusOK, `{"result": "deleted"}` })
+
+		require.NoError(t, repo.Remove(context.TODO(), 7))
+		assert.Equal(t, request{Method: http.MethodDelete, Path: "/articles/_doc/7"}, received()[0])
+	})
+
+	t.Run("not-indexed", func(t *testing.T) {
+		repo, _ := fakeNode(t, func(*http.Request) (int, string) { return http.StatusNotFound, `{"result": "not_found"}` })
+
+		assert.NoError(t, repo.Remove(context.TODO(), 7))
+	})
+}
+
+func TestEnsureIndex(t *testing.T) {
+	t.Run("exists", func(t *testing.T) {
+		repo, received := fakeNode(t, func(*http.Request) (int, string) { return http.StatusOK, "" })
+
+		require.NoError(t, repo.EnsureIndex(context.TODO()))
+		assert.Len(t, received(), 1)
+	})
+
+	t.Run("created", func(t *testing.T) {
+		repo, received := fakeNode(t, func(r *http.Request) (int, string) {
+			if r.Method == http.MethodHead {
+				return http.StatusNotFound, ""
+			}
+			return http.StatusOK, `{"acknowledged": true}`
+		})
+
+		require.NoError(t, repo.EnsureIndex(context.TODO()))
+		reqs := received()
+		require.Len(t, reqs, 2)
+		assert.Equal(t, http.MethodPut, reqs[1].Method)
+		assert.Equal(t, "/articles", reqs[1].Path)
+		assert.Contains(t, reqs[1].Body, "mappings")
+	})
+}
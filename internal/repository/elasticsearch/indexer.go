@@ -0,0 +1,99 @@
+package elasticsearch
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"apismrtbiz/domain"
+)
+
+const defaultQueueSize = 100
+
+// ArticleGetter reads back the notified articles from the database
+type ArticleGetter interface {
+	GetByID(ctx context.Context, id int64) (domain.Article, error)
+}
+
+// Indexer keeps the index in step with the database, it represent the article.Notifier interface.
+// Every notified article is read back and indexed, or removed from the index once it isn't found anymore.
+type Indexer struct {
+	repo     *ArticleRepository
+	articles ArticleGetter
+	queue    chan queued
+	done     chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// queued is an event waiting to be indexed, along the context it was notified with
+type queued struct {
+	ctx context.Context
+	ev  domain.Event
+}
+
+// NewIndexer will create an indexer writing to repo the articles read from articles and start its indexing,
+// queueSize is how many events wait to be indexed before the new ones are dropped, defaultQueueSize when zero
+func NewIndexer(repo *ArticleRepository, articles ArticleGetter, queueSize int) *Indexer {
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	i := &Indexer{
+		repo:     repo,
+		articles: articles,
+		queue:    make(chan queued, queueSize),
+		done:     make(chan struct{}),
+	}
+	go i.run()
+	return i
+}
+
+// Notify will queue the event to be indexed, the event is dropped when the queue is full
+// or the indexer is closed. The indexing outlives the context, keeping its values only.
+func (i *Indexer) Notify(ctx context.Context, ev domain.Event) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if i.closed {
+		return
+	}
+	select {
+	case i.queue <- queued{ctx: context.WithoutCancel(ctx), ev: ev}:
+	default:
+		logrus.WithContext(ctx).Warnf("search index queue is full, dropping the %s event of article %d", ev.Type, ev.ArticleID)
+	}
+}
+
+// Close will stop accepting the events and wait for the queued ones to be indexed
+func (i *Indexer) Close() error {
+	i.mu.Lock()
+	if !i.closed {
+		i.closed = true
+		close(i.queue)
+	}
+	i.mu.Unlock()
+	<-i.done
+	return nil
+}
+
+func (i *Indexer) run() {
+	defer close(i.done)
+	for q := range i.queue {
+		if err := i.index(q.ctx, q.ev.ArticleID); err != nil {
+			logrus.WithContext(q.ctx).Errorf("search index of article %d: %s", q.ev.ArticleID, err)
+		}
+	}
+}
+
+func (i *Indexer) index(ctx context.Context, id int64) error {
+	ar, err := i.articles.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return i.repo.Remove(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+	return i.repo.Index(ctx, ar)
+}
@@ -0,0 +1,41 @@
+package elasticsearch_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/domain"
+	esRepo "apismrtbiz/internal/repository/elasticsearch"
+)
+
+// articles is an in memory esRepo.ArticleGetter
+type articles map[int64]domain.Article
+
+func (a articles) GetByID(_ context.Context, id int64) (domain.Article, error) {
+	ar, ok := a[id]
+	if !ok {
+		return domain.Article{}, domain.ErrNotFound
+	}
+	return ar, nil
+}
+
+func TestIndexer(t *testing.T) {
+	repo, received := fakeNode(t, func(*http.Request) (int, string) { return http.StatusOK, `{}` })
+	indexer := esRepo.NewIndexer(repo, articles{7: {ID: 7, Title: "Hello", Content: "Content"}}, 0)
+
+	indexer.Notify(context.TODO(), domain.Event{Type: domain.EventArticleUpdated, ArticleID: 7})
+	indexer.Notify(context.TODO(), domain.Event{Type: domain.EventArticleDeleted, ArticleID: 8})
+	require.NoError(t, indexer.Close())
+	indexer.Notify(context.TODO(), domain.Event{Type: domain.EventArticleCreated, ArticleID: 7})
+
+	reqs := received()
+	require.Len(t, reqs, 2)
+	assert.Equal(t, http.MethodPut, reqs[0].Method)
+	assert.Equal(t, "/articles/_doc/7", reqs[0].Path)
+	assert.Equal(t, "Hello", reqs[0].Body["title"])
+	assert.Equal(t, request{Method: http.MethodDelete, Path: "/articles/_doc/8"}, reqs[1])
+}
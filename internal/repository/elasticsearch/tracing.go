@@ -0,0 +1,32 @@
+package elasticsearch
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("apismrtbiz/internal/repository/elasticsearch")
+
+// startSpan will start the client span of a request, annotated with its operation
+func startSpan(ctx context.Context, name, operation string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "elasticsearch"),
+			attribute.String("db.operation", operation),
+		),
+	)
+}
+
+// endSpan will end the span, marking it as failed when the request returned an error
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,252 @@
+package lru
+
+import (
+	"container/list"
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"apismrtbiz/article"
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/repository/replica"
+)
+
+const defaultCapacity = 1000
+
+type entry struct {
+	key     string
+	article domain.Article
+}
+
+// ArticleRepository caches the articles of the wrapped repository in a size bounded
+// least recently used cache, every method which isn't overridden goes straight to the wrapped repository.
+// The misses are read from the primary, so that the rows of a lagging replica aren't cached.
+type ArticleRepository struct {
+	article.ArticleRepository
+
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+// NewArticleRepository will create an object that represent the article.ArticleRepository interface,
+// a capacity below one falls back to the default capacity
+func NewArticleRepository(repo article.ArticleRepository, capacity int) *ArticleRepository {
+	if capacity < 1 {
+		capacity = defaultCapacity
+	}
+	return &ArticleRepository{
+		ArticleRepository: repo,
+		capacity:          capacity,
+		ll:                list.New(),
+		items:             make(map[string]*list.Element),
+	}
+}
+
+func idKey(id int64) string {
+	return "id:" + strconv.FormatInt(id, 10)
+}
+
+// titleKey follows the title matching of the repository, which ignores case and surrounding whitespace
+func titleKey(title string) string {
+	return "title:" + strings.ToLower(strings.TrimSpace(title))
+}
+
+func (m *ArticleRepository) get(key string) (domain.Article, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.items[key]
+	if !ok {
+		return domain.Article{}, false
+	}
+	m.ll.MoveToFront(el)
+	return el.Value.(*entry).article, true
+}
+
+func (m *ArticleRepository) add(key string, ar domain.Article) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.items[key]; ok {
+		el.Value.(*entry).article = ar
+		m.ll.MoveToFront(el)
+		return
+	}
+
+	m.items[key] = m.ll.PushFront(&entry{key: key, article: ar})
+	if m.ll.Len() > m.capacity {
+		m.removeElement(m.ll.Back())
+	}
+}
+
+func (m *ArticleRepository) removeElement(el *list.Element) {
+	m.ll.Remove(el)
+	delete(m.items, el.Value.(*entry).key)
+}
+
+// invalidate will drop every entry of the given article, whatever key it was cached under
+func (m *ArticleRepository) invalidate(id int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for el := m.ll.Front(); el != nil; {
+		next := el.Next()
+		if el.Value.(*entry).article.ID == id {
+			m.removeElement(el)
+		}
+		el = next
+	}
+}
+
+func (m *ArticleRepository) removeKey(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.items[key]; ok {
+		m.removeElement(el)
+	}
+}
+
+// txKey is the context key of the invalidations of a transaction, they are run once it ended
+// so that a reader doesn't cache the previous row again before the commit
+type txKey struct{}
+
+type pending struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+// afterCommit will run the invalidation once the transaction of the context ended, at once outside of one
+func afterCommit(ctx context.Context, fn func()) {
+	p, ok := ctx.Value(txKey{}).(*pending)
+	if !ok {
+		fn()
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fns = append(p.fns, fn)
+}
+
+func inTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*pending)
+	return ok
+}
+
+// WithinTransaction will run the invalidations of the writes of fn once the transaction ended,
+// a rolled back one included since an extra miss is harmless
+func (m *ArticleRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if inTransaction(ctx) {
+		return m.ArticleRepository.WithinTransaction(ctx, fn)
+	}
+	p := &pending{}
+	err := m.ArticleRepository.WithinTransaction(context.WithValue(ctx, txKey{}, p), fn)
+	for _, invalidate := range p.fns {
+		invalidate()
+	}
+	return err
+}
+
+// Len will return the number of cached entries
+func (m *ArticleRepository) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ll.Len()
+}
+
+func (m *ArticleRepository) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
+	// the reads of a transaction see its uncommitted writes, they are never cached
+	if inTransaction(ctx) {
+		return m.ArticleRepository.GetByID(ctx, id)
+	}
+	key := idKey(id)
+	if res, ok := m.get(key); ok {
+		return res, nil
+	}
+
+	res, err = m.ArticleRepository.GetByID(replica.WithPrimary(ctx), id)
+	if err != nil {
+		return
+	}
+	m.add(key, res)
+	return
+}
+
+func (m *ArticleRepository) GetByTitle(ctx context.Context, title string) (res domain.Article, err error) {
+	if inTransaction(ctx) {
+		return m.ArticleRepository.GetByTitle(ctx, title)
+	}
+	key := titleKey(title)
+	if res, ok := m.get(key); ok {
+		return res, nil
+	}
+
+	res, err = m.ArticleRepository.GetByTitle(replica.WithPrimary(ctx), title)
+	if err != nil {
+		return
+	}
+	m.add(key, res)
+	return
+}
+
+func (m *ArticleRepository) Store(ctx context.Context, a *domain.Article) (err error) {
+	err = m.ArticleRepository.Store(ctx, a)
+	if err != nil {
+		return
+	}
+	afterCommit(ctx, func() { m.removeKey(titleKey(a.Title)) })
+	return
+}
+
+func (m *ArticleRepository) Update(ctx context.Context, ar *domain.Article) (err error) {
+	err = m.ArticleRepository.Update(ctx, ar)
+	if err != nil {
+		return
+	}
+	afterCommit(ctx, func() {
+		m.invalidate(ar.ID)
+		m.removeKey(titleKey(ar.Title))
+	})
+	return
+}
+
+func (m *ArticleRepository) Delete(ctx context.Context, id int64) (err error) {
+	err = m.ArticleRepository.Delete(ctx, id)
+	if err != nil {
+		return
+	}
+	afterCommit(ctx, func() { m.invalidate(id) })
+	return
+}
+
+func (m *ArticleRepository) SetStatus(ctx context.Context, id int64, status domain.Status) (err error) {
+	err = m.ArticleRepository.SetStatus(ctx, id, status)
+	if err != nil {
+		return
+	}
+	afterCommit(ctx, func() { m.invalidate(id) })
+	return
+}
+
+func (m *ArticleRepository) IncrementViews(ctx context.Context, id int64) (err error) {
+	err = m.ArticleRepository.IncrementViews(ctx, id)
+	if err != nil {
+		return
+	}
+	afterCommit(ctx, func() { m.invalidate(id) })
+	return
+}
+
+func (m *ArticleRepository) PublishDue(ctx context.Context, now time.Time) (ids []int64, err error) {
+	ids, err = m.ArticleRepository.PublishDue(ctx, now)
+	afterCommit(ctx, func() {
+		for _, id := range ids {
+			m.invalidate(id)
+		}
+	})
+	return
+}
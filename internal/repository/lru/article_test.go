@@ -0,0 +1,161 @@
+package lru_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/article/mocks"
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/repository/lru"
+	"apismrtbiz/internal/repository/replica"
+)
+
+func TestEviction(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	for _, id := range []int64{1, 2, 3} {
+		mockArticleRepo.On("GetByID", mock.Anything, id).Return(domain.Article{ID: id}, nil)
+	}
+	r := lru.NewArticleRepository(mockArticleRepo, 2)
+	ctx := context.TODO()
+
+	_, err := r.GetByID(ctx, 1)
+	require.NoError(t, err)
+	_, err = r.GetByID(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, r.Len())
+
+	// touching 1 leaves 2 as the least recently used entry
+	_, err = r.GetByID(ctx, 1)
+	require.NoError(t, err)
+	mockArticleRepo.AssertNumberOfCalls(t, "GetByID", 2)
+
+	_, err = r.GetByID(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 2, r.Len())
+
+	_, err = r.GetByID(ctx, 1)
+	require.NoError(t, err)
+	mockArticleRepo.AssertNumberOfCalls(t, "GetByID", 3)
+
+	_, err = r.GetByID(ctx, 2)
+	require.NoError(t, err)
+	mockArticleRepo.AssertNumberOfCalls(t, "GetByID", 4)
+}
+
+func TestGetByTitle(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(domain.Article{ID: 1, Title: "Hello"}, nil).Once()
+	mockArticleRepo.On("GetByTitle", mock.Anything, "Missing").Return(domain.Article{}, domain.ErrNotFound).Twice()
+	r := lru.NewArticleRepository(mockArticleRepo, 10)
+	ctx := context.TODO()
+
+	_, err := r.GetByTitle(ctx, "Hello")
+	require.NoError(t, err)
+	res, err := r.GetByTitle(ctx, " hello ")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), res.ID)
+
+	for i := 0; i < 2; i++ {
+		_, err = r.GetByTitle(ctx, "Missing")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	}
+	mockArticleRepo.AssertExpectations(t)
+}
+
+func TestInvalidation(t *testing.T) {
+	t.Run("update", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1, Title: "Hello"}, nil).Once()
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(domain.Article{ID: 1, Title: "Hello"}, nil).Once()
+		mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		r := lru.NewArticleRepository(mockArticleRepo, 10)
+		ctx := context.TODO()
+
+		_, err := r.GetByID(ctx, 1)
+		require.NoError(t, err)
+		_, err = r.GetByTitle(ctx, "Hello")
+		require.NoError(t, err)
+		assert.Equal(t, 2, r.Len())
+
+		require.NoError(t, r.Update(ctx, &domain.Article{ID: 1, Title: "Updated"}))
+		assert.Equal(t, 0, r.Len())
+
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1, Title: "Updated"}, nil).Once()
+		res, err := r.GetByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Updated", res.Title)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1}, nil).Once()
+		mockArticleRepo.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+		r := lru.NewArticleRepository(mockArticleRepo, 10)
+
+		_, err := r.GetByID(context.TODO(), 1)
+		require.NoError(t, err)
+		require.NoError(t, r.Delete(context.TODO(), 1))
+		assert.Equal(t, 0, r.Len())
+	})
+
+	t.Run("set-status", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1, Status: domain.StatusDraft}, nil).Once()
+		mockArticleRepo.On("SetStatus", mock.Anything, int64(1), domain.StatusPublished).Return(nil).Once()
+		r := lru.NewArticleRepository(mockArticleRepo, 10)
+
+		_, err := r.GetByID(context.TODO(), 1)
+		require.NoError(t, err)
+		require.NoError(t, r.SetStatus(context.TODO(), 1, domain.StatusPublished))
+		assert.Equal(t, 0, r.Len())
+	})
+}
+
+func TestMissFromPrimary(t *testing.T) {
+	primary, lagging := new(mocks.ArticleRepository), new(mocks.ArticleRepository)
+	primary.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7, Title: "Hello", Version: 2}, nil).Once()
+	primary.On("GetByTitle", mock.Anything, "Hello").Return(domain.Article{ID: 7, Title: "Hello", Version: 2}, nil).Once()
+	r := lru.NewArticleRepository(replica.NewArticleRepository(primary, lagging), 10)
+
+	res, err := r.GetByID(context.TODO(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), res.Version)
+	_, err = r.GetByTitle(context.TODO(), "Hello")
+	require.NoError(t, err)
+	primary.AssertExpectations(t)
+	lagging.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	lagging.AssertNotCalled(t, "GetByTitle", mock.Anything, mock.Anything)
+}
+
+func TestInvalidationAfterCommit(t *testing.T) {
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1, Title: "Hello"}, nil).Twice()
+	mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+	mockArticleRepo.On("WithinTransaction", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }).Once()
+	r := lru.NewArticleRepository(mockArticleRepo, 10)
+	ctx := context.TODO()
+
+	_, err := r.GetByID(ctx, 1)
+	require.NoError(t, err)
+
+	err = r.WithinTransaction(ctx, func(ctx context.Context) error {
+		// the read of the transaction isn't cached
+		if _, err := r.GetByID(ctx, 1); err != nil {
+			return err
+		}
+		if err := r.Update(ctx, &domain.Article{ID: 1, Title: "Updated"}); err != nil {
+			return err
+		}
+		assert.Equal(t, 1, r.Len(), "the entry is dropped once the transaction ended")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, r.Len())
+	mockArticleRepo.AssertExpectations(t)
+}
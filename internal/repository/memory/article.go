@@ -0,0 +1,499 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"apismrtbiz/domain"
+)
+
+// txKey marks the context of a transaction, the nested transactions join it
+type txKey struct{}
+
+// ArticleRepository keeps the articles and their revisions in the process memory, for the tests and the demos
+// running without a database. It follows the semantics of the SQL repositories, the articles are soft deleted.
+type ArticleRepository struct {
+	mu        sync.RWMutex
+	articles  map[int64]domain.Article
+	revisions map[int64][]domain.Revision
+	lastID    int64
+
+	// txMu runs one transaction at a time, a transaction is rolled back by restoring the state it started from
+	txMu sync.Mutex
+}
+
+// NewArticleRepository will create an object that represent the article.ArticleRepository interface
+func NewArticleRepository() *ArticleRepository {
+	return &ArticleRepository{
+		articles:  make(map[int64]domain.Article),
+		revisions: make(map[int64][]domain.Revision),
+	}
+}
+
+// clone will copy the article so that the stored one is never shared with the callers
+func clone(ar domain.Article) domain.Article {
+	if ar.Tags != nil {
+		ar.Tags = append([]string(nil), ar.Tags...)
+	}
+	if ar.DeletedAt != nil {
+		deletedAt := *ar.DeletedAt
+		ar.DeletedAt = &deletedAt
+	}
+	if ar.PublishAt != nil {
+		publishAt := *ar.PublishAt
+		ar.PublishAt = &publishAt
+	}
+	return ar
+}
+
+// WithinTransaction will run fn in a transaction, the changes fn made are undone when it fails.
+// The repository calls given the context of fn are part of the transaction, and so is a nested WithinTransaction.
+// The writes made meanwhile outside of the transaction are undone along its own ones.
+func (m *ArticleRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(txKey{}) != nil {
+		return fn(ctx)
+	}
+	m.txMu.Lock()
+	defer m.txMu.Unlock()
+
+	m.mu.RLock()
+	articles := make(map[int64]domain.Article, len(m.articles))
+	for id, ar := range m.articles {
+		articles[id] = clone(ar)
+	}
+	revisions := make(map[int64][]domain.Revision, len(m.revisions))
+	for id, list := range m.revisions {
+		revisions[id] = append([]domain.Revision(nil), list...)
+	}
+	lastID := m.lastID
+	m.mu.RUnlock()
+
+	if err := fn(context.WithValue(ctx, txKey{}, true)); err != nil {
+		m.mu.Lock()
+		m.articles, m.revisions, m.lastID = articles, revisions, lastID
+		m.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// list will return the articles kept by keep, in no particular order
+func (m *ArticleRepository) list(keep func(ar domain.Article) bool) []domain.Article {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	res := make([]domain.Article, 0)
+	for _, ar := range m.articles {
+		if keep(ar) {
+			res = append(res, clone(ar))
+		}
+	}
+	return res
+}
+
+// limit will cut the list down to the first num articles
+func limit(list []domain.Article, num int64) []domain.Article {
+	if num >= 0 && int64(len(list)) > num {
+		return list[:num]
+	}
+	return list
+}
+
+// compareBy will compare two articles on the sort field only
+func compareBy(field domain.SortField, a, b domain.Article) int {
+	switch field {
+	case domain.SortByTitle:
+		return strings.Compare(a.Title, b.Title)
+	case domain.SortByCreatedAt:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	default:
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	}
+}
+
+// compareCursor will compare an article with the cursor in the ordering of the sort field, the id breaking the ties
+func compareCursor(field domain.SortField, ar domain.Article, cursor domain.Cursor) (int, error) {
+	var c int
+	if field == domain.SortByTitle {
+		c = strings.Compare(ar.Title, cursor.Value)
+	} else {
+		t, err := cursor.Time()
+		if err != nil {
+			return 0, err
+		}
+		if field == domain.SortByCreatedAt {
+			c = ar.CreatedAt.Compare(t)
+		} else {
+			c = ar.UpdatedAt.Compare(t)
+		}
+	}
+	if c == 0 {
+		c = compareID(ar.ID, cursor.ID)
+	}
+	return c, nil
+}
+
+func compareID(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func hasTag(ar domain.Article, tag string) bool {
+	for _, t := range ar.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// page will sort the articles by the sort field and their id, and keep the ones after the cursor
+func page(list []domain.Article, field domain.SortField, desc bool, cursor domain.Cursor, num int64) ([]domain.Article, error) {
+	res := make([]domain.Article, 0, len(list))
+	for _, ar := range list {
+		if !cursor.IsZero() {
+			c, err := compareCursor(field, ar, cursor)
+			if err != nil {
+				return nil, err
+			}
+			if (desc && c >= 0) || (!desc && c <= 0) {
+				continue
+			}
+		}
+		res = append(res, ar)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		c := compareBy(field, res[i], res[j])
+		if c == 0 {
+			c = compareID(res[i].ID, res[j].ID)
+		}
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+	return limit(res, num), nil
+}
+
+// Fetch will list the articles after the cursor in the order of the filter, by default the most recently updated first.
+// The ties of the ordering are broken by the id like in the SQL repositories. When paging backward the articles
+// are listed in the reverse ordering, closest to the cursor first.
+func (m *ArticleRepository) Fetch(_ context.Context, cursor domain.Cursor, num int64, filter domain.ArticleFilter) ([]domain.Article, error) {
+	field := filter.Sort.Field
+	if field == "" {
+		field = domain.SortByUpdatedAt
+	}
+	if !field.Valid() {
+		return nil, domain.ErrBadParamInput
+	}
+	desc := (filter.Sort.Order == domain.SortAsc) == filter.Backward
+
+	list := m.list(func(ar domain.Article) bool {
+		switch {
+		case !filter.IncludeDeleted && ar.DeletedAt != nil,
+			filter.AuthorID != 0 && ar.Author.ID != filter.AuthorID,
+			filter.CategoryID != 0 && ar.CategoryID != filter.CategoryID,
+			filter.Status != "" && ar.Status != filter.Status,
+			filter.Tag != "" && !hasTag(ar, filter.Tag),
+			!filter.CreatedFrom.IsZero() && ar.CreatedAt.Before(filter.CreatedFrom),
+			!filter.CreatedTo.IsZero() && ar.CreatedAt.After(filter.CreatedTo):
+			return false
+		}
+		return true
+	})
+	return page(list, field, desc, cursor, num)
+}
+
+// FetchRelated will list the published articles sharing tags or the author with the given article,
+// the ones with the most in common first. Every shared tag counts as much as the shared author.
+func (m *ArticleRepository) FetchRelated(_ context.Context, ar domain.Article, num int64) ([]domain.Article, error) {
+	m.mu.RLock()
+	own := m.articles[ar.ID].Tags
+	m.mu.RUnlock()
+
+	overlap := func(candidate domain.Article) int {
+		n := 0
+		if candidate.Author.ID == ar.Author.ID {
+			n++
+		}
+		for _, tag := range own {
+			if hasTag(candidate, tag) {
+				n++
+			}
+		}
+		return n
+	}
+	list := m.list(func(candidate domain.Article) bool {
+		return candidate.ID != ar.ID && candidate.Status == domain.StatusPublished && candidate.DeletedAt == nil && overlap(candidate) > 0
+	})
+	sort.Slice(list, func(i, j int) bool {
+		if oi, oj := overlap(list[i]), overlap(list[j]); oi != oj {
+			return oi > oj
+		}
+		return list[i].UpdatedAt.After(list[j].UpdatedAt)
+	})
+	return limit(list, num), nil
+}
+
+// atStatus will report whether the article is live at the status, an empty status keeps every stage
+func atStatus(ar domain.Article, status domain.Status) bool {
+	return ar.DeletedAt == nil && (status == "" || ar.Status == status)
+}
+
+// Search will list the articles at the status whose title or content contains the query regardless of the case,
+// the most recently updated first, starting after the cursor. The ties are broken by the id like in Fetch.
+func (m *ArticleRepository) Search(_ context.Context, query string, num int64, cursor domain.Cursor, status domain.Status) ([]domain.Article, error) {
+	query = strings.ToLower(query)
+	list := m.list(func(ar domain.Article) bool {
+		return atStatus(ar, status) &&
+			(strings.Contains(strings.ToLower(ar.Title), query) || strings.Contains(strings.ToLower(ar.Content), query))
+	})
+	return page(list, domain.SortByUpdatedAt, true, cursor, num)
+}
+
+// OffsetFetch will list a page of the articles at the status, an empty status lists every stage.
+// The most recently updated come first, the ties broken by the id so that the pages never overlap.
+func (m *ArticleRepository) OffsetFetch(_ context.Context, offset, num int64, status domain.Status) ([]domain.Article, error) {
+	list, err := page(m.list(func(ar domain.Article) bool { return atStatus(ar, status) }), domain.SortByUpdatedAt, true, domain.Cursor{}, -1)
+	if err != nil {
+		return nil, err
+	}
+	if offset >= int64(len(list)) {
+		return []domain.Article{}, nil
+	}
+	return limit(list[offset:], num), nil
+}
+
+// Count will count the articles at the status, an empty status counts every stage
+func (m *ArticleRepository) Count(_ context.Context, status domain.Status) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	total := int64(0)
+	for _, ar := range m.articles {
+		if atStatus(ar, status) {
+			total++
+		}
+	}
+	return total, nil
+}
+
+func (m *ArticleRepository) GetByID(_ context.Context, id int64) (domain.Article, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ar, ok := m.articles[id]
+	if !ok || ar.DeletedAt != nil {
+		return domain.Article{}, domain.ErrNotFound
+	}
+	return clone(ar), nil
+}
+
+// GetByIDs will get the articles of the given ids in their order, the unknown and the deleted ones are left out
+func (m *ArticleRepository) GetByIDs(_ context.Context, ids []int64) ([]domain.Article, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	res := make([]domain.Article, 0, len(ids))
+	for _, id := range ids {
+		if ar, ok := m.articles[id]; ok && ar.DeletedAt == nil {
+			res = append(res, clone(ar))
+		}
+	}
+	return res, nil
+}
+
+// GetByTitle will get the article of the title, which is matched regardless of the case and the surrounding whitespace
+func (m *ArticleRepository) GetByTitle(_ context.Context, title string) (domain.Article, error) {
+	title = strings.ToLower(strings.TrimSpace(title))
+	list := m.list(func(ar domain.Article) bool {
+		return ar.DeletedAt == nil && strings.ToLower(strings.TrimSpace(ar.Title)) == title
+	})
+	if len(list) == 0 {
+		return domain.Article{}, domain.ErrNotFound
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list[0], nil
+}
+
+// GetBySlug will get the article of the given slug, a soft deleted one included since its slug stays taken
+func (m *ArticleRepository) GetBySlug(_ context.Context, slug string) (domain.Article, error) {
+	list := m.list(func(ar domain.Article) bool { return ar.Slug == slug })
+	if len(list) == 0 {
+		return domain.Article{}, domain.ErrNotFound
+	}
+	return list[0], nil
+}
+
+// Store will save the article under the next id or the one it is given, a slug or an id already taken
+// is reported as a conflict
+func (m *ArticleRepository) Store(_ context.Context, a *domain.Article) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if a.Slug != "" {
+		for _, ar := range m.articles {
+			if ar.Slug == a.Slug {
+				return domain.ErrConflict
+			}
+		}
+	}
+
+	// an article given its id keeps it, the ids handed out later start past it
+	if a.ID != 0 {
+		if _, ok := m.articles[a.ID]; ok {
+			return domain.ErrConflict
+		}
+		if a.ID > m.lastID {
+			m.lastID = a.ID
+		}
+	} else {
+		m.lastID++
+		a.ID = m.lastID
+	}
+
+	now := time.Now()
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	a.Version = 1
+	a.DeletedAt = nil
+	a.ViewCount = 0
+	m.articles[a.ID] = clone(*a)
+	return nil
+}
+
+// Update will save the article when its version still matches the stored one, a stale version or an unknown
+// article is reported as a conflict. The slug, the status and the counters are left as they are.
+func (m *ArticleRepository) Update(_ context.Context, ar *domain.Article) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.articles[ar.ID]
+	if !ok || stored.Version != ar.Version {
+		return domain.ErrConflict
+	}
+
+	updated := clone(*ar)
+	stored.Title = updated.Title
+	stored.Content = updated.Content
+	stored.Author = updated.Author
+	stored.PublishAt = updated.PublishAt
+	stored.CategoryID = updated.CategoryID
+	stored.Tags = updated.Tags
+	stored.UpdatedAt = time.Now()
+	stored.Version++
+	m.articles[ar.ID] = stored
+
+	ar.UpdatedAt = stored.UpdatedAt
+	ar.Version = stored.Version
+	return nil
+}
+
+// modify will apply change to the article of the given id when ok accepts it, otherwise it is reported as ErrNotFound
+func (m *ArticleRepository) modify(id int64, ok func(ar domain.Article) bool, change func(ar *domain.Article)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ar, found := m.articles[id]
+	if !found || !ok(ar) {
+		return domain.ErrNotFound
+	}
+	change(&ar)
+	m.articles[id] = ar
+	return nil
+}
+
+func notDeleted(ar domain.Article) bool { return ar.DeletedAt == nil }
+
+func (m *ArticleRepository) Delete(_ context.Context, id int64) error {
+	return m.modify(id, notDeleted, func(ar *domain.Article) {
+		now := time.Now()
+		ar.DeletedAt = &now
+	})
+}
+
+func (m *ArticleRepository) Restore(_ context.Context, id int64) error {
+	deleted := func(ar domain.Article) bool { return ar.DeletedAt != nil }
+	return m.modify(id, deleted, func(ar *domain.Article) { ar.DeletedAt = nil })
+}
+
+// SetStatus will move the article to the stage of the publishing workflow, it counts as an update of the article
+func (m *ArticleRepository) SetStatus(_ context.Context, id int64, status domain.Status) error {
+	return m.modify(id, notDeleted, func(ar *domain.Article) {
+		ar.Status = status
+		ar.UpdatedAt = time.Now()
+		ar.Version++
+	})
+}
+
+func (m *ArticleRepository) IncrementViews(_ context.Context, id int64) error {
+	return m.modify(id, notDeleted, func(ar *domain.Article) { ar.ViewCount++ })
+}
+
+// PublishDue will publish the drafts whose publish_at has passed and return their ids, the lowest first
+func (m *ArticleRepository) PublishDue(_ context.Context, now time.Time) ([]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var ids []int64
+	for id, ar := range m.articles {
+		if ar.Status != domain.StatusDraft || ar.PublishAt == nil || ar.PublishAt.After(now) || ar.DeletedAt != nil {
+			continue
+		}
+		ar.Status = domain.StatusPublished
+		ar.UpdatedAt = now
+		ar.Version++
+		m.articles[id] = ar
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// StoreRevision will record the snapshot of an article
+func (m *ArticleRepository) StoreRevision(_ context.Context, rev *domain.Revision) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rev.CreatedAt = time.Now()
+	stored := *rev
+	stored.Tags = append([]string(nil), rev.Tags...)
+	m.revisions[rev.ArticleID] = append(m.revisions[rev.ArticleID], stored)
+	return nil
+}
+
+// ListRevisions will list the revisions of the article, the newest first
+func (m *ArticleRepository) ListRevisions(_ context.Context, articleID int64) ([]domain.Revision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list := append([]domain.Revision{}, m.revisions[articleID]...)
+	sort.Slice(list, func(i, j int) bool { return list[i].Version > list[j].Version })
+	return list, nil
+}
+
+// GetRevision will get the revision of the article at the given version
+func (m *ArticleRepository) GetRevision(_ context.Context, articleID, version int64) (domain.Revision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, rev := range m.revisions[articleID] {
+		if rev.Version == version {
+			return rev, nil
+		}
+	}
+	return domain.Revision{}, domain.ErrNotFound
+}
+
+// Ping will always succeed, there is no connection to check
+func (m *ArticleRepository) Ping(_ context.Context) error {
+	return nil
+}
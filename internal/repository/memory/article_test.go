@@ -0,0 +1,84 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/article"
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/repository/memory"
+	"apismrtbiz/internal/repository/repotest"
+)
+
+func TestArticleConformance(t *testing.T) {
+	repotest.RunRepositoryConformanceTests(t, func(*testing.T) article.ArticleRepository {
+		return memory.NewArticleRepository()
+	})
+}
+
+func TestArticleStore(t *testing.T) {
+	repo := memory.NewArticleRepository()
+	ctx := context.TODO()
+
+	ar := &domain.Article{Title: "Hello", Slug: "hello", Content: "Content", Author: domain.Author{ID: 1}, Tags: []string{"golang", "web"}}
+	require.NoError(t, repo.Store(ctx, ar))
+	assert.ErrorIs(t, repo.Store(ctx, &domain.Article{Title: "Other", Slug: "hello", Content: "Content"}), domain.ErrConflict)
+
+	got, err := repo.GetByID(ctx, ar.ID)
+	require.NoError(t, err)
+	got.Tags[0] = "changed"
+	got, err = repo.GetByID(ctx, ar.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"golang", "web"}, got.Tags, "the stored article isn't shared with the callers")
+}
+
+func TestArticleWithinTransaction(t *testing.T) {
+	repo := memory.NewArticleRepository()
+	ctx := context.TODO()
+
+	storeBoth := func(second *domain.Article) error {
+		return repo.WithinTransaction(ctx, func(ctx context.Context) error {
+			first := &domain.Article{Title: "First", Slug: "first", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusDraft}
+			if err := repo.Store(ctx, first); err != nil {
+				return err
+			}
+			return repo.Store(ctx, second)
+		})
+	}
+
+	// the slug of the first article is taken again by the second one
+	err := storeBoth(&domain.Article{Title: "Second", Slug: "first", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusDraft})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	total, err := repo.Count(ctx, "")
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	err = storeBoth(&domain.Article{Title: "Second", Slug: "second", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusDraft})
+	require.NoError(t, err)
+	total, err = repo.Count(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestArticlePublishDue(t *testing.T) {
+	repo := memory.NewArticleRepository()
+	ctx := context.TODO()
+
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	due := &domain.Article{Title: "Due", Slug: "due", Content: "Content", Status: domain.StatusDraft, PublishAt: &past}
+	later := &domain.Article{Title: "Later", Slug: "later", Content: "Content", Status: domain.StatusDraft, PublishAt: &future}
+	require.NoError(t, repo.Store(ctx, due))
+	require.NoError(t, repo.Store(ctx, later))
+
+	ids, err := repo.PublishDue(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{due.ID}, ids)
+
+	ids, err = repo.PublishDue(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
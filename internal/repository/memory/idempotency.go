@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type idempotencyEntry struct {
+	id        int64
+	expiresAt time.Time
+}
+
+// IdempotencyStore keeps the idempotency keys in the process memory
+type IdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]idempotencyEntry
+	now     func() time.Time
+}
+
+// NewIdempotencyStore will create an object that represent the rest.IdempotencyStore interface
+func NewIdempotencyStore() *IdempotencyStore {
+	return &IdempotencyStore{
+		entries: make(map[string]idempotencyEntry),
+		now:     time.Now,
+	}
+}
+
+func (s *IdempotencyStore) Get(_ context.Context, key string) (id int64, ok bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return 0, false, nil
+	}
+	if !s.now().Before(entry.expiresAt) {
+		delete(s.entries, key)
+		return 0, false, nil
+	}
+	return entry.id, true, nil
+}
+
+func (s *IdempotencyStore) Save(_ context.Context, key string, id int64, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for k, entry := range s.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(s.entries, k)
+		}
+	}
+
+	s.entries[key] = idempotencyEntry{id: id, expiresAt: now.Add(ttl)}
+	return nil
+}
@@ -0,0 +1,37 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/internal/repository/memory"
+)
+
+func TestIdempotencyStore(t *testing.T) {
+	s := memory.NewIdempotencyStore()
+
+	_, ok, err := s.Get(context.TODO(), "unknown")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, s.Save(context.TODO(), "key", 42, time.Hour))
+	id, ok, err := s.Get(context.TODO(), "key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), id)
+}
+
+func TestIdempotencyStoreExpiry(t *testing.T) {
+	s := memory.NewIdempotencyStore()
+
+	require.NoError(t, s.Save(context.TODO(), "key", 42, 10*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+
+	_, ok, err := s.Get(context.TODO(), "key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
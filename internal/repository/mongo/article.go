@@ -0,0 +1,558 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+
+	"apismrtbiz/domain"
+)
+
+// the collections of the repositories
+const (
+	articleCollection  = "article"
+	authorCollection   = "author"
+	counterCollection  = "counter"
+	revisionCollection = "article_revisions"
+	commentCollection  = "comment"
+	favoriteCollection = "favorite"
+	categoryCollection = "category"
+)
+
+// articleDocument is the BSON mapping of domain.Article, the articles keep the numeric ids of the SQL
+// repositories so the clients don't tell the databases apart
+type articleDocument struct {
+	ID         int64         `bson:"_id"`
+	Title      string        `bson:"title"`
+	TitleKey   string        `bson:"title_key"`
+	Slug       string        `bson:"slug"`
+	Content    string        `bson:"content"`
+	AuthorID   int64         `bson:"author_id"`
+	UpdatedAt  time.Time     `bson:"updated_at"`
+	CreatedAt  time.Time     `bson:"created_at"`
+	DeletedAt  *time.Time    `bson:"deleted_at"`
+	Version    int64         `bson:"version"`
+	Status     domain.Status `bson:"status"`
+	PublishAt  *time.Time    `bson:"publish_at"`
+	ViewCount  int64         `bson:"view_count"`
+	Tags       []string      `bson:"tags"`
+	CategoryID int64         `bson:"category_id"`
+}
+
+// titleKey is the form of the title the lookups by title match, the way the SQL repositories compare LOWER(TRIM(title))
+func titleKey(title string) string {
+	return strings.ToLower(strings.TrimSpace(title))
+}
+
+func newArticleDocument(ar *domain.Article) articleDocument {
+	return articleDocument{
+		ID:         ar.ID,
+		Title:      ar.Title,
+		TitleKey:   titleKey(ar.Title),
+		Slug:       ar.Slug,
+		Content:    ar.Content,
+		AuthorID:   ar.Author.ID,
+		UpdatedAt:  ar.UpdatedAt,
+		CreatedAt:  ar.CreatedAt,
+		DeletedAt:  ar.DeletedAt,
+		Version:    ar.Version,
+		Status:     ar.Status,
+		PublishAt:  ar.PublishAt,
+		ViewCount:  ar.ViewCount,
+		Tags:       ar.Tags,
+		CategoryID: ar.CategoryID,
+	}
+}
+
+func (d articleDocument) article() domain.Article {
+	ar := domain.Article{
+		ID:         d.ID,
+		Title:      d.Title,
+		Slug:       d.Slug,
+		Content:    d.Content,
+		Author:     domain.Author{ID: d.AuthorID},
+		UpdatedAt:  d.UpdatedAt,
+		CreatedAt:  d.CreatedAt,
+		DeletedAt:  d.DeletedAt,
+		Version:    d.Version,
+		Status:     d.Status,
+		PublishAt:  d.PublishAt,
+		ViewCount:  d.ViewCount,
+		CategoryID: d.CategoryID,
+	}
+	if len(d.Tags) > 0 {
+		ar.Tags = d.Tags
+	}
+	return ar
+}
+
+type ArticleRepository struct {
+	DB *mongo.Database
+}
+
+// NewArticleRepository will create an object that represent the article.Repository interface
+func NewArticleRepository(db *mongo.Database) *ArticleRepository {
+	return &ArticleRepository{db}
+}
+
+func (m *ArticleRepository) collection() *mongo.Collection {
+	return m.DB.Collection(articleCollection)
+}
+
+func (m *ArticleRepository) find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (result []domain.Article, err error) {
+	cur, err := m.collection().Find(ctx, filter, opts...)
+	if err != nil {
+		logrus.WithContext(ctx).Error(err)
+		return nil, err
+	}
+	return decodeArticles(ctx, cur)
+}
+
+func decodeArticles(ctx context.Context, cur *mongo.Cursor) (result []domain.Article, err error) {
+	defer func() {
+		errCur := cur.Close(ctx)
+		if errCur != nil {
+			logrus.WithContext(ctx).Error(errCur)
+		}
+	}()
+
+	result = make([]domain.Article, 0)
+	for cur.Next(ctx) {
+		var doc articleDocument
+		if err = cur.Decode(&doc); err != nil {
+			logrus.WithContext(ctx).Error(err)
+			return nil, err
+		}
+		result = append(result, doc.article())
+	}
+	return result, cur.Err()
+}
+
+// findOne will return the article matching the filter, none is reported as ErrNotFound
+func (m *ArticleRepository) findOne(ctx context.Context, filter interface{}) (res domain.Article, err error) {
+	var doc articleDocument
+	err = m.collection().FindOne(ctx, filter).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Article{}, domain.ErrNotFound
+	}
+	if err != nil {
+		logrus.WithContext(ctx).Error(err)
+		return domain.Article{}, err
+	}
+	return doc.article(), nil
+}
+
+// notDeleted matches the articles that aren't soft deleted, a null deleted_at also matches a missing one
+var notDeleted = bson.E{Key: "deleted_at", Value: nil}
+
+// sortFields maps the sort fields to their document field, the field names are never taken from the input
+var sortFields = map[domain.SortField]string{
+	domain.SortByUpdatedAt: "updated_at",
+	domain.SortByCreatedAt: "created_at",
+	domain.SortByTitle:     "title",
+}
+
+// cursorValue will turn the cursor value into the value of the sort field
+func cursorValue(field domain.SortField, cursor domain.Cursor) (interface{}, error) {
+	if field == domain.SortByTitle {
+		return cursor.Value, nil
+	}
+	return cursor.Time()
+}
+
+// Fetch will list the articles after the cursor in the order of the filter, by default the most recently updated first.
+// The articles sharing the sort value of the cursor are told apart by their _id, which breaks the ties of the ordering.
+// When paging backward the query runs in the reverse ordering, so the articles are returned closest to the cursor first.
+func (m *ArticleRepository) Fetch(ctx context.Context, cursor domain.Cursor, num int64, filter domain.ArticleFilter) (res []domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Fetch", "find")
+	defer func() { endSpan(span, err) }()
+
+	field := filter.Sort.Field
+	if field == "" {
+		field = domain.SortByUpdatedAt
+	}
+	name, ok := sortFields[field]
+	if !ok {
+		return nil, domain.ErrBadParamInput
+	}
+	comparison, direction := "$lt", -1
+	if (filter.Sort.Order == domain.SortAsc) != filter.Backward {
+		comparison, direction = "$gt", 1
+	}
+
+	query := bson.D{}
+	if !cursor.IsZero() {
+		value, errCursor := cursorValue(field, cursor)
+		if errCursor != nil {
+			return nil, errCursor
+		}
+		query = append(query, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: name, Value: bson.D{{Key: comparison, Value: value}}}},
+			bson.D{{Key: name, Value: value}, {Key: "_id", Value: bson.D{{Key: comparison, Value: cursor.ID}}}},
+		}})
+	}
+	if filter.AuthorID != 0 {
+		query = append(query, bson.E{Key: "author_id", Value: filter.AuthorID})
+	}
+	if filter.CategoryID != 0 {
+		query = append(query, bson.E{Key: "category_id", Value: filter.CategoryID})
+	}
+	if filter.Status != "" {
+		query = append(query, bson.E{Key: "status", Value: filter.Status})
+	}
+	if filter.Tag != "" {
+		query = append(query, bson.E{Key: "tags", Value: filter.Tag})
+	}
+	createdAt := bson.D{}
+	if !filter.CreatedFrom.IsZero() {
+		createdAt = append(createdAt, bson.E{Key: "$gte", Value: filter.CreatedFrom})
+	}
+	if !filter.CreatedTo.IsZero() {
+		createdAt = append(createdAt, bson.E{Key: "$lte", Value: filter.CreatedTo})
+	}
+	if len(createdAt) > 0 {
+		query = append(query, bson.E{Key: "created_at", Value: createdAt})
+	}
+	if !filter.IncludeDeleted {
+		query = append(query, notDeleted)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: name, Value: direction}, {Key: "_id", Value: direction}}).
+		SetLimit(num)
+	return m.find(ctx, query, opts)
+}
+
+// FetchRelated will list the published articles sharing tags or the author with the given article,
+// the ones with the most in common first. Every shared tag counts as much as the shared author.
+func (m *ArticleRepository) FetchRelated(ctx context.Context, ar domain.Article, num int64) (res []domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.FetchRelated", "aggregate")
+	defer func() { endSpan(span, err) }()
+
+	tags := ar.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$ne", Value: ar.ID}}},
+			{Key: "status", Value: domain.StatusPublished},
+			notDeleted,
+			{Key: "$or", Value: bson.A{
+				bson.D{{Key: "author_id", Value: ar.Author.ID}},
+				bson.D{{Key: "tags", Value: bson.D{{Key: "$in", Value: tags}}}},
+			}},
+		}}},
+		{{Key: "$addFields", Value: bson.D{{Key: "overlap", Value: bson.D{{Key: "$add", Value: bson.A{
+			bson.D{{Key: "$cond", Value: bson.A{bson.D{{Key: "$eq", Value: bson.A{"$author_id", ar.Author.ID}}}, 1, 0}}},
+			bson.D{{Key: "$size", Value: bson.D{{Key: "$setIntersection", Value: bson.A{
+				bson.D{{Key: "$ifNull", Value: bson.A{"$tags", bson.A{}}}}, tags,
+			}}}}},
+		}}}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "overlap", Value: -1}, {Key: "updated_at", Value: -1}}}},
+		{{Key: "$limit", Value: num}},
+	}
+
+	cur, err := m.collection().Aggregate(ctx, pipeline)
+	if err != nil {
+		logrus.WithContext(ctx).Error(err)
+		return nil, err
+	}
+	return decodeArticles(ctx, cur)
+}
+
+// withStatus will keep only the articles at the status on top of the filter, an empty status keeps every stage
+func withStatus(filter bson.D, status domain.Status) bson.D {
+	if status == "" {
+		return filter
+	}
+	return append(filter, bson.E{Key: "status", Value: status})
+}
+
+// Search will list the articles at the status whose title or content contains the query regardless of the case,
+// the most recently updated first, starting after the cursor. The ties are broken by the _id like in Fetch.
+func (m *ArticleRepository) Search(ctx context.Context, query string, num int64, cursor domain.Cursor, status domain.Status) (res []domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Search", "find")
+	defer func() { endSpan(span, err) }()
+
+	// the query is quoted so it is matched literally
+	pattern := bson.D{{Key: "$regex", Value: regexp.QuoteMeta(query)}, {Key: "$options", Value: "i"}}
+	filter := bson.D{
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "title", Value: pattern}},
+			bson.D{{Key: "content", Value: pattern}},
+		}},
+		notDeleted,
+	}
+	filter = withStatus(filter, status)
+	if !cursor.IsZero() {
+		updatedAt, errCursor := cursor.Time()
+		if errCursor != nil {
+			return nil, errCursor
+		}
+		filter = append(filter, bson.E{Key: "$and", Value: bson.A{bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "updated_at", Value: bson.D{{Key: "$lt", Value: updatedAt}}}},
+			bson.D{{Key: "updated_at", Value: updatedAt}, {Key: "_id", Value: bson.D{{Key: "$lt", Value: cursor.ID}}}},
+		}}}}})
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(num)
+	return m.find(ctx, filter, opts)
+}
+
+// OffsetFetch will list a page of the articles at the status, an empty status lists every stage.
+// The most recently updated come first, the ties broken by the id so that the pages never overlap.
+func (m *ArticleRepository) OffsetFetch(ctx context.Context, offset, limit int64, status domain.Status) (res []domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.OffsetFetch", "find")
+	defer func() { endSpan(span, err) }()
+
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}, {Key: "_id", Value: -1}}).SetSkip(offset).SetLimit(limit)
+	return m.find(ctx, withStatus(bson.D{notDeleted}, status), opts)
+}
+
+// Count will count the articles at the status, an empty status counts every stage
+func (m *ArticleRepository) Count(ctx context.Context, status domain.Status) (total int64, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Count", "count")
+	defer func() { endSpan(span, err) }()
+
+	total, err = m.collection().CountDocuments(ctx, withStatus(bson.D{notDeleted}, status))
+	if err != nil {
+		logrus.WithContext(ctx).Error(err)
+		return 0, err
+	}
+	return
+}
+
+func (m *ArticleRepository) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.GetByID", "find")
+	defer func() { endSpan(span, err) }()
+
+	return m.findOne(ctx, bson.D{{Key: "_id", Value: id}, notDeleted})
+}
+
+func (m *ArticleRepository) GetByIDs(ctx context.Context, ids []int64) (res []domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.GetByIDs", "find")
+	defer func() { endSpan(span, err) }()
+
+	if len(ids) == 0 {
+		return []domain.Article{}, nil
+	}
+	return m.find(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}, notDeleted})
+}
+
+func (m *ArticleRepository) GetByTitle(ctx context.Context, title string) (res domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.GetByTitle", "find")
+	defer func() { endSpan(span, err) }()
+
+	return m.findOne(ctx, bson.D{{Key: "title_key", Value: titleKey(title)}, notDeleted})
+}
+
+// GetBySlug will get the article of the given slug, a soft deleted one included since its slug stays taken
+func (m *ArticleRepository) GetBySlug(ctx context.Context, slug string) (res domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.GetBySlug", "find")
+	defer func() { endSpan(span, err) }()
+
+	return m.findOne(ctx, bson.D{{Key: "slug", Value: slug}})
+}
+
+// nextID will hand out the next id of the documents of the collection from its counter, the increment
+// is atomic so two documents stored at once never share an id
+func nextID(ctx context.Context, db *mongo.Database, collection string) (int64, error) {
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := db.Collection(counterCollection).FindOneAndUpdate(ctx,
+		bson.D{{Key: "_id", Value: collection}},
+		bson.D{{Key: "$inc", Value: bson.D{{Key: "seq", Value: int64(1)}}}},
+		opts,
+	).Decode(&counter)
+	return counter.Seq, err
+}
+
+// raiseID will bring the counter of the collection up to id when it is below it
+func raiseID(ctx context.Context, db *mongo.Database, collection string, id int64) error {
+	_, err := db.Collection(counterCollection).UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: collection}},
+		bson.D{{Key: "$max", Value: bson.D{{Key: "seq", Value: id}}}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func (m *ArticleRepository) Store(ctx context.Context, a *domain.Article) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Store", "insert")
+	defer func() { endSpan(span, err) }()
+
+	// an article given its id keeps it, the counter is moved past it so that it is never handed out again
+	id := a.ID
+	if id != 0 {
+		err = raiseID(ctx, m.DB, articleCollection, id)
+	} else {
+		id, err = nextID(ctx, m.DB, articleCollection)
+	}
+	if err != nil {
+		return
+	}
+
+	// Mongo keeps the times to the millisecond, the article holds what is read back
+	now := time.Now().Truncate(time.Millisecond)
+	doc := newArticleDocument(a)
+	doc.ID = id
+	doc.CreatedAt = now
+	doc.UpdatedAt = now
+	doc.Version = 1
+	// the tags are embedded in the document, so the article and its tags are stored at once
+	if _, err = m.collection().InsertOne(ctx, doc); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			err = domain.ErrConflict
+		}
+		return
+	}
+
+	a.ID = id
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	a.Version = 1
+	return
+}
+
+// updateOne will apply the update to the single article matching the filter, none is reported as ErrNotFound
+func (m *ArticleRepository) updateOne(ctx context.Context, filter, update interface{}) error {
+	res, err := m.collection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (m *ArticleRepository) Delete(ctx context.Context, id int64) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Delete", "update")
+	defer func() { endSpan(span, err) }()
+
+	return m.updateOne(ctx,
+		bson.D{{Key: "_id", Value: id}, notDeleted},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "deleted_at", Value: time.Now()}}}},
+	)
+}
+
+func (m *ArticleRepository) Restore(ctx context.Context, id int64) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Restore", "update")
+	defer func() { endSpan(span, err) }()
+
+	return m.updateOne(ctx,
+		bson.D{{Key: "_id", Value: id}, {Key: "deleted_at", Value: bson.D{{Key: "$ne", Value: nil}}}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "deleted_at", Value: nil}}}},
+	)
+}
+
+// SetStatus will move the article to the stage of the publishing workflow, it counts as an update of the article
+func (m *ArticleRepository) SetStatus(ctx context.Context, id int64, status domain.Status) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.SetStatus", "update")
+	defer func() { endSpan(span, err) }()
+
+	return m.updateOne(ctx,
+		bson.D{{Key: "_id", Value: id}, notDeleted},
+		bson.D{
+			{Key: "$set", Value: bson.D{{Key: "status", Value: status}, {Key: "updated_at", Value: time.Now()}}},
+			{Key: "$inc", Value: bson.D{{Key: "version", Value: int64(1)}}},
+		},
+	)
+}
+
+// IncrementViews will count one more view of the article, the increment is done by the database
+// so the concurrent views are never lost
+func (m *ArticleRepository) IncrementViews(ctx context.Context, id int64) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.IncrementViews", "update")
+	defer func() { endSpan(span, err) }()
+
+	return m.updateOne(ctx,
+		bson.D{{Key: "_id", Value: id}, notDeleted},
+		bson.D{{Key: "$inc", Value: bson.D{{Key: "view_count", Value: int64(1)}}}},
+	)
+}
+
+// PublishDue will publish the drafts whose publish_at has passed and return their ids.
+// Every draft is published by an update that only matches it while it is still a draft,
+// so two workers running at once don't publish an article twice.
+func (m *ArticleRepository) PublishDue(ctx context.Context, now time.Time) (ids []int64, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.PublishDue", "update")
+	defer func() { endSpan(span, err) }()
+
+	due := bson.D{
+		{Key: "status", Value: domain.StatusDraft},
+		{Key: "publish_at", Value: bson.D{{Key: "$lte", Value: now}}},
+		notDeleted,
+	}
+	candidates, err := m.find(ctx, due, options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, candidate := range candidates {
+		res, errUpdate := m.collection().UpdateOne(ctx,
+			bson.D{{Key: "_id", Value: candidate.ID}, {Key: "status", Value: domain.StatusDraft}},
+			bson.D{
+				{Key: "$set", Value: bson.D{{Key: "status", Value: domain.StatusPublished}, {Key: "updated_at", Value: now}}},
+				{Key: "$inc", Value: bson.D{{Key: "version", Value: int64(1)}}},
+			},
+		)
+		if errUpdate != nil {
+			return ids, errUpdate
+		}
+		if res.ModifiedCount == 1 {
+			ids = append(ids, candidate.ID)
+		}
+	}
+	return ids, nil
+}
+
+// Ping will check the connection to the database is still alive
+func (m *ArticleRepository) Ping(ctx context.Context) error {
+	return m.DB.Client().Ping(ctx, readpref.Primary())
+}
+
+func (m *ArticleRepository) Update(ctx context.Context, ar *domain.Article) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Update", "update")
+	defer func() { endSpan(span, err) }()
+
+	updatedAt := time.Now().Truncate(time.Millisecond)
+	res, err := m.collection().UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: ar.ID}, {Key: "version", Value: ar.Version}},
+		bson.D{
+			// the tags are replaced as a whole like the other fields
+			{Key: "$set", Value: bson.D{
+				{Key: "title", Value: ar.Title},
+				{Key: "title_key", Value: titleKey(ar.Title)},
+				{Key: "content", Value: ar.Content},
+				{Key: "author_id", Value: ar.Author.ID},
+				{Key: "updated_at", Value: updatedAt},
+				{Key: "publish_at", Value: ar.PublishAt},
+				{Key: "tags", Value: ar.Tags},
+				{Key: "category_id", Value: ar.CategoryID},
+			}},
+			{Key: "$inc", Value: bson.D{{Key: "version", Value: int64(1)}}},
+		},
+	)
+	if err != nil {
+		return
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrConflict
+	}
+
+	ar.UpdatedAt = updatedAt
+	ar.Version++
+	return
+}
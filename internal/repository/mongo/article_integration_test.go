@@ -0,0 +1,204 @@
+//go:build integration
+
+package mongo_test
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"apismrtbiz/domain"
+	mongoRepo "apismrtbiz/internal/repository/mongo"
+)
+
+// openTestDB will connect to the Mongo instance of MONGO_TEST_URI and hand out an empty database dropped after the test,
+// e.g. docker run -d -p 27017:27017 mongo:7 && MONGO_TEST_URI=mongodb://127.0.0.1:27017 go test -tags integration ./...
+func openTestDB(t *testing.T) *mongo.Database {
+	t.Helper()
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI is not set")
+	}
+
+	ctx := context.TODO()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	require.NoError(t, err)
+	db := client.Database("ctfhr_test_" + strconv.FormatInt(time.Now().UnixNano(), 36))
+	t.Cleanup(func() {
+		assert.NoError(t, db.Drop(ctx))
+		assert.NoError(t, client.Disconnect(ctx))
+	})
+
+	require.NoError(t, mongoRepo.CreateIndexes(ctx, db))
+	_, err = db.Collection("author").InsertOne(ctx, bson.D{{Key: "_id", Value: int64(1)}, {Key: "name", Value: "Iman Tumorang"}})
+	require.NoError(t, err)
+	return db
+}
+
+func TestArticleCRUD(t *testing.T) {
+	db := openTestDB(t)
+	repo := mongoRepo.NewArticleRepository(db)
+	ctx := context.TODO()
+
+	require.NoError(t, repo.Ping(ctx))
+
+	ar := &domain.Article{
+		Title:   "Hello",
+		Content: "Content",
+		Author:  domain.Author{ID: 1},
+		Status:  domain.StatusDraft,
+		Tags:    []string{"golang", "web"},
+	}
+	require.NoError(t, repo.Store(ctx, ar))
+	require.NotZero(t, ar.ID)
+	assert.Equal(t, int64(1), ar.Version)
+
+	got, err := repo.GetByID(ctx, ar.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", got.Title)
+	assert.Equal(t, []string{"golang", "web"}, got.Tags)
+	assert.True(t, ar.CreatedAt.Equal(got.CreatedAt))
+
+	got, err = repo.GetByTitle(ctx, " hello ")
+	require.NoError(t, err)
+	assert.Equal(t, ar.ID, got.ID)
+
+	ar.Title = "Updated"
+	ar.Tags = []string{"golang"}
+	require.NoError(t, repo.Update(ctx, ar))
+	assert.Equal(t, int64(2), ar.Version)
+
+	stale := *ar
+	stale.Version = 1
+	assert.ErrorIs(t, repo.Update(ctx, &stale), domain.ErrConflict)
+
+	require.NoError(t, repo.SetStatus(ctx, ar.ID, domain.StatusPublished))
+	require.NoError(t, repo.IncrementViews(ctx, ar.ID))
+
+	list, err := repo.Fetch(ctx, domain.Cursor{}, 10, domain.ArticleFilter{Tag: "golang", Status: domain.StatusPublished})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "Updated", list[0].Title)
+	assert.Equal(t, int64(1), list[0].ViewCount)
+
+	found, err := repo.Search(ctx, "UPDAT", 10, domain.Cursor{}, "")
+	require.NoError(t, err)
+	assert.Len(t, found, 1)
+	found, err = repo.Search(ctx, ".*", 10, domain.Cursor{}, "")
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	total, err := repo.Count(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	require.NoError(t, repo.Delete(ctx, ar.ID))
+	_, err = repo.GetByID(ctx, ar.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, ar.ID), domain.ErrNotFound)
+
+	require.NoError(t, repo.Restore(ctx, ar.ID))
+	assert.ErrorIs(t, repo.Restore(ctx, ar.ID), domain.ErrNotFound)
+}
+
+func TestArticleNotFound(t *testing.T) {
+	db := openTestDB(t)
+	repo := mongoRepo.NewArticleRepository(db)
+	ctx := context.TODO()
+
+	_, err := repo.GetByID(ctx, 404)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = repo.GetByTitle(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.ErrorIs(t, repo.SetStatus(ctx, 404, domain.StatusPublished), domain.ErrNotFound)
+	assert.ErrorIs(t, repo.IncrementViews(ctx, 404), domain.ErrNotFound)
+}
+
+func TestArticleFetchCursor(t *testing.T) {
+	db := openTestDB(t)
+	repo := mongoRepo.NewArticleRepository(db)
+	ctx := context.TODO()
+
+	for _, title := range []string{"first", "second", "third"} {
+		require.NoError(t, repo.Store(ctx, &domain.Article{Title: title, Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusPublished}))
+	}
+	// the articles share their update time, the _id keeps the pages apart
+	_, err := db.Collection("article").UpdateMany(ctx, bson.D{}, bson.D{{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now()}}}})
+	require.NoError(t, err)
+
+	page, err := repo.Fetch(ctx, domain.Cursor{}, 2, domain.ArticleFilter{})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, "third", page[0].Title)
+	assert.Equal(t, "second", page[1].Title)
+
+	page, err = repo.Fetch(ctx, domain.NewCursor(domain.SortByUpdatedAt, page[1]), 2, domain.ArticleFilter{})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "first", page[0].Title)
+
+	page, err = repo.OffsetFetch(ctx, 1, 1, "")
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "second", page[0].Title)
+}
+
+func TestArticlePublishDue(t *testing.T) {
+	db := openTestDB(t)
+	repo := mongoRepo.NewArticleRepository(db)
+	ctx := context.TODO()
+
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	due := &domain.Article{Title: "Due", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusDraft, PublishAt: &past}
+	later := &domain.Article{Title: "Later", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusDraft, PublishAt: &future}
+	require.NoError(t, repo.Store(ctx, due))
+	require.NoError(t, repo.Store(ctx, later))
+
+	ids, err := repo.PublishDue(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{due.ID}, ids)
+
+	got, err := repo.GetByID(ctx, later.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusDraft, got.Status)
+}
+
+func TestArticleFetchRelated(t *testing.T) {
+	db := openTestDB(t)
+	repo := mongoRepo.NewArticleRepository(db)
+	ctx := context.TODO()
+
+	ar := &domain.Article{Title: "Go", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusPublished, Tags: []string{"golang", "web"}}
+	both := &domain.Article{Title: "Both tags", Content: "Content", Author: domain.Author{ID: 2}, Status: domain.StatusPublished, Tags: []string{"golang", "web"}}
+	author := &domain.Article{Title: "Same author", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusPublished}
+	unrelated := &domain.Article{Title: "Unrelated", Content: "Content", Author: domain.Author{ID: 2}, Status: domain.StatusPublished, Tags: []string{"rust"}}
+	for _, a := range []*domain.Article{ar, both, author, unrelated} {
+		require.NoError(t, repo.Store(ctx, a))
+	}
+
+	list, err := repo.FetchRelated(ctx, *ar, 5)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, both.ID, list[0].ID)
+	assert.Equal(t, author.ID, list[1].ID)
+}
+
+func TestAuthorGetByID(t *testing.T) {
+	db := openTestDB(t)
+	repo := mongoRepo.NewAuthorRepository(db)
+
+	author, err := repo.GetByID(context.TODO(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Iman Tumorang", author.Name)
+
+	_, err = repo.GetByID(context.TODO(), 404)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,97 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"apismrtbiz/domain"
+	mongoRepo "apismrtbiz/internal/repository/mongo"
+)
+
+func TestGetArticleByID(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "ctfhr.article", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: int64(3)},
+			{Key: "title", Value: "Hello"},
+			{Key: "author_id", Value: int64(1)},
+			{Key: "version", Value: int64(2)},
+			{Key: "status", Value: "published"},
+			{Key: "tags", Value: bson.A{"golang"}},
+		}))
+		a := mongoRepo.NewArticleRepository(mt.DB)
+
+		ar, err := a.GetByID(context.TODO(), 3)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), ar.ID)
+		assert.Equal(t, int64(1), ar.Author.ID)
+		assert.Equal(t, domain.StatusPublished, ar.Status)
+		assert.Equal(t, []string{"golang"}, ar.Tags)
+	})
+
+	mt.Run("not-found", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "ctfhr.article", mtest.FirstBatch))
+		a := mongoRepo.NewArticleRepository(mt.DB)
+
+		_, err := a.GetByID(context.TODO(), 404)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestStoreArticle(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{{Key: "_id", Value: "article"}, {Key: "seq", Value: int64(12)}}}),
+			mtest.CreateSuccessResponse(),
+		)
+		a := mongoRepo.NewArticleRepository(mt.DB)
+
+		ar := &domain.Article{Title: "Judul", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusDraft, Tags: []string{"go"}}
+		err := a.Store(context.TODO(), ar)
+		require.NoError(t, err)
+		assert.Equal(t, int64(12), ar.ID)
+		assert.Equal(t, int64(1), ar.Version)
+
+		insert := mt.GetStartedEvent()
+		for insert != nil && insert.CommandName != "insert" {
+			insert = mt.GetStartedEvent()
+		}
+		require.NotNil(t, insert)
+		doc := insert.Command.Lookup("documents").Array().Index(0).Value().Document()
+		assert.Equal(t, "judul", doc.Lookup("title_key").StringValue())
+		assert.Equal(t, int64(12), doc.Lookup("_id").Int64())
+	})
+}
+
+func TestUpdateArticle(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("conflict", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+		a := mongoRepo.NewArticleRepository(mt.DB)
+
+		ar := &domain.Article{ID: 3, Title: "Judul", Content: "Content", Version: 1}
+		err := a.Update(context.TODO(), ar)
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.Equal(t, int64(1), ar.Version)
+	})
+}
+
+func TestDeleteArticle(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("not-found", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+		a := mongoRepo.NewArticleRepository(mt.DB)
+
+		assert.ErrorIs(t, a.Delete(context.TODO(), 404), domain.ErrNotFound)
+	})
+}
@@ -0,0 +1,51 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"apismrtbiz/domain"
+)
+
+type AuthorRepository struct {
+	DB *mongo.Database
+}
+
+// NewAuthorRepository will create an implementation of author.Repository
+func NewAuthorRepository(db *mongo.Database) *AuthorRepository {
+	return &AuthorRepository{
+		DB: db,
+	}
+}
+
+func (m *AuthorRepository) GetByID(ctx context.Context, id int64) (res domain.Author, err error) {
+	ctx, span := startSpan(ctx, "AuthorRepository.GetByID", "find")
+	defer func() { endSpan(span, err) }()
+
+	var doc struct {
+		ID        int64     `bson:"_id"`
+		Name      string    `bson:"name"`
+		CreatedAt time.Time `bson:"created_at"`
+		UpdatedAt time.Time `bson:"updated_at"`
+	}
+	err = m.DB.Collection(authorCollection).FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Author{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Author{}, err
+	}
+	return domain.Author{ID: doc.ID, Name: doc.Name, CreatedAt: formatTime(doc.CreatedAt), UpdatedAt: formatTime(doc.UpdatedAt)}, nil
+}
+
+// formatTime will format the time of the author the way the SQL databases do, a missing time is left empty
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.DateTime)
+}
@@ -0,0 +1,160 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"apismrtbiz/domain"
+)
+
+// categoryDocument is the BSON mapping of domain.Category
+type categoryDocument struct {
+	ID        int64     `bson:"_id"`
+	Name      string    `bson:"name"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+func (d categoryDocument) category() domain.Category {
+	return domain.Category{
+		ID:        d.ID,
+		Name:      d.Name,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
+
+type CategoryRepository struct {
+	DB *mongo.Database
+}
+
+// NewCategoryRepository will create an object that represent the category.CategoryRepository interface
+func NewCategoryRepository(db *mongo.Database) *CategoryRepository {
+	return &CategoryRepository{db}
+}
+
+func (m *CategoryRepository) collection() *mongo.Collection {
+	return m.DB.Collection(categoryCollection)
+}
+
+// Fetch will list every category by name
+func (m *CategoryRepository) Fetch(ctx context.Context) (res []domain.Category, err error) {
+	ctx, span := startSpan(ctx, "CategoryRepository.Fetch", "find")
+	defer func() { endSpan(span, err) }()
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := m.collection().Find(ctx, bson.D{}, opts)
+	if err != nil {
+		logrus.WithContext(ctx).Error(err)
+		return nil, err
+	}
+	defer func() {
+		errCur := cur.Close(ctx)
+		if errCur != nil {
+			logrus.WithContext(ctx).Error(errCur)
+		}
+	}()
+
+	res = make([]domain.Category, 0)
+	for cur.Next(ctx) {
+		var doc categoryDocument
+		if err = cur.Decode(&doc); err != nil {
+			logrus.WithContext(ctx).Error(err)
+			return nil, err
+		}
+		res = append(res, doc.category())
+	}
+	return res, cur.Err()
+}
+
+func (m *CategoryRepository) GetByID(ctx context.Context, id int64) (res domain.Category, err error) {
+	ctx, span := startSpan(ctx, "CategoryRepository.GetByID", "find")
+	defer func() { endSpan(span, err) }()
+
+	var doc categoryDocument
+	err = m.collection().FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return res, domain.ErrNotFound
+	}
+	if err != nil {
+		return
+	}
+	return doc.category(), nil
+}
+
+func (m *CategoryRepository) Store(ctx context.Context, c *domain.Category) (err error) {
+	ctx, span := startSpan(ctx, "CategoryRepository.Store", "insert")
+	defer func() { endSpan(span, err) }()
+
+	id, err := nextID(ctx, m.DB, categoryCollection)
+	if err != nil {
+		return
+	}
+
+	// Mongo keeps the times to the millisecond, the category holds what is read back
+	now := time.Now().Truncate(time.Millisecond)
+	_, err = m.collection().InsertOne(ctx, categoryDocument{ID: id, Name: c.Name, CreatedAt: now, UpdatedAt: now})
+	if err != nil {
+		return
+	}
+
+	c.ID = id
+	c.CreatedAt = now
+	c.UpdatedAt = now
+	return
+}
+
+// Update will rename the category, the creation time is read back so the category is returned whole
+func (m *CategoryRepository) Update(ctx context.Context, c *domain.Category) (err error) {
+	ctx, span := startSpan(ctx, "CategoryRepository.Update", "update")
+	defer func() { endSpan(span, err) }()
+
+	var doc categoryDocument
+	err = m.collection().FindOneAndUpdate(ctx,
+		bson.D{{Key: "_id", Value: c.ID}},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "name", Value: c.Name},
+			{Key: "updated_at", Value: time.Now().Truncate(time.Millisecond)},
+		}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return
+	}
+	*c = doc.category()
+	return
+}
+
+func (m *CategoryRepository) Delete(ctx context.Context, id int64) (err error) {
+	ctx, span := startSpan(ctx, "CategoryRepository.Delete", "delete")
+	defer func() { endSpan(span, err) }()
+
+	res, err := m.collection().DeleteOne(ctx, bson.D{{Key: "_id", Value: id}})
+	if err != nil {
+		return
+	}
+	if res.DeletedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return
+}
+
+func (m *CategoryRepository) HasArticles(ctx context.Context, id int64) (res bool, err error) {
+	ctx, span := startSpan(ctx, "CategoryRepository.HasArticles", "count")
+	defer func() { endSpan(span, err) }()
+
+	n, err := m.DB.Collection(articleCollection).CountDocuments(ctx, bson.D{{Key: "category_id", Value: id}}, options.Count().SetLimit(1))
+	if err != nil {
+		return
+	}
+	return n > 0, nil
+}
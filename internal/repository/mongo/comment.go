@@ -0,0 +1,114 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"apismrtbiz/domain"
+)
+
+// commentDocument is the BSON mapping of domain.Comment
+type commentDocument struct {
+	ID        int64     `bson:"_id"`
+	ArticleID int64     `bson:"article_id"`
+	Author    string    `bson:"author"`
+	Content   string    `bson:"content"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+func (d commentDocument) comment() domain.Comment {
+	return domain.Comment{
+		ID:        d.ID,
+		ArticleID: d.ArticleID,
+		Author:    d.Author,
+		Content:   d.Content,
+		CreatedAt: d.CreatedAt,
+	}
+}
+
+type CommentRepository struct {
+	DB *mongo.Database
+}
+
+// NewCommentRepository will create an object that represent the comment.CommentRepository interface
+func NewCommentRepository(db *mongo.Database) *CommentRepository {
+	return &CommentRepository{db}
+}
+
+func (m *CommentRepository) collection() *mongo.Collection {
+	return m.DB.Collection(commentCollection)
+}
+
+// Fetch will list the comments of the article after the cursor, the oldest first
+func (m *CommentRepository) Fetch(ctx context.Context, articleID int64, cursor domain.Cursor, num int64) (res []domain.Comment, err error) {
+	ctx, span := startSpan(ctx, "CommentRepository.Fetch", "find")
+	defer func() { endSpan(span, err) }()
+
+	filter := bson.D{{Key: "article_id", Value: articleID}}
+	if !cursor.IsZero() {
+		createdAt, errCursor := cursor.Time()
+		if errCursor != nil {
+			return nil, errCursor
+		}
+		filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: "created_at", Value: bson.D{{Key: "$gt", Value: createdAt}}}},
+			bson.D{{Key: "created_at", Value: createdAt}, {Key: "_id", Value: bson.D{{Key: "$gt", Value: cursor.ID}}}},
+		}})
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(num)
+	cur, err := m.collection().Find(ctx, filter, opts)
+	if err != nil {
+		logrus.WithContext(ctx).Error(err)
+		return nil, err
+	}
+	defer func() {
+		errCur := cur.Close(ctx)
+		if errCur != nil {
+			logrus.WithContext(ctx).Error(errCur)
+		}
+	}()
+
+	res = make([]domain.Comment, 0)
+	for cur.Next(ctx) {
+		var doc commentDocument
+		if err = cur.Decode(&doc); err != nil {
+			logrus.WithContext(ctx).Error(err)
+			return nil, err
+		}
+		res = append(res, doc.comment())
+	}
+	return res, cur.Err()
+}
+
+func (m *CommentRepository) Store(ctx context.Context, c *domain.Comment) (err error) {
+	ctx, span := startSpan(ctx, "CommentRepository.Store", "insert")
+	defer func() { endSpan(span, err) }()
+
+	id, err := nextID(ctx, m.DB, commentCollection)
+	if err != nil {
+		return
+	}
+
+	// Mongo keeps the times to the millisecond, the comment holds what is read back
+	createdAt := time.Now().Truncate(time.Millisecond)
+	_, err = m.collection().InsertOne(ctx, commentDocument{
+		ID:        id,
+		ArticleID: c.ArticleID,
+		Author:    c.Author,
+		Content:   c.Content,
+		CreatedAt: createdAt,
+	})
+	if err != nil {
+		return
+	}
+
+	c.ID = id
+	c.CreatedAt = createdAt
+	return
+}
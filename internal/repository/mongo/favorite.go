@@ -0,0 +1,90 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"apismrtbiz/domain"
+)
+
+type FavoriteRepository struct {
+	DB *mongo.Database
+}
+
+// NewFavoriteRepository will create an object that represent the favorite.FavoriteRepository interface
+func NewFavoriteRepository(db *mongo.Database) *FavoriteRepository {
+	return &FavoriteRepository{db}
+}
+
+func (m *FavoriteRepository) collection() *mongo.Collection {
+	return m.DB.Collection(favoriteCollection)
+}
+
+// Add will record the favorite, an existing one is left untouched
+func (m *FavoriteRepository) Add(ctx context.Context, userID string, articleID int64) (err error) {
+	ctx, span := startSpan(ctx, "FavoriteRepository.Add", "update")
+	defer func() { endSpan(span, err) }()
+
+	_, err = m.collection().UpdateOne(ctx,
+		bson.D{{Key: "user_id", Value: userID}, {Key: "article_id", Value: articleID}},
+		bson.D{{Key: "$setOnInsert", Value: bson.D{{Key: "created_at", Value: time.Now().Truncate(time.Millisecond)}}}},
+		options.Update().SetUpsert(true),
+	)
+	return
+}
+
+// Remove will delete the favorite, if any
+func (m *FavoriteRepository) Remove(ctx context.Context, userID string, articleID int64) (err error) {
+	ctx, span := startSpan(ctx, "FavoriteRepository.Remove", "delete")
+	defer func() { endSpan(span, err) }()
+
+	_, err = m.collection().DeleteOne(ctx, bson.D{{Key: "user_id", Value: userID}, {Key: "article_id", Value: articleID}})
+	return
+}
+
+// Status will count the favorites of the articles, telling whether the user is among them,
+// the articles without any favorite are left out
+func (m *FavoriteRepository) Status(ctx context.Context, userID string, articleIDs []int64) (res map[int64]domain.FavoriteStatus, err error) {
+	ctx, span := startSpan(ctx, "FavoriteRepository.Status", "aggregate")
+	defer func() { endSpan(span, err) }()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "article_id", Value: bson.D{{Key: "$in", Value: articleIDs}}}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$article_id"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "favorited", Value: bson.D{{Key: "$max", Value: bson.D{{Key: "$eq", Value: bson.A{"$user_id", userID}}}}}},
+		}}},
+	}
+	cur, err := m.collection().Aggregate(ctx, pipeline)
+	if err != nil {
+		logrus.WithContext(ctx).Error(err)
+		return nil, err
+	}
+	defer func() {
+		errCur := cur.Close(ctx)
+		if errCur != nil {
+			logrus.WithContext(ctx).Error(errCur)
+		}
+	}()
+
+	res = make(map[int64]domain.FavoriteStatus)
+	for cur.Next(ctx) {
+		var doc struct {
+			ArticleID int64 `bson:"_id"`
+			Count     int64 `bson:"count"`
+			Favorited bool  `bson:"favorited"`
+		}
+		if err = cur.Decode(&doc); err != nil {
+			logrus.WithContext(ctx).Error(err)
+			return nil, err
+		}
+		res[doc.ArticleID] = domain.FavoriteStatus{Favorited: doc.Favorited, Count: doc.Count}
+	}
+	return res, cur.Err()
+}
@@ -0,0 +1,102 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"apismrtbiz/domain"
+)
+
+// revisionDocument is the BSON mapping of domain.Revision
+type revisionDocument struct {
+	ArticleID int64     `bson:"article_id"`
+	Version   int64     `bson:"version"`
+	Title     string    `bson:"title"`
+	Content   string    `bson:"content"`
+	Tags      []string  `bson:"tags,omitempty"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+func (d revisionDocument) revision() domain.Revision {
+	return domain.Revision{
+		ArticleID: d.ArticleID,
+		Version:   d.Version,
+		Title:     d.Title,
+		Content:   d.Content,
+		Tags:      d.Tags,
+		CreatedAt: d.CreatedAt,
+	}
+}
+
+func (m *ArticleRepository) revisions() *mongo.Collection {
+	return m.DB.Collection(revisionCollection)
+}
+
+// StoreRevision will record the snapshot of an article, in the transaction of the context when there is one
+func (m *ArticleRepository) StoreRevision(ctx context.Context, rev *domain.Revision) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.StoreRevision", "insert")
+	defer func() { endSpan(span, err) }()
+
+	rev.CreatedAt = time.Now().Truncate(time.Millisecond)
+	_, err = m.revisions().InsertOne(ctx, revisionDocument{
+		ArticleID: rev.ArticleID,
+		Version:   rev.Version,
+		Title:     rev.Title,
+		Content:   rev.Content,
+		Tags:      rev.Tags,
+		CreatedAt: rev.CreatedAt,
+	})
+	return
+}
+
+// ListRevisions will list the revisions of the article, the newest first
+func (m *ArticleRepository) ListRevisions(ctx context.Context, articleID int64) (res []domain.Revision, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.ListRevisions", "find")
+	defer func() { endSpan(span, err) }()
+
+	cur, err := m.revisions().Find(ctx, bson.D{{Key: "article_id", Value: articleID}},
+		options.Find().SetSort(bson.D{{Key: "version", Value: -1}}))
+	if err != nil {
+		logrus.WithContext(ctx).Error(err)
+		return nil, err
+	}
+	defer func() {
+		errCur := cur.Close(ctx)
+		if errCur != nil {
+			logrus.WithContext(ctx).Error(errCur)
+		}
+	}()
+
+	res = make([]domain.Revision, 0)
+	for cur.Next(ctx) {
+		var doc revisionDocument
+		if err = cur.Decode(&doc); err != nil {
+			logrus.WithContext(ctx).Error(err)
+			return nil, err
+		}
+		res = append(res, doc.revision())
+	}
+	return res, cur.Err()
+}
+
+// GetRevision will get the revision of the article at the given version
+func (m *ArticleRepository) GetRevision(ctx context.Context, articleID, version int64) (res domain.Revision, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.GetRevision", "find")
+	defer func() { endSpan(span, err) }()
+
+	var doc revisionDocument
+	err = m.revisions().FindOne(ctx, bson.D{{Key: "article_id", Value: articleID}, {Key: "version", Value: version}}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Revision{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.Revision{}, err
+	}
+	return doc.revision(), nil
+}
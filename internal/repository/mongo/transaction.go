@@ -0,0 +1,23 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// WithinTransaction will run fn in a transaction, committed when fn succeeds and aborted on any error.
+// The repository calls given the context of fn join the session of the transaction, and so does a nested
+// WithinTransaction. The transactions need the Mongo deployment to be a replica set.
+func (m *ArticleRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	return m.DB.Client().UseSession(ctx, func(sc mongo.SessionContext) error {
+		_, err := sc.WithTransaction(sc, func(sc mongo.SessionContext) (interface{}, error) {
+			return nil, fn(sc)
+		})
+		return err
+	})
+}
@@ -3,12 +3,15 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/sirupsen/logrus"
 
 	"apismrtbiz/domain"
-	"apismrtbiz/internal/repository"
 )
 
 type ArticleRepository struct {
@@ -21,16 +24,16 @@ func NewArticleRepository(conn *sql.DB) *ArticleRepository {
 }
 
 func (m *ArticleRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Article, err error) {
-	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	rows, err := m.conn(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
-		logrus.Error(err)
+		logrus.WithContext(ctx).Error(err)
 		return nil, err
 	}
 
 	defer func() {
 		errRow := rows.Close()
 		if errRow != nil {
-			logrus.Error(errRow)
+			logrus.WithContext(ctx).Error(errRow)
 		}
 	}()
 
@@ -38,6 +41,10 @@ func (m *ArticleRepository) fetch(ctx context.Context, query string, args ...int
 	for rows.Next() {
 		t := domain.Article{}
 		authorID := int64(0)
+		deletedAt := sql.NullTime{}
+		publishAt := sql.NullTime{}
+		categoryID := sql.NullInt64{}
+		tags := sql.NullString{}
 		err = rows.Scan(
 			&t.ID,
 			&t.Title,
@@ -45,44 +52,221 @@ func (m *ArticleRepository) fetch(ctx context.Context, query string, args ...int
 			&authorID,
 			&t.UpdatedAt,
 			&t.CreatedAt,
+			&deletedAt,
+			&t.Version,
+			&t.Status,
+			&publishAt,
+			&t.ViewCount,
+			&t.Slug,
+			&categoryID,
+			&tags,
 		)
 
 		if err != nil {
-			logrus.Error(err)
+			logrus.WithContext(ctx).Error(err)
 			return nil, err
 		}
 		t.Author = domain.Author{
 			ID: authorID,
 		}
+		if deletedAt.Valid {
+			t.DeletedAt = &deletedAt.Time
+		}
+		if publishAt.Valid {
+			t.PublishAt = &publishAt.Time
+		}
+		t.CategoryID = categoryID.Int64
+		if tags.String != "" {
+			t.Tags = strings.Split(tags.String, ",")
+		}
 		result = append(result, t)
 	}
 
 	return result, nil
 }
 
-func (m *ArticleRepository) Fetch(ctx context.Context, cursor string, num int64) (res []domain.Article, nextCursor string, err error) {
-	query := `SELECT id,title,content, author_id, updated_at, created_at
-  						FROM article WHERE created_at > ? ORDER BY created_at LIMIT ? `
+// categoryArg will store an uncategorized article with a NULL category
+func categoryArg(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
+
+// tagsColumn selects the tags of the article joined by commas, the tags never contain one
+const tagsColumn = `(SELECT GROUP_CONCAT(tag ORDER BY tag SEPARATOR ',') FROM article_tag WHERE article_tag.article_id = article.id) AS tags`
+
+// sortColumns maps the sort fields to their column, the column names are never taken from the input
+var sortColumns = map[domain.SortField]string{
+	domain.SortByUpdatedAt: "updated_at",
+	domain.SortByCreatedAt: "created_at",
+	domain.SortByTitle:     "title",
+}
 
-	decodedCursor, err := repository.DecodeCursor(cursor)
-	if err != nil && cursor != "" {
-		return nil, "", domain.ErrBadParamInput
+// cursorArg will turn the cursor value into the query argument of the sort field
+func cursorArg(field domain.SortField, cursor domain.Cursor) (interface{}, error) {
+	if field == domain.SortByTitle {
+		return cursor.Value, nil
 	}
+	return cursor.Time()
+}
 
-	res, err = m.fetch(ctx, query, decodedCursor, num)
-	if err != nil {
-		return nil, "", err
+// Fetch will list the articles after the cursor in the order of the filter, by default the most recently updated first.
+// The articles sharing the sort value of the cursor are told apart by their id, which breaks the ties of the ordering,
+// so no article is skipped or repeated by a cursor. When paging backward the query runs in the reverse ordering, so the articles are returned closest to the cursor first.
+func (m *ArticleRepository) Fetch(ctx context.Context, cursor domain.Cursor, num int64, filter domain.ArticleFilter) (res []domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Fetch", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	field := filter.Sort.Field
+	if field == "" {
+		field = domain.SortByUpdatedAt
 	}
+	column, ok := sortColumns[field]
+	if !ok {
+		return nil, domain.ErrBadParamInput
+	}
+	comparison, direction := "<", "DESC"
+	if (filter.Sort.Order == domain.SortAsc) != filter.Backward {
+		comparison, direction = ">", "ASC"
+	}
+
+	query := `SELECT id,title,content, author_id, updated_at, created_at, deleted_at, version, status, publish_at, view_count, slug, category_id, ` + tagsColumn + `
+  						FROM article`
 
-	if len(res) == int(num) {
-		nextCursor = repository.EncodeCursor(res[len(res)-1].CreatedAt)
+	conditions := make([]string, 0, 6)
+	args := make([]interface{}, 0, 6)
+	if !cursor.IsZero() {
+		value, errCursor := cursorArg(field, cursor)
+		if errCursor != nil {
+			return nil, errCursor
+		}
+		conditions = append(conditions, "("+column+" "+comparison+" ? OR ("+column+" = ? AND id "+comparison+" ?))")
+		args = append(args, value, value, cursor.ID)
+	}
+	if filter.AuthorID != 0 {
+		conditions = append(conditions, "author_id = ?")
+		args = append(args, filter.AuthorID)
+	}
+	if filter.CategoryID != 0 {
+		conditions = append(conditions, "category_id = ?")
+		args = append(args, filter.CategoryID)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.Tag != "" {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM article_tag WHERE article_tag.article_id = article.id AND article_tag.tag = ?)")
+		args = append(args, filter.Tag)
+	}
+	switch {
+	case !filter.CreatedFrom.IsZero() && !filter.CreatedTo.IsZero():
+		conditions = append(conditions, "created_at BETWEEN ? AND ?")
+		args = append(args, filter.CreatedFrom, filter.CreatedTo)
+	case !filter.CreatedFrom.IsZero():
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.CreatedFrom)
+	case !filter.CreatedTo.IsZero():
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, filter.CreatedTo)
 	}
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY ` + column + ` ` + direction + `, id ` + direction + ` LIMIT ? `
+
+	return m.fetch(ctx, query, append(args, num)...)
+}
+
+// likeEscaper will escape the wildcards of LIKE so the search term is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
 
+// withStatus will keep only the articles at the status on top of the conditions of the query,
+// an empty status keeps every stage
+func withStatus(query string, args []interface{}, status domain.Status) (string, []interface{}) {
+	if status == "" {
+		return query, args
+	}
+	return query + ` AND status = ?`, append(args, status)
+}
+
+// Search will list the articles at the status whose title or content contains the query, the most recently updated first,
+// starting after the cursor. The ties are broken by the id like in Fetch.
+func (m *ArticleRepository) Search(ctx context.Context, query string, num int64, cursor domain.Cursor, status domain.Status) (res []domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Search", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	pattern := "%" + likeEscaper.Replace(query) + "%"
+	sqlQuery := `SELECT id,title,content, author_id, updated_at, created_at, deleted_at, version, status, publish_at, view_count, slug, category_id, ` + tagsColumn + `
+  						FROM article WHERE (title LIKE ? OR content LIKE ?) AND deleted_at IS NULL`
+	sqlQuery, args := withStatus(sqlQuery, []interface{}{pattern, pattern}, status)
+	if !cursor.IsZero() {
+		updatedAt, errCursor := cursor.Time()
+		if errCursor != nil {
+			return nil, errCursor
+		}
+		sqlQuery += ` AND (updated_at < ? OR (updated_at = ? AND id < ?))`
+		args = append(args, updatedAt, updatedAt, cursor.ID)
+	}
+	sqlQuery += ` ORDER BY updated_at DESC, id DESC LIMIT ? `
+
+	return m.fetch(ctx, sqlQuery, append(args, num)...)
+}
+
+// FetchRelated will list the published articles sharing tags or the author with the given article,
+// the ones with the most in common first. Every shared tag counts as much as the shared author.
+func (m *ArticleRepository) FetchRelated(ctx context.Context, ar domain.Article, num int64) (res []domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.FetchRelated", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	query := `SELECT id,title,content, author_id, updated_at, created_at, deleted_at, version, status, publish_at, view_count, slug, category_id, ` + tagsColumn + `
+  						FROM article JOIN (
+  							SELECT candidate.id AS related_id, (candidate.author_id = ?) + (
+  								SELECT COUNT(*) FROM article_tag shared
+  								JOIN article_tag own ON own.tag = shared.tag AND own.article_id = ?
+  								WHERE shared.article_id = candidate.id) AS overlap
+  							FROM article candidate) related ON related.related_id = article.id
+  						WHERE related.overlap > 0 AND id <> ? AND status = ? AND deleted_at IS NULL
+  						ORDER BY related.overlap DESC, updated_at DESC LIMIT ?`
+
+	return m.fetch(ctx, query, ar.Author.ID, ar.ID, ar.ID, domain.StatusPublished, num)
+}
+
+// OffsetFetch will list a page of the articles at the status, an empty status lists every stage.
+// The most recently updated come first, the ties broken by the id so that the pages never overlap.
+func (m *ArticleRepository) OffsetFetch(ctx context.Context, offset, limit int64, status domain.Status) (res []domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.OffsetFetch", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	query, args := withStatus(`SELECT id,title,content, author_id, updated_at, created_at, deleted_at, version, status, publish_at, view_count, slug, category_id, `+tagsColumn+`
+  						FROM article WHERE deleted_at IS NULL`, nil, status)
+	query += ` ORDER BY updated_at DESC, id DESC LIMIT ? OFFSET ?`
+
+	return m.fetch(ctx, query, append(args, limit, offset)...)
+}
+
+// Count will count the articles at the status, an empty status counts every stage
+func (m *ArticleRepository) Count(ctx context.Context, status domain.Status) (total int64, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Count", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	query, args := withStatus(`SELECT COUNT(*) FROM article WHERE deleted_at IS NULL`, nil, status)
+
+	err = m.conn(ctx).QueryRowContext(ctx, query, args...).Scan(&total)
+	if err != nil {
+		logrus.WithContext(ctx).Error(err)
+		return 0, err
+	}
 	return
 }
+
 func (m *ArticleRepository) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
-	query := `SELECT id,title,content, author_id, updated_at, created_at
-  						FROM article WHERE ID = ?`
+	ctx, span := startSpan(ctx, "ArticleRepository.GetByID", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	query := `SELECT id,title,content, author_id, updated_at, created_at, deleted_at, version, status, publish_at, view_count, slug, category_id, ` + tagsColumn + `
+  						FROM article WHERE ID = ? AND deleted_at IS NULL`
 
 	list, err := m.fetch(ctx, query, id)
 	if err != nil {
@@ -98,9 +282,32 @@ func (m *ArticleRepository) GetByID(ctx context.Context, id int64) (res domain.A
 	return
 }
 
+func (m *ArticleRepository) GetByIDs(ctx context.Context, ids []int64) (res []domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.GetByIDs", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	if len(ids) == 0 {
+		return []domain.Article{}, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	query := `SELECT id,title,content, author_id, updated_at, created_at, deleted_at, version, status, publish_at, view_count, slug, category_id, ` + tagsColumn + `
+  						FROM article WHERE id IN (` + placeholders + `) AND deleted_at IS NULL`
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	return m.fetch(ctx, query, args...)
+}
+
 func (m *ArticleRepository) GetByTitle(ctx context.Context, title string) (res domain.Article, err error) {
-	query := `SELECT id,title,content, author_id, updated_at, created_at
-  						FROM article WHERE title = ?`
+	ctx, span := startSpan(ctx, "ArticleRepository.GetByTitle", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	query := `SELECT id,title,content, author_id, updated_at, created_at, deleted_at, version, status, publish_at, view_count, slug, category_id, ` + tagsColumn + `
+  						FROM article WHERE LOWER(TRIM(title)) = LOWER(TRIM(?)) AND deleted_at IS NULL`
 
 	list, err := m.fetch(ctx, query, title)
 	if err != nil {
@@ -115,29 +322,200 @@ func (m *ArticleRepository) GetByTitle(ctx context.Context, title string) (res d
 	return
 }
 
-func (m *ArticleRepository) Store(ctx context.Context, a *domain.Article) (err error) {
-	query := `INSERT  article SET title=? , content=? , author_id=?, updated_at=? , created_at=?`
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+// GetBySlug will get the article of the given slug, a soft deleted one included since its slug stays taken
+func (m *ArticleRepository) GetBySlug(ctx context.Context, slug string) (res domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.GetBySlug", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	query := `SELECT id,title,content, author_id, updated_at, created_at, deleted_at, version, status, publish_at, view_count, slug, category_id, ` + tagsColumn + `
+  						FROM article WHERE slug = ?`
+
+	list, err := m.fetch(ctx, query, slug)
 	if err != nil {
 		return
 	}
 
-	res, err := stmt.ExecContext(ctx, a.Title, a.Content, a.Author.ID, a.UpdatedAt, a.CreatedAt)
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+	return
+}
+
+func (m *ArticleRepository) Store(ctx context.Context, a *domain.Article) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Store", "INSERT")
+	defer func() { endSpan(span, err) }()
+
+	tx, err := beginTx(ctx, m.Conn)
+	if err != nil {
+		return
+	}
+	defer func() { err = finishTx(ctx, tx, err) }()
+
+	now := time.Now()
+	a.CreatedAt = now
+	a.UpdatedAt = now
+
+	query := `INSERT  article SET title=? , content=? , author_id=?, updated_at=? , created_at=?, version=1, status=?, publish_at=?, slug=?, category_id=?`
+	args := []interface{}{a.Title, a.Content, a.Author.ID, a.UpdatedAt, a.CreatedAt, a.Status, a.PublishAt, a.Slug, categoryArg(a.CategoryID)}
+	// an article given its id keeps it, the auto increment moves past it by itself
+	if a.ID != 0 {
+		query += `, id=?`
+		args = append(args, a.ID)
+	}
+	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
-	lastID, err := res.LastInsertId()
+
+	res, err := stmt.ExecContext(ctx, args...)
 	if err != nil {
+		if isDuplicate(err) {
+			err = domain.ErrConflict
+		}
+		return
+	}
+	lastID := a.ID
+	if lastID == 0 {
+		if lastID, err = res.LastInsertId(); err != nil {
+			return
+		}
+	}
+	if err = insertTags(ctx, tx, lastID, a.Tags); err != nil {
 		return
 	}
 	a.ID = lastID
+	a.Version = 1
 	return
 }
 
+// isDuplicate tells whether err is MySQL refusing a row whose id or slug is already taken
+func isDuplicate(err error) bool {
+	var me *mysql.MySQLError
+	return errors.As(err, &me) && me.Number == 1062
+}
+
+// insertTags will attach the tags to the article within the transaction
+func insertTags(ctx context.Context, tx executor, id int64, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("(?, ?),", len(tags)), ",")
+	args := make([]interface{}, 0, 2*len(tags))
+	for _, tag := range tags {
+		args = append(args, id, tag)
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO article_tag (article_id, tag) VALUES `+placeholders, args...)
+	return err
+}
+
 func (m *ArticleRepository) Delete(ctx context.Context, id int64) (err error) {
-	query := "DELETE FROM article WHERE id = ?"
+	ctx, span := startSpan(ctx, "ArticleRepository.Delete", "UPDATE")
+	defer func() { endSpan(span, err) }()
+
+	query := "UPDATE article SET deleted_at=? WHERE id = ? AND deleted_at IS NULL"
+
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, time.Now(), id)
+	if err != nil {
+		return
+	}
+
+	rowsAfected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAfected != 1 {
+		err = fmt.Errorf("weird  Behavior. Total Affected: %d", rowsAfected)
+		return
+	}
+
+	return
+}
+
+func (m *ArticleRepository) Restore(ctx context.Context, id int64) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Restore", "UPDATE")
+	defer func() { endSpan(span, err) }()
+
+	query := "UPDATE article SET deleted_at=NULL WHERE id = ? AND deleted_at IS NOT NULL"
+
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return
+	}
+
+	rowsAfected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAfected == 0 {
+		return domain.ErrNotFound
+	}
+
+	if rowsAfected != 1 {
+		err = fmt.Errorf("weird  Behavior. Total Affected: %d", rowsAfected)
+		return
+	}
+
+	return
+}
+
+// SetStatus will move the article to the stage of the publishing workflow, it counts as an update of the article
+func (m *ArticleRepository) SetStatus(ctx context.Context, id int64, status domain.Status) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.SetStatus", "UPDATE")
+	defer func() { endSpan(span, err) }()
+
+	query := "UPDATE article SET status=?, updated_at=?, version=version+1 WHERE id = ? AND deleted_at IS NULL"
+
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, status, time.Now(), id)
+	if err != nil {
+		return
+	}
+
+	rowsAfected, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if rowsAfected == 0 {
+		return domain.ErrNotFound
+	}
 
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if rowsAfected != 1 {
+		err = fmt.Errorf("weird  Behavior. Total Affected: %d", rowsAfected)
+		return
+	}
+
+	return
+}
+
+// IncrementViews will count one more view of the article, the increment is done by the database
+// so the concurrent views are never lost
+func (m *ArticleRepository) IncrementViews(ctx context.Context, id int64) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.IncrementViews", "UPDATE")
+	defer func() { endSpan(span, err) }()
+
+	query := "UPDATE article SET view_count = view_count + 1 WHERE id = ? AND deleted_at IS NULL"
+
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
 	if err != nil {
 		return
 	}
@@ -152,6 +530,10 @@ func (m *ArticleRepository) Delete(ctx context.Context, id int64) (err error) {
 		return
 	}
 
+	if rowsAfected == 0 {
+		return domain.ErrNotFound
+	}
+
 	if rowsAfected != 1 {
 		err = fmt.Errorf("weird  Behavior. Total Affected: %d", rowsAfected)
 		return
@@ -159,15 +541,77 @@ func (m *ArticleRepository) Delete(ctx context.Context, id int64) (err error) {
 
 	return
 }
+
+// PublishDue will publish the drafts whose publish_at has passed and return their ids.
+// The drafts are locked in a transaction, so two workers running at once don't publish an article twice.
+func (m *ArticleRepository) PublishDue(ctx context.Context, now time.Time) (ids []int64, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.PublishDue", "UPDATE")
+	defer func() { endSpan(span, err) }()
+
+	tx, err := beginTx(ctx, m.Conn)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = finishTx(ctx, tx, err) }()
+
+	query := `SELECT id FROM article WHERE status = ? AND publish_at <= ? AND deleted_at IS NULL FOR UPDATE`
+	rows, err := tx.QueryContext(ctx, query, domain.StatusDraft, now)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	query = `UPDATE article SET status=?, updated_at=?, version=version+1 WHERE id IN (` + placeholders + `) AND status = ?`
+	args := make([]interface{}, 0, len(ids)+3)
+	args = append(args, domain.StatusPublished, now)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	args = append(args, domain.StatusDraft)
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Ping will check the connection to the database is still alive
+func (m *ArticleRepository) Ping(ctx context.Context) error {
+	return m.Conn.PingContext(ctx)
+}
+
 func (m *ArticleRepository) Update(ctx context.Context, ar *domain.Article) (err error) {
-	query := `UPDATE article set title=?, content=?, author_id=?, updated_at=? WHERE ID = ?`
+	ctx, span := startSpan(ctx, "ArticleRepository.Update", "UPDATE")
+	defer func() { endSpan(span, err) }()
 
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	tx, err := beginTx(ctx, m.Conn)
 	if err != nil {
 		return
 	}
+	defer func() { err = finishTx(ctx, tx, err) }()
 
-	res, err := stmt.ExecContext(ctx, ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt, ar.ID)
+	query := `UPDATE article set title=?, content=?, author_id=?, updated_at=?, publish_at=?, category_id=?, version=version+1 WHERE ID = ? AND version = ?`
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	updatedAt := time.Now()
+	res, err := stmt.ExecContext(ctx, ar.Title, ar.Content, ar.Author.ID, updatedAt, ar.PublishAt, categoryArg(ar.CategoryID), ar.ID, ar.Version)
 	if err != nil {
 		return
 	}
@@ -175,10 +619,24 @@ func (m *ArticleRepository) Update(ctx context.Context, ar *domain.Article) (err
 	if err != nil {
 		return
 	}
+	if affect == 0 {
+		return domain.ErrConflict
+	}
 	if affect != 1 {
 		err = fmt.Errorf("weird  Behavior. Total Affected: %d", affect)
 		return
 	}
 
+	// the tags are replaced as a whole like the other fields
+	if _, err = tx.ExecContext(ctx, "DELETE FROM article_tag WHERE article_id = ?", ar.ID); err != nil {
+		return
+	}
+	if err = insertTags(ctx, tx, ar.ID, ar.Tags); err != nil {
+		return
+	}
+
+	ar.UpdatedAt = updatedAt
+	ar.Version++
+
 	return
 }
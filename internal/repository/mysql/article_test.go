@@ -2,14 +2,16 @@ package mysql_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
 
 	"apismrtbiz/domain"
-	"apismrtbiz/internal/repository"
 	articleMysqlRepo "apismrtbiz/internal/repository/mysql"
 )
 
@@ -30,34 +32,363 @@ func TestFetchArticle(t *testing.T) {
 		},
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}).
 		AddRow(mockArticles[0].ID, mockArticles[0].Title, mockArticles[0].Content,
-			mockArticles[0].Author.ID, mockArticles[0].UpdatedAt, mockArticles[0].CreatedAt).
+			mockArticles[0].Author.ID, mockArticles[0].UpdatedAt, mockArticles[0].CreatedAt, nil, 1, "published", nil, 0, mockArticles[0].Slug, nil, nil).
 		AddRow(mockArticles[1].ID, mockArticles[1].Title, mockArticles[1].Content,
-			mockArticles[1].Author.ID, mockArticles[1].UpdatedAt, mockArticles[1].CreatedAt)
+			mockArticles[1].Author.ID, mockArticles[1].UpdatedAt, mockArticles[1].CreatedAt, nil, 1, "published", nil, 0, mockArticles[1].Slug, nil, nil)
 
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article WHERE created_at > \\? ORDER BY created_at LIMIT \\?"
+	query := "SELECT id,title,content, author_id, updated_at, created_at, deleted_at, version, status, publish_at, view_count, slug, category_id, \\(SELECT GROUP_CONCAT\\(tag ORDER BY tag SEPARATOR ','\\) FROM article_tag WHERE article_tag.article_id = article.id\\) AS tags FROM article WHERE \\(updated_at < \\? OR \\(updated_at = \\? AND id < \\?\\)\\) AND deleted_at IS NULL ORDER BY updated_at DESC, id DESC LIMIT \\?"
 
 	mock.ExpectQuery(query).WillReturnRows(rows)
 	a := articleMysqlRepo.NewArticleRepository(db)
-	cursor := repository.EncodeCursor(mockArticles[1].CreatedAt)
+	cursor := domain.NewCursor(domain.SortByUpdatedAt, mockArticles[1])
 	num := int64(2)
-	list, nextCursor, err := a.Fetch(context.TODO(), cursor, num)
-	assert.NotEmpty(t, nextCursor)
+	list, err := a.Fetch(context.TODO(), cursor, num, domain.ArticleFilter{})
 	assert.NoError(t, err)
 	assert.Len(t, list, 2)
 }
 
+func TestFetchArticleFirstPage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	newer := time.Now()
+	older := newer.Add(-time.Hour)
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}).
+		AddRow(2, "title 2", "Content 2", 1, newer, older, nil, 2, "published", nil, 0, "title-2", nil, nil).
+		AddRow(1, "title 1", "Content 1", 1, older, older, nil, 1, "published", nil, 0, "title-1", nil, nil)
+
+	query := "SELECT id,title,content, author_id, updated_at, created_at, deleted_at, version, status, publish_at, view_count, slug, category_id, \\(SELECT GROUP_CONCAT\\(tag ORDER BY tag SEPARATOR ','\\) FROM article_tag WHERE article_tag.article_id = article.id\\) AS tags FROM article WHERE deleted_at IS NULL ORDER BY updated_at DESC, id DESC LIMIT \\?"
+
+	mock.ExpectQuery(query).WithArgs(int64(2)).WillReturnRows(rows)
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	list, err := a.Fetch(context.TODO(), domain.Cursor{}, 2, domain.ArticleFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, int64(2), list[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchArticleSort(t *testing.T) {
+	timeCursor := domain.Cursor{ID: 1, Value: time.Now().Format(time.RFC3339Nano)}
+	titleCursor := domain.Cursor{ID: 1, Value: "b"}
+	tests := []struct {
+		name    string
+		sort    domain.ArticleSort
+		cursor  domain.Cursor
+		orderBy string
+	}{
+		{"default", domain.ArticleSort{}, timeCursor, "\\(updated_at < \\? OR \\(updated_at = \\? AND id < \\?\\)\\) AND deleted_at IS NULL ORDER BY updated_at DESC, id DESC"},
+		{"title-asc", domain.ArticleSort{Field: domain.SortByTitle, Order: domain.SortAsc}, titleCursor, "\\(title > \\? OR \\(title = \\? AND id > \\?\\)\\) AND deleted_at IS NULL ORDER BY title ASC, id ASC"},
+		{"title-desc", domain.ArticleSort{Field: domain.SortByTitle, Order: domain.SortDesc}, titleCursor, "\\(title < \\? OR \\(title = \\? AND id < \\?\\)\\) AND deleted_at IS NULL ORDER BY title DESC, id DESC"},
+		{"created-at-asc", domain.ArticleSort{Field: domain.SortByCreatedAt, Order: domain.SortAsc}, timeCursor, "\\(created_at > \\? OR \\(created_at = \\? AND id > \\?\\)\\) AND deleted_at IS NULL ORDER BY created_at ASC, id ASC"},
+		{"created-at-desc", domain.ArticleSort{Field: domain.SortByCreatedAt, Order: domain.SortDesc}, timeCursor, "\\(created_at < \\? OR \\(created_at = \\? AND id < \\?\\)\\) AND deleted_at IS NULL ORDER BY created_at DESC, id DESC"},
+		{"updated-at-asc", domain.ArticleSort{Field: domain.SortByUpdatedAt, Order: domain.SortAsc}, timeCursor, "\\(updated_at > \\? OR \\(updated_at = \\? AND id > \\?\\)\\) AND deleted_at IS NULL ORDER BY updated_at ASC, id ASC"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+
+			rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}).
+				AddRow(1, "c", "Content 1", 1, time.Now(), time.Now(), nil, 1, "published", nil, 0, "c", nil, nil)
+			mock.ExpectQuery("FROM article WHERE " + tc.orderBy + " LIMIT \\?").WillReturnRows(rows)
+			a := articleMysqlRepo.NewArticleRepository(db)
+
+			list, err := a.Fetch(context.TODO(), tc.cursor, 1, domain.ArticleFilter{Sort: tc.sort})
+			assert.NoError(t, err)
+			assert.Len(t, list, 1)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("unknown-field", func(t *testing.T) {
+		db, _, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		a := articleMysqlRepo.NewArticleRepository(db)
+
+		_, err = a.Fetch(context.TODO(), domain.Cursor{}, 1, domain.ArticleFilter{Sort: domain.ArticleSort{Field: "content"}})
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	})
+
+	t.Run("cursor-of-another-field", func(t *testing.T) {
+		db, _, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		a := articleMysqlRepo.NewArticleRepository(db)
+
+		_, err = a.Fetch(context.TODO(), titleCursor, 1, domain.ArticleFilter{Sort: domain.ArticleSort{Field: domain.SortByCreatedAt}})
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	})
+}
+
+func TestFetchArticleBackward(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	// the dataset, most recently updated first: 1, 2, 3, 4
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	updatedAt := func(id int) time.Time { return base.Add(-time.Duration(id) * time.Hour) }
+	columns := []string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	// backward from 3 lists 2, 1 closest to the cursor first
+	mock.ExpectQuery("WHERE \\(updated_at > \\? OR \\(updated_at = \\? AND id > \\?\\)\\) AND deleted_at IS NULL ORDER BY updated_at ASC, id ASC LIMIT \\?").
+		WithArgs(updatedAt(3), updatedAt(3), int64(3), int64(2)).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(2, "title 2", "Content 2", 1, updatedAt(2), base, nil, 1, "published", nil, 0, "title-2", nil, nil).
+			AddRow(1, "title 1", "Content 1", 1, updatedAt(1), base, nil, 1, "published", nil, 0, "title-1", nil, nil))
+
+	cursor := domain.Cursor{ID: 3, Value: updatedAt(3).Format(time.RFC3339Nano)}
+	list, err := a.Fetch(context.TODO(), cursor, 2, domain.ArticleFilter{Backward: true})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, int64(2), list[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchArticleByAuthor(t *testing.T) {
+	t.Run("filtered", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		cursorTime := time.Now()
+		rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}).
+			AddRow(4, "title 4", "Content 4", 3, cursorTime.Add(-time.Hour), time.Now(), nil, 1, "published", nil, 0, "title-4", nil, nil)
+		query := "FROM article WHERE \\(updated_at < \\? OR \\(updated_at = \\? AND id < \\?\\)\\) AND author_id = \\? AND deleted_at IS NULL ORDER BY updated_at DESC, id DESC LIMIT \\?"
+		mock.ExpectQuery(query).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), int64(3), int64(1)).WillReturnRows(rows)
+		a := articleMysqlRepo.NewArticleRepository(db)
+
+		cursor := domain.Cursor{ID: 5, Value: cursorTime.Format(time.RFC3339Nano)}
+		list, err := a.Fetch(context.TODO(), cursor, 1, domain.ArticleFilter{AuthorID: 3})
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		assert.Equal(t, int64(3), list[0].Author.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no-articles", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"})
+		query := "FROM article WHERE author_id = \\? AND deleted_at IS NULL ORDER BY updated_at DESC, id DESC LIMIT \\?"
+		mock.ExpectQuery(query).WithArgs(int64(9), int64(10)).WillReturnRows(rows)
+		a := articleMysqlRepo.NewArticleRepository(db)
+
+		list, err := a.Fetch(context.TODO(), domain.Cursor{}, 10, domain.ArticleFilter{AuthorID: 9})
+		assert.NoError(t, err)
+		assert.Empty(t, list)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFetchArticleByStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}).
+		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now(), nil, 1, "published", nil, 0, "title-1", nil, nil)
+	query := "FROM article WHERE status = \\? AND deleted_at IS NULL ORDER BY updated_at DESC, id DESC LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs(domain.StatusPublished, int64(10)).WillReturnRows(rows)
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	list, err := a.Fetch(context.TODO(), domain.Cursor{}, 10, domain.ArticleFilter{Status: domain.StatusPublished})
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, domain.StatusPublished, list[0].Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchArticleByTag(t *testing.T) {
+	tagCondition := "EXISTS \\(SELECT 1 FROM article_tag WHERE article_tag.article_id = article.id AND article_tag.tag = \\?\\)"
+	columns := []string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}
+
+	t.Run("filtered", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		cursorTime := time.Now()
+		rows := sqlmock.NewRows(columns).
+			AddRow(4, "title 4", "Content 4", 1, cursorTime.Add(-time.Hour), time.Now(), nil, 1, "published", nil, 0, "title-4", nil, "golang,web")
+		query := "FROM article WHERE \\(updated_at < \\? OR \\(updated_at = \\? AND id < \\?\\)\\) AND " + tagCondition + " AND deleted_at IS NULL ORDER BY updated_at DESC, id DESC LIMIT \\?"
+		mock.ExpectQuery(query).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "golang", int64(1)).WillReturnRows(rows)
+		a := articleMysqlRepo.NewArticleRepository(db)
+
+		cursor := domain.Cursor{ID: 5, Value: cursorTime.Format(time.RFC3339Nano)}
+		list, err := a.Fetch(context.TODO(), cursor, 1, domain.ArticleFilter{Tag: "golang"})
+		assert.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, []string{"golang", "web"}, list[0].Tags)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no-match", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		query := "FROM article WHERE " + tagCondition + " AND deleted_at IS NULL ORDER BY updated_at DESC, id DESC LIMIT \\?"
+		mock.ExpectQuery(query).WithArgs("rust", int64(10)).WillReturnRows(sqlmock.NewRows(columns))
+		a := articleMysqlRepo.NewArticleRepository(db)
+
+		list, err := a.Fetch(context.TODO(), domain.Cursor{}, 10, domain.ArticleFilter{Tag: "rust"})
+		assert.NoError(t, err)
+		assert.Empty(t, list)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFetchArticleCreatedRange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	cursorTime := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}).
+		AddRow(1, "title 1", "Content 1", 2, cursorTime.Add(-time.Hour), from.Add(time.Hour), nil, 1, "published", nil, 0, "title-1", nil, nil)
+	query := "FROM article WHERE \\(updated_at < \\? OR \\(updated_at = \\? AND id < \\?\\)\\) AND created_at BETWEEN \\? AND \\? AND deleted_at IS NULL ORDER BY updated_at DESC, id DESC LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), from, to, int64(1)).WillReturnRows(rows)
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	cursor := domain.Cursor{ID: 2, Value: cursorTime.Format(time.RFC3339Nano)}
+	list, err := a.Fetch(context.TODO(), cursor, 1, domain.ArticleFilter{CreatedFrom: from, CreatedTo: to})
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchArticleIncludeDeleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	deletedAt := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}).
+		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now(), nil, 1, "published", nil, 0, "title-1", nil, nil).
+		AddRow(2, "title 2", "Content 2", 1, time.Now(), time.Now(), deletedAt, 1, "published", nil, 0, "title-2", nil, nil)
+
+	query := "SELECT id,title,content, author_id, updated_at, created_at, deleted_at, version, status, publish_at, view_count, slug, category_id, \\(SELECT GROUP_CONCAT\\(tag ORDER BY tag SEPARATOR ','\\) FROM article_tag WHERE article_tag.article_id = article.id\\) AS tags FROM article ORDER BY updated_at DESC, id DESC LIMIT \\?"
+
+	mock.ExpectQuery(query).WithArgs(int64(10)).WillReturnRows(rows)
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	list, err := a.Fetch(context.TODO(), domain.Cursor{}, 10, domain.ArticleFilter{IncludeDeleted: true})
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Nil(t, list[0].DeletedAt)
+	if assert.NotNil(t, list[1].DeletedAt) {
+		assert.True(t, deletedAt.Equal(*list[1].DeletedAt))
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchRelatedArticle(t *testing.T) {
+	columns := []string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}
+	query := "FROM article JOIN (.+) related ON related.related_id = article.id WHERE related.overlap > 0 AND id <> \\? AND status = \\? AND deleted_at IS NULL ORDER BY related.overlap DESC, updated_at DESC LIMIT \\?"
+	ar := domain.Article{ID: 1, Author: domain.Author{ID: 2}, Tags: []string{"golang"}}
+
+	t.Run("related", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		rows := sqlmock.NewRows(columns).
+			AddRow(3, "title 3", "Content 3", 2, time.Now(), time.Now(), nil, 1, "published", nil, 0, "title-3", nil, "golang").
+			AddRow(4, "title 4", "Content 4", 5, time.Now(), time.Now(), nil, 1, "published", nil, 0, "title-4", nil, "golang")
+		mock.ExpectQuery(query).WithArgs(int64(2), int64(1), int64(1), domain.StatusPublished, int64(5)).WillReturnRows(rows)
+		a := articleMysqlRepo.NewArticleRepository(db)
+
+		list, err := a.FetchRelated(context.TODO(), ar, 5)
+		assert.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, int64(3), list[0].ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("nothing-related", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		mock.ExpectQuery(query).WithArgs(int64(2), int64(1), int64(1), domain.StatusPublished, int64(5)).WillReturnRows(sqlmock.NewRows(columns))
+		a := articleMysqlRepo.NewArticleRepository(db)
+
+		list, err := a.FetchRelated(context.TODO(), ar, 5)
+		assert.NoError(t, err)
+		assert.NotNil(t, list)
+		assert.Empty(t, list)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOffsetFetchArticle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}).
+		AddRow(3, "title 3", "Content 3", 1, time.Now(), time.Now(), nil, 1, "published", nil, 0, "title-3", nil, nil)
+
+	query := "SELECT id,title,content, author_id, updated_at, created_at, deleted_at, version, status, publish_at, view_count, slug, category_id, \\(SELECT GROUP_CONCAT\\(tag ORDER BY tag SEPARATOR ','\\) FROM article_tag WHERE article_tag.article_id = article.id\\) AS tags FROM article WHERE deleted_at IS NULL AND status = \\? ORDER BY updated_at DESC, id DESC LIMIT \\? OFFSET \\?"
+
+	mock.ExpectQuery(query).WithArgs(domain.StatusPublished, int64(2), int64(2)).WillReturnRows(rows)
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	list, err := a.OffsetFetch(context.TODO(), 2, 2, domain.StatusPublished)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+}
+
+func TestCountArticle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"count"}).AddRow(7)
+
+	query := "SELECT COUNT\\(\\*\\) FROM article WHERE deleted_at IS NULL AND status = \\?"
+
+	mock.ExpectQuery(query).WithArgs(domain.StatusPublished).WillReturnRows(rows)
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	total, err := a.Count(context.TODO(), domain.StatusPublished)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), total)
+}
+
 func TestGetArticleByID(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
-		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}).
+		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now(), nil, 1, "published", nil, 0, "title-1", nil, nil)
 
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article WHERE ID = \\?"
+	query := "SELECT id,title,content, author_id, updated_at, created_at, deleted_at, version, status, publish_at, view_count, slug, category_id, \\(SELECT GROUP_CONCAT\\(tag ORDER BY tag SEPARATOR ','\\) FROM article_tag WHERE article_tag.article_id = article.id\\) AS tags FROM article WHERE ID = \\? AND deleted_at IS NULL"
 
 	mock.ExpectQuery(query).WillReturnRows(rows)
 	a := articleMysqlRepo.NewArticleRepository(db)
@@ -72,6 +403,7 @@ func TestStoreArticle(t *testing.T) {
 	now := time.Now()
 	ar := &domain.Article{
 		Title:     "Judul",
+		Slug:      "judul",
 		Content:   "Content",
 		CreatedAt: now,
 		UpdatedAt: now,
@@ -79,21 +411,179 @@ func TestStoreArticle(t *testing.T) {
 			ID:   1,
 			Name: "Iman Tumorang",
 		},
+		Status:     domain.StatusDraft,
+		Tags:       []string{"go", "web"},
+		CategoryID: 5,
 	}
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	query := "INSERT  article SET title=\\? , content=\\? , author_id=\\?, updated_at=\\? , created_at=\\?"
+	query := "INSERT  article SET title=\\? , content=\\? , author_id=\\?, updated_at=\\? , created_at=\\?, version=1, status=\\?, publish_at=\\?, slug=\\?, category_id=\\?"
+	mock.ExpectBegin()
 	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.CreatedAt, ar.UpdatedAt).WillReturnResult(sqlmock.NewResult(12, 1))
+	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, sqlmock.AnyArg(), sqlmock.AnyArg(), ar.Status, ar.PublishAt, ar.Slug, ar.CategoryID).WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectExec("INSERT INTO article_tag \\(article_id, tag\\) VALUES \\(\\?, \\?\\),\\(\\?, \\?\\)").
+		WithArgs(int64(12), "go", int64(12), "web").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
 
 	a := articleMysqlRepo.NewArticleRepository(db)
 
 	err = a.Store(context.TODO(), ar)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(12), ar.ID)
+	assert.False(t, ar.CreatedAt.Before(now))
+	assert.Equal(t, ar.CreatedAt, ar.UpdatedAt)
+	assert.Equal(t, int64(1), ar.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreArticleGivenID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	ar := &domain.Article{ID: 42, Title: "Judul", Slug: "judul", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusDraft}
+	query := "INSERT  article SET title=\\? , content=\\? , author_id=\\?, updated_at=\\? , created_at=\\?, version=1, status=\\?, publish_at=\\?, slug=\\?, category_id=\\?, id=\\?"
+	mock.ExpectBegin()
+	mock.ExpectPrepare(query).ExpectExec().
+		WithArgs(ar.Title, ar.Content, ar.Author.ID, sqlmock.AnyArg(), sqlmock.AnyArg(), ar.Status, ar.PublishAt, ar.Slug, nil, int64(42)).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '42' for key 'PRIMARY'"})
+	mock.ExpectRollback()
+
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	err = a.Store(context.TODO(), ar)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithinTransaction(t *testing.T) {
+	query := "INSERT  article SET title=\\? , content=\\? , author_id=\\?, updated_at=\\? , created_at=\\?, version=1, status=\\?, publish_at=\\?, slug=\\?, category_id=\\?"
+
+	t.Run("commit", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		// both stores join the one transaction
+		mock.ExpectBegin()
+		mock.ExpectPrepare(query).ExpectExec().WillReturnResult(sqlmock.NewResult(12, 1))
+		mock.ExpectPrepare(query).ExpectExec().WillReturnResult(sqlmock.NewResult(13, 1))
+		mock.ExpectCommit()
+		a := articleMysqlRepo.NewArticleRepository(db)
+
+		err = a.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			if err := a.Store(ctx, &domain.Article{Title: "One", Content: "Content"}); err != nil {
+				return err
+			}
+			return a.Store(ctx, &domain.Article{Title: "Two", Content: "Content"})
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		mock.ExpectBegin()
+		mock.ExpectPrepare(query).ExpectExec().WillReturnResult(sqlmock.NewResult(12, 1))
+		mock.ExpectPrepare(query).ExpectExec().WillReturnError(errors.New("duplicate entry"))
+		mock.ExpectRollback()
+		a := articleMysqlRepo.NewArticleRepository(db)
+
+		err = a.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			if err := a.Store(ctx, &domain.Article{Title: "One", Content: "Content"}); err != nil {
+				return err
+			}
+			return a.Store(ctx, &domain.Article{Title: "Two", Content: "Content"})
+		})
+		assert.EqualError(t, err, "duplicate entry")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestStoreRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rev := &domain.Revision{ArticleID: 12, Version: 2, Title: "Judul", Content: "Content", Tags: []string{"go", "web"}}
+	mock.ExpectExec("INSERT INTO article_revisions \\(article_id, version, title, content, tags, created_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?\\)").
+		WithArgs(int64(12), int64(2), "Judul", "Content", "go,web", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := articleMysqlRepo.NewArticleRepository(db)
+	require.NoError(t, a.StoreRevision(context.TODO(), rev))
+	assert.False(t, rev.CreatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListRevisions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"article_id", "version", "title", "content", "tags", "created_at"}).
+		AddRow(12, 2, "Second", "Content", "", time.Now()).
+		AddRow(12, 1, "First", "Content", "go,web", time.Now())
+	mock.ExpectQuery("SELECT article_id, version, title, content, tags, created_at FROM article_revisions WHERE article_id = \\? ORDER BY version DESC").
+		WithArgs(int64(12)).WillReturnRows(rows)
+
+	a := articleMysqlRepo.NewArticleRepository(db)
+	list, err := a.ListRevisions(context.TODO(), 12)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, int64(2), list[0].Version)
+	assert.Nil(t, list[0].Tags)
+	assert.Equal(t, []string{"go", "web"}, list[1].Tags)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "SELECT article_id, version, title, content, tags, created_at FROM article_revisions WHERE article_id = \\? AND version = \\?"
+	rows := sqlmock.NewRows([]string{"article_id", "version", "title", "content", "tags", "created_at"}).
+		AddRow(12, 3, "Third", "Content", "go", time.Now())
+	mock.ExpectQuery(query).WithArgs(int64(12), int64(3)).WillReturnRows(rows)
+	mock.ExpectQuery(query).WithArgs(int64(12), int64(9)).
+		WillReturnRows(sqlmock.NewRows([]string{"article_id", "version", "title", "content", "tags", "created_at"}))
+
+	a := articleMysqlRepo.NewArticleRepository(db)
+	rev, err := a.GetRevision(context.TODO(), 12, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "Third", rev.Title)
+	assert.Equal(t, []string{"go"}, rev.Tags)
+
+	_, err = a.GetRevision(context.TODO(), 12, 9)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticleByIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}).
+		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now(), nil, 1, "published", nil, 0, "title-1", nil, nil).
+		AddRow(3, "title 3", "Content 3", 1, time.Now(), time.Now(), nil, 1, "published", nil, 0, "title-3", nil, nil)
+
+	query := "SELECT id,title,content, author_id, updated_at, created_at, deleted_at, version, status, publish_at, view_count, slug, category_id, \\(SELECT GROUP_CONCAT\\(tag ORDER BY tag SEPARATOR ','\\) FROM article_tag WHERE article_tag.article_id = article.id\\) AS tags FROM article WHERE id IN \\(\\?,\\?\\) AND deleted_at IS NULL"
+
+	mock.ExpectQuery(query).WithArgs(int64(3), int64(1)).WillReturnRows(rows)
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	list, err := a.GetByIDs(context.TODO(), []int64{3, 1})
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
 }
 
 func TestGetArticleByTitle(t *testing.T) {
@@ -102,10 +592,10 @@ func TestGetArticleByTitle(t *testing.T) {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
-		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}).
+		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now(), nil, 1, "published", nil, 0, "title-1", nil, nil)
 
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article WHERE title = \\?"
+	query := "SELECT id,title,content, author_id, updated_at, created_at, deleted_at, version, status, publish_at, view_count, slug, category_id, \\(SELECT GROUP_CONCAT\\(tag ORDER BY tag SEPARATOR ','\\) FROM article_tag WHERE article_tag.article_id = article.id\\) AS tags FROM article WHERE LOWER\\(TRIM\\(title\\)\\) = LOWER\\(TRIM\\(\\?\\)\\) AND deleted_at IS NULL"
 
 	mock.ExpectQuery(query).WillReturnRows(rows)
 	a := articleMysqlRepo.NewArticleRepository(db)
@@ -116,16 +606,78 @@ func TestGetArticleByTitle(t *testing.T) {
 	assert.NotNil(t, anArticle)
 }
 
+func TestGetArticleBySlug(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}).
+		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now(), nil, 1, "published", nil, 0, "title-1", nil, nil)
+
+	query := "SELECT id,title,content, author_id, updated_at, created_at, deleted_at, version, status, publish_at, view_count, slug, category_id, \\(SELECT GROUP_CONCAT\\(tag ORDER BY tag SEPARATOR ','\\) FROM article_tag WHERE article_tag.article_id = article.id\\) AS tags FROM article WHERE slug = \\?"
+
+	mock.ExpectQuery(query).WithArgs("title-1").WillReturnRows(rows)
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	anArticle, err := a.GetBySlug(context.TODO(), "title-1")
+	require.NoError(t, err)
+	assert.Equal(t, "title-1", anArticle.Slug)
+
+	mock.ExpectQuery(query).WithArgs("missing").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = a.GetBySlug(context.TODO(), "missing")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchArticle(t *testing.T) {
+	t.Run("matching", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}).
+			AddRow(1, "Go generics", "Content 1", 1, time.Now(), time.Now(), nil, 1, "published", nil, 0, "go-generics", nil, nil)
+		query := "FROM article WHERE \\(title LIKE \\? OR content LIKE \\?\\) AND deleted_at IS NULL AND status = \\? ORDER BY updated_at DESC, id DESC LIMIT \\?"
+		mock.ExpectQuery(query).WithArgs("%generics%", "%generics%", domain.StatusPublished, int64(1)).WillReturnRows(rows)
+		a := articleMysqlRepo.NewArticleRepository(db)
+
+		list, err := a.Search(context.TODO(), "generics", 1, domain.Cursor{}, domain.StatusPublished)
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("special-characters", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		cursorTime := time.Now()
+		rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"})
+		query := "FROM article WHERE \\(title LIKE \\? OR content LIKE \\?\\) AND deleted_at IS NULL AND \\(updated_at < \\? OR \\(updated_at = \\? AND id < \\?\\)\\) ORDER BY updated_at DESC, id DESC LIMIT \\?"
+		pattern := `%100\% off\_now\\%`
+		mock.ExpectQuery(query).WithArgs(pattern, pattern, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), int64(10)).WillReturnRows(rows)
+		a := articleMysqlRepo.NewArticleRepository(db)
+
+		cursor := domain.Cursor{ID: 3, Value: cursorTime.Format(time.RFC3339Nano)}
+		list, err := a.Search(context.TODO(), `100% off_now\`, 10, cursor, "")
+		assert.NoError(t, err)
+		assert.Empty(t, list)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestDeleteArticle(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	query := "DELETE FROM article WHERE id = \\?"
+	query := "UPDATE article SET deleted_at=\\? WHERE id = \\? AND deleted_at IS NULL"
 
 	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(12).WillReturnResult(sqlmock.NewResult(12, 1))
+	prep.ExpectExec().WithArgs(sqlmock.AnyArg(), 12).WillReturnResult(sqlmock.NewResult(12, 1))
 
 	a := articleMysqlRepo.NewArticleRepository(db)
 
@@ -134,32 +686,207 @@ func TestDeleteArticle(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestUpdateArticle(t *testing.T) {
-	now := time.Now()
-	ar := &domain.Article{
-		ID:        12,
-		Title:     "Judul",
-		Content:   "Content",
-		CreatedAt: now,
-		UpdatedAt: now,
-		Author: domain.Author{
-			ID:   1,
-			Name: "Iman Tumorang",
-		},
+func TestRestoreArticle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "UPDATE article SET deleted_at=NULL WHERE id = \\? AND deleted_at IS NOT NULL"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(12).WillReturnResult(sqlmock.NewResult(12, 1))
+	prep = mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(13).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	err = a.Restore(context.TODO(), 12)
+	assert.NoError(t, err)
+
+	err = a.Restore(context.TODO(), 13)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestSetStatusArticle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
+	query := "UPDATE article SET status=\\?, updated_at=\\?, version=version\\+1 WHERE id = \\? AND deleted_at IS NULL"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(domain.StatusPublished, sqlmock.AnyArg(), 12).WillReturnResult(sqlmock.NewResult(12, 1))
+	prep = mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(domain.StatusPublished, sqlmock.AnyArg(), 13).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	err = a.SetStatus(context.TODO(), 12, domain.StatusPublished)
+	assert.NoError(t, err)
+
+	err = a.SetStatus(context.TODO(), 13, domain.StatusPublished)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestIncrementViewsArticle(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	query := "UPDATE article set title=\\?, content=\\?, author_id=\\?, updated_at=\\? WHERE ID = \\?"
+	query := "UPDATE article SET view_count = view_count \\+ 1 WHERE id = \\? AND deleted_at IS NULL"
 
 	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt, ar.ID).WillReturnResult(sqlmock.NewResult(12, 1))
+	prep.ExpectExec().WithArgs(12).WillReturnResult(sqlmock.NewResult(12, 1))
+	prep = mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(13).WillReturnResult(sqlmock.NewResult(0, 0))
 
 	a := articleMysqlRepo.NewArticleRepository(db)
 
-	err = a.Update(context.TODO(), ar)
+	err = a.IncrementViews(context.TODO(), 12)
 	assert.NoError(t, err)
+
+	err = a.IncrementViews(context.TODO(), 13)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestPublishDueArticle(t *testing.T) {
+	now := time.Now()
+	selectQuery := "SELECT id FROM article WHERE status = \\? AND publish_at <= \\? AND deleted_at IS NULL FOR UPDATE"
+
+	t.Run("scheduled-in-the-past", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectQuery).WithArgs(domain.StatusDraft, now).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec("UPDATE article SET status=\\?, updated_at=\\?, version=version\\+1 WHERE id IN \\(\\?\\) AND status = \\?").
+			WithArgs(domain.StatusPublished, now, int64(1), domain.StatusDraft).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		a := articleMysqlRepo.NewArticleRepository(db)
+
+		ids, err := a.PublishDue(context.TODO(), now)
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1}, ids)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("scheduled-in-the-future", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		// the article scheduled later doesn't match the publish_at bound, so nothing is updated
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectQuery).WithArgs(domain.StatusDraft, now).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectCommit()
+		a := articleMysqlRepo.NewArticleRepository(db)
+
+		ids, err := a.PublishDue(context.TODO(), now)
+		assert.NoError(t, err)
+		assert.Empty(t, ids)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolled-back-on-error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(selectQuery).WithArgs(domain.StatusDraft, now).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec("UPDATE article SET status").WillReturnError(errors.New("deadlock"))
+		mock.ExpectRollback()
+		a := articleMysqlRepo.NewArticleRepository(db)
+
+		ids, err := a.PublishDue(context.TODO(), now)
+		assert.Error(t, err)
+		assert.Empty(t, ids)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateArticle(t *testing.T) {
+	now := time.Now()
+	query := "UPDATE article set title=\\?, content=\\?, author_id=\\?, updated_at=\\?, publish_at=\\?, category_id=\\?, version=version\\+1 WHERE ID = \\? AND version = \\?"
+
+	t.Run("matching-version", func(t *testing.T) {
+		ar := &domain.Article{
+			ID:        12,
+			Title:     "Judul",
+			Content:   "Content",
+			CreatedAt: now,
+			UpdatedAt: now,
+			Author: domain.Author{
+				ID:   1,
+				Name: "Iman Tumorang",
+			},
+			Version: 3,
+			Tags:    []string{"go"},
+		}
+
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		mock.ExpectBegin()
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, sqlmock.AnyArg(), ar.PublishAt, nil, ar.ID, int64(3)).WillReturnResult(sqlmock.NewResult(12, 1))
+		mock.ExpectExec("DELETE FROM article_tag WHERE article_id = \\?").WithArgs(ar.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO article_tag").WithArgs(ar.ID, "go").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		a := articleMysqlRepo.NewArticleRepository(db)
+
+		err = a.Update(context.TODO(), ar)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), ar.Version)
+		assert.True(t, ar.UpdatedAt.After(now))
+		assert.Equal(t, now, ar.CreatedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("stale-version", func(t *testing.T) {
+		ar := &domain.Article{
+			ID:        12,
+			Title:     "Judul",
+			Content:   "Content",
+			CreatedAt: now,
+			UpdatedAt: now,
+			Author:    domain.Author{ID: 1},
+			Version:   2,
+		}
+
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+
+		mock.ExpectBegin()
+		prep := mock.ExpectPrepare(query)
+		prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, sqlmock.AnyArg(), ar.PublishAt, nil, ar.ID, int64(2)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		a := articleMysqlRepo.NewArticleRepository(db)
+
+		err = a.Update(context.TODO(), ar)
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.Equal(t, int64(2), ar.Version)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPingArticle(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	a := articleMysqlRepo.NewArticleRepository(db)
+
+	assert.NoError(t, a.Ping(context.TODO()))
 }
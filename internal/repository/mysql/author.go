@@ -35,7 +35,10 @@ func (m *AuthorRepository) getOne(ctx context.Context, query string, args ...int
 	return
 }
 
-func (m *AuthorRepository) GetByID(ctx context.Context, id int64) (domain.Author, error) {
+func (m *AuthorRepository) GetByID(ctx context.Context, id int64) (res domain.Author, err error) {
+	ctx, span := startSpan(ctx, "AuthorRepository.GetByID", "SELECT")
+	defer func() { endSpan(span, err) }()
+
 	query := `SELECT id, name, created_at, updated_at FROM author WHERE id=?`
 	return m.getOne(ctx, query, id)
 }
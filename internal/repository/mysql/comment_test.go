@@ -0,0 +1,48 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"apismrtbiz/domain"
+	repository "apismrtbiz/internal/repository/mysql"
+)
+
+func TestFetchComments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	postedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "article_id", "author", "content", "created_at"}).
+		AddRow(3, 7, "Iman", "Third", postedAt.Add(time.Minute))
+	query := "SELECT id, article_id, author, content, created_at FROM comment WHERE article_id = \\? AND \\(created_at > \\? OR \\(created_at = \\? AND id > \\?\\)\\) ORDER BY created_at, id LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs(int64(7), postedAt, postedAt, int64(2), int64(2)).WillReturnRows(rows)
+	c := repository.NewCommentRepository(db)
+
+	cursor := domain.NewCommentCursor(domain.Comment{ID: 2, CreatedAt: postedAt})
+	list, err := c.Fetch(context.TODO(), 7, cursor, 2)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "Third", list[0].Content)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreComment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	cm := &domain.Comment{ArticleID: 7, Author: "Iman", Content: "Nice"}
+	mock.ExpectExec("INSERT INTO comment \\(article_id, author, content, created_at\\) VALUES \\(\\?, \\?, \\?, \\?\\)").
+		WithArgs(cm.ArticleID, cm.Author, cm.Content, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(12, 1))
+	c := repository.NewCommentRepository(db)
+
+	require.NoError(t, c.Store(context.TODO(), cm))
+	assert.Equal(t, int64(12), cm.ID)
+	assert.False(t, cm.CreatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,81 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"apismrtbiz/domain"
+)
+
+type FavoriteRepository struct {
+	Conn *sql.DB
+}
+
+// NewFavoriteRepository will create an object that represent the favorite.FavoriteRepository interface
+func NewFavoriteRepository(conn *sql.DB) *FavoriteRepository {
+	return &FavoriteRepository{conn}
+}
+
+// Add will record the favorite, an existing one is left untouched
+func (m *FavoriteRepository) Add(ctx context.Context, userID string, articleID int64) (err error) {
+	ctx, span := startSpan(ctx, "FavoriteRepository.Add", "INSERT")
+	defer func() { endSpan(span, err) }()
+
+	query := `INSERT IGNORE INTO favorite (user_id, article_id, created_at) VALUES (?, ?, ?)`
+	_, err = m.Conn.ExecContext(ctx, query, userID, articleID, time.Now())
+	return
+}
+
+// Remove will delete the favorite, if any
+func (m *FavoriteRepository) Remove(ctx context.Context, userID string, articleID int64) (err error) {
+	ctx, span := startSpan(ctx, "FavoriteRepository.Remove", "DELETE")
+	defer func() { endSpan(span, err) }()
+
+	query := `DELETE FROM favorite WHERE user_id = ? AND article_id = ?`
+	_, err = m.Conn.ExecContext(ctx, query, userID, articleID)
+	return
+}
+
+// Status will count the favorites of the articles, telling whether the user is among them,
+// the articles without any favorite are left out
+func (m *FavoriteRepository) Status(ctx context.Context, userID string, articleIDs []int64) (res map[int64]domain.FavoriteStatus, err error) {
+	ctx, span := startSpan(ctx, "FavoriteRepository.Status", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(articleIDs)), ",")
+	query := `SELECT article_id, COUNT(*), MAX(user_id = ?) FROM favorite
+  						WHERE article_id IN (` + placeholders + `) GROUP BY article_id`
+	args := make([]interface{}, 0, len(articleIDs)+1)
+	args = append(args, userID)
+	for _, id := range articleIDs {
+		args = append(args, id)
+	}
+
+	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.WithContext(ctx).Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.WithContext(ctx).Error(errRow)
+		}
+	}()
+
+	res = make(map[int64]domain.FavoriteStatus)
+	for rows.Next() {
+		var articleID int64
+		var status domain.FavoriteStatus
+		if err = rows.Scan(&articleID, &status.Count, &status.Favorited); err != nil {
+			logrus.WithContext(ctx).Error(err)
+			return nil, err
+		}
+		res[articleID] = status
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,581 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"apismrtbiz/domain"
+)
+
+type ArticleRepository struct {
+	Conn *sql.DB
+}
+
+// NewArticleRepository will create an object that represent the article.Repository interface
+func NewArticleRepository(conn *sql.DB) *ArticleRepository {
+	return &ArticleRepository{conn}
+}
+
+// categoryArg will store an uncategorized article with a NULL category
+func categoryArg(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
+
+// articleColumns is the select list scanned by fetch, the tags are aggregated by commas since they never contain one
+const articleColumns = `id, title, content, author_id, updated_at, created_at, deleted_at, version, status, publish_at, view_count, slug, category_id,
+  						(SELECT string_agg(tag, ',' ORDER BY tag) FROM article_tag WHERE article_tag.article_id = article.id) AS tags`
+
+func (m *ArticleRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.Article, err error) {
+	rows, err := m.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.WithContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.WithContext(ctx).Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Article, 0)
+	for rows.Next() {
+		t := domain.Article{}
+		authorID := int64(0)
+		deletedAt := sql.NullTime{}
+		publishAt := sql.NullTime{}
+		categoryID := sql.NullInt64{}
+		tags := sql.NullString{}
+		err = rows.Scan(
+			&t.ID,
+			&t.Title,
+			&t.Content,
+			&authorID,
+			&t.UpdatedAt,
+			&t.CreatedAt,
+			&deletedAt,
+			&t.Version,
+			&t.Status,
+			&publishAt,
+			&t.ViewCount,
+			&t.Slug,
+			&categoryID,
+			&tags,
+		)
+
+		if err != nil {
+			logrus.WithContext(ctx).Error(err)
+			return nil, err
+		}
+		t.Author = domain.Author{
+			ID: authorID,
+		}
+		if deletedAt.Valid {
+			t.DeletedAt = &deletedAt.Time
+		}
+		if publishAt.Valid {
+			t.PublishAt = &publishAt.Time
+		}
+		t.CategoryID = categoryID.Int64
+		if tags.String != "" {
+			t.Tags = strings.Split(tags.String, ",")
+		}
+		result = append(result, t)
+	}
+
+	return result, nil
+}
+
+// queryArgs collects the arguments of a query, handing out their numbered placeholder
+type queryArgs []interface{}
+
+func (a *queryArgs) add(arg interface{}) string {
+	*a = append(*a, arg)
+	return "$" + strconv.Itoa(len(*a))
+}
+
+// status will keep only the articles at the status on top of the conditions of the query,
+// an empty status keeps every stage
+func (a *queryArgs) status(status domain.Status) string {
+	if status == "" {
+		return ""
+	}
+	return ` AND status = ` + a.add(status)
+}
+
+// sortColumns maps the sort fields to their column, the column names are never taken from the input
+var sortColumns = map[domain.SortField]string{
+	domain.SortByUpdatedAt: "updated_at",
+	domain.SortByCreatedAt: "created_at",
+	domain.SortByTitle:     "title",
+}
+
+// cursorArg will turn the cursor value into the query argument of the sort field
+func cursorArg(field domain.SortField, cursor domain.Cursor) (interface{}, error) {
+	if field == domain.SortByTitle {
+		return cursor.Value, nil
+	}
+	return cursor.Time()
+}
+
+// Fetch will list the articles after the cursor in the order of the filter, by default the most recently updated first.
+// The articles sharing the sort value of the cursor are told apart by their id, which breaks the ties of the ordering,
+// so no article is skipped or repeated by a cursor. When paging backward the query runs in the reverse ordering, so the articles are returned closest to the cursor first.
+func (m *ArticleRepository) Fetch(ctx context.Context, cursor domain.Cursor, num int64, filter domain.ArticleFilter) (res []domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Fetch", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	field := filter.Sort.Field
+	if field == "" {
+		field = domain.SortByUpdatedAt
+	}
+	column, ok := sortColumns[field]
+	if !ok {
+		return nil, domain.ErrBadParamInput
+	}
+	comparison, direction := "<", "DESC"
+	if (filter.Sort.Order == domain.SortAsc) != filter.Backward {
+		comparison, direction = ">", "ASC"
+	}
+
+	query := `SELECT ` + articleColumns + `
+  						FROM article`
+
+	conditions := make([]string, 0, 6)
+	args := make(queryArgs, 0, 6)
+	if !cursor.IsZero() {
+		value, errCursor := cursorArg(field, cursor)
+		if errCursor != nil {
+			return nil, errCursor
+		}
+		conditions = append(conditions, "("+column+", id) "+comparison+" ("+args.add(value)+", "+args.add(cursor.ID)+")")
+	}
+	if filter.AuthorID != 0 {
+		conditions = append(conditions, "author_id = "+args.add(filter.AuthorID))
+	}
+	if filter.CategoryID != 0 {
+		conditions = append(conditions, "category_id = "+args.add(filter.CategoryID))
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = "+args.add(filter.Status))
+	}
+	if filter.Tag != "" {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM article_tag WHERE article_tag.article_id = article.id AND article_tag.tag = "+args.add(filter.Tag)+")")
+	}
+	switch {
+	case !filter.CreatedFrom.IsZero() && !filter.CreatedTo.IsZero():
+		conditions = append(conditions, "created_at BETWEEN "+args.add(filter.CreatedFrom)+" AND "+args.add(filter.CreatedTo))
+	case !filter.CreatedFrom.IsZero():
+		conditions = append(conditions, "created_at >= "+args.add(filter.CreatedFrom))
+	case !filter.CreatedTo.IsZero():
+		conditions = append(conditions, "created_at <= "+args.add(filter.CreatedTo))
+	}
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY ` + column + ` ` + direction + `, id ` + direction + ` LIMIT ` + args.add(num)
+
+	return m.fetch(ctx, query, args...)
+}
+
+// FetchRelated will list the published articles sharing tags or the author with the given article,
+// the ones with the most in common first. Every shared tag counts as much as the shared author.
+func (m *ArticleRepository) FetchRelated(ctx context.Context, ar domain.Article, num int64) (res []domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.FetchRelated", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	query := `SELECT ` + articleColumns + `
+  						FROM article JOIN (
+  							SELECT candidate.id AS related_id, (candidate.author_id = $1)::int + (
+  								SELECT COUNT(*) FROM article_tag shared
+  								JOIN article_tag own ON own.tag = shared.tag AND own.article_id = $2
+  								WHERE shared.article_id = candidate.id) AS overlap
+  							FROM article candidate) related ON related.related_id = article.id
+  						WHERE related.overlap > 0 AND id <> $2 AND status = $3 AND deleted_at IS NULL
+  						ORDER BY related.overlap DESC, updated_at DESC LIMIT $4`
+
+	return m.fetch(ctx, query, ar.Author.ID, ar.ID, domain.StatusPublished, num)
+}
+
+// likeEscaper will escape the wildcards of LIKE so the search term is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Search will list the articles at the status whose title or content contains the query, the most recently updated first,
+// starting after the cursor. The ties are broken by the id like in Fetch.
+func (m *ArticleRepository) Search(ctx context.Context, query string, num int64, cursor domain.Cursor, status domain.Status) (res []domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Search", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	args := make(queryArgs, 0, 3)
+	pattern := args.add("%" + likeEscaper.Replace(query) + "%")
+	sqlQuery := `SELECT ` + articleColumns + `
+  						FROM article WHERE (title ILIKE ` + pattern + ` OR content ILIKE ` + pattern + `) AND deleted_at IS NULL` + args.status(status)
+	if !cursor.IsZero() {
+		updatedAt, errCursor := cursor.Time()
+		if errCursor != nil {
+			return nil, errCursor
+		}
+		sqlQuery += ` AND (updated_at, id) < (` + args.add(updatedAt) + `, ` + args.add(cursor.ID) + `)`
+	}
+	sqlQuery += ` ORDER BY updated_at DESC, id DESC LIMIT ` + args.add(num)
+
+	return m.fetch(ctx, sqlQuery, args...)
+}
+
+// OffsetFetch will list a page of the articles at the status, an empty status lists every stage.
+// The most recently updated come first, the ties broken by the id so that the pages never overlap.
+func (m *ArticleRepository) OffsetFetch(ctx context.Context, offset, limit int64, status domain.Status) (res []domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.OffsetFetch", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	args := make(queryArgs, 0, 3)
+	query := `SELECT ` + articleColumns + `
+  						FROM article WHERE deleted_at IS NULL` + args.status(status)
+	query += ` ORDER BY updated_at DESC, id DESC LIMIT ` + args.add(limit) + ` OFFSET ` + args.add(offset)
+
+	return m.fetch(ctx, query, args...)
+}
+
+// Count will count the articles at the status, an empty status counts every stage
+func (m *ArticleRepository) Count(ctx context.Context, status domain.Status) (total int64, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Count", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	args := make(queryArgs, 0, 1)
+	query := `SELECT COUNT(*) FROM article WHERE deleted_at IS NULL` + args.status(status)
+
+	err = m.conn(ctx).QueryRowContext(ctx, query, args...).Scan(&total)
+	if err != nil {
+		logrus.WithContext(ctx).Error(err)
+		return 0, err
+	}
+	return
+}
+
+func (m *ArticleRepository) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.GetByID", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	query := `SELECT ` + articleColumns + `
+  						FROM article WHERE id = $1 AND deleted_at IS NULL`
+
+	list, err := m.fetch(ctx, query, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+
+	return
+}
+
+func (m *ArticleRepository) GetByIDs(ctx context.Context, ids []int64) (res []domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.GetByIDs", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	if len(ids) == 0 {
+		return []domain.Article{}, nil
+	}
+
+	args := make(queryArgs, 0, len(ids))
+	placeholders := make([]string, len(ids))
+	for i, id := range ids {
+		placeholders[i] = args.add(id)
+	}
+	query := `SELECT ` + articleColumns + `
+  						FROM article WHERE id IN (` + strings.Join(placeholders, ",") + `) AND deleted_at IS NULL`
+
+	return m.fetch(ctx, query, args...)
+}
+
+func (m *ArticleRepository) GetByTitle(ctx context.Context, title string) (res domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.GetByTitle", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	query := `SELECT ` + articleColumns + `
+  						FROM article WHERE LOWER(TRIM(title)) = LOWER(TRIM($1)) AND deleted_at IS NULL`
+
+	list, err := m.fetch(ctx, query, title)
+	if err != nil {
+		return
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+	return
+}
+
+// GetBySlug will get the article of the given slug, a soft deleted one included since its slug stays taken
+func (m *ArticleRepository) GetBySlug(ctx context.Context, slug string) (res domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.GetBySlug", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	query := `SELECT ` + articleColumns + `
+  						FROM article WHERE slug = $1`
+
+	list, err := m.fetch(ctx, query, slug)
+	if err != nil {
+		return
+	}
+
+	if len(list) > 0 {
+		res = list[0]
+	} else {
+		return res, domain.ErrNotFound
+	}
+	return
+}
+
+func (m *ArticleRepository) Store(ctx context.Context, a *domain.Article) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Store", "INSERT")
+	defer func() { endSpan(span, err) }()
+
+	tx, err := beginTx(ctx, m.Conn)
+	if err != nil {
+		return
+	}
+	defer func() { err = finishTx(ctx, tx, err) }()
+
+	now := time.Now()
+	a.CreatedAt = now
+	a.UpdatedAt = now
+
+	var lastID int64
+	if a.ID != 0 {
+		lastID, err = storeWithID(ctx, tx, a)
+	} else {
+		query := `INSERT INTO article (title, content, author_id, updated_at, created_at, version, status, publish_at, slug, category_id)
+  						VALUES ($1, $2, $3, $4, $5, 1, $6, $7, $8, $9) RETURNING id`
+		err = tx.QueryRowContext(ctx, query, a.Title, a.Content, a.Author.ID, a.UpdatedAt, a.CreatedAt, a.Status, a.PublishAt, a.Slug, categoryArg(a.CategoryID)).Scan(&lastID)
+	}
+	if err != nil {
+		if isDuplicate(err) {
+			err = domain.ErrConflict
+		}
+		return
+	}
+	if err = insertTags(ctx, tx, lastID, a.Tags); err != nil {
+		return
+	}
+	a.ID = lastID
+	a.Version = 1
+	return
+}
+
+// storeWithID will insert the article under the id it is given, the sequence of the ids is moved
+// past it so that it is never handed out again
+func storeWithID(ctx context.Context, tx executor, a *domain.Article) (int64, error) {
+	query := `INSERT INTO article (id, title, content, author_id, updated_at, created_at, version, status, publish_at, slug, category_id)
+  						VALUES ($1, $2, $3, $4, $5, $6, 1, $7, $8, $9, $10)`
+	_, err := tx.ExecContext(ctx, query, a.ID, a.Title, a.Content, a.Author.ID, a.UpdatedAt, a.CreatedAt, a.Status, a.PublishAt, a.Slug, categoryArg(a.CategoryID))
+	if err != nil {
+		return 0, err
+	}
+	_, err = tx.ExecContext(ctx, `SELECT setval(pg_get_serial_sequence('article', 'id'), (SELECT MAX(id) FROM article))`)
+	return a.ID, err
+}
+
+// isDuplicate tells whether err is Postgres refusing a row whose id or slug is already taken
+func isDuplicate(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// insertTags will attach the tags to the article within the transaction
+func insertTags(ctx context.Context, tx executor, id int64, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	args := make(queryArgs, 0, 2*len(tags))
+	values := make([]string, len(tags))
+	for i, tag := range tags {
+		values[i] = "(" + args.add(id) + ", " + args.add(tag) + ")"
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO article_tag (article_id, tag) VALUES `+strings.Join(values, ","), args...)
+	return err
+}
+
+// exactlyOne will check a write touched the single row it targeted, none is reported as ErrNotFound
+func exactlyOne(res sql.Result) error {
+	rowsAfected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAfected == 0 {
+		return domain.ErrNotFound
+	}
+
+	if rowsAfected != 1 {
+		return fmt.Errorf("weird  Behavior. Total Affected: %d", rowsAfected)
+	}
+	return nil
+}
+
+func (m *ArticleRepository) Delete(ctx context.Context, id int64) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Delete", "UPDATE")
+	defer func() { endSpan(span, err) }()
+
+	query := "UPDATE article SET deleted_at=$1 WHERE id = $2 AND deleted_at IS NULL"
+
+	res, err := m.conn(ctx).ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return
+	}
+	return exactlyOne(res)
+}
+
+func (m *ArticleRepository) Restore(ctx context.Context, id int64) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Restore", "UPDATE")
+	defer func() { endSpan(span, err) }()
+
+	query := "UPDATE article SET deleted_at=NULL WHERE id = $1 AND deleted_at IS NOT NULL"
+
+	res, err := m.conn(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return
+	}
+	return exactlyOne(res)
+}
+
+// SetStatus will move the article to the stage of the publishing workflow, it counts as an update of the article
+func (m *ArticleRepository) SetStatus(ctx context.Context, id int64, status domain.Status) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.SetStatus", "UPDATE")
+	defer func() { endSpan(span, err) }()
+
+	query := "UPDATE article SET status=$1, updated_at=$2, version=version+1 WHERE id = $3 AND deleted_at IS NULL"
+
+	res, err := m.conn(ctx).ExecContext(ctx, query, status, time.Now(), id)
+	if err != nil {
+		return
+	}
+	return exactlyOne(res)
+}
+
+// IncrementViews will count one more view of the article, the increment is done by the database
+// so the concurrent views are never lost
+func (m *ArticleRepository) IncrementViews(ctx context.Context, id int64) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.IncrementViews", "UPDATE")
+	defer func() { endSpan(span, err) }()
+
+	query := "UPDATE article SET view_count = view_count + 1 WHERE id = $1 AND deleted_at IS NULL"
+
+	res, err := m.conn(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return
+	}
+	return exactlyOne(res)
+}
+
+// PublishDue will publish the drafts whose publish_at has passed and return their ids.
+// The drafts are locked in a transaction, so two workers running at once don't publish an article twice.
+func (m *ArticleRepository) PublishDue(ctx context.Context, now time.Time) (ids []int64, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.PublishDue", "UPDATE")
+	defer func() { endSpan(span, err) }()
+
+	tx, err := beginTx(ctx, m.Conn)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = finishTx(ctx, tx, err) }()
+
+	query := `SELECT id FROM article WHERE status = $1 AND publish_at <= $2 AND deleted_at IS NULL FOR UPDATE`
+	rows, err := tx.QueryContext(ctx, query, domain.StatusDraft, now)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	args := make(queryArgs, 0, len(ids)+3)
+	set := "status=" + args.add(domain.StatusPublished) + ", updated_at=" + args.add(now)
+	placeholders := make([]string, len(ids))
+	for i, id := range ids {
+		placeholders[i] = args.add(id)
+	}
+	query = `UPDATE article SET ` + set + `, version=version+1 WHERE id IN (` + strings.Join(placeholders, ",") + `) AND status = ` + args.add(domain.StatusDraft)
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Ping will check the connection to the database is still alive
+func (m *ArticleRepository) Ping(ctx context.Context) error {
+	return m.Conn.PingContext(ctx)
+}
+
+func (m *ArticleRepository) Update(ctx context.Context, ar *domain.Article) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.Update", "UPDATE")
+	defer func() { endSpan(span, err) }()
+
+	tx, err := beginTx(ctx, m.Conn)
+	if err != nil {
+		return
+	}
+	defer func() { err = finishTx(ctx, tx, err) }()
+
+	query := `UPDATE article SET title=$1, content=$2, author_id=$3, updated_at=$4, publish_at=$5, category_id=$6, version=version+1 WHERE id = $7 AND version = $8`
+
+	updatedAt := time.Now()
+	res, err := tx.ExecContext(ctx, query, ar.Title, ar.Content, ar.Author.ID, updatedAt, ar.PublishAt, categoryArg(ar.CategoryID), ar.ID, ar.Version)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		return domain.ErrConflict
+	}
+	if affect != 1 {
+		err = fmt.Errorf("weird  Behavior. Total Affected: %d", affect)
+		return
+	}
+
+	// the tags are replaced as a whole like the other fields
+	if _, err = tx.ExecContext(ctx, "DELETE FROM article_tag WHERE article_id = $1", ar.ID); err != nil {
+		return
+	}
+	if err = insertTags(ctx, tx, ar.ID, ar.Tags); err != nil {
+		return
+	}
+
+	ar.UpdatedAt = updatedAt
+	ar.Version++
+
+	return
+}
@@ -0,0 +1,71 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"apismrtbiz/domain"
+	postgresRepo "apismrtbiz/internal/repository/postgres"
+)
+
+var columns = []string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}
+
+func TestFetchArticle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	cursorTime := time.Now()
+	rows := sqlmock.NewRows(columns).
+		AddRow(4, "title 4", "Content 4", 3, cursorTime.Add(-time.Hour), time.Now(), nil, 1, "published", nil, 0, "title-4", nil, "golang,web")
+	query := "FROM article WHERE \\(updated_at, id\\) < \\(\\$1, \\$2\\) AND author_id = \\$3 AND status = \\$4 AND deleted_at IS NULL ORDER BY updated_at DESC, id DESC LIMIT \\$5"
+	mock.ExpectQuery(query).WithArgs(sqlmock.AnyArg(), int64(5), int64(3), domain.StatusPublished, int64(1)).WillReturnRows(rows)
+	a := postgresRepo.NewArticleRepository(db)
+
+	cursor := domain.Cursor{ID: 5, Value: cursorTime.Format(time.RFC3339Nano)}
+	list, err := a.Fetch(context.TODO(), cursor, 1, domain.ArticleFilter{AuthorID: 3, Status: domain.StatusPublished})
+	assert.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, []string{"golang", "web"}, list[0].Tags)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreArticle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	ar := &domain.Article{Title: "Judul", Slug: "judul", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusDraft, Tags: []string{"go"}}
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO article (.+) VALUES \\(\\$1, \\$2, \\$3, \\$4, \\$5, 1, \\$6, \\$7, \\$8, \\$9\\) RETURNING id").
+		WithArgs(ar.Title, ar.Content, ar.Author.ID, sqlmock.AnyArg(), sqlmock.AnyArg(), ar.Status, ar.PublishAt, ar.Slug, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+	mock.ExpectExec("INSERT INTO article_tag \\(article_id, tag\\) VALUES \\(\\$1, \\$2\\)").
+		WithArgs(int64(12), "go").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	a := postgresRepo.NewArticleRepository(db)
+
+	err = a.Store(context.TODO(), ar)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), ar.ID)
+	assert.Equal(t, int64(1), ar.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetArticleByIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	rows := sqlmock.NewRows(columns).
+		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now(), nil, 1, "published", nil, 0, "title-1", nil, nil)
+	mock.ExpectQuery("FROM article WHERE id IN \\(\\$1,\\$2\\) AND deleted_at IS NULL").WithArgs(int64(1), int64(3)).WillReturnRows(rows)
+	a := postgresRepo.NewArticleRepository(db)
+
+	list, err := a.GetByIDs(context.TODO(), []int64{1, 3})
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"apismrtbiz/domain"
+)
+
+type CommentRepository struct {
+	Conn *sql.DB
+}
+
+// NewCommentRepository will create an object that represent the comment.CommentRepository interface
+func NewCommentRepository(conn *sql.DB) *CommentRepository {
+	return &CommentRepository{conn}
+}
+
+// Fetch will list the comments of the article after the cursor, the oldest first
+func (m *CommentRepository) Fetch(ctx context.Context, articleID int64, cursor domain.Cursor, num int64) (res []domain.Comment, err error) {
+	ctx, span := startSpan(ctx, "CommentRepository.Fetch", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	query := `SELECT id, article_id, author, content, created_at FROM comment WHERE article_id = $1`
+	args := []interface{}{articleID}
+	if !cursor.IsZero() {
+		createdAt, errCursor := cursor.Time()
+		if errCursor != nil {
+			return nil, errCursor
+		}
+		query += ` AND (created_at, id) > ($2, $3)`
+		args = append(args, createdAt, cursor.ID)
+	}
+	query += fmt.Sprintf(` ORDER BY created_at, id LIMIT $%d`, len(args)+1)
+
+	rows, err := m.Conn.QueryContext(ctx, query, append(args, num)...)
+	if err != nil {
+		logrus.WithContext(ctx).Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.WithContext(ctx).Error(errRow)
+		}
+	}()
+
+	res = make([]domain.Comment, 0)
+	for rows.Next() {
+		c := domain.Comment{}
+		if err = rows.Scan(&c.ID, &c.ArticleID, &c.Author, &c.Content, &c.CreatedAt); err != nil {
+			logrus.WithContext(ctx).Error(err)
+			return nil, err
+		}
+		res = append(res, c)
+	}
+	return res, rows.Err()
+}
+
+func (m *CommentRepository) Store(ctx context.Context, c *domain.Comment) (err error) {
+	ctx, span := startSpan(ctx, "CommentRepository.Store", "INSERT")
+	defer func() { endSpan(span, err) }()
+
+	c.CreatedAt = time.Now()
+	query := `INSERT INTO comment (article_id, author, content, created_at) VALUES ($1, $2, $3, $4) RETURNING id`
+	err = m.Conn.QueryRowContext(ctx, query, c.ArticleID, c.Author, c.Content, c.CreatedAt).Scan(&c.ID)
+	return
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"apismrtbiz/domain"
+)
+
+type FavoriteRepository struct {
+	Conn *sql.DB
+}
+
+// NewFavoriteRepository will create an object that represent the favorite.FavoriteRepository interface
+func NewFavoriteRepository(conn *sql.DB) *FavoriteRepository {
+	return &FavoriteRepository{conn}
+}
+
+// Add will record the favorite, an existing one is left untouched
+func (m *FavoriteRepository) Add(ctx context.Context, userID string, articleID int64) (err error) {
+	ctx, span := startSpan(ctx, "FavoriteRepository.Add", "INSERT")
+	defer func() { endSpan(span, err) }()
+
+	query := `INSERT INTO favorite (user_id, article_id, created_at) VALUES ($1, $2, $3)
+  						ON CONFLICT DO NOTHING`
+	_, err = m.Conn.ExecContext(ctx, query, userID, articleID, time.Now())
+	return
+}
+
+// Remove will delete the favorite, if any
+func (m *FavoriteRepository) Remove(ctx context.Context, userID string, articleID int64) (err error) {
+	ctx, span := startSpan(ctx, "FavoriteRepository.Remove", "DELETE")
+	defer func() { endSpan(span, err) }()
+
+	query := `DELETE FROM favorite WHERE user_id = $1 AND article_id = $2`
+	_, err = m.Conn.ExecContext(ctx, query, userID, articleID)
+	return
+}
+
+// Status will count the favorites of the articles, telling whether the user is among them,
+// the articles without any favorite are left out
+func (m *FavoriteRepository) Status(ctx context.Context, userID string, articleIDs []int64) (res map[int64]domain.FavoriteStatus, err error) {
+	ctx, span := startSpan(ctx, "FavoriteRepository.Status", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	args := make(queryArgs, 0, len(articleIDs)+1)
+	user := args.add(userID)
+	placeholders := make([]string, len(articleIDs))
+	for i, id := range articleIDs {
+		placeholders[i] = args.add(id)
+	}
+	query := `SELECT article_id, COUNT(*), bool_or(user_id = ` + user + `) FROM favorite
+  						WHERE article_id IN (` + strings.Join(placeholders, ",") + `) GROUP BY article_id`
+
+	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.WithContext(ctx).Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.WithContext(ctx).Error(errRow)
+		}
+	}()
+
+	res = make(map[int64]domain.FavoriteStatus)
+	for rows.Next() {
+		var articleID int64
+		var status domain.FavoriteStatus
+		if err = rows.Scan(&articleID, &status.Count, &status.Favorited); err != nil {
+			logrus.WithContext(ctx).Error(err)
+			return nil, err
+		}
+		res[articleID] = status
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,183 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"apismrtbiz/article"
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/repository/replica"
+)
+
+const (
+	defaultTTL       = 5 * time.Minute
+	defaultKeyPrefix = "article:"
+)
+
+// Config represent the settings of the article cache
+type Config struct {
+	// TTL is how long a cached article is kept, defaults to 5 minutes
+	TTL time.Duration
+	// KeyPrefix is prepended to the article id to build the cache key, defaults to "article:"
+	KeyPrefix string
+}
+
+// ArticleRepository caches the articles of the wrapped repository in redis,
+// every method which isn't overridden goes straight to the wrapped repository.
+// The misses are read from the primary, so that the rows of a lagging replica aren't cached.
+type ArticleRepository struct {
+	article.ArticleRepository
+	client Client
+	ttl    time.Duration
+	prefix string
+}
+
+// NewArticleRepository will create an object that represent the article.ArticleRepository interface
+func NewArticleRepository(repo article.ArticleRepository, client Client, cfg Config) *ArticleRepository {
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultTTL
+	}
+	if cfg.KeyPrefix == "" {
+		cfg.KeyPrefix = defaultKeyPrefix
+	}
+	return &ArticleRepository{
+		ArticleRepository: repo,
+		client:            client,
+		ttl:               cfg.TTL,
+		prefix:            cfg.KeyPrefix,
+	}
+}
+
+func (m *ArticleRepository) key(id int64) string {
+	return m.prefix + strconv.FormatInt(id, 10)
+}
+
+// txKey is the context key of the invalidations of a transaction, they are run once it ended
+// so that a reader doesn't cache the previous row again before the commit
+type txKey struct{}
+
+type pending struct {
+	mu  sync.Mutex
+	ids []int64
+}
+
+func inTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*pending)
+	return ok
+}
+
+// WithinTransaction will run the invalidations of the writes of fn once the transaction ended,
+// a rolled back one included since an extra miss is harmless
+func (m *ArticleRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if inTransaction(ctx) {
+		return m.ArticleRepository.WithinTransaction(ctx, fn)
+	}
+	p := &pending{}
+	err := m.ArticleRepository.WithinTransaction(context.WithValue(ctx, txKey{}, p), fn)
+	for _, id := range p.ids {
+		m.del(ctx, id)
+	}
+	return err
+}
+
+func (m *ArticleRepository) GetByID(ctx context.Context, id int64) (res domain.Article, err error) {
+	// the reads of a transaction see its uncommitted writes, they are never cached
+	if inTransaction(ctx) {
+		return m.ArticleRepository.GetByID(ctx, id)
+	}
+	key := m.key(id)
+
+	cached, err := m.client.Get(ctx, key)
+	if err == nil {
+		if err = json.Unmarshal(cached, &res); err == nil {
+			return res, nil
+		}
+		logrus.WithContext(ctx).Error(err)
+	} else if !errors.Is(err, ErrCacheMiss) {
+		logrus.WithContext(ctx).Error(err)
+	}
+
+	res, err = m.ArticleRepository.GetByID(replica.WithPrimary(ctx), id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+
+	encoded, err := json.Marshal(res)
+	if err != nil {
+		logrus.WithContext(ctx).Error(err)
+		return res, nil
+	}
+	if err = m.client.Set(ctx, key, encoded, m.ttl); err != nil {
+		logrus.WithContext(ctx).Error(err)
+	}
+	return res, nil
+}
+
+func (m *ArticleRepository) Update(ctx context.Context, ar *domain.Article) (err error) {
+	err = m.ArticleRepository.Update(ctx, ar)
+	if err != nil {
+		return
+	}
+	m.invalidate(ctx, ar.ID)
+	return
+}
+
+func (m *ArticleRepository) Delete(ctx context.Context, id int64) (err error) {
+	err = m.ArticleRepository.Delete(ctx, id)
+	if err != nil {
+		return
+	}
+	m.invalidate(ctx, id)
+	return
+}
+
+func (m *ArticleRepository) SetStatus(ctx context.Context, id int64, status domain.Status) (err error) {
+	err = m.ArticleRepository.SetStatus(ctx, id, status)
+	if err != nil {
+		return
+	}
+	m.invalidate(ctx, id)
+	return
+}
+
+func (m *ArticleRepository) IncrementViews(ctx context.Context, id int64) (err error) {
+	err = m.ArticleRepository.IncrementViews(ctx, id)
+	if err != nil {
+		return
+	}
+	m.invalidate(ctx, id)
+	return
+}
+
+func (m *ArticleRepository) PublishDue(ctx context.Context, now time.Time) (ids []int64, err error) {
+	ids, err = m.ArticleRepository.PublishDue(ctx, now)
+	for _, id := range ids {
+		m.invalidate(ctx, id)
+	}
+	return
+}
+
+// invalidate will drop the cached article once the transaction of the context ended, at once outside of one
+func (m *ArticleRepository) invalidate(ctx context.Context, id int64) {
+	p, ok := ctx.Value(txKey{}).(*pending)
+	if !ok {
+		m.del(ctx, id)
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ids = append(p.ids, id)
+}
+
+// del will drop the cached article, a failure is only logged since the entry expires anyway
+func (m *ArticleRepository) del(ctx context.Context, id int64) {
+	if err := m.client.Del(ctx, m.key(id)); err != nil {
+		logrus.WithContext(ctx).Error(err)
+	}
+}
@@ -0,0 +1,211 @@
+package redis_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/article/mocks"
+	"apismrtbiz/domain"
+	cacheRepo "apismrtbiz/internal/repository/redis"
+	"apismrtbiz/internal/repository/replica"
+)
+
+type fakeClient struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+	ttls    map[string]time.Duration
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		entries: map[string][]byte{},
+		ttls:    map[string]time.Duration{},
+	}
+}
+
+func (f *fakeClient) Get(_ context.Context, key string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	val, ok := f.entries[key]
+	if !ok {
+		return nil, cacheRepo.ErrCacheMiss
+	}
+	return val, nil
+}
+
+func (f *fakeClient) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.entries[key] = value
+	f.ttls[key] = ttl
+	return nil
+}
+
+func (f *fakeClient) Del(_ context.Context, keys ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, key := range keys {
+		delete(f.entries, key)
+	}
+	return nil
+}
+
+func TestGetByID(t *testing.T) {
+	mockArticle := domain.Article{ID: 7, Title: "Hello", Content: "Content", Author: domain.Author{ID: 1}, Version: 1}
+
+	t.Run("miss", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+		client := newFakeClient()
+		r := cacheRepo.NewArticleRepository(mockArticleRepo, client, cacheRepo.Config{TTL: time.Minute, KeyPrefix: "test:"})
+
+		res, err := r.GetByID(context.TODO(), 7)
+		require.NoError(t, err)
+		assert.Equal(t, mockArticle, res)
+		assert.Contains(t, client.entries, "test:7")
+		assert.Equal(t, time.Minute, client.ttls["test:7"])
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("hit", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+		r := cacheRepo.NewArticleRepository(mockArticleRepo, newFakeClient(), cacheRepo.Config{})
+
+		_, err := r.GetByID(context.TODO(), 7)
+		require.NoError(t, err)
+
+		res, err := r.GetByID(context.TODO(), 7)
+		require.NoError(t, err)
+		assert.Equal(t, mockArticle, res)
+		mockArticleRepo.AssertNumberOfCalls(t, "GetByID", 1)
+	})
+
+	t.Run("not-found-is-not-cached", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{}, domain.ErrNotFound).Once()
+		client := newFakeClient()
+		r := cacheRepo.NewArticleRepository(mockArticleRepo, client, cacheRepo.Config{})
+
+		_, err := r.GetByID(context.TODO(), 7)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Empty(t, client.entries)
+	})
+}
+
+func TestInvalidation(t *testing.T) {
+	mockArticle := domain.Article{ID: 7, Title: "Hello", Content: "Content", Author: domain.Author{ID: 1}, Version: 1}
+
+	t.Run("update", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Twice()
+		mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		client := newFakeClient()
+		r := cacheRepo.NewArticleRepository(mockArticleRepo, client, cacheRepo.Config{})
+
+		_, err := r.GetByID(context.TODO(), 7)
+		require.NoError(t, err)
+
+		updated := mockArticle
+		updated.Title = "Updated"
+		require.NoError(t, r.Update(context.TODO(), &updated))
+		assert.NotContains(t, client.entries, "article:7")
+
+		_, err = r.GetByID(context.TODO(), 7)
+		require.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+		mockArticleRepo.On("Delete", mock.Anything, int64(7)).Return(nil).Once()
+		client := newFakeClient()
+		r := cacheRepo.NewArticleRepository(mockArticleRepo, client, cacheRepo.Config{})
+
+		_, err := r.GetByID(context.TODO(), 7)
+		require.NoError(t, err)
+
+		require.NoError(t, r.Delete(context.TODO(), 7))
+		assert.Empty(t, client.entries)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("set-status", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+		mockArticleRepo.On("SetStatus", mock.Anything, int64(7), domain.StatusPublished).Return(nil).Once()
+		client := newFakeClient()
+		r := cacheRepo.NewArticleRepository(mockArticleRepo, client, cacheRepo.Config{})
+
+		_, err := r.GetByID(context.TODO(), 7)
+		require.NoError(t, err)
+
+		require.NoError(t, r.SetStatus(context.TODO(), 7, domain.StatusPublished))
+		assert.Empty(t, client.entries)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("failed-update-keeps-entry", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+		mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(domain.ErrConflict).Once()
+		client := newFakeClient()
+		r := cacheRepo.NewArticleRepository(mockArticleRepo, client, cacheRepo.Config{})
+
+		_, err := r.GetByID(context.TODO(), 7)
+		require.NoError(t, err)
+
+		stale := mockArticle
+		assert.ErrorIs(t, r.Update(context.TODO(), &stale), domain.ErrConflict)
+		assert.Contains(t, client.entries, "article:7")
+	})
+}
+
+func TestMissFromPrimary(t *testing.T) {
+	primary, lagging := new(mocks.ArticleRepository), new(mocks.ArticleRepository)
+	primary.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7, Version: 2}, nil).Once()
+	r := cacheRepo.NewArticleRepository(replica.NewArticleRepository(primary, lagging), newFakeClient(), cacheRepo.Config{})
+
+	res, err := r.GetByID(context.TODO(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), res.Version)
+	primary.AssertExpectations(t)
+	lagging.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestInvalidationAfterCommit(t *testing.T) {
+	mockArticle := domain.Article{ID: 7, Title: "Hello", Version: 1}
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockArticleRepo.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Twice()
+	mockArticleRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+	mockArticleRepo.On("WithinTransaction", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }).Once()
+	client := newFakeClient()
+	r := cacheRepo.NewArticleRepository(mockArticleRepo, client, cacheRepo.Config{})
+
+	_, err := r.GetByID(context.TODO(), 7)
+	require.NoError(t, err)
+
+	err = r.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+		// the read of the transaction isn't cached
+		if _, err := r.GetByID(ctx, 7); err != nil {
+			return err
+		}
+		updated := mockArticle
+		if err := r.Update(ctx, &updated); err != nil {
+			return err
+		}
+		assert.Contains(t, client.entries, "article:7", "the entry is dropped once the transaction ended")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Empty(t, client.entries)
+	mockArticleRepo.AssertExpectations(t)
+}
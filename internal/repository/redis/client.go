@@ -0,0 +1,44 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// ErrCacheMiss is returned by the Client when the key doesn't exist
+var ErrCacheMiss = errors.New("cache miss")
+
+// Client represent the subset of redis commands used by the cache
+type Client interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Del(ctx context.Context, keys ...string) error
+}
+
+type client struct {
+	rdb goredis.UniversalClient
+}
+
+// NewClient will create an object that represent the Client interface on top of go-redis
+func NewClient(rdb goredis.UniversalClient) Client {
+	return &client{rdb: rdb}
+}
+
+func (c *client) Get(ctx context.Context, key string) ([]byte, error) {
+	res, err := c.rdb.Get(ctx, key).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, ErrCacheMiss
+	}
+	return res, err
+}
+
+func (c *client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.rdb.Set(ctx, key, value, ttl).Err()
+}
+
+func (c *client) Del(ctx context.Context, keys ...string) error {
+	return c.rdb.Del(ctx, keys...).Err()
+}
@@ -0,0 +1,92 @@
+// Package replica splits the article queries between the primary database and a read replica
+package replica
+
+import (
+	"context"
+
+	"apismrtbiz/article"
+	"apismrtbiz/domain"
+)
+
+// primaryKey is the context key of the read-your-writes override
+type primaryKey struct{}
+
+// WithPrimary will send the reads of the context to the primary, for the callers which must see
+// their own writes before the replica caught up with them
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func usePrimary(ctx context.Context) bool {
+	v, _ := ctx.Value(primaryKey{}).(bool)
+	return v
+}
+
+// ArticleRepository reads the articles from the replica repository and writes them to the primary one,
+// every method which isn't overridden, the writes, the transactions and the ping, goes to the primary.
+// The reads within a transaction join it, so they are served by the primary as well.
+type ArticleRepository struct {
+	article.ArticleRepository
+	replica article.ArticleRepository
+}
+
+// NewArticleRepository will create an object that represent the article.ArticleRepository interface,
+// both repositories are expected to be built with the same driver over the primary and the replica database
+func NewArticleRepository(primary, replica article.ArticleRepository) *ArticleRepository {
+	return &ArticleRepository{
+		ArticleRepository: primary,
+		replica:           replica,
+	}
+}
+
+// reader is the repository serving the reads of the context
+func (m *ArticleRepository) reader(ctx context.Context) article.ArticleRepository {
+	if usePrimary(ctx) {
+		return m.ArticleRepository
+	}
+	return m.replica
+}
+
+func (m *ArticleRepository) Fetch(ctx context.Context, cursor domain.Cursor, num int64, filter domain.ArticleFilter) ([]domain.Article, error) {
+	return m.reader(ctx).Fetch(ctx, cursor, num, filter)
+}
+
+func (m *ArticleRepository) FetchRelated(ctx context.Context, ar domain.Article, num int64) ([]domain.Article, error) {
+	return m.reader(ctx).FetchRelated(ctx, ar, num)
+}
+
+func (m *ArticleRepository) OffsetFetch(ctx context.Context, offset, limit int64, status domain.Status) ([]domain.Article, error) {
+	return m.reader(ctx).OffsetFetch(ctx, offset, limit, status)
+}
+
+func (m *ArticleRepository) Count(ctx context.Context, status domain.Status) (int64, error) {
+	return m.reader(ctx).Count(ctx, status)
+}
+
+func (m *ArticleRepository) GetByID(ctx context.Context, id int64) (domain.Article, error) {
+	return m.reader(ctx).GetByID(ctx, id)
+}
+
+func (m *ArticleRepository) GetByIDs(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	return m.reader(ctx).GetByIDs(ctx, ids)
+}
+
+func (m *ArticleRepository) GetByTitle(ctx context.Context, title string) (domain.Article, error) {
+	return m.reader(ctx).GetByTitle(ctx, title)
+}
+
+func (m *ArticleRepository) GetBySlug(ctx context.Context, slug string) (domain.Article, error) {
+	return m.reader(ctx).GetBySlug(ctx, slug)
+}
+
+func (m *ArticleRepository) Search(ctx context.Context, query string, num int64, cursor domain.Cursor, status domain.Status) ([]domain.Article, error) {
+	return m.reader(ctx).Search(ctx, query, num, cursor, status)
+}
+
+func (m *ArticleRepository) ListRevisions(ctx context.Context, articleID int64) ([]domain.Revision, error) {
+	return m.reader(ctx).ListRevisions(ctx, articleID)
+}
+
+func (m *ArticleRepository) GetRevision(ctx context.Context, articleID, version int64) (domain.Revision, error) {
+	return m.reader(ctx).GetRevision(ctx, articleID, version)
+}
@@ -0,0 +1,123 @@
+package replica_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"apismrtbiz/domain"
+	mysqlRepo "apismrtbiz/internal/repository/mysql"
+	"apismrtbiz/internal/repository/replica"
+)
+
+var columns = []string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}
+
+// newRepository will split the queries between two stub databases, any query a database
+// doesn't expect fails, so every query must hit the expected one
+func newRepository(t *testing.T) (*replica.ArticleRepository, sqlmock.Sqlmock, sqlmock.Sqlmock) {
+	t.Helper()
+	primaryDB, primary, err := sqlmock.New()
+	require.NoError(t, err)
+	replicaDB, replicaMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, primary.ExpectationsWereMet())
+		assert.NoError(t, replicaMock.ExpectationsWereMet())
+		primaryDB.Close()
+		replicaDB.Close()
+	})
+
+	repo := replica.NewArticleRepository(mysqlRepo.NewArticleRepository(primaryDB), mysqlRepo.NewArticleRepository(replicaDB))
+	return repo, primary, replicaMock
+}
+
+func articleRows() *sqlmock.Rows {
+	return sqlmock.NewRows(columns).AddRow(1, "Hello", "Content", 1, time.Now(), time.Now(), nil, 1, "published", nil, 0, "hello", nil, nil)
+}
+
+func TestReads(t *testing.T) {
+	t.Run("fetch", func(t *testing.T) {
+		repo, _, replicaMock := newRepository(t)
+		replicaMock.ExpectQuery("FROM article WHERE deleted_at IS NULL ORDER BY updated_at DESC, id DESC LIMIT \\?").WillReturnRows(articleRows())
+
+		list, err := repo.Fetch(context.TODO(), domain.Cursor{}, 10, domain.ArticleFilter{})
+		require.NoError(t, err)
+		assert.Len(t, list, 1)
+	})
+
+	t.Run("get-by-id", func(t *testing.T) {
+		repo, _, replicaMock := newRepository(t)
+		replicaMock.ExpectQuery("FROM article WHERE ID = \\? AND deleted_at IS NULL").WithArgs(int64(1)).WillReturnRows(articleRows())
+
+		ar, err := repo.GetByID(context.TODO(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Hello", ar.Title)
+	})
+
+	t.Run("get-by-title", func(t *testing.T) {
+		repo, _, replicaMock := newRepository(t)
+		replicaMock.ExpectQuery("FROM article WHERE (.+)title(.+) AND deleted_at IS NULL").WithArgs("Hello").WillReturnRows(articleRows())
+
+		ar, err := repo.GetByTitle(context.TODO(), "Hello")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), ar.ID)
+	})
+
+	t.Run("read-your-writes", func(t *testing.T) {
+		repo, primary, _ := newRepository(t)
+		primary.ExpectQuery("FROM article WHERE ID = \\? AND deleted_at IS NULL").WithArgs(int64(1)).WillReturnRows(articleRows())
+
+		_, err := repo.GetByID(replica.WithPrimary(context.TODO()), 1)
+		require.NoError(t, err)
+	})
+
+	t.Run("within-transaction", func(t *testing.T) {
+		repo, primary, _ := newRepository(t)
+		primary.ExpectBegin()
+		primary.ExpectQuery("FROM article WHERE ID = \\? AND deleted_at IS NULL").WithArgs(int64(1)).WillReturnRows(articleRows())
+		primary.ExpectCommit()
+
+		err := repo.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			_, err := repo.GetByID(ctx, 1)
+			return err
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestWrites(t *testing.T) {
+	t.Run("store", func(t *testing.T) {
+		repo, primary, _ := newRepository(t)
+		primary.ExpectBegin()
+		primary.ExpectPrepare("INSERT  article SET").ExpectExec().WillReturnResult(sqlmock.NewResult(12, 1))
+		primary.ExpectCommit()
+
+		ar := &domain.Article{Title: "Hello", Content: "Content", Author: domain.Author{ID: 1}}
+		require.NoError(t, repo.Store(context.TODO(), ar))
+		assert.Equal(t, int64(12), ar.ID)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repo, primary, _ := newRepository(t)
+		primary.ExpectBegin()
+		primary.ExpectPrepare("UPDATE article set").ExpectExec().WillReturnResult(sqlmock.NewResult(12, 1))
+		primary.ExpectExec("DELETE FROM article_tag WHERE article_id = \\?").WithArgs(int64(12)).WillReturnResult(sqlmock.NewResult(0, 0))
+		primary.ExpectCommit()
+
+		ar := &domain.Article{ID: 12, Title: "Hello", Content: "Content", Author: domain.Author{ID: 1}, Version: 1}
+		require.NoError(t, repo.Update(context.TODO(), ar))
+		assert.Equal(t, int64(2), ar.Version)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repo, primary, _ := newRepository(t)
+		primary.ExpectPrepare("UPDATE article SET deleted_at=\\? WHERE id = \\? AND deleted_at IS NULL").
+			ExpectExec().WithArgs(sqlmock.AnyArg(), int64(12)).WillReturnResult(sqlmock.NewResult(12, 1))
+
+		require.NoError(t, repo.Delete(context.TODO(), 12))
+	})
+}
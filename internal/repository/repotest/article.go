@@ -0,0 +1,263 @@
+// Package repotest checks that the article repositories behave alike, whatever their backend
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/article"
+	"apismrtbiz/domain"
+)
+
+// Factory will create an empty repository for a single test, the authors of ids 1 and 2 are expected to exist
+type Factory func(t *testing.T) article.ArticleRepository
+
+// RunRepositoryConformanceTests will run the shared assertions on the CRUD, the pagination, the not found
+// and the conflict semantics against the repositories of the factory, each subtest gets a fresh one
+func RunRepositoryConformanceTests(t *testing.T, factory Factory) {
+	t.Helper()
+	t.Run("CRUD", func(t *testing.T) { testCRUD(t, factory(t)) })
+	t.Run("NotFound", func(t *testing.T) { testNotFound(t, factory(t)) })
+	t.Run("Conflict", func(t *testing.T) { testConflict(t, factory(t)) })
+	t.Run("Pagination", func(t *testing.T) { testPagination(t, factory(t)) })
+	t.Run("Transaction", func(t *testing.T) { testTransaction(t, factory(t)) })
+	t.Run("GivenID", func(t *testing.T) { testGivenID(t, factory(t)) })
+}
+
+func newArticle(title string) *domain.Article {
+	return &domain.Article{
+		Title:   title,
+		Slug:    domain.Slugify(title),
+		Content: "Content of " + title,
+		Author:  domain.Author{ID: 1},
+		Status:  domain.StatusPublished,
+	}
+}
+
+func testCRUD(t *testing.T, repo article.ArticleRepository) {
+	ctx := context.TODO()
+
+	ar := newArticle("Hello")
+	ar.Status = domain.StatusDraft
+	ar.Tags = []string{"golang", "web"}
+	require.NoError(t, repo.Store(ctx, ar))
+	require.NotZero(t, ar.ID)
+	assert.Equal(t, int64(1), ar.Version)
+	assert.False(t, ar.CreatedAt.IsZero())
+
+	got, err := repo.GetByID(ctx, ar.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", got.Title)
+	assert.Equal(t, "hello", got.Slug)
+	assert.Equal(t, int64(1), got.Author.ID)
+	assert.Equal(t, domain.StatusDraft, got.Status)
+	assert.Equal(t, []string{"golang", "web"}, got.Tags)
+
+	got, err = repo.GetByTitle(ctx, " hello ")
+	require.NoError(t, err)
+	assert.Equal(t, ar.ID, got.ID)
+	got, err = repo.GetBySlug(ctx, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, ar.ID, got.ID)
+
+	ar.Title = "Updated"
+	ar.Tags = []string{"golang"}
+	require.NoError(t, repo.Update(ctx, ar))
+	assert.Equal(t, int64(2), ar.Version)
+	got, err = repo.GetByID(ctx, ar.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", got.Title)
+	assert.Equal(t, "hello", got.Slug, "the slug is kept by an update")
+	assert.Equal(t, []string{"golang"}, got.Tags)
+
+	require.NoError(t, repo.SetStatus(ctx, ar.ID, domain.StatusPublished))
+	require.NoError(t, repo.IncrementViews(ctx, ar.ID))
+	got, err = repo.GetByID(ctx, ar.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusPublished, got.Status)
+	assert.Equal(t, int64(3), got.Version)
+	assert.Equal(t, int64(1), got.ViewCount)
+
+	list, err := repo.Search(ctx, "UPDAT", 10, domain.Cursor{}, "")
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+	list, err = repo.Search(ctx, "UPDAT", 10, domain.Cursor{}, domain.StatusDraft)
+	require.NoError(t, err)
+	assert.Empty(t, list, "the search keeps only the articles at the status")
+	list, err = repo.OffsetFetch(ctx, 0, 10, domain.StatusDraft)
+	require.NoError(t, err)
+	assert.Empty(t, list, "the page keeps only the articles at the status")
+	list, err = repo.OffsetFetch(ctx, 0, 10, domain.StatusPublished)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	total, err := repo.Count(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	total, err = repo.Count(ctx, domain.StatusDraft)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	require.NoError(t, repo.Delete(ctx, ar.ID))
+	_, err = repo.GetByID(ctx, ar.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	total, err = repo.Count(ctx, "")
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	list, err = repo.Fetch(ctx, domain.Cursor{}, 10, domain.ArticleFilter{IncludeDeleted: true})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.NotNil(t, list[0].DeletedAt)
+
+	require.NoError(t, repo.Restore(ctx, ar.ID))
+	_, err = repo.GetByID(ctx, ar.ID)
+	assert.NoError(t, err)
+}
+
+func testNotFound(t *testing.T, repo article.ArticleRepository) {
+	ctx := context.TODO()
+
+	_, err := repo.GetByID(ctx, 404)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = repo.GetByTitle(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = repo.GetBySlug(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = repo.GetRevision(ctx, 404, 1)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, 404), domain.ErrNotFound)
+	assert.ErrorIs(t, repo.Restore(ctx, 404), domain.ErrNotFound)
+	assert.ErrorIs(t, repo.SetStatus(ctx, 404, domain.StatusPublished), domain.ErrNotFound)
+	assert.ErrorIs(t, repo.IncrementViews(ctx, 404), domain.ErrNotFound)
+
+	ar := newArticle("Hello")
+	require.NoError(t, repo.Store(ctx, ar))
+	require.NoError(t, repo.Delete(ctx, ar.ID))
+	assert.ErrorIs(t, repo.Delete(ctx, ar.ID), domain.ErrNotFound, "an article is deleted once")
+	assert.ErrorIs(t, repo.IncrementViews(ctx, ar.ID), domain.ErrNotFound)
+	require.NoError(t, repo.Restore(ctx, ar.ID))
+	assert.ErrorIs(t, repo.Restore(ctx, ar.ID), domain.ErrNotFound, "an article is restored once")
+
+	list, err := repo.GetByIDs(ctx, []int64{ar.ID, 404})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, ar.ID, list[0].ID)
+}
+
+func testConflict(t *testing.T, repo article.ArticleRepository) {
+	ctx := context.TODO()
+
+	ar := newArticle("Hello")
+	require.NoError(t, repo.Store(ctx, ar))
+	require.NoError(t, repo.Update(ctx, ar))
+
+	stale := *ar
+	stale.Version = 1
+	stale.Title = "Stale"
+	assert.ErrorIs(t, repo.Update(ctx, &stale), domain.ErrConflict)
+	got, err := repo.GetByID(ctx, ar.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", got.Title, "a stale update isn't saved")
+	assert.Equal(t, int64(2), got.Version)
+
+	assert.ErrorIs(t, repo.Update(ctx, &domain.Article{ID: 404, Title: "title", Content: "content", Author: domain.Author{ID: 1}, Version: 1}), domain.ErrConflict)
+}
+
+func testGivenID(t *testing.T, repo article.ArticleRepository) {
+	ctx := context.TODO()
+
+	ar := newArticle("Given")
+	ar.ID = 42
+	require.NoError(t, repo.Store(ctx, ar))
+	assert.Equal(t, int64(42), ar.ID)
+	got, err := repo.GetByID(ctx, 42)
+	require.NoError(t, err)
+	assert.Equal(t, "Given", got.Title)
+
+	next := newArticle("Next")
+	require.NoError(t, repo.Store(ctx, next))
+	assert.Greater(t, next.ID, int64(42), "the ids handed out afterwards are past the given one")
+
+	taken := newArticle("Taken")
+	taken.ID = 42
+	assert.ErrorIs(t, repo.Store(ctx, taken), domain.ErrConflict)
+}
+
+func testPagination(t *testing.T, repo article.ArticleRepository) {
+	ctx := context.TODO()
+
+	for _, title := range []string{"first", "second", "third"} {
+		require.NoError(t, repo.Store(ctx, newArticle(title)))
+		// the articles are told apart by their update time
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	page, err := repo.Fetch(ctx, domain.Cursor{}, 2, domain.ArticleFilter{})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, "third", page[0].Title)
+	assert.Equal(t, "second", page[1].Title)
+
+	page, err = repo.Fetch(ctx, domain.NewCursor(domain.SortByUpdatedAt, page[1]), 2, domain.ArticleFilter{})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "first", page[0].Title)
+
+	page, err = repo.Fetch(ctx, domain.NewCursor(domain.SortByUpdatedAt, page[0]), 2, domain.ArticleFilter{Backward: true})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, "second", page[0].Title, "paging backward lists the articles closest to the cursor first")
+	assert.Equal(t, "third", page[1].Title)
+
+	sort := domain.ArticleFilter{Sort: domain.ArticleSort{Field: domain.SortByTitle, Order: domain.SortAsc}}
+	page, err = repo.Fetch(ctx, domain.Cursor{}, 2, sort)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, "first", page[0].Title)
+	page, err = repo.Fetch(ctx, domain.NewCursor(domain.SortByTitle, page[1]), 2, sort)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "third", page[0].Title)
+
+	page, err = repo.OffsetFetch(ctx, 1, 1, "")
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "second", page[0].Title)
+
+	page, err = repo.Search(ctx, "Content", 2, domain.Cursor{}, "")
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	page, err = repo.Search(ctx, "Content", 2, domain.NewCursor(domain.SortByUpdatedAt, page[1]), "")
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "first", page[0].Title)
+}
+
+func testTransaction(t *testing.T, repo article.ArticleRepository) {
+	ctx := context.TODO()
+	errAbort := errors.New("abort")
+
+	err := repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := repo.Store(ctx, newArticle("Rolled back")); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+	total, err := repo.Count(ctx, "")
+	require.NoError(t, err)
+	assert.Zero(t, total, "the writes of a failed transaction are undone")
+
+	err = repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		return repo.Store(ctx, newArticle("Committed"))
+	})
+	require.NoError(t, err)
+	total, err = repo.Count(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
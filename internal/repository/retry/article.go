@@ -0,0 +1,150 @@
+// Package retry retries the article writes failing with a transient database error
+package retry
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"syscall"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"apismrtbiz/article"
+	"apismrtbiz/domain"
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultBackoff     = 50 * time.Millisecond
+)
+
+// Config represent the tunable settings of the retries
+type Config struct {
+	// MaxAttempts caps the attempts of a write, the first one included, defaultMaxAttempts when zero
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubled on every other one, defaultBackoff when zero
+	Backoff time.Duration
+	// Retryable reports whether a failed attempt is worth another one, IsTransient when nil
+	Retryable func(err error) bool
+}
+
+// IsTransient reports whether err is a dropped connection, a deadlock or a lock wait timeout of MySQL,
+// or a deadlock or a serialization failure of PostgreSQL
+func IsTransient(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		// ER_LOCK_DEADLOCK and ER_LOCK_WAIT_TIMEOUT
+		return myErr.Number == 1213 || myErr.Number == 1205
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// deadlock_detected and serialization_failure
+		return pqErr.Code == "40P01" || pqErr.Code == "40001"
+	}
+	return false
+}
+
+// txKey marks the context of a transaction, whose statements are retried along the whole transaction
+type txKey struct{}
+
+// ArticleRepository retries the writes of the wrapped repository, every method which isn't overridden,
+// the reads among them, goes straight to the wrapped repository. A transaction is retried as a whole as
+// a failed statement aborts it, so the writes within it are not retried on their own.
+type ArticleRepository struct {
+	article.ArticleRepository
+	cfg Config
+}
+
+// NewArticleRepository will create an object that represent the article.ArticleRepository interface
+func NewArticleRepository(repo article.ArticleRepository, cfg Config) *ArticleRepository {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = defaultBackoff
+	}
+	if cfg.Retryable == nil {
+		cfg.Retryable = IsTransient
+	}
+	return &ArticleRepository{
+		ArticleRepository: repo,
+		cfg:               cfg,
+	}
+}
+
+// do will run the write until it succeeds, fails with an error which isn't retryable or runs out of
+// attempts, waiting an exponential backoff between the attempts
+func (m *ArticleRepository) do(ctx context.Context, op string, fn func() error) error {
+	if ctx.Value(txKey{}) != nil {
+		return fn()
+	}
+	backoff := m.cfg.Backoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt == m.cfg.MaxAttempts || !m.cfg.Retryable(err) {
+			return err
+		}
+		logrus.WithContext(ctx).WithError(err).Warnf("retrying %s, attempt %d of %d", op, attempt+1, m.cfg.MaxAttempts)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+func (m *ArticleRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.do(ctx, "WithinTransaction", func() error {
+		return m.ArticleRepository.WithinTransaction(ctx, func(ctx context.Context) error {
+			return fn(context.WithValue(ctx, txKey{}, true))
+		})
+	})
+}
+
+func (m *ArticleRepository) Store(ctx context.Context, ar *domain.Article) error {
+	return m.do(ctx, "Store", func() error { return m.ArticleRepository.Store(ctx, ar) })
+}
+
+func (m *ArticleRepository) Update(ctx context.Context, ar *domain.Article) error {
+	return m.do(ctx, "Update", func() error { return m.ArticleRepository.Update(ctx, ar) })
+}
+
+func (m *ArticleRepository) Delete(ctx context.Context, id int64) error {
+	return m.do(ctx, "Delete", func() error { return m.ArticleRepository.Delete(ctx, id) })
+}
+
+func (m *ArticleRepository) Restore(ctx context.Context, id int64) error {
+	return m.do(ctx, "Restore", func() error { return m.ArticleRepository.Restore(ctx, id) })
+}
+
+func (m *ArticleRepository) SetStatus(ctx context.Context, id int64, status domain.Status) error {
+	return m.do(ctx, "SetStatus", func() error { return m.ArticleRepository.SetStatus(ctx, id, status) })
+}
+
+func (m *ArticleRepository) PublishDue(ctx context.Context, now time.Time) (ids []int64, err error) {
+	err = m.do(ctx, "PublishDue", func() (err error) {
+		ids, err = m.ArticleRepository.PublishDue(ctx, now)
+		return err
+	})
+	return ids, err
+}
+
+func (m *ArticleRepository) IncrementViews(ctx context.Context, id int64) error {
+	return m.do(ctx, "IncrementViews", func() error { return m.ArticleRepository.IncrementViews(ctx, id) })
+}
+
+func (m *ArticleRepository) StoreRevision(ctx context.Context, rev *domain.Revision) error {
+	return m.do(ctx, "StoreRevision", func() error { return m.ArticleRepository.StoreRevision(ctx, rev) })
+}
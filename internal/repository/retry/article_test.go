@@ -0,0 +1,115 @@
+package retry_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/article/mocks"
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/repository/retry"
+)
+
+var deadlock = &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+
+func newRepository(repo *mocks.ArticleRepository) *retry.ArticleRepository {
+	return retry.NewArticleRepository(repo, retry.Config{MaxAttempts: 3, Backoff: time.Millisecond})
+}
+
+func TestRetry(t *testing.T) {
+	t.Run("transient", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Delete", mock.Anything, int64(1)).Return(deadlock).Twice()
+		mockArticleRepo.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+
+		require.NoError(t, newRepository(mockArticleRepo).Delete(context.TODO(), 1))
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("out-of-attempts", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Store", mock.Anything, mock.Anything).Return(driver.ErrBadConn).Times(3)
+
+		err := newRepository(mockArticleRepo).Store(context.TODO(), &domain.Article{Title: "Hello"})
+		assert.ErrorIs(t, err, driver.ErrBadConn)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("not-retryable", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Update", mock.Anything, mock.Anything).Return(domain.ErrConflict).Once()
+
+		err := newRepository(mockArticleRepo).Update(context.TODO(), &domain.Article{ID: 1})
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("reads", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{}, deadlock).Once()
+
+		_, err := newRepository(mockArticleRepo).GetByID(context.TODO(), 1)
+		assert.ErrorIs(t, err, deadlock)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		runTx := func(args mock.Arguments) {
+			fn := args.Get(1).(func(ctx context.Context) error)
+			_ = fn(args.Get(0).(context.Context))
+		}
+		mockArticleRepo.On("WithinTransaction", mock.Anything, mock.Anything).Run(runTx).Return(deadlock).Once()
+		mockArticleRepo.On("WithinTransaction", mock.Anything, mock.Anything).Run(runTx).Return(nil).Once()
+		// the write fails once within the transaction, it is the transaction which is retried
+		mockArticleRepo.On("Store", mock.Anything, mock.Anything).Return(deadlock).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.Anything).Return(nil).Once()
+		r := newRepository(mockArticleRepo)
+
+		err := r.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			return r.Store(ctx, &domain.Article{Title: "Hello"})
+		})
+		require.NoError(t, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		mockArticleRepo := new(mocks.ArticleRepository)
+		mockArticleRepo.On("Delete", mock.Anything, int64(1)).Return(deadlock).Once()
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+
+		err := newRepository(mockArticleRepo).Delete(ctx, 1)
+		assert.ErrorIs(t, err, deadlock)
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
+
+func TestIsTransient(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"bad-conn", driver.ErrBadConn, true},
+		{"mysql-deadlock", deadlock, true},
+		{"mysql-lock-wait", &mysql.MySQLError{Number: 1205}, true},
+		{"mysql-duplicate", &mysql.MySQLError{Number: 1062}, false},
+		{"postgres-serialization", &pq.Error{Code: "40001"}, true},
+		{"postgres-unique", &pq.Error{Code: "23505"}, false},
+		{"not-found", domain.ErrNotFound, false},
+		{"wrapped", errors.Join(errors.New("store"), driver.ErrBadConn), true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, retry.IsTransient(tc.err))
+		})
+	}
+}
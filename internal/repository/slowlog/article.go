@@ -0,0 +1,151 @@
+// Package slowlog logs the article queries running longer than a threshold
+package slowlog
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"apismrtbiz/article"
+	"apismrtbiz/domain"
+)
+
+const defaultThreshold = 200 * time.Millisecond
+
+// ArticleRepository logs a warning for every call of the wrapped repository taking longer than the threshold,
+// the fast calls are not logged
+type ArticleRepository struct {
+	repo      article.ArticleRepository
+	threshold time.Duration
+}
+
+// NewArticleRepository will create an object that represent the article.ArticleRepository interface,
+// a threshold below one falls back to the default threshold
+func NewArticleRepository(repo article.ArticleRepository, threshold time.Duration) *ArticleRepository {
+	if threshold <= 0 {
+		threshold = defaultThreshold
+	}
+	return &ArticleRepository{
+		repo:      repo,
+		threshold: threshold,
+	}
+}
+
+// observe will log the operation started at start once it exceeded the threshold, it is deferred by every method
+func (m *ArticleRepository) observe(ctx context.Context, op string, start time.Time) {
+	elapsed := time.Since(start)
+	if elapsed <= m.threshold {
+		return
+	}
+	logrus.WithContext(ctx).WithFields(logrus.Fields{
+		"operation":  op,
+		"elapsed_ms": elapsed.Milliseconds(),
+		"threshold":  m.threshold.String(),
+	}).Warn("slow query")
+}
+
+func (m *ArticleRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	defer m.observe(ctx, "WithinTransaction", time.Now())
+	return m.repo.WithinTransaction(ctx, fn)
+}
+
+func (m *ArticleRepository) Fetch(ctx context.Context, cursor domain.Cursor, num int64, filter domain.ArticleFilter) ([]domain.Article, error) {
+	defer m.observe(ctx, "Fetch", time.Now())
+	return m.repo.Fetch(ctx, cursor, num, filter)
+}
+
+func (m *ArticleRepository) OffsetFetch(ctx context.Context, offset, limit int64, status domain.Status) ([]domain.Article, error) {
+	defer m.observe(ctx, "OffsetFetch", time.Now())
+	return m.repo.OffsetFetch(ctx, offset, limit, status)
+}
+
+func (m *ArticleRepository) FetchRelated(ctx context.Context, ar domain.Article, num int64) ([]domain.Article, error) {
+	defer m.observe(ctx, "FetchRelated", time.Now())
+	return m.repo.FetchRelated(ctx, ar, num)
+}
+
+func (m *ArticleRepository) Count(ctx context.Context, status domain.Status) (int64, error) {
+	defer m.observe(ctx, "Count", time.Now())
+	return m.repo.Count(ctx, status)
+}
+
+func (m *ArticleRepository) GetByID(ctx context.Context, id int64) (domain.Article, error) {
+	defer m.observe(ctx, "GetByID", time.Now())
+	return m.repo.GetByID(ctx, id)
+}
+
+func (m *ArticleRepository) GetByIDs(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	defer m.observe(ctx, "GetByIDs", time.Now())
+	return m.repo.GetByIDs(ctx, ids)
+}
+
+func (m *ArticleRepository) GetByTitle(ctx context.Context, title string) (domain.Article, error) {
+	defer m.observe(ctx, "GetByTitle", time.Now())
+	return m.repo.GetByTitle(ctx, title)
+}
+
+func (m *ArticleRepository) GetBySlug(ctx context.Context, slug string) (domain.Article, error) {
+	defer m.observe(ctx, "GetBySlug", time.Now())
+	return m.repo.GetBySlug(ctx, slug)
+}
+
+func (m *ArticleRepository) Update(ctx context.Context, ar *domain.Article) error {
+	defer m.observe(ctx, "Update", time.Now())
+	return m.repo.Update(ctx, ar)
+}
+
+func (m *ArticleRepository) Store(ctx context.Context, ar *domain.Article) error {
+	defer m.observe(ctx, "Store", time.Now())
+	return m.repo.Store(ctx, ar)
+}
+
+func (m *ArticleRepository) Delete(ctx context.Context, id int64) error {
+	defer m.observe(ctx, "Delete", time.Now())
+	return m.repo.Delete(ctx, id)
+}
+
+func (m *ArticleRepository) Restore(ctx context.Context, id int64) error {
+	defer m.observe(ctx, "Restore", time.Now())
+	return m.repo.Restore(ctx, id)
+}
+
+func (m *ArticleRepository) SetStatus(ctx context.Context, id int64, status domain.Status) error {
+	defer m.observe(ctx, "SetStatus", time.Now())
+	return m.repo.SetStatus(ctx, id, status)
+}
+
+func (m *ArticleRepository) PublishDue(ctx context.Context, now time.Time) ([]int64, error) {
+	defer m.observe(ctx, "PublishDue", time.Now())
+	return m.repo.PublishDue(ctx, now)
+}
+
+func (m *ArticleRepository) IncrementViews(ctx context.Context, id int64) error {
+	defer m.observe(ctx, "IncrementViews", time.Now())
+	return m.repo.IncrementViews(ctx, id)
+}
+
+func (m *ArticleRepository) Search(ctx context.Context, query string, num int64, cursor domain.Cursor, status domain.Status) ([]domain.Article, error) {
+	defer m.observe(ctx, "Search", time.Now())
+	return m.repo.Search(ctx, query, num, cursor, status)
+}
+
+func (m *ArticleRepository) StoreRevision(ctx context.Context, rev *domain.Revision) error {
+	defer m.observe(ctx, "StoreRevision", time.Now())
+	return m.repo.StoreRevision(ctx, rev)
+}
+
+func (m *ArticleRepository) ListRevisions(ctx context.Context, articleID int64) ([]domain.Revision, error) {
+	defer m.observe(ctx, "ListRevisions", time.Now())
+	return m.repo.ListRevisions(ctx, articleID)
+}
+
+func (m *ArticleRepository) GetRevision(ctx context.Context, articleID, version int64) (domain.Revision, error) {
+	defer m.observe(ctx, "GetRevision", time.Now())
+	return m.repo.GetRevision(ctx, articleID, version)
+}
+
+func (m *ArticleRepository) Ping(ctx context.Context) error {
+	defer m.observe(ctx, "Ping", time.Now())
+	return m.repo.Ping(ctx)
+}
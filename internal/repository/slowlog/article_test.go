@@ -0,0 +1,50 @@
+package slowlog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/article/mocks"
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/repository/slowlog"
+)
+
+func TestSlowQuery(t *testing.T) {
+	var logs bytes.Buffer
+	logrus.SetOutput(&logs)
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+	defer func() {
+		logrus.SetOutput(os.Stderr)
+		logrus.SetFormatter(&logrus.TextFormatter{})
+	}()
+
+	mockArticleRepo := new(mocks.ArticleRepository)
+	mockArticleRepo.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1}, nil)
+	mockArticleRepo.On("GetByID", mock.Anything, int64(2)).After(30*time.Millisecond).Return(domain.Article{ID: 2}, nil)
+	r := slowlog.NewArticleRepository(mockArticleRepo, 20*time.Millisecond)
+
+	_, err := r.GetByID(context.TODO(), 1)
+	require.NoError(t, err)
+	assert.Empty(t, logs.String(), "a fast query is not logged")
+
+	ar, err := r.GetByID(context.TODO(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), ar.ID)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+	assert.Equal(t, "warning", entry["level"])
+	assert.Equal(t, "slow query", entry["msg"])
+	assert.Equal(t, "GetByID", entry["operation"])
+	assert.Equal(t, "20ms", entry["threshold"])
+	assert.GreaterOrEqual(t, entry["elapsed_ms"], float64(30))
+}
@@ -0,0 +1,324 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"apismrtbiz/article"
+	sqliteMigrations "apismrtbiz/database/sqlite/migrations"
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/database"
+	"apismrtbiz/internal/repository/repotest"
+	sqliteRepo "apismrtbiz/internal/repository/sqlite"
+)
+
+// openTestDB will create a fresh in-memory database, the pool is kept to one connection
+// since every connection would open its own in-memory database
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", sqliteRepo.DSN(":memory:"))
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	require.NoError(t, database.Migrate(context.TODO(), db, "sqlite", sqliteMigrations.FS))
+
+	_, err = db.Exec(`INSERT INTO author (id, name) VALUES (1, 'Iman Tumorang'), (2, 'Bxcodec')`)
+	require.NoError(t, err)
+	return db
+}
+
+func TestArticleConformance(t *testing.T) {
+	repotest.RunRepositoryConformanceTests(t, func(t *testing.T) article.ArticleRepository {
+		return sqliteRepo.NewArticleRepository(openTestDB(t))
+	})
+}
+
+func TestArticleCRUD(t *testing.T) {
+	db := openTestDB(t)
+	repo := sqliteRepo.NewArticleRepository(db)
+	ctx := context.TODO()
+
+	ar := &domain.Article{
+		Title:   "Hello",
+		Content: "Content",
+		Author:  domain.Author{ID: 1},
+		Status:  domain.StatusDraft,
+		Tags:    []string{"golang", "web"},
+	}
+	require.NoError(t, repo.Store(ctx, ar))
+	require.NotZero(t, ar.ID)
+	assert.Equal(t, int64(1), ar.Version)
+
+	got, err := repo.GetByID(ctx, ar.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", got.Title)
+	assert.Equal(t, []string{"golang", "web"}, got.Tags)
+	assert.WithinDuration(t, ar.CreatedAt, got.CreatedAt, time.Millisecond)
+
+	got, err = repo.GetByTitle(ctx, " hello ")
+	require.NoError(t, err)
+	assert.Equal(t, ar.ID, got.ID)
+
+	ar.Title = "Updated"
+	ar.Tags = []string{"golang"}
+	require.NoError(t, repo.Update(ctx, ar))
+	assert.Equal(t, int64(2), ar.Version)
+
+	stale := *ar
+	stale.Version = 1
+	assert.ErrorIs(t, repo.Update(ctx, &stale), domain.ErrConflict)
+
+	require.NoError(t, repo.SetStatus(ctx, ar.ID, domain.StatusPublished))
+	require.NoError(t, repo.IncrementViews(ctx, ar.ID))
+
+	list, err := repo.Fetch(ctx, domain.Cursor{}, 10, domain.ArticleFilter{Tag: "golang", Status: domain.StatusPublished})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "Updated", list[0].Title)
+	assert.Equal(t, []string{"golang"}, list[0].Tags)
+	assert.Equal(t, int64(1), list[0].ViewCount)
+
+	found, err := repo.Search(ctx, "UPDAT", 10, domain.Cursor{}, "")
+	require.NoError(t, err)
+	assert.Len(t, found, 1)
+	found, err = repo.Search(ctx, "%", 10, domain.Cursor{}, "")
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	total, err := repo.Count(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	require.NoError(t, repo.Delete(ctx, ar.ID))
+	_, err = repo.GetByID(ctx, ar.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, ar.ID), domain.ErrNotFound)
+
+	require.NoError(t, repo.Restore(ctx, ar.ID))
+	assert.ErrorIs(t, repo.Restore(ctx, ar.ID), domain.ErrNotFound)
+}
+
+func TestArticleNotFound(t *testing.T) {
+	db := openTestDB(t)
+	repo := sqliteRepo.NewArticleRepository(db)
+	ctx := context.TODO()
+
+	_, err := repo.GetByID(ctx, 404)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = repo.GetByTitle(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.ErrorIs(t, repo.SetStatus(ctx, 404, domain.StatusPublished), domain.ErrNotFound)
+	assert.ErrorIs(t, repo.IncrementViews(ctx, 404), domain.ErrNotFound)
+	assert.ErrorIs(t, repo.Update(ctx, &domain.Article{ID: 404, Title: "title", Content: "content", Version: 1}), domain.ErrConflict)
+}
+
+func TestArticleFetchCursor(t *testing.T) {
+	db := openTestDB(t)
+	repo := sqliteRepo.NewArticleRepository(db)
+	ctx := context.TODO()
+
+	for _, title := range []string{"first", "second", "third"} {
+		require.NoError(t, repo.Store(ctx, &domain.Article{Title: title, Slug: domain.Slugify(title), Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusPublished}))
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	page, err := repo.Fetch(ctx, domain.Cursor{}, 2, domain.ArticleFilter{})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, "third", page[0].Title)
+	assert.Equal(t, "second", page[1].Title)
+
+	cursor := domain.Cursor{ID: page[1].ID, Value: page[1].UpdatedAt.Format(time.RFC3339Nano)}
+	page, err = repo.Fetch(ctx, cursor, 2, domain.ArticleFilter{})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "first", page[0].Title)
+
+	page, err = repo.Fetch(ctx, domain.Cursor{}, 3, domain.ArticleFilter{Sort: domain.ArticleSort{Field: domain.SortByTitle, Order: domain.SortAsc}})
+	require.NoError(t, err)
+	require.Len(t, page, 3)
+	assert.Equal(t, "first", page[0].Title)
+
+	page, err = repo.OffsetFetch(ctx, 1, 1, "")
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "second", page[0].Title)
+
+	page, err = repo.GetByIDs(ctx, []int64{page[0].ID, 404})
+	require.NoError(t, err)
+	assert.Len(t, page, 1)
+}
+
+func TestArticleFetchCursorTies(t *testing.T) {
+	db := openTestDB(t)
+	repo := sqliteRepo.NewArticleRepository(db)
+	ctx := context.TODO()
+
+	// the articles are updated at the same time, only their id tells them apart
+	updatedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, title := range []string{"one", "two", "three", "four"} {
+		require.NoError(t, repo.Store(ctx, &domain.Article{Title: title, Slug: domain.Slugify(title), Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusPublished}))
+	}
+	_, err := db.Exec(`UPDATE article SET updated_at = ?`, updatedAt)
+	require.NoError(t, err)
+
+	seen := map[string]int{}
+	page, err := repo.Fetch(ctx, domain.Cursor{}, 2, domain.ArticleFilter{})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	for _, ar := range page {
+		seen[ar.Title]++
+	}
+
+	// an article inserted between the two pages sorts before the cursor, it must not shift the next page
+	require.NoError(t, repo.Store(ctx, &domain.Article{Title: "five", Slug: "five", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusPublished}))
+	_, err = db.Exec(`UPDATE article SET updated_at = ? WHERE title = 'five'`, updatedAt)
+	require.NoError(t, err)
+
+	page, err = repo.Fetch(ctx, domain.NewCursor(domain.SortByUpdatedAt, page[1]), 2, domain.ArticleFilter{})
+	require.NoError(t, err)
+	for _, ar := range page {
+		seen[ar.Title]++
+	}
+	assert.Equal(t, map[string]int{"one": 1, "two": 1, "three": 1, "four": 1}, seen)
+
+	list, err := repo.Search(ctx, "Content", 3, domain.Cursor{}, "")
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	next, err := repo.Search(ctx, "Content", 3, domain.NewCursor(domain.SortByUpdatedAt, list[2]), "")
+	require.NoError(t, err)
+	require.Len(t, next, 2)
+	ids := map[int64]bool{}
+	for _, ar := range append(list, next...) {
+		assert.False(t, ids[ar.ID], "article %d is repeated", ar.ID)
+		ids[ar.ID] = true
+	}
+}
+
+func TestArticleFetchRelated(t *testing.T) {
+	db := openTestDB(t)
+	repo := sqliteRepo.NewArticleRepository(db)
+	ctx := context.TODO()
+
+	ar := &domain.Article{Title: "Go", Slug: "go", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusPublished, Tags: []string{"golang", "web"}}
+	both := &domain.Article{Title: "Both tags", Slug: "both-tags", Content: "Content", Author: domain.Author{ID: 2}, Status: domain.StatusPublished, Tags: []string{"golang", "web"}}
+	author := &domain.Article{Title: "Same author", Slug: "same-author", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusPublished}
+	draft := &domain.Article{Title: "Draft", Slug: "draft", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusDraft, Tags: []string{"golang"}}
+	unrelated := &domain.Article{Title: "Unrelated", Slug: "unrelated", Content: "Content", Author: domain.Author{ID: 2}, Status: domain.StatusPublished, Tags: []string{"rust"}}
+	for _, a := range []*domain.Article{ar, both, author, draft, unrelated} {
+		require.NoError(t, repo.Store(ctx, a))
+	}
+
+	list, err := repo.FetchRelated(ctx, *ar, 5)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, both.ID, list[0].ID)
+	assert.Equal(t, author.ID, list[1].ID)
+}
+
+func TestArticlePublishDue(t *testing.T) {
+	db := openTestDB(t)
+	repo := sqliteRepo.NewArticleRepository(db)
+	ctx := context.TODO()
+
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	due := &domain.Article{Title: "Due", Slug: "due", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusDraft, PublishAt: &past}
+	later := &domain.Article{Title: "Later", Slug: "later", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusDraft, PublishAt: &future}
+	require.NoError(t, repo.Store(ctx, due))
+	require.NoError(t, repo.Store(ctx, later))
+
+	ids, err := repo.PublishDue(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{due.ID}, ids)
+
+	got, err := repo.GetByID(ctx, due.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusPublished, got.Status)
+	assert.Equal(t, int64(2), got.Version)
+
+	got, err = repo.GetByID(ctx, later.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusDraft, got.Status)
+
+	// the published drafts aren't published again
+	ids, err = repo.PublishDue(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestArticleWithinTransaction(t *testing.T) {
+	db := openTestDB(t)
+	repo := sqliteRepo.NewArticleRepository(db)
+	ctx := context.TODO()
+
+	storeBoth := func(second *domain.Article) error {
+		return repo.WithinTransaction(ctx, func(ctx context.Context) error {
+			first := &domain.Article{Title: "First", Slug: "first", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusDraft, Tags: []string{"golang"}}
+			if err := repo.Store(ctx, first); err != nil {
+				return err
+			}
+			return repo.Store(ctx, second)
+		})
+	}
+
+	// the tag repeated breaks the primary key of article_tag on the second store
+	err := storeBoth(&domain.Article{Title: "Second", Slug: "second", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusDraft, Tags: []string{"web", "web"}})
+	require.Error(t, err)
+	total, err := repo.Count(ctx, "")
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	err = storeBoth(&domain.Article{Title: "Second", Slug: "second", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusDraft, Tags: []string{"web"}})
+	require.NoError(t, err)
+	total, err = repo.Count(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+}
+
+func TestArticleRevisions(t *testing.T) {
+	db := openTestDB(t)
+	repo := sqliteRepo.NewArticleRepository(db)
+	svc := article.NewService(repo, sqliteRepo.NewAuthorRepository(db))
+	ctx := context.TODO()
+
+	ar := &domain.Article{Title: "First", Slug: "first", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusDraft, Tags: []string{"golang"}}
+	require.NoError(t, svc.Store(ctx, ar))
+
+	// every update keeps the version it replaced
+	for _, title := range []string{"Second", "Third"} {
+		ar.Title = title
+		require.NoError(t, svc.Update(ctx, ar))
+	}
+	stale := *ar
+	stale.Version = 1
+	assert.ErrorIs(t, svc.Update(ctx, &stale), domain.ErrConflict)
+
+	list, err := svc.ListRevisions(ctx, ar.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, int64(2), list[0].Version)
+	assert.Equal(t, "Second", list[0].Title)
+	assert.Equal(t, int64(1), list[1].Version)
+	assert.Equal(t, "First", list[1].Title)
+	assert.Equal(t, []string{"golang"}, list[1].Tags)
+	assert.False(t, list[1].CreatedAt.IsZero())
+
+	_, err = svc.ListRevisions(ctx, 404)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestAuthorGetByID(t *testing.T) {
+	db := openTestDB(t)
+	repo := sqliteRepo.NewAuthorRepository(db)
+
+	author, err := repo.GetByID(context.TODO(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Iman Tumorang", author.Name)
+}
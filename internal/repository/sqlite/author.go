@@ -0,0 +1,44 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"apismrtbiz/domain"
+)
+
+type AuthorRepository struct {
+	DB *sql.DB
+}
+
+// NewAuthorRepository will create an implementation of author.Repository
+func NewAuthorRepository(db *sql.DB) *AuthorRepository {
+	return &AuthorRepository{
+		DB: db,
+	}
+}
+
+func (m *AuthorRepository) getOne(ctx context.Context, query string, args ...interface{}) (res domain.Author, err error) {
+	stmt, err := m.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.Author{}, err
+	}
+	row := stmt.QueryRowContext(ctx, args...)
+	res = domain.Author{}
+
+	err = row.Scan(
+		&res.ID,
+		&res.Name,
+		&res.CreatedAt,
+		&res.UpdatedAt,
+	)
+	return
+}
+
+func (m *AuthorRepository) GetByID(ctx context.Context, id int64) (res domain.Author, err error) {
+	ctx, span := startSpan(ctx, "AuthorRepository.GetByID", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	query := `SELECT id, name, created_at, updated_at FROM author WHERE id=?`
+	return m.getOne(ctx, query, id)
+}
@@ -0,0 +1,136 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"apismrtbiz/domain"
+)
+
+type CategoryRepository struct {
+	Conn *sql.DB
+}
+
+// NewCategoryRepository will create an object that represent the category.CategoryRepository interface
+func NewCategoryRepository(conn *sql.DB) *CategoryRepository {
+	return &CategoryRepository{conn}
+}
+
+func (m *CategoryRepository) fetch(ctx context.Context, query string, args ...interface{}) (res []domain.Category, err error) {
+	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.WithContext(ctx).Error(err)
+		return nil, err
+	}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.WithContext(ctx).Error(errRow)
+		}
+	}()
+
+	res = make([]domain.Category, 0)
+	for rows.Next() {
+		c := domain.Category{}
+		if err = rows.Scan(&c.ID, &c.Name, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			logrus.WithContext(ctx).Error(err)
+			return nil, err
+		}
+		res = append(res, c)
+	}
+	return res, rows.Err()
+}
+
+// Fetch will list every category by name
+func (m *CategoryRepository) Fetch(ctx context.Context) (res []domain.Category, err error) {
+	ctx, span := startSpan(ctx, "CategoryRepository.Fetch", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	return m.fetch(ctx, `SELECT id, name, created_at, updated_at FROM category ORDER BY name, id`)
+}
+
+func (m *CategoryRepository) GetByID(ctx context.Context, id int64) (res domain.Category, err error) {
+	ctx, span := startSpan(ctx, "CategoryRepository.GetByID", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	list, err := m.fetch(ctx, `SELECT id, name, created_at, updated_at FROM category WHERE id = ?`, id)
+	if err != nil {
+		return
+	}
+	if len(list) == 0 {
+		return res, domain.ErrNotFound
+	}
+	return list[0], nil
+}
+
+func (m *CategoryRepository) Store(ctx context.Context, c *domain.Category) (err error) {
+	ctx, span := startSpan(ctx, "CategoryRepository.Store", "INSERT")
+	defer func() { endSpan(span, err) }()
+
+	now := time.Now()
+	query := `INSERT INTO category (name, created_at, updated_at) VALUES (?, ?, ?)`
+	res, err := m.Conn.ExecContext(ctx, query, c.Name, now, now)
+	if err != nil {
+		return
+	}
+	if c.ID, err = res.LastInsertId(); err != nil {
+		return
+	}
+	c.CreatedAt = now
+	c.UpdatedAt = now
+	return
+}
+
+// Update will rename the category, the creation time is read back so the category is returned whole
+func (m *CategoryRepository) Update(ctx context.Context, c *domain.Category) (err error) {
+	ctx, span := startSpan(ctx, "CategoryRepository.Update", "UPDATE")
+	defer func() { endSpan(span, err) }()
+
+	updatedAt := time.Now()
+	res, err := m.Conn.ExecContext(ctx, `UPDATE category SET name = ?, updated_at = ? WHERE id = ?`, c.Name, updatedAt, c.ID)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		return domain.ErrNotFound
+	}
+	updated, err := m.GetByID(ctx, c.ID)
+	if err != nil {
+		return
+	}
+	*c = updated
+	return
+}
+
+func (m *CategoryRepository) Delete(ctx context.Context, id int64) (err error) {
+	ctx, span := startSpan(ctx, "CategoryRepository.Delete", "DELETE")
+	defer func() { endSpan(span, err) }()
+
+	res, err := m.Conn.ExecContext(ctx, `DELETE FROM category WHERE id = ?`, id)
+	if err != nil {
+		return
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+	if affect == 0 {
+		return domain.ErrNotFound
+	}
+	return
+}
+
+func (m *CategoryRepository) HasArticles(ctx context.Context, id int64) (res bool, err error) {
+	ctx, span := startSpan(ctx, "CategoryRepository.HasArticles", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	err = m.Conn.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM article WHERE category_id = ?)`, id).Scan(&res)
+	return
+}
@@ -0,0 +1,52 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/category"
+	"apismrtbiz/domain"
+	sqliteRepo "apismrtbiz/internal/repository/sqlite"
+)
+
+func TestCategories(t *testing.T) {
+	db := openTestDB(t)
+	articleRepo := sqliteRepo.NewArticleRepository(db)
+	svc := category.NewService(sqliteRepo.NewCategoryRepository(db))
+	ctx := context.TODO()
+
+	golang := &domain.Category{Name: "Golang"}
+	require.NoError(t, svc.Store(ctx, golang))
+	require.NotZero(t, golang.ID)
+	empty := &domain.Category{Name: "Empty"}
+	require.NoError(t, svc.Store(ctx, empty))
+
+	filed := &domain.Article{Title: "Filed", Slug: "filed", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusPublished, CategoryID: golang.ID}
+	require.NoError(t, articleRepo.Store(ctx, filed))
+	loose := &domain.Article{Title: "Loose", Slug: "loose", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusPublished}
+	require.NoError(t, articleRepo.Store(ctx, loose))
+
+	list, err := articleRepo.Fetch(ctx, domain.Cursor{}, 10, domain.ArticleFilter{CategoryID: golang.ID})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, filed.ID, list[0].ID)
+	assert.Equal(t, golang.ID, list[0].CategoryID)
+
+	categories, err := svc.Fetch(ctx)
+	require.NoError(t, err)
+	require.Len(t, categories, 2)
+	assert.Equal(t, "Empty", categories[0].Name)
+
+	err = svc.Delete(ctx, golang.ID)
+	assert.ErrorIs(t, err, domain.ErrInUse)
+	_, err = svc.GetByID(ctx, golang.ID)
+	assert.NoError(t, err)
+
+	require.NoError(t, svc.Delete(ctx, empty.ID))
+	_, err = svc.GetByID(ctx, empty.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.ErrorIs(t, svc.Delete(ctx, empty.ID), domain.ErrNotFound)
+}
@@ -0,0 +1,44 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/comment"
+	"apismrtbiz/domain"
+	sqliteRepo "apismrtbiz/internal/repository/sqlite"
+)
+
+func TestCommentPages(t *testing.T) {
+	db := openTestDB(t)
+	articleRepo := sqliteRepo.NewArticleRepository(db)
+	svc := comment.NewService(sqliteRepo.NewCommentRepository(db), articleRepo)
+	ctx := context.TODO()
+
+	ar := &domain.Article{Title: "Hello", Slug: "hello", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusPublished}
+	require.NoError(t, articleRepo.Store(ctx, ar))
+	for _, content := range []string{"First", "Second", "Third"} {
+		c := &domain.Comment{ArticleID: ar.ID, Author: "Iman", Content: content}
+		require.NoError(t, svc.Store(ctx, c))
+		require.NotZero(t, c.ID)
+	}
+
+	page, next, err := svc.Fetch(ctx, ar.ID, "", 2)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, "First", page[0].Content)
+	assert.Equal(t, "Second", page[1].Content)
+	require.NotEmpty(t, next)
+
+	page, next, err = svc.Fetch(ctx, ar.ID, next, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "Third", page[0].Content)
+	assert.Empty(t, next)
+
+	err = svc.Store(ctx, &domain.Comment{ArticleID: ar.ID + 1, Author: "Iman", Content: "Lost"})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
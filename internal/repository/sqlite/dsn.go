@@ -0,0 +1,16 @@
+package sqlite
+
+import (
+	"net/url"
+)
+
+// DSN will build the data source name of the database file at path, use ":memory:" for a throwaway database.
+// The times are written in the SQLite format so they compare in order, and the transactions take the write
+// lock upfront so two of them never deadlock upgrading their lock.
+func DSN(path string) string {
+	params := url.Values{}
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_time_format", "sqlite")
+	params.Add("_txlock", "immediate")
+	return "file:" + path + "?" + params.Encode()
+}
@@ -0,0 +1,41 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/domain"
+	"apismrtbiz/favorite"
+	sqliteRepo "apismrtbiz/internal/repository/sqlite"
+)
+
+func TestFavorites(t *testing.T) {
+	db := openTestDB(t)
+	articleRepo := sqliteRepo.NewArticleRepository(db)
+	svc := favorite.NewService(sqliteRepo.NewFavoriteRepository(db), articleRepo)
+	ctx := context.TODO()
+
+	ar := &domain.Article{Title: "Hello", Slug: "hello", Content: "Content", Author: domain.Author{ID: 1}, Status: domain.StatusPublished}
+	require.NoError(t, articleRepo.Store(ctx, ar))
+
+	require.NoError(t, svc.Favorite(ctx, "42", ar.ID))
+	require.NoError(t, svc.Favorite(ctx, "42", ar.ID))
+	require.NoError(t, svc.Favorite(ctx, "7", ar.ID))
+
+	status, err := svc.Status(ctx, "42", []int64{ar.ID})
+	require.NoError(t, err)
+	assert.Equal(t, domain.FavoriteStatus{Favorited: true, Count: 2}, status[ar.ID])
+
+	require.NoError(t, svc.Unfavorite(ctx, "42", ar.ID))
+	require.NoError(t, svc.Unfavorite(ctx, "42", ar.ID))
+
+	status, err = svc.Status(ctx, "42", []int64{ar.ID})
+	require.NoError(t, err)
+	assert.Equal(t, domain.FavoriteStatus{Favorited: false, Count: 1}, status[ar.ID])
+
+	err = svc.Favorite(ctx, "42", ar.ID+1)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
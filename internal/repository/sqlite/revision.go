@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"apismrtbiz/domain"
+)
+
+// StoreRevision will record the snapshot of an article, in the transaction of the context when there is one
+func (m *ArticleRepository) StoreRevision(ctx context.Context, rev *domain.Revision) (err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.StoreRevision", "INSERT")
+	defer func() { endSpan(span, err) }()
+
+	rev.CreatedAt = time.Now()
+	query := `INSERT INTO article_revisions (article_id, version, title, content, tags, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err = m.conn(ctx).ExecContext(ctx, query, rev.ArticleID, rev.Version, rev.Title, rev.Content, strings.Join(rev.Tags, ","), rev.CreatedAt)
+	return
+}
+
+// ListRevisions will list the revisions of the article, the newest first
+func (m *ArticleRepository) ListRevisions(ctx context.Context, articleID int64) (res []domain.Revision, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.ListRevisions", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	query := `SELECT article_id, version, title, content, tags, created_at FROM article_revisions
+  						WHERE article_id = ? ORDER BY version DESC`
+	return m.fetchRevisions(ctx, query, articleID)
+}
+
+// GetRevision will get the revision of the article at the given version
+func (m *ArticleRepository) GetRevision(ctx context.Context, articleID, version int64) (res domain.Revision, err error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.GetRevision", "SELECT")
+	defer func() { endSpan(span, err) }()
+
+	query := `SELECT article_id, version, title, content, tags, created_at FROM article_revisions
+  						WHERE article_id = ? AND version = ?`
+	list, err := m.fetchRevisions(ctx, query, articleID, version)
+	if err != nil {
+		return domain.Revision{}, err
+	}
+	if len(list) == 0 {
+		return domain.Revision{}, domain.ErrNotFound
+	}
+	return list[0], nil
+}
+
+func (m *ArticleRepository) fetchRevisions(ctx context.Context, query string, args ...interface{}) (result []domain.Revision, err error) {
+	rows, err := m.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.WithContext(ctx).Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.WithContext(ctx).Error(errRow)
+		}
+	}()
+
+	result = make([]domain.Revision, 0)
+	for rows.Next() {
+		rev := domain.Revision{}
+		tags := ""
+		if err = rows.Scan(&rev.ArticleID, &rev.Version, &rev.Title, &rev.Content, &tags, &rev.CreatedAt); err != nil {
+			logrus.WithContext(ctx).Error(err)
+			return nil, err
+		}
+		if tags != "" {
+			rev.Tags = strings.Split(tags, ",")
+		}
+		result = append(result, rev)
+	}
+
+	return result, rows.Err()
+}
@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+)
+
+// txKey is the context key of the transaction the repository calls join
+type txKey struct{}
+
+// executor runs the queries, either on the database or in the transaction carried by the context
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// WithinTransaction will run fn in a transaction, committed when fn succeeds and rolled back on any error.
+// The repository calls given the context of fn join the transaction, and so does a nested WithinTransaction.
+func (m *ArticleRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	tx, err := beginTx(ctx, m.Conn)
+	if err != nil {
+		return
+	}
+	defer func() { err = finishTx(ctx, tx, err) }()
+
+	return fn(context.WithValue(ctx, txKey{}, tx))
+}
+
+// conn will return the transaction carried by the context, or the database outside of one
+func (m *ArticleRepository) conn(ctx context.Context) executor {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return m.Conn
+}
+
+// beginTx will start a transaction, unless the context already carries one which is joined instead
+func beginTx(ctx context.Context, db *sql.DB) (*sql.Tx, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx, nil
+	}
+	return db.BeginTx(ctx, nil)
+}
+
+// finishTx will commit the transaction when the work succeeded and roll it back otherwise,
+// a joined transaction is left to the WithinTransaction which started it
+func finishTx(ctx context.Context, tx *sql.Tx, err error) error {
+	if joined, ok := ctx.Value(txKey{}).(*sql.Tx); ok && joined == tx {
+		return err
+	}
+	if err == nil {
+		return tx.Commit()
+	}
+	if errRollback := tx.Rollback(); errRollback != nil {
+		logrus.WithContext(ctx).Error(errRollback)
+	}
+	return err
+}
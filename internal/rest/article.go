@@ -2,163 +2,1112 @@ package rest
 
 import (
 	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
 	"github.com/gofiber/fiber/v2"
+	"math"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/sirupsen/logrus"
-	validator "gopkg.in/go-playground/validator.v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"apismrtbiz/domain"
+	"apismrtbiz/internal/rest/middleware"
 )
 
 // ResponseError represent the response error struct
 type ResponseError struct {
-	Message string `json:"message"`
+	XMLName xml.Name `json:"-" xml:"error"`
+	Message string   `json:"message" xml:"message"`
 }
 
 // ArticleService represent the article's usecases
 //
 //go:generate mockery --name ArticleService
 type ArticleService interface {
-	Fetch(ctx context.Context, cursor string, num int64) ([]domain.Article, string, error)
+	Fetch(ctx context.Context, cursor string, num int64, filter domain.ArticleFilter) ([]domain.Article, string, string, error)
+	OffsetFetch(ctx context.Context, page, perPage int64, status domain.Status) ([]domain.Article, int64, error)
+	Count(ctx context.Context, status domain.Status) (int64, error)
 	GetByID(ctx context.Context, id int64) (domain.Article, error)
+	GetByIDs(ctx context.Context, ids []int64) ([]domain.Article, error)
 	Update(ctx context.Context, ar *domain.Article) error
+	Upsert(ctx context.Context, ar *domain.Article) (bool, error)
 	GetByTitle(ctx context.Context, title string) (domain.Article, error)
+	GetBySlug(ctx context.Context, slug string) (domain.Article, error)
 	Store(context.Context, *domain.Article) error
+	StoreBatch(ctx context.Context, list []*domain.Article) error
 	Delete(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) error
+	Search(ctx context.Context, query string, num int64, cursor string, status domain.Status) ([]domain.Article, string, error)
+	FuzzySearch(ctx context.Context, query string, num int64) ([]domain.Article, error)
+	Publish(ctx context.Context, id int64) error
+	Unpublish(ctx context.Context, id int64) error
+	IncrementViews(ctx context.Context, id int64) error
+	GetRelated(ctx context.Context, id int64, num int64) ([]domain.Article, error)
+	ListRevisions(ctx context.Context, id int64) ([]domain.Revision, error)
+	DiffRevisions(ctx context.Context, id, from, to int64) (domain.RevisionDiff, error)
+	RenderHTML(content string) (string, error)
+}
+
+// HandlerConfig represent the tunable settings of the article handler
+type HandlerConfig struct {
+	// DefaultPageSize is used when the client doesn't ask for a page size, defaultNum when zero
+	DefaultPageSize int
+	// MaxPageSize caps the page size asked by the client, maxNum when zero
+	MaxPageSize int
+	// IdempotencyStore enables the Idempotency-Key header on article creation when set
+	IdempotencyStore IdempotencyStore
+	// IdempotencyTTL is how long an idempotency key is remembered, defaultIdempotencyTTL when zero
+	IdempotencyTTL time.Duration
+	// WordsPerMinute is the reading speed the reading time of the articles is estimated with,
+	// defaultWordsPerMinute when zero
+	WordsPerMinute int
+	// Favorites fills in the favorite status of the articles read by an authenticated user when set
+	Favorites FavoriteService
+	// BasePath prefixes the routes, the Location headers and the links of the articles, e.g. /api/v1,
+	// the routes are mounted at the root when empty
+	BasePath string
+	// Version is the shape the articles are written in, APIVersion1 when empty
+	Version APIVersion
+	// RequireIfMatch rejects the updates of an article without an If-Match header with a 428,
+	// they are let through when false
+	RequireIfMatch bool
+	// FuzzyRateLimit limits the fuzzy searches of every client, they rank the articles in the service
+	// so they are limited apart from the other requests, defaultFuzzyRateLimit when the rate is zero
+	FuzzyRateLimit middleware.RateLimitConfig
+}
+
+// CountResponse represent the response of the article count
+type CountResponse struct {
+	XMLName xml.Name `json:"-" xml:"count"`
+	Count   int64    `json:"count" xml:",chardata"`
+}
+
+// BulkResult represent the outcome of a single article within a bulk request
+type BulkResult struct {
+	Index   int          `json:"index" xml:"index"`
+	ID      int64        `json:"id,omitempty" xml:"id,omitempty"`
+	Success bool         `json:"success" xml:"success"`
+	Errors  []FieldError `json:"errors,omitempty" xml:"errors>error,omitempty"`
+}
+
+// articlePatch represent the partial update of an article, nil fields are left untouched
+type articlePatch struct {
+	Title      *string   `json:"title"`
+	Content    *string   `json:"content"`
+	Tags       *[]string `json:"tags"`
+	CategoryID *int64    `json:"category_id"`
+	Version    *int64    `json:"version"`
+}
+
+func (p articlePatch) isEmpty() bool {
+	return p.Title == nil && p.Content == nil && p.Tags == nil && p.CategoryID == nil
+}
+
+// fields will list the fields of the article the patch changes, by their name in domain.Article
+func (p articlePatch) fields() []string {
+	var fields []string
+	if p.Title != nil {
+		fields = append(fields, "Title")
+	}
+	if p.Content != nil {
+		fields = append(fields, "Content")
+	}
+	if p.Tags != nil {
+		fields = append(fields, "Tags")
+	}
+	if p.CategoryID != nil {
+		fields = append(fields, "CategoryID")
+	}
+	return fields
+}
+
+func (p articlePatch) apply(ar *domain.Article) {
+	if p.Title != nil {
+		ar.Title = *p.Title
+	}
+	if p.Content != nil {
+		ar.Content = *p.Content
+	}
+	if p.Tags != nil {
+		ar.Tags = *p.Tags
+	}
+	if p.CategoryID != nil {
+		ar.CategoryID = *p.CategoryID
+	}
+	if p.Version != nil {
+		ar.Version = *p.Version
+	}
 }
 
 // ArticleHandler  represent the httphandler for article
 type ArticleHandler struct {
 	Service ArticleService
+	Config  HandlerConfig
 }
 
-const defaultNum = 10
+const (
+	defaultNum = 10
+	maxNum     = 100
+	// maxPage bounds the offset of the page mode, the deeper articles are reached with the cursor
+	maxPage = 10000
+
+	maxSearchQueryLength = 100
+
+	defaultRelatedNum = 5
+
+	// the formats of the content of an article, the stored markdown or the rendered HTML
+	formatMarkdown = "markdown"
+	formatHTML     = "html"
+)
+
+// defaultFuzzyRateLimit lets a client run a fuzzy search a second, with bursts of five
+var defaultFuzzyRateLimit = middleware.RateLimitConfig{Rate: 1, Burst: 5}
 
 // NewArticleHandler will initialize the articles/ resources endpoint
-func NewArticleHandler(e *fiber.App, svc ArticleService) {
+func NewArticleHandler(e *fiber.App, svc ArticleService, cfg HandlerConfig) {
+	cfg.BasePath = strings.TrimRight(cfg.BasePath, "/")
+	if cfg.FuzzyRateLimit.Rate <= 0 {
+		cfg.FuzzyRateLimit = defaultFuzzyRateLimit
+	}
+	cfg.FuzzyRateLimit.Next = func(c *fiber.Ctx) bool {
+		return !c.QueryBool("fuzzy")
+	}
 	handler := &ArticleHandler{
 		Service: svc,
+		Config:  cfg,
+	}
+	var r fiber.Router = e
+	if cfg.BasePath != "" || cfg.Version != "" {
+		r = e.Group(cfg.BasePath, func(c *fiber.Ctx) error {
+			c.Locals(localsBasePath, cfg.BasePath)
+			c.Locals(localsAPIVersion, cfg.Version)
+			if !acceptsVersion(c) {
+				return c.Status(http.StatusNotAcceptable).JSON(ResponseError{Message: errV2Representation.Error()})
+			}
+			return c.Next()
+		})
 	}
-	e.Get("/articles", handler.FetchArticle)
-	e.Post("/articles", handler.Store)
-	e.Get("/articles/:id", handler.GetByID)
-	e.Delete("/articles/:id", handler.Delete)
+	r.Get("/articles", handler.FetchArticle)
+	r.Post("/articles", handler.Store)
+	r.Post("/articles/bulk", handler.StoreBulk)
+	r.Post("/articles/import", handler.ImportCSV)
+	r.Get("/articles/count", handler.Count)
+	r.Get("/articles/export.csv", handler.ExportCSV)
+	r.Get("/articles/search", middleware.RateLimit(cfg.FuzzyRateLimit), handler.GetByTitle)
+	r.Get("/articles/slug/:slug", handler.GetBySlug)
+	r.Get("/articles/:id", handler.GetByID)
+	r.Get("/articles/:id/related", handler.Related)
+	r.Get("/articles/:id/revisions", handler.Revisions)
+	r.Get("/articles/:id/revisions/diff", handler.RevisionDiff)
+	r.Put("/articles/:id", handler.Update)
+	r.Patch("/articles/:id", handler.Patch)
+	r.Delete("/articles/:id", handler.Delete)
+	r.Post("/articles/:id/restore", handler.Restore)
+	r.Post("/articles/:id/publish", handler.Publish)
+	r.Post("/articles/:id/unpublish", handler.Unpublish)
+	r.Post("/articles/:id/view", handler.View)
 }
 
 // FetchArticle will fetch the article based on given params
 func (a *ArticleHandler) FetchArticle(c *fiber.Ctx) error {
+	if c.Query("ids") != "" {
+		return a.getByIDs(c)
+	}
 
-	numS := c.Query("num")
-	num, err := strconv.Atoi(numS)
-	if err != nil || num == 0 {
-		num = defaultNum
+	if c.Query("page") != "" || c.Query("per_page") != "" {
+		if c.Query("cursor") != "" {
+			return send(c.Status(http.StatusBadRequest), ResponseError{Message: "cursor and page params are mutually exclusive"})
+		}
+		return a.offsetFetchArticle(c)
+	}
+
+	num := a.pageSize(c.Query("num"))
+
+	sort, err := parseSort(c.Query("sort"), c.Query("order"))
+	if err != nil {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: err.Error()})
+	}
+
+	var authorID int64
+	if raw := c.Query("author_id"); raw != "" {
+		authorID, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || authorID <= 0 {
+			return send(c.Status(http.StatusBadRequest), ResponseError{Message: "author_id must be a positive integer"})
+		}
+	}
+
+	var categoryID int64
+	if raw := c.Query("category_id"); raw != "" {
+		categoryID, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || categoryID <= 0 {
+			return send(c.Status(http.StatusBadRequest), ResponseError{Message: "category_id must be a positive integer"})
+		}
+	}
+
+	createdFrom, createdTo, err := parseCreatedRange(c.Query("created_from"), c.Query("created_to"))
+	if err != nil {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: err.Error()})
 	}
 
 	cursor := c.Query("cursor")
+	var backward bool
+	switch c.Query("direction") {
+	case "", "forward":
+	case "backward":
+		if cursor == "" {
+			return send(c.Status(http.StatusBadRequest), ResponseError{Message: "direction=backward requires a cursor"})
+		}
+		backward = true
+	default:
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: "direction must be one of forward, backward"})
+	}
+
+	status, err := parseStatus(c)
+	if err != nil {
+		return sendStatusErr(c, err)
+	}
 
-	listAr, nextCursor, err := a.Service.Fetch(c.Context(), cursor, int64(num))
+	includeDeleted := c.QueryBool("include_deleted")
+	if includeDeleted && !isEditor(c) {
+		return send(c.Status(http.StatusForbidden), ResponseError{Message: errDeletedForbidden.Error()})
+	}
 
-	if rep := ReturnErr(c, err); rep != nil {
-		return rep
+	filter := domain.ArticleFilter{
+		IncludeDeleted: includeDeleted,
+		Sort:           sort,
+		AuthorID:       authorID,
+		CreatedFrom:    createdFrom,
+		CreatedTo:      createdTo,
+		Backward:       backward,
+		Status:         status,
+		Tag:            strings.ToLower(strings.TrimSpace(c.Query("tag"))),
+		CategoryID:     categoryID,
+	}
+
+	trace.SpanFromContext(c.UserContext()).SetAttributes(
+		attribute.String("article.cursor", cursor),
+		attribute.Int("article.num", num),
+	)
+
+	listAr, nextCursor, prevCursor, err := a.Service.Fetch(c.UserContext(), cursor, int64(num), filter)
+
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+	for i := range listAr {
+		listAr[i].ReadingTimeMinutes = a.readingTime(listAr[i].Content)
+	}
+	if err = a.setFavorites(c, listAr); err != nil {
+		return ReturnErr(c, err)
 	}
 
 	c.Set(`X-Cursor`, nextCursor)
+	if prevCursor != "" {
+		c.Set(`X-Prev-Cursor`, prevCursor)
+	}
+	setPageLinks(c, num, nextCursor, prevCursor)
+
+	return sendPage(c, listAr, nextCursor, prevCursor)
+}
+
+// parseSort will read the sort and order params, only the allowlisted sort fields are accepted.
+// An explicit sort field is ascending unless told otherwise, no param at all keeps the default ordering.
+func parseSort(field, order string) (sort domain.ArticleSort, err error) {
+	if field != "" {
+		sort.Field = domain.SortField(field)
+		if !sort.Field.Valid() {
+			return sort, fmt.Errorf("sort must be one of %s, %s, %s", domain.SortByUpdatedAt, domain.SortByCreatedAt, domain.SortByTitle)
+		}
+		sort.Order = domain.SortAsc
+	}
+	if order != "" {
+		sort.Order = domain.SortOrder(strings.ToLower(order))
+		if !sort.Order.Valid() {
+			return sort, fmt.Errorf("order must be one of %s, %s", domain.SortAsc, domain.SortDesc)
+		}
+	}
+	return sort, nil
+}
+
+// isEditor will report whether the request is authenticated, by a bearer token or an api key
+func isEditor(c *fiber.Ctx) bool {
+	if _, ok := middleware.UserID(c); ok {
+		return true
+	}
+	_, ok := middleware.APIKeyLabel(c)
+	return ok
+}
+
+// errDraftsForbidden is reported when someone else than an editor asks for the drafts
+var errDraftsForbidden = errors.New("only an authenticated editor can list the drafts")
+
+// errDeletedForbidden is reported when someone else than an editor asks for the deleted articles
+var errDeletedForbidden = errors.New("only an authenticated editor can list the deleted articles")
+
+// hidden will report whether the article is a draft kept from the public, only an editor gets the drafts
+func hidden(c *fiber.Ctx, ar domain.Article) bool {
+	return ar.Status != domain.StatusPublished && !isEditor(c)
+}
 
-	return c.JSON(listAr)
+// parseStatus will read the status param, the public feed only lists the published articles
+// while an editor may ask for the drafts or for every article with "all"
+func parseStatus(c *fiber.Ctx) (domain.Status, error) {
+	raw := c.Query("status")
+	status := domain.Status(raw)
+	switch {
+	case raw == "" || status == domain.StatusPublished:
+		return domain.StatusPublished, nil
+	case raw != "all" && !status.Valid():
+		return "", errors.New("status must be one of draft, published, all")
+	case !isEditor(c):
+		return "", errDraftsForbidden
+	case raw == "all":
+		return "", nil
+	default:
+		return status, nil
+	}
+}
+
+// sendStatusErr will answer the error of parseStatus, the drafts asked by someone else than an editor are forbidden
+func sendStatusErr(c *fiber.Ctx, err error) error {
+	code := http.StatusBadRequest
+	if errors.Is(err, errDraftsForbidden) {
+		code = http.StatusForbidden
+	}
+	return send(c.Status(code), ResponseError{Message: err.Error()})
+}
+
+// parseCreatedRange will read the RFC3339 created_from and created_to params,
+// either side may be left out but the range can't be inverted
+func parseCreatedRange(rawFrom, rawTo string) (from, to time.Time, err error) {
+	if rawFrom != "" {
+		from, err = time.Parse(time.RFC3339, rawFrom)
+		if err != nil {
+			return from, to, errors.New("created_from must be an RFC3339 timestamp")
+		}
+	}
+	if rawTo != "" {
+		to, err = time.Parse(time.RFC3339, rawTo)
+		if err != nil {
+			return from, to, errors.New("created_to must be an RFC3339 timestamp")
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		return from, to, errors.New("created_from must not be after created_to")
+	}
+	return from, to, nil
+}
+
+// offsetFetchArticle will fetch the article based on the page and per_page params
+func (a *ArticleHandler) offsetFetchArticle(c *fiber.Ctx) error {
+	page := 1
+	if raw := c.Query("page"); raw != "" {
+		var err error
+		page, err = strconv.Atoi(raw)
+		if err != nil || page < 1 || page > maxPage {
+			return send(c.Status(http.StatusBadRequest), ResponseError{Message: fmt.Sprintf("page must be an integer between 1 and %d", maxPage)})
+		}
+	}
+
+	perPage := a.pageSize(c.Query("per_page"))
+
+	status, err := parseStatus(c)
+	if err != nil {
+		return sendStatusErr(c, err)
+	}
+
+	listAr, total, err := a.Service.OffsetFetch(c.UserContext(), int64(page), int64(perPage), status)
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+	if err = a.setFavorites(c, listAr); err != nil {
+		return ReturnErr(c, err)
+	}
+
+	totalPages := (total + int64(perPage) - 1) / int64(perPage)
+	c.Set(`X-Total-Count`, strconv.FormatInt(total, 10))
+	c.Set(`X-Total-Pages`, strconv.FormatInt(totalPages, 10))
+
+	return sendProjected(c, listAr)
+}
+
+// getByIDs will get the articles of the comma separated ids param, the drafts are left out unless an editor asks
+func (a *ArticleHandler) getByIDs(c *fiber.Ctx) error {
+	tokens := strings.Split(c.Query("ids"), ",")
+
+	maxSize := a.maxPageSize()
+	if len(tokens) > maxSize {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: fmt.Sprintf("at most %d ids are allowed", maxSize)})
+	}
+
+	ids := make([]int64, 0, len(tokens))
+	for _, token := range tokens {
+		id, err := strconv.ParseInt(strings.TrimSpace(token), 10, 64)
+		if err != nil {
+			return send(c.Status(http.StatusBadRequest), ResponseError{Message: fmt.Sprintf("invalid id %q", token)})
+		}
+		ids = append(ids, id)
+	}
+
+	listAr, err := a.Service.GetByIDs(c.UserContext(), ids)
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+	if !isEditor(c) {
+		listAr = slices.DeleteFunc(listAr, func(ar domain.Article) bool { return ar.Status != domain.StatusPublished })
+	}
+	if err = a.setFavorites(c, listAr); err != nil {
+		return ReturnErr(c, err)
+	}
+
+	return sendProjected(c, listAr)
+}
+
+// pageSize will parse the requested page size, falling back to the configured
+// default for a missing or non-positive value and clamping it to the configured maximum
+func (a *ArticleHandler) pageSize(raw string) int {
+	maxSize := a.maxPageSize()
+	defaultSize := a.Config.DefaultPageSize
+	if defaultSize <= 0 {
+		defaultSize = defaultNum
+	}
+
+	size, err := strconv.Atoi(raw)
+	if err != nil || size <= 0 {
+		size = defaultSize
+	}
+	if size > maxSize {
+		size = maxSize
+	}
+	return size
+}
+
+func (a *ArticleHandler) maxPageSize() int {
+	if a.Config.MaxPageSize <= 0 {
+		return maxNum
+	}
+	return a.Config.MaxPageSize
 }
 
 type errRep struct {
-	Message string `json:"message,omitempty"`
+	XMLName xml.Name `json:"-" xml:"error"`
+	Code    string   `json:"code,omitempty" xml:"code,omitempty"`
+	Message string   `json:"message,omitempty" xml:"message,omitempty"`
 }
 
+// genericErrMessage is returned to the client for any error that is not a domain error
+const genericErrMessage = "internal server error"
+
+// invalidIDMessage answers the ids which can't be the one of an article, they are never looked up
+const invalidIDMessage = "id must be a positive integer"
+
+// maxArticleID is the largest id the article tables can hold, an upsert past it would exhaust their sequence
+const maxArticleID = math.MaxInt32
+
+// errVersionRequired answers the updates which don't tell the version of the article they were made from
+var errVersionRequired = ValidationError{
+	Message: validationErrMessage,
+	Errors:  []FieldError{{Field: "version", Tag: "required", Message: "version is required"}},
+}
+
+// errInternal answers the errors that are not a domain error
+var errInternal = &domain.AppError{
+	Code:    domain.ErrInternalServerError.Code,
+	Status:  domain.ErrInternalServerError.Status,
+	Message: genericErrMessage,
+}
+
+// ReturnErr will write the error response with the code and the status of the domain error,
+// the message is translated to the locale of the Accept-Language header when there is a translation of it
 func ReturnErr(c *fiber.Ctx, er error) error {
 	var rep error
 	if er != nil {
-		rep = c.JSON(errRep{er.Error()})
+		logrus.WithContext(c.UserContext()).Error(er)
+		appErr := appError(er)
+		locale := negotiateLocale(c)
+		c.Set(fiber.HeaderContentLanguage, locale.String())
+		rep = send(c.Status(appErr.Status), errRep{Code: appErr.Code, Message: localize(locale, appErr)})
 	}
 	return rep
 }
 
-// GetByID will get article by given id
+// appError returns the domain error that is safe to expose to the client,
+// hiding the details of any other error behind errInternal
+func appError(err error) *domain.AppError {
+	var appErr *domain.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return errInternal
+}
+
+// GetByID will get article by given id, answering 304 when the If-None-Match header still matches its ETag.
+// The content is rendered to HTML with ?format=html, it is the raw markdown otherwise. A draft is found by an editor only
 func (a *ArticleHandler) GetByID(c *fiber.Ctx) error {
 	idP, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+		return send(c.Status(http.StatusNotFound), ResponseError{Message: domain.ErrNotFound.Error()})
+	}
+	if idP <= 0 {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: invalidIDMessage})
 	}
 
 	id := int64(idP)
 
-	art, err := a.Service.GetByID(c.Context(), id)
-	if rep := ReturnErr(c, err); rep != nil {
-		return rep
+	format := c.Query("format", formatMarkdown)
+	if format != formatMarkdown && format != formatHTML {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: "format must be one of markdown, html"})
+	}
+
+	art, err := a.Service.GetByID(c.UserContext(), id)
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+	if hidden(c, art) {
+		return ReturnErr(c, domain.ErrNotFound)
+	}
+
+	art.ReadingTimeMinutes = a.readingTime(art.Content)
+	if err = a.setFavorite(c, &art); err != nil {
+		return ReturnErr(c, err)
+	}
+	etag := articleETag(art)
+	if format == formatHTML {
+		if art.Content, err = a.Service.RenderHTML(art.Content); err != nil {
+			return ReturnErr(c, err)
+		}
+		// the rendered content is an other representation of the article
+		etag = strings.TrimSuffix(etag, `"`) + `-html"`
+	}
+	c.Set(fiber.HeaderETag, etag)
+	if etagMatches(c.Get(fiber.HeaderIfNoneMatch), etag) {
+		return c.SendStatus(http.StatusNotModified)
+	}
+
+	return sendProjected(c, art)
+}
+
+// Revisions will list the revisions of the article of the given id, the newest first
+func (a *ArticleHandler) Revisions(c *fiber.Ctx) error {
+	idP, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return send(c.Status(http.StatusNotFound), ResponseError{Message: domain.ErrNotFound.Error()})
+	}
+
+	list, err := a.Service.ListRevisions(c.UserContext(), int64(idP))
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+
+	return send(c, list)
+}
+
+// RevisionDiff will compare the revisions of the from and to params of the article of the given id
+func (a *ArticleHandler) RevisionDiff(c *fiber.Ctx) error {
+	idP, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return send(c.Status(http.StatusNotFound), ResponseError{Message: domain.ErrNotFound.Error()})
 	}
 
-	return c.JSON(art)
+	from, errFrom := strconv.ParseInt(c.Query("from"), 10, 64)
+	to, errTo := strconv.ParseInt(c.Query("to"), 10, 64)
+	if errFrom != nil || errTo != nil || from <= 0 || to <= 0 {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: "from and to must be positive revision numbers"})
+	}
+
+	diff, err := a.Service.DiffRevisions(c.UserContext(), int64(idP), from, to)
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+
+	return send(c, diff)
 }
 
-func isRequestValid(m *domain.Article) (bool, error) {
-	validate := validator.New()
-	err := validate.Struct(m)
+// Related will list the articles sharing tags or the author with the article of the given id
+func (a *ArticleHandler) Related(c *fiber.Ctx) error {
+	idP, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return false, err
+		return send(c.Status(http.StatusNotFound), ResponseError{Message: domain.ErrNotFound.Error()})
 	}
-	return true, nil
+
+	num, err := strconv.Atoi(c.Query("num"))
+	if err != nil || num <= 0 {
+		num = defaultRelatedNum
+	}
+	if maxSize := a.maxPageSize(); num > maxSize {
+		num = maxSize
+	}
+
+	listAr, err := a.Service.GetRelated(c.UserContext(), int64(idP), int64(num))
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+	if err = a.setFavorites(c, listAr); err != nil {
+		return ReturnErr(c, err)
+	}
+
+	return sendProjected(c, listAr)
 }
 
-// Store will store the article by given request body
+// Count will count the published articles, or those of the status param for an editor
+func (a *ArticleHandler) Count(c *fiber.Ctx) error {
+	status, err := parseStatus(c)
+	if err != nil {
+		return sendStatusErr(c, err)
+	}
+
+	total, err := a.Service.Count(c.UserContext(), status)
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+
+	return send(c, CountResponse{Count: total})
+}
+
+// GetBySlug will get the article of the given slug, a draft is found by an editor only
+func (a *ArticleHandler) GetBySlug(c *fiber.Ctx) error {
+	art, err := a.Service.GetBySlug(c.UserContext(), c.Params("slug"))
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+	if hidden(c, art) {
+		return ReturnErr(c, domain.ErrNotFound)
+	}
+
+	art.ReadingTimeMinutes = a.readingTime(art.Content)
+	if err = a.setFavorite(c, &art); err != nil {
+		return ReturnErr(c, err)
+	}
+	return sendProjected(c, art)
+}
+
+// GetByTitle will get article by given title query param,
+// a q query param runs a search over the title and content instead
+func (a *ArticleHandler) GetByTitle(c *fiber.Ctx) error {
+	if c.Context().QueryArgs().Has("q") {
+		return a.search(c)
+	}
+
+	title := c.Query("title")
+	if title == "" {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: "title query param is required"})
+	}
+
+	art, err := a.Service.GetByTitle(c.UserContext(), title)
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+	if hidden(c, art) {
+		return ReturnErr(c, domain.ErrNotFound)
+	}
+	if err = a.setFavorite(c, &art); err != nil {
+		return ReturnErr(c, err)
+	}
+
+	return sendProjected(c, art)
+}
+
+// search will list the articles matching the q query param a page at a time
+func (a *ArticleHandler) search(c *fiber.Ctx) error {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: "q query param is required"})
+	}
+	if utf8.RuneCountInString(query) > maxSearchQueryLength {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: fmt.Sprintf("q must be at most %d characters", maxSearchQueryLength)})
+	}
+
+	num := a.pageSize(c.Query("num"))
+	if c.QueryBool("fuzzy") {
+		return a.fuzzySearch(c, query, num)
+	}
+
+	status, err := parseStatus(c)
+	if err != nil {
+		return sendStatusErr(c, err)
+	}
+
+	listAr, nextCursor, err := a.Service.Search(c.UserContext(), query, int64(num), c.Query("cursor"), status)
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+	if err = a.setFavorites(c, listAr); err != nil {
+		return ReturnErr(c, err)
+	}
+
+	c.Set(`X-Cursor`, nextCursor)
+	setPageLinks(c, num, nextCursor, "")
+
+	return sendPage(c, listAr, nextCursor, "")
+}
+
+// fuzzySearch will list the articles approximately matching the query, the most relevant first.
+// The ranking isn't paged, so the cursor is refused.
+func (a *ArticleHandler) fuzzySearch(c *fiber.Ctx, query string, num int) error {
+	if c.Query("cursor") != "" {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: "cursor can't be used with fuzzy"})
+	}
+
+	listAr, err := a.Service.FuzzySearch(c.UserContext(), query, int64(num))
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+	if err = a.setFavorites(c, listAr); err != nil {
+		return ReturnErr(c, err)
+	}
+
+	return sendPage(c, listAr, "", "")
+}
+
+// Store will store the article by given request body. With ?dry_run=true the article is only
+// checked, it is answered with a 200 as it would have been stored
 func (a *ArticleHandler) Store(c *fiber.Ctx) (err error) {
 	var article domain.Article
 
-	//err = c.Bind(&article)
-	//if err != nil {
-	//	return c.JSON(http.StatusUnprocessableEntity, err.Error())
-	//}
-	//
-	//var ok bool
-	//if ok, err = isRequestValid(&article); !ok {
-	//	return c.JSON(http.StatusBadRequest, err.Error())
-	//}
+	err = parseArticle(c, &article)
+	if err != nil {
+		return sendParseError(c, err)
+	}
+
+	var ok bool
+	if ok, err = isRequestValid(&article); !ok {
+		return send(c.Status(http.StatusUnprocessableEntity), NewValidationError(err))
+	}
+	if c.QueryBool("dry_run") {
+		return send(c, present(c, article))
+	}
+
+	key := c.Get(HeaderIdempotencyKey)
+	store := a.Config.IdempotencyStore
+	if key != "" && store != nil {
+		id, found, errK := store.Get(c.UserContext(), key)
+		if errK != nil {
+			return ReturnErr(c, errK)
+		}
+		if found {
+			article, err = a.Service.GetByID(c.UserContext(), id)
+			if err != nil {
+				return ReturnErr(c, err)
+			}
+			c.Set(HeaderIdempotentReplayed, "true")
+			c.Location(articlePath(c, article.ID))
+			return send(c.Status(http.StatusCreated), present(c, article))
+		}
+	}
+
+	err = a.Service.Store(c.UserContext(), &article)
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+
+	if article.ID == 0 {
+		logrus.WithContext(c.UserContext()).Error("stored article has no id")
+		return ReturnErr(c, domain.ErrInternalServerError)
+	}
+
+	if key != "" && store != nil {
+		if errK := store.Save(c.UserContext(), key, article.ID, a.idempotencyTTL()); errK != nil {
+			logrus.WithContext(c.UserContext()).Error(errK)
+		}
+	}
+
+	c.Location(articlePath(c, article.ID))
+	return send(c.Status(http.StatusCreated), present(c, article))
+}
+
+// StoreBulk will store the list of articles by given request body, reporting the result of each item
+func (a *ArticleHandler) StoreBulk(c *fiber.Ctx) (err error) {
+	var list []domain.Article
+	err = c.BodyParser(&list)
+	if err != nil {
+		return send(c.Status(http.StatusUnprocessableEntity), ResponseError{Message: err.Error()})
+	}
+	if len(list) == 0 {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: "request body must contain at least one article"})
+	}
+
+	results := make([]BulkResult, len(list))
+	valid := make([]*domain.Article, 0, len(list))
+	for i := range list {
+		results[i].Index = i
+		if ok, errV := isRequestValid(&list[i]); !ok {
+			results[i].Errors = NewValidationError(errV).Errors
+			continue
+		}
+		valid = append(valid, &list[i])
+	}
+
+	if len(valid) == 0 {
+		return send(c.Status(http.StatusUnprocessableEntity), results)
+	}
+
+	err = a.Service.StoreBatch(c.UserContext(), valid)
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+
+	for i := range list {
+		if results[i].Errors == nil {
+			results[i].ID = list[i].ID
+			results[i].Success = true
+		}
+	}
+
+	if len(valid) < len(list) {
+		return send(c.Status(http.StatusMultiStatus), results)
+	}
+	return send(c.Status(http.StatusCreated), results)
+}
+
+// Update will update the article by given param and request body, the body must carry
+// the version of the article the client has read unless an If-Match header matches its ETag,
+// a stale one is answered with a 412. With ?upsert=true an article missing is stored under
+// the id of the path instead, and answered with a 201. The article is answered as it is stored,
+// along its ETag. With ?dry_run=true the request is only checked, the database isn't read to
+// match the If-Match header.
+func (a *ArticleHandler) Update(c *fiber.Ctx) (err error) {
+	idP, err := strconv.Atoi(c.Params("id"))
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return send(c.Status(http.StatusNotFound), ResponseError{Message: domain.ErrNotFound.Error()})
+	}
+	// the id is checked before the upsert can create an article under it
+	if err != nil || idP <= 0 || idP > maxArticleID {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: fmt.Sprintf("id must be an integer between 1 and %d", maxArticleID)})
+	}
+
+	id := int64(idP)
+
+	var article domain.Article
+	err = parseArticle(c, &article)
+	if err != nil {
+		return sendParseError(c, err)
+	}
+
+	if article.ID != 0 && article.ID != id {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: "article id in body does not match the path"})
+	}
+	article.ID = id
+
+	var ok bool
+	if ok, err = isRequestValid(&article); !ok {
+		return send(c.Status(http.StatusUnprocessableEntity), NewValidationError(err))
+	}
+	// the dry run ends with the checks the request alone allows, the database is left untouched
+	if c.QueryBool("dry_run") {
+		if article.Version == 0 && c.Get(fiber.HeaderIfMatch) == "" && !c.QueryBool("upsert") {
+			return send(c.Status(http.StatusUnprocessableEntity), errVersionRequired)
+		}
+		return send(c, present(c, article))
+	}
+
+	// a missing article has no ETag to require, it is left to the update or the upsert
+	if c.Get(fiber.HeaderIfMatch) != "" || a.Config.RequireIfMatch {
+		current, err := a.Service.GetByID(c.UserContext(), id)
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			if c.Get(fiber.HeaderIfMatch) != "" {
+				return ReturnErr(c, errPreconditionFailed)
+			}
+		case err != nil:
+			return ReturnErr(c, err)
+		default:
+			if err = a.checkIfMatch(c, current); err != nil {
+				return ReturnErr(c, err)
+			}
+			// the matched ETag stands for the version the client has read
+			if article.Version == 0 {
+				article.Version = current.Version
+			}
+		}
+	}
+
+	// the article to create has no version yet, one which exists still needs it to be updated
+	if c.QueryBool("upsert") {
+		created, err := a.Service.Upsert(c.UserContext(), &article)
+		if err != nil {
+			return ReturnErr(c, err)
+		}
+		if created {
+			c.Location(articlePath(c, article.ID))
+			return a.sendStored(c.Status(http.StatusCreated), article.ID)
+		}
+		return a.sendStored(c, article.ID)
+	}
+
+	if article.Version == 0 {
+		return send(c.Status(http.StatusUnprocessableEntity), errVersionRequired)
+	}
+
+	err = a.Service.Update(c.UserContext(), &article)
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+	return a.sendStored(c, article.ID)
+}
+
+// sendStored will answer a write with the article as it is stored, it is read again so that the
+// fields the body left out, the version and the ETag are the ones a GET would answer
+func (a *ArticleHandler) sendStored(c *fiber.Ctx, id int64) error {
+	article, err := a.Service.GetByID(c.UserContext(), id)
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+	article.ReadingTimeMinutes = a.readingTime(article.Content)
+	if err = a.setFavorite(c, &article); err != nil {
+		return ReturnErr(c, err)
+	}
+	c.Set(fiber.HeaderETag, articleETag(article))
+	return send(c, present(c, article))
+}
+
+// Patch will partially update the article by given param, only the fields present in the request body are changed.
+// An If-Match header which doesn't match the ETag of the article is answered with a 412. With ?dry_run=true only
+// the fields present are checked, without reading the article, and answered with a 200.
+func (a *ArticleHandler) Patch(c *fiber.Ctx) (err error) {
+	idP, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return send(c.Status(http.StatusNotFound), ResponseError{Message: domain.ErrNotFound.Error()})
+	}
+
+	id := int64(idP)
+
+	if len(c.Body()) == 0 {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: "request body is empty"})
+	}
+
+	var patch articlePatch
+	err = c.BodyParser(&patch)
+	if err != nil {
+		return send(c.Status(http.StatusUnprocessableEntity), ResponseError{Message: err.Error()})
+	}
+	if patch.isEmpty() {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: "request body has no field to update"})
+	}
+
+	if c.QueryBool("dry_run") {
+		changes := domain.Article{ID: id}
+		patch.apply(&changes)
+		if err = validate.StructPartial(&changes, patch.fields()...); err != nil {
+			return send(c.Status(http.StatusUnprocessableEntity), NewValidationError(err))
+		}
+		return send(c, present(c, changes))
+	}
+
+	article, err := a.Service.GetByID(c.UserContext(), id)
+	if errors.Is(err, domain.ErrNotFound) && c.Get(fiber.HeaderIfMatch) != "" {
+		err = errPreconditionFailed
+	}
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+	if err = a.checkIfMatch(c, article); err != nil {
+		return ReturnErr(c, err)
+	}
+	patch.apply(&article)
+
+	var ok bool
+	if ok, err = isRequestValid(&article); !ok {
+		return send(c.Status(http.StatusUnprocessableEntity), NewValidationError(err))
+	}
 
-	err = a.Service.Store(c.Context(), &article)
-	if rep := ReturnErr(c, err); rep != nil {
-		return rep
+	err = a.Service.Update(c.UserContext(), &article)
+	if err != nil {
+		return ReturnErr(c, err)
 	}
-	return c.JSON(article)
+	return send(c, present(c, article))
 }
 
 // Delete will delete article by given param
 func (a *ArticleHandler) Delete(c *fiber.Ctx) error {
 	idP, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+		return send(c.Status(http.StatusNotFound), ResponseError{Message: domain.ErrNotFound.Error()})
+	}
+	if idP <= 0 {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: invalidIDMessage})
 	}
 
 	id := int64(idP)
 
-	err = a.Service.Delete(c.Context(), id)
-	if rep := ReturnErr(c, err); rep != nil {
-		return rep
+	err = a.Service.Delete(c.UserContext(), id)
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// Restore will restore the soft deleted article by given param
+func (a *ArticleHandler) Restore(c *fiber.Ctx) error {
+	idP, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return send(c.Status(http.StatusNotFound), ResponseError{Message: domain.ErrNotFound.Error()})
+	}
+
+	err = a.Service.Restore(c.UserContext(), int64(idP))
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// Publish will make the draft article of the given id public
+func (a *ArticleHandler) Publish(c *fiber.Ctx) error {
+	idP, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return send(c.Status(http.StatusNotFound), ResponseError{Message: domain.ErrNotFound.Error()})
+	}
+
+	err = a.Service.Publish(c.UserContext(), int64(idP))
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// Unpublish will take the published article of the given id back to draft
+func (a *ArticleHandler) Unpublish(c *fiber.Ctx) error {
+	idP, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return send(c.Status(http.StatusNotFound), ResponseError{Message: domain.ErrNotFound.Error()})
 	}
 
-	return nil
+	err = a.Service.Unpublish(c.UserContext(), int64(idP))
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// View will count one more view of the article of the given id
+func (a *ArticleHandler) View(c *fiber.Ctx) error {
+	idP, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return send(c.Status(http.StatusNotFound), ResponseError{Message: domain.ErrNotFound.Error()})
+	}
+
+	err = a.Service.IncrementViews(c.UserContext(), int64(idP))
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+
+	return c.SendStatus(http.StatusNoContent)
 }
 
 func getStatusCode(err error) int {
 	if err == nil {
 		return http.StatusOK
 	}
-
-	logrus.Error(err)
-	switch err {
-	case domain.ErrInternalServerError:
-		return http.StatusInternalServerError
-	case domain.ErrNotFound:
-		return http.StatusNotFound
-	case domain.ErrConflict:
-		return http.StatusConflict
-	default:
-		return http.StatusInternalServerError
-	}
+	return appError(err).Status
 }
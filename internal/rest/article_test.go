@@ -1,61 +1,2423 @@
 package rest_test
 
-/*func TestFetch(t *testing.T) {
-	var mockArticle domain.Article
-	err := faker.FakeData(&mockArticle)
-	assert.NoError(t, err)
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/repository/memory"
+	"apismrtbiz/internal/rest"
+	"apismrtbiz/internal/rest/middleware"
+	"apismrtbiz/internal/rest/mocks"
+)
+
+func sendJSON(t *testing.T, app *fiber.App, method, target, body string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	res, err := app.Test(req)
+	require.NoError(t, err)
+	return res
+}
+
+func TestReturnErr(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantMsg    string
+	}{
+		{"not-found", domain.ErrNotFound, http.StatusNotFound, "NOT_FOUND", domain.ErrNotFound.Error()},
+		{"conflict", domain.ErrConflict, http.StatusConflict, "CONFLICT", domain.ErrConflict.Error()},
+		{"bad-param", domain.ErrBadParamInput, http.StatusBadRequest, "BAD_PARAM_INPUT", domain.ErrBadParamInput.Error()},
+		{"internal", domain.ErrInternalServerError, http.StatusInternalServerError, "INTERNAL_ERROR", domain.ErrInternalServerError.Error()},
+		{"wrapped-not-found", fmt.Errorf("lookup: %w", domain.ErrNotFound), http.StatusNotFound, "NOT_FOUND", domain.ErrNotFound.Error()},
+		{"custom", &domain.AppError{Code: "ARTICLE_LOCKED", Status: http.StatusLocked, Message: "article is locked"}, http.StatusLocked, "ARTICLE_LOCKED", "article is locked"},
+		{"unknown", errors.New("unexpected"), http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				return rest.ReturnErr(c, tc.err)
+			})
+
+			res, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+			assert.Equal(t, tc.wantStatus, res.StatusCode)
+			assert.Equal(t, tc.wantCode, body["code"])
+			assert.Equal(t, tc.wantMsg, body["message"])
+		})
+	}
+}
+
+func TestReturnErrLocalized(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		err            error
+		wantMsg        string
+		wantLanguage   string
+	}{
+		{"indonesian", "id-ID,id;q=0.9,en;q=0.8", domain.ErrNotFound, "item yang anda minta tidak ditemukan", "id"},
+		{"indonesian-second-choice", "fr-FR, id;q=0.5", domain.ErrConflict, "item anda sudah ada", "id"},
+		{"unknown-locale", "fr-FR,fr;q=0.9", domain.ErrNotFound, domain.ErrNotFound.Error(), "en"},
+		{"malformed", "not a language;;", domain.ErrNotFound, domain.ErrNotFound.Error(), "en"},
+		{"untranslated-code", "id", &domain.AppError{Code: "ARTICLE_LOCKED", Status: http.StatusLocked, Message: "article is locked"}, "article is locked", "id"},
+		{"hidden-internal", "id", errors.New("unexpected"), "terjadi kesalahan pada server", "id"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				return rest.ReturnErr(c, tc.err)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(fiber.HeaderAcceptLanguage, tc.acceptLanguage)
+			res, err := app.Test(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+			assert.Equal(t, tc.wantMsg, body["message"])
+			assert.Equal(t, tc.wantLanguage, res.Header.Get(fiber.HeaderContentLanguage))
+		})
+	}
+}
+
+func TestReturnErrHidesInternalDetails(t *testing.T) {
+	var buf bytes.Buffer
+	logrus.SetOutput(&buf)
+	defer logrus.SetOutput(os.Stderr)
+
+	dbErr := fmt.Errorf("store article: %w", errors.New("Error 1146: Table 'ctfhr.article' doesn't exist"))
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return rest.ReturnErr(c, dbErr)
+	})
+
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	assert.JSONEq(t, `{"code":"INTERNAL_ERROR","message":"internal server error"}`, string(body))
+	assert.NotContains(t, string(body), "ctfhr.article")
+	assert.Contains(t, buf.String(), "level=error")
+	assert.Contains(t, buf.String(), "Table 'ctfhr.article' doesn't exist")
+}
+
+func TestReturnErrNil(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		assert.NoError(t, rest.ReturnErr(c, nil))
+		return c.SendString("ok")
+	})
+
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func TestStore(t *testing.T) {
+	mockArticle := domain.Article{
+		Title:     "Title",
+		Content:   "Content",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		j, err := json.Marshal(mockArticle)
+		require.NoError(t, err)
+
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Article).ID = 42
+		}).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPost, "/articles", string(j))
+
+		var got domain.Article
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, "/articles/42", res.Header.Get(fiber.HeaderLocation))
+		assert.Equal(t, int64(42), got.ID)
+		assert.Equal(t, mockArticle.Title, got.Title)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("missing-generated-id", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		j, err := json.Marshal(mockArticle)
+		require.NoError(t, err)
+
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPost, "/articles", string(j))
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		assert.Empty(t, res.Header.Get(fiber.HeaderLocation))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("malformed-body", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPost, "/articles", `{"title":`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid-article", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPost, "/articles", `{"title":"Title"}`)
+
+		var body rest.ValidationError
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+		require.Len(t, body.Errors, 1)
+		assert.Equal(t, "content", body.Errors[0].Field)
+		mockUCase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("two-invalid-fields", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPost, "/articles", `{}`)
+
+		var body rest.ValidationError
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+		assert.Equal(t, []rest.FieldError{
+			{Field: "title", Tag: "required", Message: "title is required"},
+			{Field: "content", Tag: "required", Message: "content is required"},
+		}, body.Errors)
+		mockUCase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("title-too-long", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPost, "/articles", `{"title":"`+strings.Repeat("a", 46)+`","content":"Content"}`)
+
+		var body rest.ValidationError
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+		assert.Equal(t, []rest.FieldError{
+			{Field: "title", Tag: "max", Message: "title must be at most 45 characters long"},
+		}, body.Errors)
+		mockUCase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("with-tags", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Store", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool {
+			return assert.ObjectsAreEqual([]string{"golang", "web"}, ar.Tags)
+		})).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Article).ID = 42
+		}).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPost, "/articles", `{"title":"Title","content":"Content","tags":["golang","web"]}`)
+
+		var got domain.Article
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, []string{"golang", "web"}, got.Tags)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("invalid-tag", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		for _, tags := range []string{`[""]`, `["go,web"]`} {
+			res := sendJSON(t, app, http.MethodPost, "/articles", `{"title":"Title","content":"Content","tags":`+tags+`}`)
+			assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode, tags)
+		}
+		mockUCase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
+func TestStoreIdempotency(t *testing.T) {
+	body := `{"title":"Title","content":"Content"}`
+	setID := func(args mock.Arguments) {
+		args.Get(1).(*domain.Article).ID = 42
+	}
+	sendWithKey := func(t *testing.T, app *fiber.App, key string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		if key != "" {
+			req.Header.Set(rest.HeaderIdempotencyKey, key)
+		}
+		res, err := app.Test(req)
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("first-and-duplicate", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Run(setID).Once()
+		mockUCase.On("GetByID", mock.Anything, int64(42)).
+			Return(domain.Article{ID: 42, Title: "Title", Content: "Content"}, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{IdempotencyStore: memory.NewIdempotencyStore()})
+
+		first := sendWithKey(t, app, "abc")
+		assert.Equal(t, http.StatusCreated, first.StatusCode)
+		assert.Empty(t, first.Header.Get(rest.HeaderIdempotentReplayed))
+
+		second := sendWithKey(t, app, "abc")
+		var got domain.Article
+		require.NoError(t, json.NewDecoder(second.Body).Decode(&got))
+		assert.Equal(t, http.StatusCreated, second.StatusCode)
+		assert.Equal(t, "true", second.Header.Get(rest.HeaderIdempotentReplayed))
+		assert.Equal(t, "/articles/42", second.Header.Get(fiber.HeaderLocation))
+		assert.Equal(t, int64(42), got.ID)
+
+		mockUCase.AssertExpectations(t)
+		mockUCase.AssertNumberOfCalls(t, "Store", 1)
+	})
+
+	t.Run("no-key", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Run(setID).Twice()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{IdempotencyStore: memory.NewIdempotencyStore()})
+
+		assert.Equal(t, http.StatusCreated, sendWithKey(t, app, "").StatusCode)
+		assert.Equal(t, http.StatusCreated, sendWithKey(t, app, "").StatusCode)
+
+		mockUCase.AssertExpectations(t)
+		mockUCase.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestStoreBulk(t *testing.T) {
+	setIDs := func(args mock.Arguments) {
+		for i, ar := range args.Get(1).([]*domain.Article) {
+			ar.ID = int64(i + 1)
+		}
+	}
+
+	t.Run("all-success", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("StoreBatch", mock.Anything, mock.MatchedBy(func(list []*domain.Article) bool {
+			return len(list) == 2
+		})).Return(nil).Run(setIDs).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPost, "/articles/bulk",
+			`[{"title":"One","content":"Content"},{"title":"Two","content":"Content"}]`)
+
+		var got []rest.BulkResult
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, []rest.BulkResult{
+			{Index: 0, ID: 1, Success: true},
+			{Index: 1, ID: 2, Success: true},
+		}, got)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("mixed", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("StoreBatch", mock.Anything, mock.MatchedBy(func(list []*domain.Article) bool {
+			return len(list) == 1 && list[0].Title == "Two"
+		})).Return(nil).Run(setIDs).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPost, "/articles/bulk",
+			`[{"title":"One"},{"title":"Two","content":"Content"}]`)
+
+		var got []rest.BulkResult
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusMultiStatus, res.StatusCode)
+		require.Len(t, got, 2)
+		assert.False(t, got[0].Success)
+		require.Len(t, got[0].Errors, 1)
+		assert.Equal(t, "content", got[0].Errors[0].Field)
+		assert.Equal(t, rest.BulkResult{Index: 1, ID: 1, Success: true}, got[1])
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("all-invalid", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPost, "/articles/bulk", `[{"title":"One"}]`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "StoreBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPost, "/articles/bulk", `[]`)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		stored := domain.Article{
+			ID: 12, Title: "Title", Content: "Content", Author: domain.Author{ID: 3, Name: "Iman"},
+			Status: domain.StatusPublished, CreatedAt: createdAt, UpdatedAt: createdAt.Add(time.Hour), Version: 2,
+		}
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool {
+			return ar.ID == 12
+		})).Return(nil).Once()
+		mockUCase.On("GetByID", mock.Anything, int64(12)).Return(stored, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPut, "/articles/12", `{"title":"Title","content":"Content","version":1}`)
+
+		var got domain.Article
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		// the stored article is answered, not the body of the request
+		assert.Equal(t, int64(12), got.ID)
+		assert.Equal(t, "Title", got.Title)
+		assert.Equal(t, int64(2), got.Version)
+		assert.True(t, createdAt.Equal(got.CreatedAt))
+		assert.Equal(t, domain.StatusPublished, got.Status)
+		assert.Equal(t, "Iman", got.Author.Name)
+		assert.Equal(t, `W/"12-2-0"`, res.Header.Get(fiber.HeaderETag))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("invalid-id", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPut, "/articles/abc", `{"title":"Title","content":"Content","version":1}`)
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("id-mismatch", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPut, "/articles/12", `{"id":13,"title":"Title","content":"Content","version":1}`)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid-article", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPut, "/articles/12", `{"title":"Title"}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing-version", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPut, "/articles/12", `{"title":"Title","content":"Content"}`)
+
+		var got rest.ValidationError
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+		require.Len(t, got.Errors, 1)
+		assert.Equal(t, "version", got.Errors[0].Field)
+		mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("stale-version", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool {
+			return ar.Version == 1
+		})).Return(domain.ErrConflict).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPut, "/articles/12", `{"title":"Title","content":"Content","version":1}`)
+
+		assert.Equal(t, http.StatusConflict, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(domain.ErrNotFound).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPut, "/articles/12", `{"title":"Title","content":"Content","version":1}`)
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("upsert-existing", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Upsert", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool {
+			return ar.ID == 12 && ar.Version == 1
+		})).Return(false, nil).Once()
+		mockUCase.On("GetByID", mock.Anything, int64(12)).Return(domain.Article{ID: 12, Title: "Title", Version: 2}, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPut, "/articles/12?upsert=true", `{"title":"Title","content":"Content","version":1}`)
+
+		var got domain.Article
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Empty(t, res.Header.Get(fiber.HeaderLocation))
+		assert.Equal(t, int64(12), got.ID)
+		mockUCase.AssertExpectations(t)
+		mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("upsert-missing", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Upsert", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool {
+			return ar.ID == 42
+		})).Return(true, nil).Once()
+		mockUCase.On("GetByID", mock.Anything, int64(42)).Return(domain.Article{ID: 42, Title: "Title", Version: 1}, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		// the article to create has no version yet
+		res := sendJSON(t, app, http.MethodPut, "/articles/42?upsert=true", `{"title":"Title","content":"Content"}`)
+
+		var got domain.Article
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, "/articles/42", res.Header.Get(fiber.HeaderLocation))
+		assert.Equal(t, int64(42), got.ID)
+		assert.Equal(t, int64(1), got.Version)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("upsert-conflict", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Upsert", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(false, domain.ErrConflict).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPut, "/articles/12?upsert=true", `{"title":"Title","content":"Content"}`)
+
+		assert.Equal(t, http.StatusConflict, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("upsert-invalid-id", func(t *testing.T) {
+		for _, id := range []string{"-1", "0", "2147483648", "99999999999999999999"} {
+			mockUCase := new(mocks.ArticleService)
+
+			app := fiber.New()
+			rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+			res := sendJSON(t, app, http.MethodPut, "/articles/"+id+"?upsert=true", `{"title":"Title","content":"Content"}`)
+
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode, id)
+			mockUCase.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+			mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		}
+	})
+}
+
+func TestCount(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Count", mock.Anything, domain.StatusPublished).Return(int64(42), nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/count", "")
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.JSONEq(t, `{"count":42}`, string(body))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Count", mock.Anything, domain.StatusPublished).Return(int64(0), domain.ErrInternalServerError).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/count", "")
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestRequestIDPropagation(t *testing.T) {
 	mockUCase := new(mocks.ArticleService)
-	mockListArticle := make([]domain.Article, 0)
-	mockListArticle = append(mockListArticle, mockArticle)
-	num := 1
-	cursor := "2"
-	mockUCase.On("Fetch", mock.Anything, cursor, int64(num)).Return(mockListArticle, "10", nil)
-
-	e := fiber.New()
-	req, err := http.NewRequestWithContext(context.TODO(),
-		echo.GET, "/article?num=1&cursor="+cursor, strings.NewReader(""))
-	assert.NoError(t, err)
+	mockUCase.On("Count", mock.MatchedBy(func(ctx context.Context) bool {
+		requestID, ok := middleware.RequestIDFromContext(ctx)
+		return ok && requestID == "req-1"
+	}), domain.StatusPublished).Return(int64(1), nil).Once()
+
+	app := fiber.New()
+	app.Use(middleware.RequestID())
+	rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/count", nil)
+	req.Header.Set(fiber.HeaderXRequestID, "req-1")
+	res, err := app.Test(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "req-1", res.Header.Get(fiber.HeaderXRequestID))
+	mockUCase.AssertExpectations(t)
+}
+
+func TestGetByTitle(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		title := "Hello, World & Co: 100% (really)?"
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByTitle", mock.Anything, title).Return(domain.Article{ID: 1, Status: domain.StatusPublished, Title: title}, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/search?title="+url.QueryEscape(title), "")
+
+		var got domain.Article
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, title, got.Title)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByTitle", mock.Anything, "missing").Return(domain.Article{}, domain.ErrNotFound).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/search?title=missing", "")
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("empty-title", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/search?title=", "")
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "GetByTitle", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetBySlug(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetBySlug", mock.Anything, "hello-world").Return(domain.Article{ID: 1, Status: domain.StatusPublished, Title: "Hello, World", Slug: "hello-world"}, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/slug/hello-world", "")
+
+		var got domain.Article
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, int64(1), got.ID)
+		assert.Equal(t, "hello-world", got.Slug)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetBySlug", mock.Anything, "missing").Return(domain.Article{}, domain.ErrNotFound).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/slug/missing", "")
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestDraftVisibility(t *testing.T) {
+	draft := domain.Article{ID: 2, Title: "Draft", Slug: "draft", Status: domain.StatusDraft}
+	published := domain.Article{ID: 1, Title: "Published", Slug: "published", Status: domain.StatusPublished}
+	editorKey := middleware.APIKey(middleware.APIKeyConfig{Keys: map[string]string{"secret": "editor"}, Optional: true})
+
+	newApp := func() *fiber.App {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(2)).Return(draft, nil)
+		mockUCase.On("GetBySlug", mock.Anything, "draft").Return(draft, nil)
+		mockUCase.On("GetByTitle", mock.Anything, "Draft").Return(draft, nil)
+		mockUCase.On("GetByIDs", mock.Anything, []int64{1, 2}).Return([]domain.Article{published, draft}, nil)
+
+		app := fiber.New()
+		app.Use(editorKey)
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+		return app
+	}
+	get := func(t *testing.T, app *fiber.App, target, apiKey string) *http.Response {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		res, err := app.Test(req)
+		require.NoError(t, err)
+		return res
+	}
+
+	for name, target := range map[string]string{
+		"id":    "/articles/2",
+		"slug":  "/articles/slug/draft",
+		"title": "/articles/search?title=Draft",
+	} {
+		t.Run(name, func(t *testing.T) {
+			app := newApp()
+
+			assert.Equal(t, http.StatusNotFound, get(t, app, target, "").StatusCode, "the drafts are hidden from the public")
+			assert.Equal(t, http.StatusOK, get(t, app, target, "secret").StatusCode)
+		})
+	}
+
+	t.Run("ids", func(t *testing.T) {
+		app := newApp()
+
+		var got []domain.Article
+		res := get(t, app, "/articles?ids=1,2", "")
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.Len(t, got, 1, "the drafts are left out of the batch")
+		assert.Equal(t, int64(1), got[0].ID)
+
+		res = get(t, app, "/articles?ids=1,2", "secret")
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Len(t, got, 2)
+	})
+}
+
+func TestSearch(t *testing.T) {
+	mockListArticle := []domain.Article{{ID: 1, Title: "Go generics", Content: "Content"}}
+
+	t.Run("matching", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Search", mock.Anything, "generics", int64(2), "", domain.StatusPublished).Return(mockListArticle, "10", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/search?q=generics&num=2", "")
+
+		var got []domain.Article
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "10", res.Header.Get("X-Cursor"))
+		assert.Len(t, got, 1)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("special-characters", func(t *testing.T) {
+		term := "100% off_now"
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Search", mock.Anything, term, int64(10), "", domain.StatusPublished).Return([]domain.Article{}, "", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/search?q="+url.QueryEscape(term), "")
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("drafts-forbidden", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/search?q=generics&status=draft", "")
+
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("fuzzy", func(t *testing.T) {
+		score := 0.8
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("FuzzySearch", mock.Anything, "Helo", int64(10)).
+			Return([]domain.Article{{ID: 2, Title: "Hello world", Content: "Content", SearchScore: &score}}, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/search?q=Helo&fuzzy=true", "")
+
+		var got []map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Empty(t, res.Header.Get("X-Cursor"))
+		require.Len(t, got, 1)
+		assert.Equal(t, "Hello world", got[0]["title"])
+		assert.Equal(t, 0.8, got[0]["search_score"])
+		mockUCase.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("fuzzy-cursor", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/search?q=Helo&fuzzy=true&cursor=abc", "")
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "FuzzySearch", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("fuzzy-rate-limit", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("FuzzySearch", mock.Anything, "Helo", int64(10)).Return([]domain.Article{}, nil).Once()
+		mockUCase.On("Search", mock.Anything, "Helo", int64(10), "", domain.StatusPublished).Return([]domain.Article{}, "", nil).Twice()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{FuzzyRateLimit: middleware.RateLimitConfig{Rate: 0.1, Burst: 1}})
+
+		assert.Equal(t, http.StatusOK, sendJSON(t, app, http.MethodGet, "/articles/search?q=Helo&fuzzy=true", "").StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, sendJSON(t, app, http.MethodGet, "/articles/search?q=Helo&fuzzy=true", "").StatusCode)
+		// the other searches aren't limited with the fuzzy ones
+		assert.Equal(t, http.StatusOK, sendJSON(t, app, http.MethodGet, "/articles/search?q=Helo", "").StatusCode)
+		assert.Equal(t, http.StatusOK, sendJSON(t, app, http.MethodGet, "/articles/search?q=Helo", "").StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		for _, query := range []string{"", "+++", strings.Repeat("a", 101)} {
+			res := sendJSON(t, app, http.MethodGet, "/articles/search?q="+query, "")
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+		}
+		mockUCase.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFetch(t *testing.T) {
+	mockListArticle := []domain.Article{{ID: 1, Title: "Title", Content: "Content"}}
+
+	t.Run("cursor", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "2", int64(1), domain.ArticleFilter{Status: domain.StatusPublished}).Return(mockListArticle, "10", "", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?num=1&cursor=2", "")
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "10", res.Header.Get("X-Cursor"))
+		assert.Empty(t, res.Header.Get("X-Total-Count"))
+		mockUCase.AssertExpectations(t)
+		mockUCase.AssertNotCalled(t, "OffsetFetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("include-deleted", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", int64(10), domain.ArticleFilter{IncludeDeleted: true, Status: domain.StatusPublished}).
+			Return(mockListArticle, "", "", nil).Once()
+
+		app := fiber.New()
+		app.Use(middleware.APIKey(middleware.APIKeyConfig{Keys: map[string]string{"secret": "editor"}, Optional: true}))
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		req := httptest.NewRequest(http.MethodGet, "/articles?include_deleted=true", nil)
+		req.Header.Set("X-API-Key", "secret")
+		res, err := app.Test(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("include-deleted-public", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?include_deleted=true", "")
+
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("sort", func(t *testing.T) {
+		tests := []struct {
+			query string
+			want  domain.ArticleSort
+		}{
+			{"sort=title", domain.ArticleSort{Field: domain.SortByTitle, Order: domain.SortAsc}},
+			{"sort=title&order=desc", domain.ArticleSort{Field: domain.SortByTitle, Order: domain.SortDesc}},
+			{"sort=created_at&order=asc", domain.ArticleSort{Field: domain.SortByCreatedAt, Order: domain.SortAsc}},
+			{"sort=created_at&order=DESC", domain.ArticleSort{Field: domain.SortByCreatedAt, Order: domain.SortDesc}},
+			{"sort=updated_at", domain.ArticleSort{Field: domain.SortByUpdatedAt, Order: domain.SortAsc}},
+			{"order=asc", domain.ArticleSort{Order: domain.SortAsc}},
+		}
+		for _, tc := range tests {
+			tc := tc
+			t.Run(tc.query, func(t *testing.T) {
+				mockUCase := new(mocks.ArticleService)
+				mockUCase.On("Fetch", mock.Anything, "", int64(10), domain.ArticleFilter{Sort: tc.want, Status: domain.StatusPublished}).
+					Return(mockListArticle, "", "", nil).Once()
+
+				app := fiber.New()
+				rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+				res := sendJSON(t, app, http.MethodGet, "/articles?"+tc.query, "")
+
+				assert.Equal(t, http.StatusOK, res.StatusCode)
+				mockUCase.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("sort-rejected", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		for _, query := range []string{"sort=content", "sort=title;DROP%20TABLE%20article", "sort=title&order=sideways"} {
+			res := sendJSON(t, app, http.MethodGet, "/articles?"+query, "")
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+		}
+		mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("author", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "2", int64(1), domain.ArticleFilter{AuthorID: 3, Status: domain.StatusPublished}).Return(mockListArticle, "10", "", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?author_id=3&num=1&cursor=2", "")
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "10", res.Header.Get("X-Cursor"))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("author-rejected", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		for _, query := range []string{"author_id=abc", "author_id=0", "author_id=-1"} {
+			res := sendJSON(t, app, http.MethodGet, "/articles?"+query, "")
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+		}
+		mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("category", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", int64(1), domain.ArticleFilter{CategoryID: 4, Status: domain.StatusPublished}).Return(mockListArticle, "10", "", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?category_id=4&num=1", "")
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("category-rejected", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		for _, query := range []string{"category_id=abc", "category_id=0", "category_id=-1"} {
+			res := sendJSON(t, app, http.MethodGet, "/articles?"+query, "")
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+		}
+		mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("created-range", func(t *testing.T) {
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "2", int64(1), domain.ArticleFilter{CreatedFrom: from, CreatedTo: to, Status: domain.StatusPublished}).Return(mockListArticle, "10", "", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?created_from=2024-01-01T00:00:00Z&created_to=2024-02-01T00:00:00Z&num=1&cursor=2", "")
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "10", res.Header.Get("X-Cursor"))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("created-range-rejected", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		for _, query := range []string{
+			"created_from=2024-02-01T00:00:00Z&created_to=2024-01-01T00:00:00Z",
+			"created_from=yesterday",
+			"created_to=2024-01-01",
+		} {
+			res := sendJSON(t, app, http.MethodGet, "/articles?"+query, "")
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+		}
+		mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("links", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", int64(1), domain.ArticleFilter{AuthorID: 3, Status: domain.StatusPublished}).Return(mockListArticle, "10", "", nil).Once()
+		mockUCase.On("Fetch", mock.Anything, "10", int64(1), domain.ArticleFilter{AuthorID: 3, Status: domain.StatusPublished}).Return(mockListArticle, "", "", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?author_id=3&num=1", "")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, `<http://example.com/articles?author_id=3&cursor=10&num=1>; rel="next"`, res.Header.Get(fiber.HeaderLink))
+
+		res = sendJSON(t, app, http.MethodGet, "/articles?author_id=3&num=1&cursor=10", "")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Empty(t, res.Header.Get(fiber.HeaderLink))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("backward", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "5", int64(1), domain.ArticleFilter{Backward: true, Status: domain.StatusPublished}).Return(mockListArticle, "4", "3", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?direction=backward&num=1&cursor=5", "")
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "4", res.Header.Get("X-Cursor"))
+		assert.Equal(t, "3", res.Header.Get("X-Prev-Cursor"))
+		assert.Equal(t, `<http://example.com/articles?cursor=4&num=1>; rel="next", `+
+			`<http://example.com/articles?cursor=3&direction=backward&num=1>; rel="prev"`, res.Header.Get(fiber.HeaderLink))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("direction-rejected", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		for _, query := range []string{"direction=sideways&cursor=5", "direction=backward"} {
+			res := sendJSON(t, app, http.MethodGet, "/articles?"+query, "")
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+		}
+		mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("envelope", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", int64(1), domain.ArticleFilter{Status: domain.StatusPublished}).Return(mockListArticle, "10", "", nil).Times(2)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?num=1&envelope=true", "")
+		var got struct {
+			Data []domain.Article `json:"data"`
+			Meta rest.ListMeta    `json:"meta"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Len(t, got.Data, 1)
+		assert.Equal(t, 1, got.Meta.Count)
+		assert.Equal(t, res.Header.Get("X-Cursor"), got.Meta.NextCursor)
+
+		res = sendJSON(t, app, http.MethodGet, "/articles?num=1", "")
+		var bare []domain.Article
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&bare))
+		assert.Len(t, bare, 1)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("status", func(t *testing.T) {
+		editorKey := middleware.APIKey(middleware.APIKeyConfig{Keys: map[string]string{"secret": "editor"}, Optional: true})
+		tests := []struct {
+			name       string
+			query      string
+			apiKey     string
+			want       domain.Status
+			wantStatus int
+		}{
+			{"public-default", "", "", domain.StatusPublished, http.StatusOK},
+			{"public-published", "status=published", "", domain.StatusPublished, http.StatusOK},
+			{"editor-draft", "status=draft", "secret", domain.StatusDraft, http.StatusOK},
+			{"editor-all", "status=all", "secret", "", http.StatusOK},
+			{"public-draft", "status=draft", "", "", http.StatusForbidden},
+			{"public-all", "status=all", "", "", http.StatusForbidden},
+			{"invalid", "status=archived", "secret", "", http.StatusBadRequest},
+		}
+		for _, tc := range tests {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				mockUCase := new(mocks.ArticleService)
+				if tc.wantStatus == http.StatusOK {
+					mockUCase.On("Fetch", mock.Anything, "", int64(10), domain.ArticleFilter{Status: tc.want}).
+						Return(mockListArticle, "", "", nil).Once()
+				}
+
+				app := fiber.New()
+				app.Use(editorKey)
+				rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+				req := httptest.NewRequest(http.MethodGet, "/articles?"+tc.query, nil)
+				if tc.apiKey != "" {
+					req.Header.Set("X-API-Key", tc.apiKey)
+				}
+				res, err := app.Test(req)
+				require.NoError(t, err)
+
+				assert.Equal(t, tc.wantStatus, res.StatusCode)
+				mockUCase.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "2", int64(10), domain.ArticleFilter{Status: domain.StatusPublished, Tag: "golang"}).
+			Return(mockListArticle, "", "", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?tag=GoLang&cursor=2", "")
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("cursor-error", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "2", int64(1), domain.ArticleFilter{Status: domain.StatusPublished}).Return(nil, "", "", domain.ErrInternalServerError).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?num=1&cursor=2", "")
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		assert.Empty(t, res.Header.Get("X-Cursor"))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("num-bounds", func(t *testing.T) {
+		tests := []struct {
+			name string
+			num  string
+			want int64
+		}{
+			{"zero", "0", 10},
+			{"negative", "-5", 10},
+			{"above-cap", "1000000", 100},
+			{"normal", "25", 25},
+		}
+		for _, tc := range tests {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				mockUCase := new(mocks.ArticleService)
+				mockUCase.On("Fetch", mock.Anything, "", tc.want, domain.ArticleFilter{Status: domain.StatusPublished}).Return(mockListArticle, "", "", nil).Once()
+
+				app := fiber.New()
+				rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+				res := sendJSON(t, app, http.MethodGet, "/articles?num="+tc.num, "")
+
+				assert.Equal(t, http.StatusOK, res.StatusCode)
+				mockUCase.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("custom-page-size", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", int64(25), domain.ArticleFilter{Status: domain.StatusPublished}).Return(mockListArticle, "", "", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{DefaultPageSize: 25, MaxPageSize: 50})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles", "")
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("ids", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByIDs", mock.Anything, []int64{3, 1, 3}).
+			Return([]domain.Article{{ID: 3, Status: domain.StatusPublished}, {ID: 1, Status: domain.StatusPublished}}, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?ids=3,1,3", "")
+
+		var got []domain.Article
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.Len(t, got, 2)
+		assert.Equal(t, int64(3), got[0].ID)
+		assert.Equal(t, int64(1), got[1].ID)
+		mockUCase.AssertExpectations(t)
+		mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ids-malformed", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?ids=1,abc", "")
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "GetByIDs", mock.Anything, mock.Anything)
+	})
+
+	t.Run("offset", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("OffsetFetch", mock.Anything, int64(2), int64(20), domain.StatusPublished).Return(mockListArticle, int64(45), nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?page=2&per_page=20", "")
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "45", res.Header.Get("X-Total-Count"))
+		assert.Equal(t, "3", res.Header.Get("X-Total-Pages"))
+		assert.Empty(t, res.Header.Get("X-Cursor"))
+		mockUCase.AssertExpectations(t)
+		mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("offset-default-per-page", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("OffsetFetch", mock.Anything, int64(3), int64(10), domain.StatusPublished).Return(mockListArticle, int64(0), nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?page=3", "")
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "0", res.Header.Get("X-Total-Pages"))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("offset-invalid-page", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		for _, page := range []string{"0", "-1", "abc", "10001", "9223372036854775807"} {
+			res := sendJSON(t, app, http.MethodGet, "/articles?page="+page, "")
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode, page)
+		}
+		mockUCase.AssertNotCalled(t, "OffsetFetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("offset-per-page-capped", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("OffsetFetch", mock.Anything, int64(1), int64(50), domain.StatusPublished).Return(mockListArticle, int64(1), nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{MaxPageSize: 50})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?per_page=500", "")
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("offset-status", func(t *testing.T) {
+		editorKey := middleware.APIKey(middleware.APIKeyConfig{Keys: map[string]string{"secret": "editor"}, Optional: true})
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("OffsetFetch", mock.Anything, int64(1), int64(10), domain.StatusPublished).Return(mockListArticle, int64(1), nil).Once()
+		mockUCase.On("OffsetFetch", mock.Anything, int64(1), int64(10), domain.StatusDraft).Return(mockListArticle, int64(1), nil).Once()
+
+		app := fiber.New()
+		app.Use(editorKey)
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?page=1", "")
+		assert.Equal(t, http.StatusOK, res.StatusCode, "the drafts are left out of the public pages")
+		res = sendJSON(t, app, http.MethodGet, "/articles?page=1&status=draft", "")
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+
+		req := httptest.NewRequest(http.MethodGet, "/articles?page=1&status=draft", nil)
+		req.Header.Set("X-API-Key", "secret")
+		res, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("cursor-and-page", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?page=1&cursor=2", "")
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockUCase.AssertNotCalled(t, "OffsetFetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestPatch(t *testing.T) {
+	existing := domain.Article{ID: 12, Title: "Old Title", Content: "Old Content"}
+
+	t.Run("title-only", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(12)).Return(existing, nil).Once()
+		mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool {
+			return ar.ID == 12 && ar.Title == "New Title" && ar.Content == "Old Content"
+		})).Return(nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPatch, "/articles/12", `{"title":"New Title"}`)
+
+		var got domain.Article
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "New Title", got.Title)
+		assert.Equal(t, "Old Content", got.Content)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("empty-body", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		for _, body := range []string{"", "{}"} {
+			res := sendJSON(t, app, http.MethodPatch, "/articles/12", body)
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		}
+		mockUCase.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+		mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("blank-title", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(12)).Return(existing, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPatch, "/articles/12", `{"title":""}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(12)).Return(domain.Article{}, domain.ErrNotFound).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPatch, "/articles/12", `{"title":"New Title"}`)
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"restored", nil, http.StatusNoContent},
+		{"already-active", domain.ErrConflict, http.StatusConflict},
+		{"not-exist", domain.ErrNotFound, http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("Restore", mock.Anything, int64(7)).Return(tc.err).Once()
+
+			app := fiber.New()
+			rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+			res := sendJSON(t, app, http.MethodPost, "/articles/7/restore", "")
+
+			assert.Equal(t, tc.wantStatus, res.StatusCode)
+			mockUCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPublish(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		method     string
+		err        error
+		wantStatus int
+	}{
+		{"published", "/articles/7/publish", "Publish", nil, http.StatusNoContent},
+		{"already-published", "/articles/7/publish", "Publish", domain.ErrConflict, http.StatusConflict},
+		{"not-exist", "/articles/7/publish", "Publish", domain.ErrNotFound, http.StatusNotFound},
+		{"unpublished", "/articles/7/unpublish", "Unpublish", nil, http.StatusNoContent},
+		{"already-draft", "/articles/7/unpublish", "Unpublish", domain.ErrConflict, http.StatusConflict},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On(tc.method, mock.Anything, int64(7)).Return(tc.err).Once()
+
+			app := fiber.New()
+			rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+			res := sendJSON(t, app, http.MethodPost, tc.path, "")
+
+			assert.Equal(t, tc.wantStatus, res.StatusCode)
+			mockUCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRelated(t *testing.T) {
+	t.Run("related", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetRelated", mock.Anything, int64(7), int64(3)).Return([]domain.Article{{ID: 8, Title: "Title"}}, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7/related?num=3", "")
+
+		var got []domain.Article
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.Len(t, got, 1)
+		assert.Equal(t, int64(8), got[0].ID)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("nothing-related", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetRelated", mock.Anything, int64(7), int64(5)).Return([]domain.Article{}, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7/related", "")
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.JSONEq(t, `[]`, string(body))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("not-exist", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetRelated", mock.Anything, int64(7), int64(5)).Return(nil, domain.ErrNotFound).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7/related", "")
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestRevisions(t *testing.T) {
+	t.Run("newest-first", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("ListRevisions", mock.Anything, int64(7)).
+			Return([]domain.Revision{{ArticleID: 7, Version: 2, Title: "Second"}, {ArticleID: 7, Version: 1, Title: "First"}}, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7/revisions", "")
+
+		var got []domain.Revision
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.Len(t, got, 2)
+		assert.Equal(t, int64(2), got[0].Version)
+		assert.Equal(t, int64(1), got[1].Version)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("not-exist", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("ListRevisions", mock.Anything, int64(7)).Return(nil, domain.ErrNotFound).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7/revisions", "")
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestRevisionDiff(t *testing.T) {
+	t.Run("changed-title", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("DiffRevisions", mock.Anything, int64(7), int64(3), int64(5)).Return(domain.RevisionDiff{
+			ArticleID: 7, From: 3, To: 5,
+			Changes: []domain.FieldChange{{Field: "title", Op: domain.ChangeChanged, From: "Old", To: "New"}},
+		}, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7/revisions/diff?from=3&to=5", "")
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.JSONEq(t, `{"article_id":7,"from":3,"to":5,"changes":[{"field":"title","op":"changed","from":"Old","to":"New"}]}`, string(body))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("revision-not-exist", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("DiffRevisions", mock.Anything, int64(7), int64(3), int64(9)).Return(domain.RevisionDiff{}, domain.ErrNotFound).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7/revisions/diff?from=3&to=9", "")
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("missing-param", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7/revisions/diff?from=3", "")
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "DiffRevisions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestView(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"counted", nil, http.StatusNoContent},
+		{"not-exist", domain.ErrNotFound, http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("IncrementViews", mock.Anything, int64(7)).Return(tc.err).Once()
+
+			app := fiber.New()
+			rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+			res := sendJSON(t, app, http.MethodPost, "/articles/7/view", "")
+
+			assert.Equal(t, tc.wantStatus, res.StatusCode)
+			mockUCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestContentNegotiation(t *testing.T) {
+	mockArticle := domain.Article{ID: 7, Status: domain.StatusPublished, Title: "Title", Content: "Content", Author: domain.Author{ID: 1, Name: "Iman"}}
+
+	get := func(t *testing.T, app *fiber.App, target, accept string) *http.Response {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if accept != "" {
+			req.Header.Set(fiber.HeaderAccept, accept)
+		}
+		res, err := app.Test(req)
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("json-default", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Times(3)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		for _, accept := range []string{"", "*/*", fiber.MIMEApplicationJSON} {
+			res := get(t, app, "/articles/7", accept)
+
+			var got domain.Article
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+			assert.Equal(t, "Title", got.Title)
+			assert.Equal(t, fiber.MIMEApplicationJSON, res.Header.Get(fiber.HeaderContentType), accept)
+		}
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("xml-article", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := get(t, app, "/articles/7", "application/xml")
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, fiber.MIMEApplicationXML, res.Header.Get(fiber.HeaderContentType))
+		assert.Contains(t, string(body), "<article><id>7</id><title>Title</title>")
+
+		var got domain.Article
+		require.NoError(t, xml.Unmarshal(body, &got))
+		assert.Equal(t, "Iman", got.Author.Name)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("xml-list", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", int64(10), domain.ArticleFilter{Status: domain.StatusPublished}).Return([]domain.Article{mockArticle, mockArticle}, "", "", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := get(t, app, "/articles", "application/xml;q=0.9, application/json;q=0.5")
+
+		var got struct {
+			Articles []domain.Article `xml:"article"`
+		}
+		require.NoError(t, xml.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, fiber.MIMEApplicationXML, res.Header.Get(fiber.HeaderContentType))
+		assert.Len(t, got.Articles, 2)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("xml-error", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{}, domain.ErrNotFound).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := get(t, app, "/articles/7", "text/xml")
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		assert.Equal(t, fiber.MIMEApplicationXML, res.Header.Get(fiber.HeaderContentType))
+		assert.Equal(t, "<error><code>NOT_FOUND</code><message>"+domain.ErrNotFound.Error()+"</message></error>", string(body))
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestExportCSV(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	firstPage := []domain.Article{
+		{ID: 1, Title: "First", Author: domain.Author{Name: "Iman"}, CreatedAt: createdAt},
+		{ID: 2, Title: "Second, with comma", Author: domain.Author{Name: "Iman"}, CreatedAt: createdAt},
+	}
+	lastPage := []domain.Article{{ID: 3, Title: "Third", Author: domain.Author{Name: "Budi"}, CreatedAt: createdAt}}
+
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", int64(100), domain.ArticleFilter{Status: domain.StatusPublished}).Return(firstPage, "next", "", nil).Once()
+		mockUCase.On("Fetch", mock.Anything, "next", int64(100), domain.ArticleFilter{Status: domain.StatusPublished}).Return(lastPage, "", "", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/export.csv", "")
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/csv", res.Header.Get(fiber.HeaderContentType))
+		assert.Equal(t, `attachment; filename="articles.csv"`, res.Header.Get(fiber.HeaderContentDisposition))
+		assert.Equal(t, "id,title,author,created_at\n"+
+			"1,First,Iman,2024-05-01T10:00:00Z\n"+
+			"2,\"Second, with comma\",Iman,2024-05-01T10:00:00Z\n"+
+			"3,Third,Budi,2024-05-01T10:00:00Z\n", string(body))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", int64(100), domain.ArticleFilter{Status: domain.StatusPublished}).Return(nil, "", "", domain.ErrInternalServerError).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/export.csv", "")
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestImportCSV(t *testing.T) {
+	upload := func(t *testing.T, app *fiber.App, content string) *http.Response {
+		t.Helper()
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", "articles.csv")
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, form.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/articles/import", &body)
+		req.Header.Set(fiber.HeaderContentType, form.FormDataContentType())
+		res, err := app.Test(req)
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("clean", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("StoreBatch", mock.Anything, mock.MatchedBy(func(list []*domain.Article) bool {
+			return len(list) == 2 && list[0].Title == "First" && list[1].Author.ID == 2
+		})).Return(nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := upload(t, app, "title,content,author_id\nFirst,Content 1,1\n\"Second, quoted\",Content 2,2\n")
+
+		var got rest.ImportSummary
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, 2, got.Imported)
+		assert.Empty(t, got.Failed)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("partially-invalid", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("StoreBatch", mock.Anything, mock.MatchedBy(func(list []*domain.Article) bool {
+			return len(list) == 1 && list[0].Title == "First"
+		})).Return(nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := upload(t, app, "title,content,author_id\nFirst,Content 1,1\n,Content 2,1\nThird,Content 3,abc\n")
+
+		var got rest.ImportSummary
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusMultiStatus, res.StatusCode)
+		assert.Equal(t, 1, got.Imported)
+		require.Len(t, got.Failed, 2)
+		assert.Equal(t, 3, got.Failed[0].Row)
+		assert.Equal(t, "title", got.Failed[0].Errors[0].Field)
+		assert.Equal(t, 4, got.Failed[1].Row)
+		assert.Equal(t, "author_id", got.Failed[1].Errors[0].Field)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		for _, content := range []string{
+			"title,content\nFirst,Content 1\n\"unterminated,Content 2\n",
+			"name,body\nFirst,Content 1\n",
+			"",
+		} {
+			res := upload(t, app, content)
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode, content)
+		}
+		mockUCase.AssertNotCalled(t, "StoreBatch", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetByIDTimeout(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("GetByID", mock.Anything, int64(7)).
+		Return(func(ctx context.Context, _ int64) (domain.Article, error) {
+			// a slow query, answered once the deadline of the request passed
+			<-ctx.Done()
+			return domain.Article{}, ctx.Err()
+		}).Once()
+
+	app := fiber.New()
+	app.Use(middleware.Timeout(20 * time.Millisecond))
+	rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/articles/7", nil), int(time.Second.Milliseconds()))
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	var body map[string]string
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	assert.Equal(t, "TIMEOUT", body["code"])
+	mockUCase.AssertExpectations(t)
+}
+
+func TestReadingTime(t *testing.T) {
+	long := strings.Repeat("word ", 450)
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{"empty", "", 0},
+		{"short", "Hello world", 1},
+		{"long", long, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7, Status: domain.StatusPublished, Title: "Title", Content: tt.content}, nil).Once()
+
+			app := fiber.New()
+			rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+			res := sendJSON(t, app, http.MethodGet, "/articles/7", "")
+
+			var got domain.Article
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, tt.want, got.ReadingTimeMinutes)
+			mockUCase.AssertExpectations(t)
+		})
+	}
+
+	t.Run("fetch-with-words-per-minute", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", int64(10), mock.AnythingOfType("domain.ArticleFilter")).
+			Return([]domain.Article{{ID: 1, Content: long}, {ID: 2, Content: "Hello world"}}, "", "", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{WordsPerMinute: 100})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles", "")
+
+		var got []domain.Article
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		require.Len(t, got, 2)
+		assert.Equal(t, 5, got[0].ReadingTimeMinutes)
+		assert.Equal(t, 1, got[1].ReadingTimeMinutes)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestGetByIDFormat(t *testing.T) {
+	mockArticle := domain.Article{ID: 7, Status: domain.StatusPublished, Title: "Title", Content: "# Hello\n\n*world*"}
+
+	t.Run("raw-by-default", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7", "")
+
+		var got domain.Article
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, mockArticle.Content, got.Content)
+		mockUCase.AssertExpectations(t)
+		mockUCase.AssertNotCalled(t, "RenderHTML", mock.Anything)
+	})
+
+	t.Run("html", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+		mockUCase.On("RenderHTML", mockArticle.Content).Return("<h1>Hello</h1>\n<p><em>world</em></p>\n", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7?format=html", "")
+
+		var got domain.Article
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "<h1>Hello</h1>\n<p><em>world</em></p>\n", got.Content)
+		assert.True(t, strings.HasSuffix(res.Header.Get("ETag"), `-html"`))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("unknown-format", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7?format=pdf", "")
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+}
 
-	rec := httptest.NewRecorder()
+func TestGetByIDETag(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	mockArticle := domain.Article{ID: 7, Status: domain.StatusPublished, Title: "Title", Content: "Content", UpdatedAt: updatedAt, Version: 2}
 
-	c := e.NewContext(req, rec)
-	handler := rest.ArticleHandler{
-		Service: mockUCase,
+	t.Run("first-request", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7", "")
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, `W/"7-2-0"`, res.Header.Get("ETag"))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("conditional-request", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Twice()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		etag := sendJSON(t, app, http.MethodGet, "/articles/7", "").Header.Get("ETag")
+		require.NotEmpty(t, etag)
+
+		req := httptest.NewRequest(http.MethodGet, "/articles/7", nil)
+		req.Header.Set("If-None-Match", etag)
+		res, err := app.Test(req)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotModified, res.StatusCode)
+		assert.Empty(t, body)
+		assert.Equal(t, etag, res.Header.Get("ETag"))
+	})
+
+	t.Run("changed-after-update", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		// an update within the same second keeps the update time the database stores
+		updated := mockArticle
+		updated.Version = 3
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(updated, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		req := httptest.NewRequest(http.MethodGet, "/articles/7", nil)
+		req.Header.Set("If-None-Match", `W/"7-2-0"`)
+		res, err := app.Test(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.NotEqual(t, req.Header.Get("If-None-Match"), res.Header.Get("ETag"))
+	})
+
+	t.Run("changed-after-view", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		// the views leave the version as it is
+		viewed := mockArticle
+		viewed.ViewCount = 1
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(viewed, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		req := httptest.NewRequest(http.MethodGet, "/articles/7", nil)
+		req.Header.Set("If-None-Match", `W/"7-2-0"`)
+		res, err := app.Test(req)
+		require.NoError(t, err)
+
+		var got domain.Article
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, `W/"7-2-1"`, res.Header.Get("ETag"))
+		assert.Equal(t, int64(1), got.ViewCount)
+	})
+}
+
+func TestIfMatch(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	stored := domain.Article{ID: 7, Title: "Title", Content: "Content", UpdatedAt: updatedAt, Version: 3}
+	updated := domain.Article{ID: 7, Title: "New title", Content: "Content", UpdatedAt: updatedAt.Add(time.Hour), Version: 4}
+	etag := `W/"7-3"`
+	// the stale ETag was read within the same second as the last update
+	stale := `W/"7-2"`
+
+	update := func(t *testing.T, app *fiber.App, method, ifMatch, body string) *http.Response {
+		t.Helper()
+		req := httptest.NewRequest(method, "/articles/7", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		if ifMatch != "" {
+			req.Header.Set(fiber.HeaderIfMatch, ifMatch)
+		}
+		res, err := app.Test(req)
+		require.NoError(t, err)
+		return res
 	}
-	err = handler.FetchArticle(c)
-	require.NoError(t, err)
 
-	responseCursor := rec.Header().Get("X-Cursor")
-	assert.Equal(t, "10", responseCursor)
-	assert.Equal(t, http.StatusOK, rec.Code)
-	mockUCase.AssertExpectations(t)
+	t.Run("matching", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(stored, nil).Once()
+		mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool {
+			return ar.ID == 7 && ar.Version == 3
+		})).Return(nil).Once()
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(updated, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		// the ETag stands for the version, the body needs none
+		res := update(t, app, http.MethodPut, etag, `{"title":"New title","content":"Content"}`)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, `W/"7-4-0"`, res.Header.Get(fiber.HeaderETag), "the ETag is the one of the stored version")
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("matching-after-view", func(t *testing.T) {
+		viewed := stored
+		viewed.ViewCount = 5
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(viewed, nil).Once()
+		mockUCase.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(updated, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		// the views counted since the article was read don't make the ETag stale
+		res := update(t, app, http.MethodPut, `W/"7-3-0"`, `{"title":"New title","content":"Content"}`)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("matching-html-representation", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(stored, nil).Once()
+		mockUCase.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(updated, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := update(t, app, http.MethodPut, strings.TrimSuffix(etag, `"`)+`-html"`, `{"title":"New title","content":"Content"}`)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(stored, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := update(t, app, http.MethodPut, stale, `{"title":"New title","content":"Content","version":3}`)
+
+		var got map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusPreconditionFailed, res.StatusCode)
+		assert.Equal(t, "PRECONDITION_FAILED", got["code"])
+		mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("stale-patch", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(stored, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := update(t, app, http.MethodPatch, stale, `{"title":"New title"}`)
+
+		assert.Equal(t, http.StatusPreconditionFailed, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing-article", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{}, domain.ErrNotFound).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := update(t, app, http.MethodPut, "*", `{"title":"New title","content":"Content","version":3}`)
+
+		assert.Equal(t, http.StatusPreconditionFailed, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing-header", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Update", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Once()
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(updated, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := update(t, app, http.MethodPut, "", `{"title":"New title","content":"Content","version":3}`)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+		// the article is only read again once it is updated
+		mockUCase.AssertNumberOfCalls(t, "GetByID", 1)
+	})
+
+	t.Run("missing-header-strict", func(t *testing.T) {
+		for _, method := range []string{http.MethodPut, http.MethodPatch} {
+			t.Run(method, func(t *testing.T) {
+				mockUCase := new(mocks.ArticleService)
+				mockUCase.On("GetByID", mock.Anything, int64(7)).Return(stored, nil).Once()
+
+				app := fiber.New()
+				rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{RequireIfMatch: true})
+
+				res := update(t, app, method, "", `{"title":"New title","content":"Content","version":3}`)
+
+				assert.Equal(t, http.StatusPreconditionRequired, res.StatusCode)
+				mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("missing-header-strict-upsert", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{}, domain.ErrNotFound).Once()
+		mockUCase.On("Upsert", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(true, nil).Once()
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7, Title: "New title", Version: 1}, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{RequireIfMatch: true})
+
+		// the article created has no ETag to require yet
+		req := httptest.NewRequest(http.MethodPut, "/articles/7?upsert=true", strings.NewReader(`{"title":"New title","content":"Content"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		res, err := app.Test(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
 }
 
-func TestFetchError(t *testing.T) {
-	mockUCase := new(mocks.ArticleService)
-	num := 1
-	cursor := "2"
-	mockUCase.On("Fetch", mock.Anything, cursor, int64(num)).Return(nil, "", domain.ErrInternalServerError)
+func TestEmbedAuthor(t *testing.T) {
+	mockArticle := domain.Article{ID: 7, Status: domain.StatusPublished, Title: "Title", Content: "Content", Author: domain.Author{ID: 1, Name: "Iman", CreatedAt: "2024-05-01"}}
 
-	e := echo.New()
-	req, err := http.NewRequestWithContext(context.TODO(), echo.GET, "/article?num=1&cursor="+cursor, strings.NewReader(""))
-	assert.NoError(t, err)
+	t.Run("default-shape", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
 
-	rec := httptest.NewRecorder()
-	c := e.NewContext(req, rec)
-	handler := rest.ArticleHandler{
-		Service: mockUCase,
-	}
-	err = handler.FetchArticle(c)
-	require.NoError(t, err)
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
 
-	responseCursor := rec.Header().Get("X-Cursor")
-	assert.Equal(t, "", responseCursor)
-	assert.Equal(t, http.StatusInternalServerError, rec.Code)
-	mockUCase.AssertExpectations(t)
+		res := sendJSON(t, app, http.MethodGet, "/articles/7", "")
+
+		var got map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, map[string]interface{}{"id": float64(1), "name": "Iman", "created_at": "2024-05-01", "updated_at": ""}, got["author"])
+	})
+
+	t.Run("default-shape-xml", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		req := httptest.NewRequest(http.MethodGet, "/articles/7", nil)
+		req.Header.Set(fiber.HeaderAccept, fiber.MIMEApplicationXML)
+		res, err := app.Test(req)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Contains(t, string(body), "<author><id>1</id><name>Iman</name><created_at>2024-05-01</created_at><updated_at></updated_at></author>")
+	})
+
+	t.Run("v2-author-id", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{BasePath: "/v2", Version: rest.APIVersion2})
+
+		res := sendJSON(t, app, http.MethodGet, "/v2/articles/7", "")
+
+		var got map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, float64(1), got["author_id"])
+		assert.NotContains(t, got, "author")
+	})
+
+	t.Run("v2-embedded", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{BasePath: "/v2", Version: rest.APIVersion2})
+
+		res := sendJSON(t, app, http.MethodGet, "/v2/articles/7?embed=author", "")
+
+		var got map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, float64(1), got["author_id"])
+		assert.Equal(t, map[string]interface{}{"id": float64(1), "name": "Iman", "created_at": "2024-05-01", "updated_at": ""}, got["author"])
+	})
+
+	t.Run("v2-embedded-list", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", int64(10), mock.Anything).Return([]domain.Article{mockArticle, mockArticle}, "", "", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{BasePath: "/v2", Version: rest.APIVersion2})
+
+		res := sendJSON(t, app, http.MethodGet, "/v2/articles?embed=author&fields=author", "")
+
+		var got []map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.Len(t, got, 2)
+		for _, item := range got {
+			assert.Equal(t, "Iman", item["author"].(map[string]interface{})["name"])
+		}
+	})
+
+	t.Run("unknown-relation", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7?embed=comments", "")
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func TestFieldSelection(t *testing.T) {
+	mockArticle := domain.Article{ID: 7, Status: domain.StatusPublished, Title: "Title", Content: "Content", Author: domain.Author{ID: 1, Name: "Iman"}}
+
+	t.Run("list", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", int64(10), mock.Anything).Return([]domain.Article{mockArticle, mockArticle}, "", "", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?fields=title,unknown", "")
+
+		var got []map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.Len(t, got, 2)
+		for _, item := range got {
+			assert.Equal(t, map[string]interface{}{"id": float64(7), "title": "Title"}, item)
+		}
+	})
+
+	t.Run("single", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7?fields=content,author", "")
+
+		var got map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.ElementsMatch(t, []string{"id", "content", "author"}, keysOf(got))
+	})
+
+	t.Run("no-fields", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7", "")
+
+		var got map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Contains(t, got, "title")
+		assert.Contains(t, got, "updated_at")
+	})
+}
+
+func keysOf(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
 }
 
-func TestGetByID(t *testing.T) {
+/*func TestGetByID(t *testing.T) {
 	var mockArticle domain.Article
 	err := faker.FakeData(&mockArticle)
 	assert.NoError(t, err)
@@ -85,42 +2447,6 @@ func TestGetByID(t *testing.T) {
 	mockUCase.AssertExpectations(t)
 }
 
-func TestStore(t *testing.T) {
-	mockArticle := domain.Article{
-		Title:     "Title",
-		Content:   "Content",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	tempMockArticle := mockArticle
-	tempMockArticle.ID = 0
-	mockUCase := new(mocks.ArticleService)
-
-	j, err := json.Marshal(tempMockArticle)
-	assert.NoError(t, err)
-
-	mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil)
-
-	e := echo.New()
-	req, err := http.NewRequestWithContext(context.TODO(), echo.POST, "/article", strings.NewReader(string(j)))
-	assert.NoError(t, err)
-	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-
-	rec := httptest.NewRecorder()
-	c := e.NewContext(req, rec)
-	c.SetPath("/article")
-
-	handler := rest.ArticleHandler{
-		Service: mockUCase,
-	}
-	err = handler.Store(c)
-	require.NoError(t, err)
-
-	assert.Equal(t, http.StatusCreated, rec.Code)
-	mockUCase.AssertExpectations(t)
-}
-
 func TestDelete(t *testing.T) {
 	var mockArticle domain.Article
 	err := faker.FakeData(&mockArticle)
@@ -151,3 +2477,501 @@ func TestDelete(t *testing.T) {
 	mockUCase.AssertExpectations(t)
 }
 */
+
+func TestHATEOASLinks(t *testing.T) {
+	mockArticle := domain.Article{ID: 7, Status: domain.StatusPublished, Title: "Title", Content: "Content", Author: domain.Author{ID: 1}}
+	wantLinks := map[string]interface{}{
+		"self":   map[string]interface{}{"href": "/articles/7", "method": "GET"},
+		"update": map[string]interface{}{"href": "/articles/7", "method": "PUT"},
+		"delete": map[string]interface{}{"href": "/articles/7", "method": "DELETE"},
+	}
+
+	t.Run("absent-by-default", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7", "")
+
+		var got map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.NotContains(t, got, "_links")
+	})
+
+	t.Run("article", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7?hateoas=true", "")
+
+		var got map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "Title", got["title"])
+		assert.Equal(t, wantLinks, got["_links"])
+	})
+
+	t.Run("projected-list", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", int64(10), mock.Anything).Return([]domain.Article{mockArticle}, "", "", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles?hateoas=true&fields=title", "")
+
+		var got []map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.Len(t, got, 1)
+		assert.Equal(t, map[string]interface{}{"id": float64(7), "title": "Title", "_links": wantLinks}, got[0])
+	})
+
+	t.Run("created", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).
+			Run(func(args mock.Arguments) { args.Get(1).(*domain.Article).ID = 7 }).Return(nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodPost, "/articles?hateoas=true", `{"title": "Title", "content": "Content"}`)
+
+		var got map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, wantLinks, got["_links"])
+	})
+}
+
+func TestBasePath(t *testing.T) {
+	mockListArticle := []domain.Article{{ID: 7, Title: "Title", Content: "Content"}}
+
+	t.Run("prefixed", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", int64(10), domain.ArticleFilter{Status: domain.StatusPublished}).Return(mockListArticle, "", "", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{BasePath: "/api/v1/"})
+
+		res := sendJSON(t, app, http.MethodGet, "/api/v1/articles?hateoas=true", "")
+
+		var got []map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.Len(t, got, 1)
+		assert.Equal(t, map[string]interface{}{"href": "/api/v1/articles/7", "method": "GET"}, got[0]["_links"].(map[string]interface{})["self"])
+
+		res = sendJSON(t, app, http.MethodGet, "/articles", "")
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("location", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Article).ID = 42
+		}).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{BasePath: "/api/v1"})
+
+		res := sendJSON(t, app, http.MethodPost, "/api/v1/articles", `{"title": "Title", "content": "Content"}`)
+
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, "/api/v1/articles/42", res.Header.Get(fiber.HeaderLocation))
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestJSONAPI(t *testing.T) {
+	sendJSONAPI := func(t *testing.T, app *fiber.App, method, target, body string) *http.Response {
+		t.Helper()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderAccept, rest.MIMEApplicationJSONAPI)
+		if body != "" {
+			req.Header.Set(fiber.HeaderContentType, rest.MIMEApplicationJSONAPI)
+		}
+		res, err := app.Test(req)
+		require.NoError(t, err)
+		return res
+	}
+	type resource struct {
+		Type       string                 `json:"type"`
+		ID         string                 `json:"id"`
+		Attributes map[string]interface{} `json:"attributes"`
+	}
+
+	t.Run("create-and-fetch", func(t *testing.T) {
+		var stored domain.Article
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Article")).
+			Run(func(args mock.Arguments) {
+				ar := args.Get(1).(*domain.Article)
+				ar.ID = 7
+				stored = *ar
+			}).Return(nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSONAPI(t, app, http.MethodPost, "/articles",
+			`{"data": {"type": "articles", "attributes": {"title": "Hello", "content": "Content", "status": "published", "tags": ["go"]}}}`)
+
+		var created struct {
+			Data resource `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&created))
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, rest.MIMEApplicationJSONAPI, res.Header.Get(fiber.HeaderContentType))
+		assert.Equal(t, "articles", created.Data.Type)
+		assert.Equal(t, "7", created.Data.ID)
+		assert.Equal(t, "Hello", created.Data.Attributes["title"])
+		assert.NotContains(t, created.Data.Attributes, "id")
+		assert.Equal(t, []string{"go"}, stored.Tags)
+
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(stored, nil).Once()
+		res = sendJSONAPI(t, app, http.MethodGet, "/articles/7", "")
+
+		var fetched struct {
+			Data resource `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&fetched))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, created.Data.ID, fetched.Data.ID)
+		for _, attr := range []string{"title", "content", "tags"} {
+			assert.Equal(t, created.Data.Attributes[attr], fetched.Data.Attributes[attr], attr)
+		}
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", int64(10), mock.Anything).
+			Return([]domain.Article{{ID: 7, Title: "Hello"}, {ID: 8, Title: "World"}}, "", "", nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSONAPI(t, app, http.MethodGet, "/articles", "")
+
+		var got struct {
+			Data []resource `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		require.Len(t, got.Data, 2)
+		assert.Equal(t, "8", got.Data[1].ID)
+		assert.Equal(t, "World", got.Data[1].Attributes["title"])
+	})
+
+	t.Run("wrong-type", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSONAPI(t, app, http.MethodPost, "/articles", `{"data": {"type": "people", "attributes": {"title": "Hello", "content": "Content"}}}`)
+
+		assert.Equal(t, http.StatusConflict, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("plain-json-unchanged", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7, Status: domain.StatusPublished, Title: "Hello"}, nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7", "")
+
+		var got map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, float64(7), got["id"])
+		assert.NotContains(t, got, "data")
+	})
+}
+
+func TestAPIVersions(t *testing.T) {
+	publishAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	mockArticle := domain.Article{
+		ID: 7, Title: "Title", Content: "Content", Author: domain.Author{ID: 1, Name: "Iman"},
+		Status: domain.StatusPublished, PublishAt: &publishAt, ViewCount: 3, CategoryID: 2,
+	}
+	newApp := func(mockUCase *mocks.ArticleService) *fiber.App {
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{BasePath: "/v1", Version: rest.APIVersion1})
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{BasePath: "/v2", Version: rest.APIVersion2})
+		return app
+	}
+	get := func(t *testing.T, app *fiber.App, target string) map[string]interface{} {
+		t.Helper()
+		res := sendJSON(t, app, http.MethodGet, target, "")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var got map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		return got
+	}
+
+	t.Run("v1", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+
+		got := get(t, newApp(mockUCase), "/v1/articles/7")
+
+		assert.Equal(t, "Content", got["content"])
+		assert.Equal(t, float64(3), got["view_count"])
+		assert.Equal(t, "2024-05-01T10:00:00Z", got["publish_at"])
+		assert.NotContains(t, got, "body")
+		assert.NotContains(t, got, "stats")
+	})
+
+	t.Run("v2", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+
+		got := get(t, newApp(mockUCase), "/v2/articles/7?hateoas=true")
+
+		assert.Equal(t, "Content", got["body"])
+		assert.Equal(t, "2024-05-01T10:00:00Z", got["published_at"])
+		assert.Equal(t, float64(2), got["category_id"])
+		assert.Equal(t, []interface{}{}, got["tags"])
+		assert.Equal(t, map[string]interface{}{"views": float64(3), "reading_time_minutes": float64(1)}, got["stats"])
+		assert.Equal(t, float64(1), got["author_id"])
+		assert.NotContains(t, got, "author")
+		assert.Equal(t, "/v2/articles/7", got["_links"].(map[string]interface{})["self"].(map[string]interface{})["href"])
+		for _, v1Field := range []string{"content", "view_count", "publish_at"} {
+			assert.NotContains(t, got, v1Field)
+		}
+	})
+
+	t.Run("v2-list-fields", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", int64(10), domain.ArticleFilter{Status: domain.StatusPublished}).
+			Return([]domain.Article{mockArticle}, "", "", nil).Once()
+
+		res := sendJSON(t, newApp(mockUCase), http.MethodGet, "/v2/articles?fields=body", "")
+
+		var got []map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, []map[string]interface{}{{"id": float64(7), "body": "Content"}}, got)
+	})
+
+	t.Run("v2-representations", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockArticle, nil).Once()
+		app := newApp(mockUCase)
+
+		// the v2 shape has no XML nor JSON:API mapper
+		for _, accept := range []string{fiber.MIMEApplicationXML, rest.MIMEApplicationJSONAPI} {
+			req := httptest.NewRequest(http.MethodGet, "/v2/articles/7", nil)
+			req.Header.Set(fiber.HeaderAccept, accept)
+			res, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusNotAcceptable, res.StatusCode, accept)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/articles/7", nil)
+		req.Header.Set(fiber.HeaderAccept, fiber.MIMEApplicationXML)
+		res, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("unknown-version", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		res := sendJSON(t, newApp(mockUCase), http.MethodGet, "/v3/articles/7", "")
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestLenientRouting(t *testing.T) {
+	mockListArticle := []domain.Article{{ID: 7, Status: domain.StatusPublished, Title: "Title", Content: "Content"}}
+
+	// the app of main only turns StrictRouting and CaseSensitive on when it is asked to
+	for name, target := range map[string]string{
+		"trailing-slash":       "/api/articles/",
+		"mixed-case":           "/API/Articles",
+		"trailing-slash-query": "/api/articles/?num=10",
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+			mockUCase.On("Fetch", mock.Anything, "", int64(10), domain.ArticleFilter{Status: domain.StatusPublished}).Return(mockListArticle, "", "", nil).Once()
+
+			app := fiber.New(fiber.Config{StrictRouting: false, CaseSensitive: false})
+			rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{BasePath: "/api"})
+
+			res := sendJSON(t, app, http.MethodGet, target, "")
+
+			var got []domain.Article
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Len(t, got, 1)
+			mockUCase.AssertExpectations(t)
+		})
+	}
+
+	t.Run("mixed-case-id", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(mockListArticle[0], nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodGet, "/Articles/7/", "")
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestInvalidID(t *testing.T) {
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		t.Run(method, func(t *testing.T) {
+			mockUCase := new(mocks.ArticleService)
+
+			app := fiber.New()
+			rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+			res := sendJSON(t, app, method, "/articles/abc", "")
+
+			var body rest.ResponseError
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+			assert.Equal(t, http.StatusNotFound, res.StatusCode)
+			assert.Equal(t, rest.ResponseError{Message: domain.ErrNotFound.Error()}, body)
+			mockUCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Delete", mock.Anything, int64(7)).Return(nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodDelete, "/articles/7", "")
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		assert.Empty(t, body)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Delete", mock.Anything, int64(404)).Return(domain.ErrNotFound).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodDelete, "/articles/404", "")
+
+		var body rest.ResponseError
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		assert.Equal(t, domain.ErrNotFound.Error(), body.Message)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("bad-id", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		res := sendJSON(t, app, http.MethodDelete, "/articles/7abc", "")
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestNonPositiveID(t *testing.T) {
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		for name, id := range map[string]string{"zero": "0", "negative": "-7"} {
+			t.Run(method+"-"+name, func(t *testing.T) {
+				mockUCase := new(mocks.ArticleService)
+
+				app := fiber.New()
+				rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+				res := sendJSON(t, app, method, "/articles/"+id, "")
+
+				var body rest.ResponseError
+				require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+				assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+				assert.Equal(t, "id must be a positive integer", body.Message)
+				mockUCase.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+				mockUCase.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+			})
+		}
+	}
+
+	t.Run("positive", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1, Status: domain.StatusPublished, Title: "Title", Content: "Content"}, nil).Once()
+		mockUCase.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+
+		app := fiber.New()
+		rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+		assert.Equal(t, http.StatusOK, sendJSON(t, app, http.MethodGet, "/articles/1", "").StatusCode)
+		assert.Equal(t, http.StatusNoContent, sendJSON(t, app, http.MethodDelete, "/articles/1", "").StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestDryRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		wantTitle  string
+	}{
+		{"store", http.MethodPost, "/articles?dry_run=true", `{"title":"Title","content":"Content"}`, http.StatusOK, "Title"},
+		{"store-invalid", http.MethodPost, "/articles?dry_run=true", `{"title":"Title"}`, http.StatusUnprocessableEntity, ""},
+		{"update", http.MethodPut, "/articles/12?dry_run=true", `{"title":"Title","content":"Content","version":1}`, http.StatusOK, "Title"},
+		{"update-invalid", http.MethodPut, "/articles/12?dry_run=true", `{"title":"` + strings.Repeat("a", 46) + `","content":"Content","version":1}`, http.StatusUnprocessableEntity, ""},
+		{"update-missing-version", http.MethodPut, "/articles/12?dry_run=true", `{"title":"Title","content":"Content"}`, http.StatusUnprocessableEntity, ""},
+		{"update-id-mismatch", http.MethodPut, "/articles/12?dry_run=true", `{"id":13,"title":"Title","content":"Content","version":1}`, http.StatusBadRequest, ""},
+		{"upsert", http.MethodPut, "/articles/12?dry_run=true&upsert=true", `{"title":"Title","content":"Content"}`, http.StatusOK, "Title"},
+		{"patch", http.MethodPatch, "/articles/12?dry_run=true", `{"title":"Title"}`, http.StatusOK, "Title"},
+		{"patch-invalid", http.MethodPatch, "/articles/12?dry_run=true", `{"content":""}`, http.StatusUnprocessableEntity, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// the service has no expectation, any call to it fails the test
+			mockUCase := new(mocks.ArticleService)
+
+			app := fiber.New()
+			rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+			res := sendJSON(t, app, tc.method, tc.target, tc.body)
+
+			assert.Equal(t, tc.wantStatus, res.StatusCode)
+			if tc.wantStatus == http.StatusOK {
+				var got domain.Article
+				require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+				assert.Equal(t, tc.wantTitle, got.Title)
+			}
+			assert.Empty(t, mockUCase.Calls)
+		})
+	}
+}
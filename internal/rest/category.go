@@ -0,0 +1,117 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"apismrtbiz/domain"
+)
+
+// CategoryService represent the category's usecases
+//
+//go:generate mockery --name CategoryService
+type CategoryService interface {
+	Fetch(ctx context.Context) ([]domain.Category, error)
+	GetByID(ctx context.Context, id int64) (domain.Category, error)
+	Store(ctx context.Context, c *domain.Category) error
+	Update(ctx context.Context, c *domain.Category) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// CategoryHandler represent the httphandler for the categories of the articles
+type CategoryHandler struct {
+	Service CategoryService
+}
+
+// NewCategoryHandler will initialize the categories/ resources endpoint
+func NewCategoryHandler(e fiber.Router, svc CategoryService) {
+	handler := &CategoryHandler{
+		Service: svc,
+	}
+	e.Get("/categories", handler.FetchCategories)
+	e.Post("/categories", handler.Store)
+	e.Get("/categories/:id", handler.GetByID)
+	e.Put("/categories/:id", handler.Update)
+	e.Delete("/categories/:id", handler.Delete)
+}
+
+// FetchCategories will list every category by name
+func (h *CategoryHandler) FetchCategories(c *fiber.Ctx) error {
+	list, err := h.Service.Fetch(c.UserContext())
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+	return send(c, list)
+}
+
+// GetByID will get the category of the given id
+func (h *CategoryHandler) GetByID(c *fiber.Ctx) error {
+	idP, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return send(c.Status(http.StatusNotFound), ResponseError{Message: domain.ErrNotFound.Error()})
+	}
+
+	category, err := h.Service.GetByID(c.UserContext(), int64(idP))
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+	return send(c, category)
+}
+
+// Store will create the category of the request body
+func (h *CategoryHandler) Store(c *fiber.Ctx) error {
+	var category domain.Category
+	if err := c.BodyParser(&category); err != nil {
+		return send(c.Status(http.StatusUnprocessableEntity), ResponseError{Message: err.Error()})
+	}
+	if err := validate.Struct(category); err != nil {
+		return send(c.Status(http.StatusUnprocessableEntity), NewValidationError(err))
+	}
+
+	category.ID = 0
+	if err := h.Service.Store(c.UserContext(), &category); err != nil {
+		return ReturnErr(c, err)
+	}
+	return send(c.Status(http.StatusCreated), category)
+}
+
+// Update will rename the category of the given id after the request body
+func (h *CategoryHandler) Update(c *fiber.Ctx) error {
+	idP, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return send(c.Status(http.StatusNotFound), ResponseError{Message: domain.ErrNotFound.Error()})
+	}
+
+	var category domain.Category
+	if err = c.BodyParser(&category); err != nil {
+		return send(c.Status(http.StatusUnprocessableEntity), ResponseError{Message: err.Error()})
+	}
+	if category.ID != 0 && category.ID != int64(idP) {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: "category id in body does not match the path"})
+	}
+	if err = validate.Struct(category); err != nil {
+		return send(c.Status(http.StatusUnprocessableEntity), NewValidationError(err))
+	}
+
+	category.ID = int64(idP)
+	if err = h.Service.Update(c.UserContext(), &category); err != nil {
+		return ReturnErr(c, err)
+	}
+	return send(c, category)
+}
+
+// Delete will remove the category of the given id, it is refused with 409 while articles are filed under it
+func (h *CategoryHandler) Delete(c *fiber.Ctx) error {
+	idP, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return send(c.Status(http.StatusNotFound), ResponseError{Message: domain.ErrNotFound.Error()})
+	}
+
+	if err = h.Service.Delete(c.UserContext(), int64(idP)); err != nil {
+		return ReturnErr(c, err)
+	}
+	return c.SendStatus(http.StatusNoContent)
+}
@@ -0,0 +1,94 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/rest"
+	"apismrtbiz/internal/rest/mocks"
+)
+
+func TestStoreCategory(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.CategoryService)
+		mockUCase.On("Store", mock.Anything, mock.MatchedBy(func(c *domain.Category) bool { return c.Name == "Golang" })).
+			Run(func(args mock.Arguments) { args.Get(1).(*domain.Category).ID = 4 }).Return(nil).Once()
+
+		app := fiber.New()
+		rest.NewCategoryHandler(app, mockUCase)
+
+		res := sendJSON(t, app, http.MethodPost, "/categories", `{"name": "Golang"}`)
+
+		var got domain.Category
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, int64(4), got.ID)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		mockUCase := new(mocks.CategoryService)
+
+		app := fiber.New()
+		rest.NewCategoryHandler(app, mockUCase)
+
+		res := sendJSON(t, app, http.MethodPost, "/categories", `{"name": ""}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateCategory(t *testing.T) {
+	mockUCase := new(mocks.CategoryService)
+	mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(c *domain.Category) bool { return c.ID == 4 && c.Name == "Go" })).Return(nil).Once()
+	mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(c *domain.Category) bool { return c.ID == 404 })).Return(domain.ErrNotFound).Once()
+
+	app := fiber.New()
+	rest.NewCategoryHandler(app, mockUCase)
+
+	res := sendJSON(t, app, http.MethodPut, "/categories/4", `{"name": "Go"}`)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	res = sendJSON(t, app, http.MethodPut, "/categories/404", `{"name": "Go"}`)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestDeleteCategory(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.CategoryService)
+		mockUCase.On("Delete", mock.Anything, int64(4)).Return(nil).Once()
+
+		app := fiber.New()
+		rest.NewCategoryHandler(app, mockUCase)
+
+		res := sendJSON(t, app, http.MethodDelete, "/categories/4", "")
+
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("has-articles", func(t *testing.T) {
+		mockUCase := new(mocks.CategoryService)
+		mockUCase.On("Delete", mock.Anything, int64(4)).Return(domain.ErrInUse).Once()
+
+		app := fiber.New()
+		rest.NewCategoryHandler(app, mockUCase)
+
+		res := sendJSON(t, app, http.MethodDelete, "/categories/4", "")
+
+		var got map[string]string
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusConflict, res.StatusCode)
+		assert.Equal(t, "IN_USE", got["code"])
+		mockUCase.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"apismrtbiz/domain"
+)
+
+// CommentService represent the comment's usecases
+//
+//go:generate mockery --name CommentService
+type CommentService interface {
+	Fetch(ctx context.Context, articleID int64, cursor string, num int64) ([]domain.Comment, string, error)
+	Store(ctx context.Context, c *domain.Comment) error
+}
+
+// CommentHandler represent the httphandler for the comments of the articles
+type CommentHandler struct {
+	Service CommentService
+}
+
+// NewCommentHandler will initialize the articles/:id/comments resources endpoint
+func NewCommentHandler(e fiber.Router, svc CommentService) {
+	handler := &CommentHandler{
+		Service: svc,
+	}
+	e.Get("/articles/:id/comments", handler.FetchComments)
+	e.Post("/articles/:id/comments", handler.Store)
+}
+
+// FetchComments will list a page of the comments of the article of the given id, the oldest first
+func (h *CommentHandler) FetchComments(c *fiber.Ctx) error {
+	idP, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return send(c.Status(http.StatusNotFound), ResponseError{Message: domain.ErrNotFound.Error()})
+	}
+
+	num, err := strconv.Atoi(c.Query("num"))
+	if err != nil || num <= 0 {
+		num = defaultNum
+	}
+	if num > maxNum {
+		num = maxNum
+	}
+
+	list, nextCursor, err := h.Service.Fetch(c.UserContext(), int64(idP), c.Query("cursor"), int64(num))
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+
+	c.Set(`X-Cursor`, nextCursor)
+	setPageLinks(c, num, nextCursor, "")
+	return send(c, list)
+}
+
+// Store will post the comment of the request body on the article of the given id
+func (h *CommentHandler) Store(c *fiber.Ctx) error {
+	idP, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return send(c.Status(http.StatusNotFound), ResponseError{Message: domain.ErrNotFound.Error()})
+	}
+
+	var comment domain.Comment
+	if err = c.BodyParser(&comment); err != nil {
+		return send(c.Status(http.StatusUnprocessableEntity), ResponseError{Message: err.Error()})
+	}
+	if err = validate.Struct(comment); err != nil {
+		return send(c.Status(http.StatusUnprocessableEntity), NewValidationError(err))
+	}
+
+	comment.ArticleID = int64(idP)
+	if err = h.Service.Store(c.UserContext(), &comment); err != nil {
+		return ReturnErr(c, err)
+	}
+	return send(c.Status(http.StatusCreated), comment)
+}
@@ -0,0 +1,101 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/rest"
+	"apismrtbiz/internal/rest/mocks"
+)
+
+func TestFetchComments(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		list := []domain.Comment{
+			{ID: 1, ArticleID: 7, Author: "Iman", Content: "First", CreatedAt: time.Now()},
+			{ID: 2, ArticleID: 7, Author: "Bxcodec", Content: "Second", CreatedAt: time.Now()},
+		}
+		mockUCase := new(mocks.CommentService)
+		mockUCase.On("Fetch", mock.Anything, int64(7), "", int64(2)).Return(list, "next", nil).Once()
+
+		app := fiber.New()
+		rest.NewCommentHandler(app, mockUCase)
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/7/comments?num=2", "")
+
+		var got []domain.Comment
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "next", res.Header.Get("X-Cursor"))
+		assert.Contains(t, res.Header.Get(fiber.HeaderLink), `rel="next"`)
+		require.Len(t, got, 2)
+		assert.Equal(t, "First", got[0].Content)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("article-not-found", func(t *testing.T) {
+		mockUCase := new(mocks.CommentService)
+		mockUCase.On("Fetch", mock.Anything, int64(404), "", int64(10)).Return(nil, "", domain.ErrNotFound).Once()
+
+		app := fiber.New()
+		rest.NewCommentHandler(app, mockUCase)
+
+		res := sendJSON(t, app, http.MethodGet, "/articles/404/comments", "")
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+}
+
+func TestStoreComment(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUCase := new(mocks.CommentService)
+		mockUCase.On("Store", mock.Anything, mock.MatchedBy(func(c *domain.Comment) bool {
+			return c.ArticleID == 7 && c.Author == "Iman" && c.Content == "Nice"
+		})).Run(func(args mock.Arguments) { args.Get(1).(*domain.Comment).ID = 12 }).Return(nil).Once()
+
+		app := fiber.New()
+		rest.NewCommentHandler(app, mockUCase)
+
+		res := sendJSON(t, app, http.MethodPost, "/articles/7/comments", `{"author": "Iman", "content": "Nice"}`)
+
+		var got domain.Comment
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, int64(12), got.ID)
+		assert.Equal(t, int64(7), got.ArticleID)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("article-not-found", func(t *testing.T) {
+		mockUCase := new(mocks.CommentService)
+		mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*domain.Comment")).Return(domain.ErrNotFound).Once()
+
+		app := fiber.New()
+		rest.NewCommentHandler(app, mockUCase)
+
+		res := sendJSON(t, app, http.MethodPost, "/articles/404/comments", `{"author": "Iman", "content": "Nice"}`)
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("invalid-body", func(t *testing.T) {
+		mockUCase := new(mocks.CommentService)
+
+		app := fiber.New()
+		rest.NewCommentHandler(app, mockUCase)
+
+		res := sendJSON(t, app, http.MethodPost, "/articles/7/comments", `{"author": "Iman"}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
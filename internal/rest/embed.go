@@ -0,0 +1,27 @@
+package rest
+
+import (
+	"strings"
+
+	"apismrtbiz/domain"
+)
+
+// embedAuthor is the value of the embed query param including the author details in the v2 articles,
+// they hold the author_id only otherwise. The v1 articles keep the details the clients already read.
+const embedAuthor = "author"
+
+// parseEmbed will read the comma separated list of the embed query param into the set of the
+// embedded relations, author is the only relation which can be embedded
+func parseEmbed(raw string) (map[string]bool, error) {
+	embedded := map[string]bool{}
+	for _, rel := range strings.Split(raw, ",") {
+		switch rel = strings.TrimSpace(rel); rel {
+		case "":
+		case embedAuthor:
+			embedded[rel] = true
+		default:
+			return nil, domain.ErrBadParamInput
+		}
+	}
+	return embedded, nil
+}
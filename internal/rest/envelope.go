@@ -0,0 +1,41 @@
+package rest
+
+import (
+	"encoding/xml"
+
+	"github.com/gofiber/fiber/v2"
+
+	"apismrtbiz/domain"
+)
+
+// ListEnvelope represent a page of articles wrapped along its pagination metadata
+type ListEnvelope struct {
+	XMLName xml.Name    `json:"-" xml:"page"`
+	Data    interface{} `json:"data" xml:"data>article"`
+	Meta    ListMeta    `json:"meta" xml:"meta"`
+}
+
+// ListMeta represent the pagination metadata of a ListEnvelope, the cursors match the X-Cursor
+// and X-Prev-Cursor headers
+type ListMeta struct {
+	NextCursor string `json:"next_cursor" xml:"next_cursor"`
+	PrevCursor string `json:"prev_cursor,omitempty" xml:"prev_cursor,omitempty"`
+	Count      int    `json:"count" xml:"count"`
+}
+
+// sendPage will write a page of a cursor listing, as a bare array unless the envelope query param asks for a ListEnvelope.
+// A JSON:API document has a data member of its own and is never wrapped.
+func sendPage(c *fiber.Ctx, list []domain.Article, nextCursor, prevCursor string) error {
+	if !c.QueryBool("envelope") || wantsJSONAPI(c) {
+		return sendProjected(c, list)
+	}
+
+	data, err := projected(c, list)
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+	return send(c, ListEnvelope{
+		Data: data,
+		Meta: ListMeta{NextCursor: nextCursor, PrevCursor: prevCursor, Count: len(list)},
+	})
+}
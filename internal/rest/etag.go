@@ -0,0 +1,87 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"apismrtbiz/domain"
+)
+
+// articleState identifies the article as it is stored by its version, which every write bumps.
+// The update time isn't used, the datetime column of MySQL keeps it to the second only.
+func articleState(ar domain.Article) string {
+	return fmt.Sprintf("%d-%d", ar.ID, ar.Version)
+}
+
+// articleETag will build the weak entity tag of the article, it changes on every write of the article,
+// on every view counted and on every favorite of the article read by a user. The views don't bump the
+// version, so that they leave the article an update is made against as it is.
+func articleETag(ar domain.Article) string {
+	if ar.Favorited != nil && ar.FavoritesCount != nil {
+		return fmt.Sprintf(`W/"%s-%d-%t-%d"`, articleState(ar), ar.ViewCount, *ar.Favorited, *ar.FavoritesCount)
+	}
+	return fmt.Sprintf(`W/"%s-%d"`, articleState(ar), ar.ViewCount)
+}
+
+// etagMatches will report whether the etag is listed in the If-None-Match header,
+// using the weak comparison as the header is only used for conditional reads
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// errPreconditionFailed answers the updates whose If-Match header doesn't match the article as it is stored
+var errPreconditionFailed = &domain.AppError{
+	Code:    "PRECONDITION_FAILED",
+	Status:  http.StatusPreconditionFailed,
+	Message: "the article has changed since it was read",
+}
+
+// errPreconditionRequired answers the updates without an If-Match header when it is required
+var errPreconditionRequired = &domain.AppError{
+	Code:    "PRECONDITION_REQUIRED",
+	Status:  http.StatusPreconditionRequired,
+	Message: "the If-Match header is required",
+}
+
+// stateMatches will report whether the If-Match header lists an ETag of the article as it is stored. Every
+// representation GetByID answers is accepted, neither the views, the favorites nor the format of the content
+// change the state an update is made against. The comparison is weak as the ETags of the articles are.
+func stateMatches(header string, ar domain.Article) bool {
+	state := articleState(ar)
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.Trim(strings.TrimPrefix(strings.TrimSpace(candidate), "W/"), `"`)
+		if candidate == "*" || candidate == state || strings.HasPrefix(candidate, state+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIfMatch will check the If-Match header of an update against the article as it is stored. A missing
+// header is let through unless HandlerConfig.RequireIfMatch is set.
+func (a *ArticleHandler) checkIfMatch(c *fiber.Ctx, current domain.Article) error {
+	header := c.Get(fiber.HeaderIfMatch)
+	if header == "" {
+		if a.Config.RequireIfMatch {
+			return errPreconditionRequired
+		}
+		return nil
+	}
+	if !stateMatches(header, current) {
+		return errPreconditionFailed
+	}
+	return nil
+}
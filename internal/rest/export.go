@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"apismrtbiz/domain"
+)
+
+// exportPageSize is the number of articles read at a time while exporting
+const exportPageSize = maxNum
+
+var exportHeader = []string{"id", "title", "author", "created_at"}
+
+// ExportCSV will stream every article as CSV, the articles are read a page at a time
+// so the memory stays bounded however large the table is. The drafts are only exported to an editor.
+func (a *ArticleHandler) ExportCSV(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	filter := domain.ArticleFilter{Status: domain.StatusPublished}
+	if isEditor(c) {
+		filter.Status = ""
+	}
+
+	// the first page is read upfront, so a failing service is still reported with its status
+	list, nextCursor, _, err := a.Service.Fetch(ctx, "", exportPageSize, filter)
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+
+	c.Attachment("articles.csv")
+	c.Set(fiber.HeaderContentType, "text/csv")
+	// the body is streamed once the handler returned, past the deadline of the request
+	ctx = context.WithoutCancel(ctx)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		out := csv.NewWriter(w)
+		defer out.Flush()
+
+		if err := out.Write(exportHeader); err != nil {
+			logrus.WithContext(ctx).Error(err)
+			return
+		}
+		for {
+			for _, ar := range list { //nolint
+				if err := out.Write(exportRow(ar)); err != nil {
+					logrus.WithContext(ctx).Error(err)
+					return
+				}
+			}
+			if nextCursor == "" {
+				return
+			}
+
+			list, nextCursor, _, err = a.Service.Fetch(ctx, nextCursor, exportPageSize, filter)
+			if err != nil {
+				// the status is already sent, the export ends truncated
+				logrus.WithContext(ctx).Error(err)
+				return
+			}
+		}
+	})
+	return nil
+}
+
+func exportRow(ar domain.Article) []string {
+	return []string{
+		strconv.FormatInt(ar.ID, 10),
+		ar.Title,
+		ar.Author.Name,
+		ar.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,96 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/rest/middleware"
+)
+
+// FavoriteService represent the favorite's usecases
+//
+//go:generate mockery --name FavoriteService
+type FavoriteService interface {
+	Favorite(ctx context.Context, userID string, articleID int64) error
+	Unfavorite(ctx context.Context, userID string, articleID int64) error
+	Status(ctx context.Context, userID string, articleIDs []int64) (map[int64]domain.FavoriteStatus, error)
+}
+
+// FavoriteHandler represent the httphandler for the favorites of the users
+type FavoriteHandler struct {
+	Service FavoriteService
+}
+
+// NewFavoriteHandler will initialize the articles/:id/favorite resources endpoint
+func NewFavoriteHandler(e fiber.Router, svc FavoriteService) {
+	handler := &FavoriteHandler{
+		Service: svc,
+	}
+	e.Post("/articles/:id/favorite", handler.Favorite)
+	e.Delete("/articles/:id/favorite", handler.Unfavorite)
+}
+
+// Favorite will bookmark the article of the given id for the authenticated user
+func (h *FavoriteHandler) Favorite(c *fiber.Ctx) error {
+	return h.apply(c, h.Service.Favorite)
+}
+
+// Unfavorite will remove the bookmark of the article of the given id for the authenticated user
+func (h *FavoriteHandler) Unfavorite(c *fiber.Ctx) error {
+	return h.apply(c, h.Service.Unfavorite)
+}
+
+func (h *FavoriteHandler) apply(c *fiber.Ctx, fn func(ctx context.Context, userID string, articleID int64) error) error {
+	userID, ok := middleware.UserID(c)
+	if !ok {
+		return send(c.Status(http.StatusUnauthorized), ResponseError{Message: "favorites need an authenticated user"})
+	}
+
+	idP, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return send(c.Status(http.StatusNotFound), ResponseError{Message: domain.ErrNotFound.Error()})
+	}
+
+	if err = fn(c.UserContext(), userID, int64(idP)); err != nil {
+		return ReturnErr(c, err)
+	}
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// setFavorites will fill in the favorite status of the articles for the authenticated user,
+// the articles are left as is for an anonymous request or when no favorite service is configured
+func (a *ArticleHandler) setFavorites(c *fiber.Ctx, list []domain.Article) error {
+	userID, ok := middleware.UserID(c)
+	if !ok || a.Config.Favorites == nil || len(list) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(list))
+	for i, ar := range list {
+		ids[i] = ar.ID
+	}
+	status, err := a.Config.Favorites.Status(c.UserContext(), userID, ids)
+	if err != nil {
+		return err
+	}
+	for i := range list {
+		st := status[list[i].ID]
+		list[i].Favorited = &st.Favorited
+		list[i].FavoritesCount = &st.Count
+	}
+	return nil
+}
+
+// setFavorite will fill in the favorite status of the article for the authenticated user
+func (a *ArticleHandler) setFavorite(c *fiber.Ctx, ar *domain.Article) error {
+	list := []domain.Article{*ar}
+	if err := a.setFavorites(c, list); err != nil {
+		return err
+	}
+	*ar = list[0]
+	return nil
+}
@@ -0,0 +1,133 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/rest"
+	"apismrtbiz/internal/rest/middleware"
+	"apismrtbiz/internal/rest/mocks"
+)
+
+var favoriteSecret = []byte("secret")
+
+// sendAs will send the request with a bearer token of the user, anonymously when the user is empty
+func sendAs(t *testing.T, app *fiber.App, method, target, userID string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(method, target, nil)
+	if userID != "" {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{Subject: userID}).SignedString(favoriteSecret)
+		require.NoError(t, err)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	}
+	res, err := app.Test(req)
+	require.NoError(t, err)
+	return res
+}
+
+func newFavoriteApp(svc rest.FavoriteService) *fiber.App {
+	app := fiber.New()
+	app.Use(middleware.JWT(favoriteSecret))
+	rest.NewFavoriteHandler(app, svc)
+	return app
+}
+
+func TestFavorite(t *testing.T) {
+	t.Run("favorite", func(t *testing.T) {
+		mockUCase := new(mocks.FavoriteService)
+		mockUCase.On("Favorite", mock.Anything, "42", int64(7)).Return(nil).Once()
+
+		res := sendAs(t, newFavoriteApp(mockUCase), http.MethodPost, "/articles/7/favorite", "42")
+
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("favorite-twice", func(t *testing.T) {
+		mockUCase := new(mocks.FavoriteService)
+		mockUCase.On("Favorite", mock.Anything, "42", int64(7)).Return(nil).Twice()
+		app := newFavoriteApp(mockUCase)
+
+		for i := 0; i < 2; i++ {
+			res := sendAs(t, app, http.MethodPost, "/articles/7/favorite", "42")
+			assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		}
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("unfavorite", func(t *testing.T) {
+		mockUCase := new(mocks.FavoriteService)
+		mockUCase.On("Unfavorite", mock.Anything, "42", int64(7)).Return(nil).Once()
+
+		res := sendAs(t, newFavoriteApp(mockUCase), http.MethodDelete, "/articles/7/favorite", "42")
+
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mockUCase := new(mocks.FavoriteService)
+		mockUCase.On("Favorite", mock.Anything, "42", int64(404)).Return(domain.ErrNotFound).Once()
+
+		res := sendAs(t, newFavoriteApp(mockUCase), http.MethodPost, "/articles/404/favorite", "42")
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		mockUCase := new(mocks.FavoriteService)
+		app := fiber.New()
+		rest.NewFavoriteHandler(app, mockUCase)
+
+		res := sendAs(t, app, http.MethodPost, "/articles/7/favorite", "")
+
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		mockUCase.AssertNotCalled(t, "Favorite", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFavoriteStatus(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockFavorites := new(mocks.FavoriteService)
+	app := fiber.New()
+	app.Use(middleware.JWT(favoriteSecret))
+	rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{Favorites: mockFavorites})
+
+	t.Run("authenticated", func(t *testing.T) {
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7, Status: domain.StatusPublished, Title: "Hello"}, nil).Once()
+		mockFavorites.On("Status", mock.Anything, "42", []int64{7}).
+			Return(map[int64]domain.FavoriteStatus{7: {Favorited: true, Count: 3}}, nil).Once()
+
+		res := sendAs(t, app, http.MethodGet, "/articles/7", "42")
+
+		var got map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, true, got["favorited"])
+		assert.Equal(t, float64(3), got["favorites_count"])
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		mockUCase.On("GetByID", mock.Anything, int64(7)).Return(domain.Article{ID: 7, Status: domain.StatusPublished, Title: "Hello"}, nil).Once()
+
+		res := sendAs(t, app, http.MethodGet, "/articles/7", "")
+
+		var got map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.NotContains(t, got, "favorited")
+		assert.NotContains(t, got, "favorites_count")
+	})
+	mockUCase.AssertExpectations(t)
+	mockFavorites.AssertExpectations(t)
+}
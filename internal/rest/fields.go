@@ -0,0 +1,85 @@
+package rest
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseFields will read the comma separated list of the fields query param, the id is always kept
+// so the projected articles can still be referenced. A nil set means every field is returned.
+func parseFields(raw string) map[string]struct{} {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+
+	fields := map[string]struct{}{"id": {}}
+	for _, field := range strings.Split(raw, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields[field] = struct{}{}
+		}
+	}
+	return fields
+}
+
+// projectFields will keep only the given json keys of an object or of every object of a list,
+// the unknown field names are ignored
+func projectFields(v interface{}, fields map[string]struct{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(string(raw)), "[") {
+		var list []map[string]json.RawMessage
+		if err = json.Unmarshal(raw, &list); err != nil {
+			return nil, err
+		}
+		for _, item := range list {
+			keepFields(item, fields)
+		}
+		return list, nil
+	}
+
+	var item map[string]json.RawMessage
+	if err = json.Unmarshal(raw, &item); err != nil {
+		return nil, err
+	}
+	keepFields(item, fields)
+	return item, nil
+}
+
+func keepFields(item map[string]json.RawMessage, fields map[string]struct{}) {
+	for key := range item {
+		if _, ok := fields[key]; !ok {
+			delete(item, key)
+		}
+	}
+}
+
+// projected will project the value to the fields query param when one is given, map the
+// articles to the shape of the API version and add their links with ?hateoas=true. The XML and
+// JSON:API representations are always complete and in the v1 shape, v2 refuses them.
+func projected(c *fiber.Ctx, v interface{}) (interface{}, error) {
+	if _, err := parseEmbed(c.Query("embed")); err != nil || wantsXML(c) || wantsJSONAPI(c) {
+		return v, err
+	}
+	v = present(c, v)
+
+	fields := parseFields(c.Query("fields"))
+	if fields == nil {
+		return v, nil
+	}
+	fields[linksField] = struct{}{}
+	return projectFields(v, fields)
+}
+
+// sendProjected will write the value as json, projected to the fields query param when one is given
+func sendProjected(c *fiber.Ctx, v interface{}) error {
+	body, err := projected(c, v)
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+	return send(c, body)
+}
@@ -0,0 +1,76 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"apismrtbiz/domain"
+)
+
+// linksField is the json key the links of an article are written under, it survives the fields projection
+const linksField = "_links"
+
+// Link represent a hypermedia link to an action on a resource
+type Link struct {
+	Href   string `json:"href"`
+	Method string `json:"method"`
+}
+
+// ArticleLinks represent the actions available on an article
+type ArticleLinks struct {
+	Self   Link `json:"self"`
+	Update Link `json:"update"`
+	Delete Link `json:"delete"`
+}
+
+// linkedArticle represent an article along its links
+type linkedArticle struct {
+	domain.Article
+	Links ArticleLinks `json:"_links"`
+}
+
+// localsBasePath is the key of the base path the routes of the articles are mounted under
+const localsBasePath = "basePath"
+
+// articlePath is the path of the article resource under the base path of the request,
+// the Location headers and the links point at it
+func articlePath(c *fiber.Ctx, id int64) string {
+	basePath, _ := c.Locals(localsBasePath).(string)
+	return fmt.Sprintf("%s/articles/%d", basePath, id)
+}
+
+func newArticleLinks(c *fiber.Ctx, id int64) ArticleLinks {
+	href := articlePath(c, id)
+	return ArticleLinks{
+		Self:   Link{Href: href, Method: http.MethodGet},
+		Update: Link{Href: href, Method: http.MethodPut},
+		Delete: Link{Href: href, Method: http.MethodDelete},
+	}
+}
+
+// wantsLinks will report whether ?hateoas=true asks for the links of the articles, they are left out by default
+// so the existing clients keep the same documents. The XML and JSON:API representations have no links.
+func wantsLinks(c *fiber.Ctx) bool {
+	return c.QueryBool("hateoas") && !wantsXML(c) && !wantsJSONAPI(c)
+}
+
+// withLinks will add the links to an article or to every article of a list when the request asks for them,
+// any other value is returned as is
+func withLinks(c *fiber.Ctx, v interface{}) interface{} {
+	if !wantsLinks(c) {
+		return v
+	}
+	switch t := v.(type) {
+	case domain.Article:
+		return linkedArticle{Article: t, Links: newArticleLinks(c, t.ID)}
+	case []domain.Article:
+		list := make([]linkedArticle, len(t))
+		for i, ar := range t {
+			list[i] = linkedArticle{Article: ar, Links: newArticleLinks(c, ar.ID)}
+		}
+		return list
+	}
+	return v
+}
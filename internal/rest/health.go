@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const healthCheckTimeout = 2 * time.Second
+
+// HealthChecker represent a dependency which must be reachable for the service to be ready
+//
+//go:generate mockery --name HealthChecker
+type HealthChecker interface {
+	Ping(ctx context.Context) error
+}
+
+// HealthResponse represent the result of a health check
+type HealthResponse struct {
+	Status    string `json:"status"`
+	Component string `json:"component,omitempty"`
+}
+
+// HealthHandler represent the httphandler for the health checks
+type HealthHandler struct {
+	Checks map[string]HealthChecker
+}
+
+// NewHealthHandler will initialize the health check endpoints, the checks are keyed by their component name
+func NewHealthHandler(e *fiber.App, checks map[string]HealthChecker) {
+	handler := &HealthHandler{
+		Checks: checks,
+	}
+	e.Get("/livez", handler.Live)
+	e.Get("/healthz", handler.Health)
+}
+
+// Live will report the process is up, it doesn't look at any dependency
+func (h *HealthHandler) Live(c *fiber.Ctx) error {
+	return c.JSON(HealthResponse{Status: "ok"})
+}
+
+// Health will report whether every dependency is reachable, naming the first one which is not
+func (h *HealthHandler) Health(c *fiber.Ctx) error {
+	names := make([]string, 0, len(h.Checks))
+	for name := range h.Checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), healthCheckTimeout)
+	defer cancel()
+
+	for _, name := range names {
+		if err := h.Checks[name].Ping(ctx); err != nil {
+			logrus.WithContext(ctx).WithField("component", name).Error(err)
+			return c.Status(http.StatusServiceUnavailable).JSON(HealthResponse{Status: "unavailable", Component: name})
+		}
+	}
+	return c.JSON(HealthResponse{Status: "ok"})
+}
@@ -0,0 +1,59 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/internal/rest"
+	"apismrtbiz/internal/rest/mocks"
+)
+
+func TestHealth(t *testing.T) {
+	tests := []struct {
+		name       string
+		pingErr    error
+		wantStatus int
+		want       rest.HealthResponse
+	}{
+		{"healthy", nil, http.StatusOK, rest.HealthResponse{Status: "ok"}},
+		{"failing-ping", errors.New("connection refused"), http.StatusServiceUnavailable, rest.HealthResponse{Status: "unavailable", Component: "database"}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := new(mocks.HealthChecker)
+			mockDB.On("Ping", mock.Anything).Return(tc.pingErr).Once()
+
+			app := fiber.New()
+			rest.NewHealthHandler(app, map[string]rest.HealthChecker{"database": mockDB})
+
+			res := sendJSON(t, app, http.MethodGet, "/healthz", "")
+
+			var got rest.HealthResponse
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+			assert.Equal(t, tc.wantStatus, res.StatusCode)
+			assert.Equal(t, tc.want, got)
+			mockDB.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLive(t *testing.T) {
+	mockDB := new(mocks.HealthChecker)
+
+	app := fiber.New()
+	rest.NewHealthHandler(app, map[string]rest.HealthChecker{"database": mockDB})
+
+	res := sendJSON(t, app, http.MethodGet, "/livez", "")
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	mockDB.AssertNotCalled(t, "Ping", mock.Anything)
+}
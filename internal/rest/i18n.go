@@ -0,0 +1,47 @@
+package rest
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/text/language"
+
+	"apismrtbiz/domain"
+)
+
+// locales are the languages the errors are answered in, English first as it is the language
+// of the messages of the domain errors and the fallback of any other locale
+var locales = []language.Tag{language.English, language.Indonesian}
+
+var localeMatcher = language.NewMatcher(locales)
+
+// translations are the messages of the domain errors by locale and error code, English needs none
+var translations = map[language.Tag]map[string]string{
+	language.Indonesian: {
+		domain.ErrInternalServerError.Code: "terjadi kesalahan pada server",
+		domain.ErrNotFound.Code:            "item yang anda minta tidak ditemukan",
+		domain.ErrConflict.Code:            "item anda sudah ada",
+		domain.ErrInUse.Code:               "item anda masih digunakan",
+		domain.ErrBadParamInput.Code:       "parameter yang diberikan tidak valid",
+	},
+}
+
+// negotiateLocale will pick the supported locale the Accept-Language header prefers, English when none matches
+func negotiateLocale(c *fiber.Ctx) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage))
+	if err != nil || len(tags) == 0 {
+		return language.English
+	}
+	_, index, confidence := localeMatcher.Match(tags...)
+	if confidence == language.No {
+		return language.English
+	}
+	return locales[index]
+}
+
+// localize will translate the message of the domain error to the locale, the codes without
+// a translation keep their English message
+func localize(locale language.Tag, appErr *domain.AppError) string {
+	if msg, ok := translations[locale][appErr.Code]; ok {
+		return msg
+	}
+	return appErr.Message
+}
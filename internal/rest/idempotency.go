@@ -0,0 +1,30 @@
+package rest
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// HeaderIdempotencyKey is the request header carrying the client generated idempotency key
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed is set on a response which is replayed from an earlier request
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	defaultIdempotencyTTL = 24 * time.Hour
+)
+
+// IdempotencyStore represent the storage of the idempotency keys used on article creation
+type IdempotencyStore interface {
+	// Get will return the id of the article created with the key, ok is false when the key is unknown or expired
+	Get(ctx context.Context, key string) (id int64, ok bool, err error)
+	// Save will remember the id of the article created with the key for the given ttl
+	Save(ctx context.Context, key string, id int64, ttl time.Duration) error
+}
+
+func (a *ArticleHandler) idempotencyTTL() time.Duration {
+	if a.Config.IdempotencyTTL <= 0 {
+		return defaultIdempotencyTTL
+	}
+	return a.Config.IdempotencyTTL
+}
@@ -0,0 +1,137 @@
+package rest
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"apismrtbiz/domain"
+)
+
+// importFormField is the multipart form field carrying the CSV file
+const importFormField = "file"
+
+// ImportSummary represent the outcome of a CSV import
+type ImportSummary struct {
+	XMLName  xml.Name        `json:"-" xml:"import"`
+	Imported int             `json:"imported" xml:"imported"`
+	Failed   []ImportFailure `json:"failed,omitempty" xml:"failed>row,omitempty"`
+}
+
+// ImportFailure represent a row of the CSV which failed the validation, rows are numbered
+// by their line in the file, the header being line 1
+type ImportFailure struct {
+	Row    int          `json:"row" xml:"row,attr"`
+	Errors []FieldError `json:"errors" xml:"error"`
+}
+
+// importColumns maps the known header names to their column index
+type importColumns map[string]int
+
+func (cols importColumns) value(record []string, name string) string {
+	if i, ok := cols[name]; ok && i < len(record) {
+		return strings.TrimSpace(record[i])
+	}
+	return ""
+}
+
+// ImportCSV will store the articles of the uploaded CSV file, its header must name the title and content
+// columns and may name an author_id one. A malformed file is rejected as a whole.
+func (a *ArticleHandler) ImportCSV(c *fiber.Ctx) (err error) {
+	fileHeader, err := c.FormFile(importFormField)
+	if err != nil {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: fmt.Sprintf("%s form field is required", importFormField)})
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+	defer file.Close()
+
+	list, failed, err := parseImport(csv.NewReader(file))
+	if err != nil {
+		return send(c.Status(http.StatusBadRequest), ResponseError{Message: err.Error()})
+	}
+	summary := ImportSummary{Failed: failed}
+	if len(list) == 0 {
+		return send(c.Status(http.StatusUnprocessableEntity), summary)
+	}
+
+	err = a.Service.StoreBatch(c.UserContext(), list)
+	if err != nil {
+		return ReturnErr(c, err)
+	}
+
+	summary.Imported = len(list)
+	if len(failed) > 0 {
+		return send(c.Status(http.StatusMultiStatus), summary)
+	}
+	return send(c.Status(http.StatusCreated), summary)
+}
+
+// parseImport will read every row of the CSV, the rows failing the validation are reported apart
+// while an unreadable file is an error
+func parseImport(r *csv.Reader) (list []*domain.Article, failed []ImportFailure, err error) {
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, errors.New("CSV file is empty")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("malformed CSV: %w", err)
+	}
+	cols := importColumns{}
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"title", "content"} {
+		if _, ok := cols[name]; !ok {
+			return nil, nil, fmt.Errorf("CSV header must have a %s column", name)
+		}
+	}
+
+	for {
+		record, errRead := r.Read()
+		if errors.Is(errRead, io.EOF) {
+			break
+		}
+		if errRead != nil {
+			return nil, nil, fmt.Errorf("malformed CSV: %w", errRead)
+		}
+		row, _ := r.FieldPos(0)
+
+		ar := &domain.Article{
+			Title:   cols.value(record, "title"),
+			Content: cols.value(record, "content"),
+		}
+		var fieldErrs []FieldError
+		if raw := cols.value(record, "author_id"); raw != "" {
+			authorID, errParse := strconv.ParseInt(raw, 10, 64)
+			ar.Author.ID = authorID
+			if errParse != nil {
+				fieldErrs = append(fieldErrs, FieldError{Field: "author_id", Tag: "numeric", Message: "author_id must be an integer"})
+			}
+		}
+		if ok, errV := isRequestValid(ar); !ok {
+			fieldErrs = append(fieldErrs, NewValidationError(errV).Errors...)
+		}
+		if len(fieldErrs) > 0 {
+			failed = append(failed, ImportFailure{Row: row, Errors: fieldErrs})
+			continue
+		}
+		list = append(list, ar)
+	}
+
+	if len(list) == 0 && len(failed) == 0 {
+		return nil, nil, errors.New("CSV file must contain at least one article")
+	}
+	return list, failed, nil
+}
@@ -0,0 +1,144 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"apismrtbiz/domain"
+)
+
+const (
+	// MIMEApplicationJSONAPI is the media type of the JSON:API documents (https://jsonapi.org)
+	MIMEApplicationJSONAPI = "application/vnd.api+json"
+
+	// jsonAPIArticleType is the type of the article resource objects
+	jsonAPIArticleType = "articles"
+)
+
+// errJSONAPIType is answered with 409 as the JSON:API specification asks when the resource type doesn't match the endpoint
+var errJSONAPIType = errors.New(`the type of the resource must be "` + jsonAPIArticleType + `"`)
+
+// jsonAPIDocument represent a JSON:API top level document, data is a resource object or a list of them
+type jsonAPIDocument struct {
+	Data interface{} `json:"data"`
+}
+
+// jsonAPIResource represent a JSON:API resource object, the attributes are every field of the article but its id
+type jsonAPIResource struct {
+	Type       string          `json:"type"`
+	ID         string          `json:"id,omitempty"`
+	Attributes json.RawMessage `json:"attributes"`
+}
+
+// wantsJSONAPI will report whether the Accept header asks for the JSON:API media type,
+// it is only served when named explicitly
+func wantsJSONAPI(c *fiber.Ctx) bool {
+	for _, accept := range strings.Split(c.Get(fiber.HeaderAccept), ",") {
+		mediaType, _, _ := strings.Cut(accept, ";")
+		if strings.EqualFold(strings.TrimSpace(mediaType), MIMEApplicationJSONAPI) {
+			return true
+		}
+	}
+	return false
+}
+
+// isJSONAPIBody will report whether the request body is a JSON:API document
+func isJSONAPIBody(c *fiber.Ctx) bool {
+	mediaType, _, _ := strings.Cut(c.Get(fiber.HeaderContentType), ";")
+	return strings.EqualFold(strings.TrimSpace(mediaType), MIMEApplicationJSONAPI)
+}
+
+// newJSONAPIResource will turn the article into a resource object
+func newJSONAPIResource(ar domain.Article) (jsonAPIResource, error) {
+	raw, err := json.Marshal(ar)
+	if err != nil {
+		return jsonAPIResource{}, err
+	}
+	var attributes map[string]json.RawMessage
+	if err = json.Unmarshal(raw, &attributes); err != nil {
+		return jsonAPIResource{}, err
+	}
+	delete(attributes, "id")
+	if raw, err = json.Marshal(attributes); err != nil {
+		return jsonAPIResource{}, err
+	}
+	return jsonAPIResource{Type: jsonAPIArticleType, ID: strconv.FormatInt(ar.ID, 10), Attributes: raw}, nil
+}
+
+// sendJSONAPI will write the article or the list of articles as a JSON:API document,
+// reporting false for any other value so that it is sent as plain JSON
+func sendJSONAPI(c *fiber.Ctx, v interface{}) (bool, error) {
+	var doc jsonAPIDocument
+	switch t := v.(type) {
+	case domain.Article:
+		res, err := newJSONAPIResource(t)
+		if err != nil {
+			return true, err
+		}
+		doc.Data = res
+	case []domain.Article:
+		list := make([]jsonAPIResource, len(t))
+		for i, ar := range t {
+			res, err := newJSONAPIResource(ar)
+			if err != nil {
+				return true, err
+			}
+			list[i] = res
+		}
+		doc.Data = list
+	default:
+		return false, nil
+	}
+
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return true, err
+	}
+	c.Set(fiber.HeaderContentType, MIMEApplicationJSONAPI)
+	return true, c.Send(body)
+}
+
+// parseArticle will read the article of the request body, either a JSON:API document
+// or any representation the body parser knows of
+func parseArticle(c *fiber.Ctx, ar *domain.Article) error {
+	if !isJSONAPIBody(c) {
+		return c.BodyParser(ar)
+	}
+
+	var doc struct {
+		Data jsonAPIResource `json:"data"`
+	}
+	if err := json.Unmarshal(c.Body(), &doc); err != nil {
+		return err
+	}
+	if doc.Data.Type != jsonAPIArticleType {
+		return errJSONAPIType
+	}
+	if len(doc.Data.Attributes) > 0 {
+		if err := json.Unmarshal(doc.Data.Attributes, ar); err != nil {
+			return err
+		}
+	}
+	if doc.Data.ID != "" {
+		id, err := strconv.ParseInt(doc.Data.ID, 10, 64)
+		if err != nil {
+			return err
+		}
+		ar.ID = id
+	}
+	return nil
+}
+
+// sendParseError will answer the error of parseArticle, 409 for a resource of an other type
+// and 422 for a body which can't be read
+func sendParseError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, errJSONAPIType) {
+		return send(c.Status(http.StatusConflict), ResponseError{Message: err.Error()})
+	}
+	return send(c.Status(http.StatusUnprocessableEntity), ResponseError{Message: err.Error()})
+}
@@ -0,0 +1,38 @@
+package rest
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// setPageLinks will set the Link header (RFC 5988) pointing at the next and previous pages,
+// a page without a cursor on that side gets no link for it. The prev link pages backward.
+func setPageLinks(c *fiber.Ctx, num int, next, prev string) {
+	links := make([]string, 0, 2)
+	if next != "" {
+		links = append(links, `<`+pageURL(c, next, num, false)+`>; rel="next"`)
+	}
+	if prev != "" {
+		links = append(links, `<`+pageURL(c, prev, num, true)+`>; rel="prev"`)
+	}
+	if len(links) == 0 {
+		return
+	}
+	c.Set(fiber.HeaderLink, strings.Join(links, ", "))
+}
+
+// pageURL will build the full URL of the current request with the cursor, num and direction replaced,
+// every other query param such as the filters is kept
+func pageURL(c *fiber.Ctx, cursor string, num int, backward bool) string {
+	query, _ := url.ParseQuery(string(c.Request().URI().QueryString()))
+	query.Set("cursor", cursor)
+	query.Set("num", strconv.Itoa(num))
+	query.Del("direction")
+	if backward {
+		query.Set("direction", "backward")
+	}
+	return c.BaseURL() + c.Path() + "?" + query.Encode()
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HeaderAPIKey is the request header carrying the api key
+const HeaderAPIKey = "X-API-Key"
+
+type apiKeyLabelKey struct{}
+
+const localsAPIKeyLabel = "apiKeyLabel"
+
+// APIKeyConfig represent the settings of the api key authentication
+type APIKeyConfig struct {
+	// Keys maps every accepted key to the label of its client, several keys of a client
+	// can be active at the same time while it is rotated
+	Keys map[string]string
+	// Optional lets the write requests without a key through, so another authentication like JWT handles them
+	Optional bool
+}
+
+// APIKey will authenticate the write requests with a static key sent in the X-API-Key header,
+// the label of the key is made available through APIKeyLabel and APIKeyLabelFromContext.
+// Safe methods stay public, but an unknown key is rejected on every method.
+func APIKey(cfg APIKeyConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderAPIKey)
+		if key == "" {
+			if cfg.Optional || isSafeMethod(c.Method()) {
+				return c.Next()
+			}
+			return c.Status(http.StatusUnauthorized).JSON(errorResponse{Message: "missing api key"})
+		}
+
+		label, ok := LookupAPIKey(cfg.Keys, key)
+		if !ok {
+			return c.Status(http.StatusUnauthorized).JSON(errorResponse{Message: "api key is invalid"})
+		}
+
+		c.Locals(localsAPIKeyLabel, label)
+		c.SetUserContext(ContextWithAPIKeyLabel(c.UserContext(), label))
+		return c.Next()
+	}
+}
+
+// LookupAPIKey will return the label of the key, it compares in constant time so the response time
+// doesn't leak how much of a key matched
+func LookupAPIKey(keys map[string]string, key string) (label string, ok bool) {
+	for candidate, candidateLabel := range keys {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(key)) == 1 {
+			label, ok = candidateLabel, true
+		}
+	}
+	return
+}
+
+// APIKeyLabel will return the label of the api key which authenticated the request
+func APIKeyLabel(c *fiber.Ctx) (string, bool) {
+	label, ok := c.Locals(localsAPIKeyLabel).(string)
+	return label, ok
+}
+
+// ContextWithAPIKeyLabel will return a copy of ctx carrying the label of the api key which authenticated
+// the request, for the servers other than fiber
+func ContextWithAPIKeyLabel(ctx context.Context, label string) context.Context {
+	return context.WithValue(ctx, apiKeyLabelKey{}, label)
+}
+
+// APIKeyLabelFromContext will return the label of the api key which authenticated the request
+func APIKeyLabelFromContext(ctx context.Context) (string, bool) {
+	label, ok := ctx.Value(apiKeyLabelKey{}).(string)
+	return label, ok
+}
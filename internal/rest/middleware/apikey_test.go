@@ -0,0 +1,95 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	test "net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/internal/rest/middleware"
+)
+
+func newAPIKeyApp(cfg middleware.APIKeyConfig) *fiber.App {
+	app := fiber.New()
+	app.Use(middleware.APIKey(cfg))
+	handler := func(c *fiber.Ctx) error {
+		label, _ := middleware.APIKeyLabel(c)
+		ctxLabel, _ := middleware.APIKeyLabelFromContext(c.UserContext())
+		return c.SendString(label + "|" + ctxLabel)
+	}
+	app.Get("/articles", handler)
+	app.Post("/articles", handler)
+	return app
+}
+
+func sendWithAPIKey(t *testing.T, app *fiber.App, method, key string) (int, string) {
+	t.Helper()
+	req := test.NewRequest(method, "/articles", nil)
+	if key != "" {
+		req.Header.Set(middleware.HeaderAPIKey, key)
+	}
+	res, err := app.Test(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return res.StatusCode, string(body)
+}
+
+func TestAPIKey(t *testing.T) {
+	app := newAPIKeyApp(middleware.APIKeyConfig{Keys: map[string]string{"key-1": "billing"}})
+
+	t.Run("valid-key", func(t *testing.T) {
+		status, body := sendWithAPIKey(t, app, http.MethodPost, "key-1")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "billing|billing", body)
+	})
+
+	t.Run("unknown-key", func(t *testing.T) {
+		for _, method := range []string{http.MethodPost, http.MethodGet} {
+			status, body := sendWithAPIKey(t, app, method, "key-2")
+			assert.Equal(t, http.StatusUnauthorized, status)
+			assert.Equal(t, `{"message":"api key is invalid"}`, body)
+		}
+	})
+
+	t.Run("missing-key", func(t *testing.T) {
+		status, body := sendWithAPIKey(t, app, http.MethodPost, "")
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, `{"message":"missing api key"}`, body)
+
+		status, _ = sendWithAPIKey(t, app, http.MethodGet, "")
+		assert.Equal(t, http.StatusOK, status)
+	})
+}
+
+func TestAPIKeyRotation(t *testing.T) {
+	app := newAPIKeyApp(middleware.APIKeyConfig{Keys: map[string]string{
+		"old-key": "billing",
+		"new-key": "billing",
+	}})
+
+	for _, key := range []string{"old-key", "new-key"} {
+		status, body := sendWithAPIKey(t, app, http.MethodPost, key)
+		assert.Equal(t, http.StatusOK, status, key)
+		assert.Equal(t, "billing|billing", body, key)
+	}
+}
+
+func TestAPIKeyOptionalWithJWT(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.APIKey(middleware.APIKeyConfig{Keys: map[string]string{"key-1": "billing"}, Optional: true}))
+	app.Use(middleware.JWT(jwtSecret))
+	app.Post("/articles", func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusCreated)
+	})
+
+	status, _ := sendWithAPIKey(t, app, http.MethodPost, "key-1")
+	assert.Equal(t, http.StatusCreated, status)
+
+	status, _ = sendWithAPIKey(t, app, http.MethodPost, "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
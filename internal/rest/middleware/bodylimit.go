@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DefaultBodyLimit is the size of the largest request body accepted when none is configured, in bytes
+const DefaultBodyLimit = 1 << 20
+
+// BodyLimit will return the settings of an app refusing the request bodies larger than limit bytes,
+// the default limit when zero. The server stops reading such a body once it knows its size, before
+// any handler parses it, and answers 413 in the JSON shape of the other middlewares.
+func BodyLimit(limit int) fiber.Config {
+	if limit <= 0 {
+		limit = DefaultBodyLimit
+	}
+	return fiber.Config{
+		BodyLimit:    limit,
+		ErrorHandler: bodyLimitErrorHandler,
+	}
+}
+
+// bodyLimitErrorHandler will render the 413 raised by the server as JSON, the other errors are left
+// to the default error handler of fiber
+func bodyLimitErrorHandler(c *fiber.Ctx, err error) error {
+	var fe *fiber.Error
+	if errors.As(err, &fe) && fe.Code == http.StatusRequestEntityTooLarge {
+		return c.Status(http.StatusRequestEntityTooLarge).JSON(errorResponse{Code: "PAYLOAD_TOO_LARGE", Message: "request body is too large"})
+	}
+	return fiber.DefaultErrorHandler(c, err)
+}
@@ -0,0 +1,64 @@
+package middleware_test
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/internal/rest/middleware"
+)
+
+func TestBodyLimit(t *testing.T) {
+	parsed := false
+	cfg := middleware.BodyLimit(16)
+	cfg.DisableStartupMessage = true
+	app := fiber.New(cfg)
+	app.Post("/articles", func(c *fiber.Ctx) error {
+		parsed = true
+		return c.SendString(string(c.Body()))
+	})
+
+	// the body is refused by the server as it is read, app.Test reports that as an error instead of the response
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = app.Listener(ln) }()
+	t.Cleanup(func() { _ = app.Shutdown() })
+	url := "http://" + ln.Addr().String()
+
+	post := func(t *testing.T, body string) (int, string) {
+		t.Helper()
+		res, err := http.Post(url+"/articles", fiber.MIMEApplicationJSON, strings.NewReader(body))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		byt, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res.StatusCode, string(byt)
+	}
+
+	t.Run("within-limit", func(t *testing.T) {
+		status, _ := post(t, `{"title":"Hi"}`)
+		assert.Equal(t, http.StatusOK, status)
+		assert.True(t, parsed)
+	})
+
+	t.Run("oversized", func(t *testing.T) {
+		parsed = false
+		status, body := post(t, `{"title":"`+strings.Repeat("a", 64)+`"}`)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+		assert.JSONEq(t, `{"code":"PAYLOAD_TOO_LARGE","message":"request body is too large"}`, body)
+		assert.False(t, parsed, "the handler is never reached")
+	})
+
+	t.Run("other-errors", func(t *testing.T) {
+		res, err := http.Get(url + "/missing")
+		require.NoError(t, err)
+		res.Body.Close()
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
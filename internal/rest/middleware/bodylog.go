@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultBodyLogMaxBytes is how much of each body is logged when no cap is configured
+const defaultBodyLogMaxBytes = 4 << 10
+
+// redacted replaces the values which must not reach the logs
+const redacted = "[REDACTED]"
+
+// defaultRedactedHeaders are the headers carrying credentials, they are always redacted
+var defaultRedactedHeaders = []string{fiber.HeaderAuthorization, fiber.HeaderProxyAuthorization, fiber.HeaderCookie, fiber.HeaderSetCookie, HeaderAPIKey}
+
+// BodyLogConfig represent the settings of the body logging middleware
+type BodyLogConfig struct {
+	// Paths lists the patterns of the paths whose requests are logged with their bodies, in the syntax
+	// of path.Match, e.g. /articles/* matches /articles/7 but not /articles/7/revisions
+	Paths []string
+	// MaxBytes caps the bytes logged of each body, defaultBodyLogMaxBytes when zero
+	MaxBytes int
+	// RedactHeaders lists the headers logged as [REDACTED] on top of defaultRedactedHeaders
+	RedactHeaders []string
+	// RedactFields lists the fields of the JSON bodies logged as [REDACTED] at any depth, e.g. password
+	RedactFields []string
+}
+
+// BodyLog will log the headers and the bodies of the request and of the response of the requests whose path
+// matches one of the patterns, a debugging aid for a route at a time. The entry is written at debug level
+// by a logger of its own, so that it is logged whatever the level of the standard logger.
+func BodyLog(cfg BodyLogConfig) fiber.Handler {
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = defaultBodyLogMaxBytes
+	}
+	headers := make(map[string]struct{}, len(defaultRedactedHeaders)+len(cfg.RedactHeaders))
+	for _, h := range append(defaultRedactedHeaders, cfg.RedactHeaders...) {
+		headers[strings.ToLower(h)] = struct{}{}
+	}
+	fields := make(map[string]struct{}, len(cfg.RedactFields))
+	for _, f := range cfg.RedactFields {
+		fields[strings.ToLower(f)] = struct{}{}
+	}
+
+	return func(c *fiber.Ctx) error {
+		if !matchesAny(cfg.Paths, c.Path()) {
+			return c.Next()
+		}
+
+		// the request body is copied, the buffer of fasthttp is reused once the handler returns
+		reqBody := string(c.Body())
+		reqHeaders := make(map[string]string)
+		c.Request().Header.VisitAll(func(key, value []byte) {
+			reqHeaders[string(key)] = redactHeader(headers, string(key), string(value))
+		})
+
+		// let the error handler write the response now, so that it is the one logged
+		if err := c.Next(); err != nil {
+			if errHandler := c.App().ErrorHandler(c, err); errHandler != nil {
+				_ = c.SendStatus(http.StatusInternalServerError)
+			}
+		}
+
+		resHeaders := make(map[string]string)
+		c.Response().Header.VisitAll(func(key, value []byte) {
+			resHeaders[string(key)] = redactHeader(headers, string(key), string(value))
+		})
+
+		debugLogger().WithContext(c.UserContext()).WithFields(logrus.Fields{
+			"method":           c.Method(),
+			"path":             c.Path(),
+			"status":           c.Response().StatusCode(),
+			"request_headers":  reqHeaders,
+			"request_body":     capBody(redactBody(fields, reqBody), cfg.MaxBytes),
+			"response_headers": resHeaders,
+			"response_body":    capBody(redactBody(fields, string(c.Response().Body())), cfg.MaxBytes),
+		}).Debug("request bodies")
+		return nil
+	}
+}
+
+// matchesAny will report whether the path matches one of the patterns, a malformed pattern matches nothing
+func matchesAny(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// debugLogger will return a logger writing like the standard one, only at debug level
+func debugLogger() *logrus.Logger {
+	std := logrus.StandardLogger()
+	return &logrus.Logger{
+		Out:          std.Out,
+		Hooks:        std.Hooks,
+		Formatter:    std.Formatter,
+		ReportCaller: std.ReportCaller,
+		Level:        logrus.DebugLevel,
+		ExitFunc:     std.ExitFunc,
+	}
+}
+
+func redactHeader(redactedHeaders map[string]struct{}, key, value string) string {
+	if _, ok := redactedHeaders[strings.ToLower(key)]; ok {
+		return redacted
+	}
+	return value
+}
+
+// redactBody will redact the fields of a JSON body, any other body is left as it is
+func redactBody(fields map[string]struct{}, body string) string {
+	if len(fields) == 0 || body == "" {
+		return body
+	}
+	var doc interface{}
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return body
+	}
+	byt, err := json.Marshal(redactValue(fields, doc))
+	if err != nil {
+		return body
+	}
+	return string(byt)
+}
+
+func redactValue(fields map[string]struct{}, v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if _, ok := fields[strings.ToLower(key)]; ok {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(fields, value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(fields, value)
+		}
+	}
+	return v
+}
+
+// capBody will cut the body to max bytes, telling how much was left out
+func capBody(body string, max int) string {
+	if len(body) <= max {
+		return body
+	}
+	return body[:max] + "...(" + strconv.Itoa(len(body)-max) + " more bytes)"
+}
@@ -0,0 +1,91 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	test "net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/internal/rest/middleware"
+)
+
+func TestBodyLog(t *testing.T) {
+	var logs bytes.Buffer
+	logrus.SetOutput(&logs)
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+	defer func() {
+		logrus.SetOutput(os.Stderr)
+		logrus.SetFormatter(&logrus.TextFormatter{})
+	}()
+	// the entries are logged whatever the level of the standard logger
+	require.Equal(t, logrus.InfoLevel, logrus.GetLevel())
+
+	app := fiber.New()
+	app.Use(middleware.BodyLog(middleware.BodyLogConfig{
+		Paths:        []string{"/articles/*"},
+		MaxBytes:     64,
+		RedactFields: []string{"password"},
+	}))
+	app.Post("/articles/:id", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderSetCookie, "session=secret")
+		return c.JSON(fiber.Map{"id": c.Params("id"), "title": "Title"})
+	})
+	app.Post("/comments", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"id": 1})
+	})
+
+	post := func(t *testing.T, target, body string) {
+		t.Helper()
+		req := test.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer secret-token")
+		res, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+
+	t.Run("matched", func(t *testing.T) {
+		logs.Reset()
+		post(t, "/articles/7", `{"title":"Title","author":{"password":"hunter2"}}`)
+
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(logs.Bytes(), &entry), logs.String())
+		assert.Equal(t, "debug", entry["level"])
+		assert.Equal(t, "/articles/7", entry["path"])
+		assert.JSONEq(t, `{"title":"Title","author":{"password":"[REDACTED]"}}`, entry["request_body"].(string))
+		assert.JSONEq(t, `{"id":"7","title":"Title"}`, entry["response_body"].(string))
+
+		reqHeaders := entry["request_headers"].(map[string]interface{})
+		assert.Equal(t, "[REDACTED]", reqHeaders["Authorization"])
+		assert.Equal(t, fiber.MIMEApplicationJSON, reqHeaders["Content-Type"])
+		assert.Equal(t, "[REDACTED]", entry["response_headers"].(map[string]interface{})["Set-Cookie"])
+		assert.NotContains(t, logs.String(), "secret")
+		assert.NotContains(t, logs.String(), "hunter2")
+	})
+
+	t.Run("capped", func(t *testing.T) {
+		logs.Reset()
+		post(t, "/articles/7", `{"content":"`+strings.Repeat("a", 100)+`"}`)
+
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(logs.Bytes(), &entry), logs.String())
+		body := entry["request_body"].(string)
+		assert.True(t, strings.HasPrefix(body, `{"content":"aaa`), body)
+		assert.True(t, strings.HasSuffix(body, "...(50 more bytes)"), body)
+	})
+
+	t.Run("not-matched", func(t *testing.T) {
+		logs.Reset()
+		post(t, "/comments", `{"content":"Content"}`)
+
+		assert.Empty(t, logs.String())
+	})
+}
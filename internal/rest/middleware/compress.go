@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const defaultCompressMinSize = 1024
+
+// CompressConfig represent the settings of the response compression
+type CompressConfig struct {
+	// MinSize is the smallest body in bytes worth compressing, defaultCompressMinSize when zero
+	MinSize int
+}
+
+// Compress will gzip the response body when the client accepts it and the body is at least MinSize bytes,
+// smaller bodies are sent as is since compressing them costs more than it saves. Streamed bodies are left untouched.
+func Compress(cfg CompressConfig) fiber.Handler {
+	if cfg.MinSize <= 0 {
+		cfg.MinSize = defaultCompressMinSize
+	}
+
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		c.Vary(fiber.HeaderAcceptEncoding)
+		res := c.Response()
+		if !acceptsGzip(c.Get(fiber.HeaderAcceptEncoding)) || res.IsBodyStream() ||
+			len(res.Header.Peek(fiber.HeaderContentEncoding)) > 0 || len(res.Body()) < cfg.MinSize {
+			return nil
+		}
+
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(res.Body()); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		res.SetBodyRaw(buf.Bytes())
+		c.Set(fiber.HeaderContentEncoding, "gzip")
+		return nil
+	}
+}
+
+// acceptsGzip will report whether the Accept-Encoding header allows gzip, an explicit q=0 refuses it
+func acceptsGzip(header string) bool {
+	for _, token := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(token), ";")
+		name = strings.TrimSpace(name)
+		if name != "gzip" && name != "*" {
+			continue
+		}
+		q := 1.0
+		if raw, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, _ = strconv.ParseFloat(raw, 64)
+		}
+		return q > 0
+	}
+	return false
+}
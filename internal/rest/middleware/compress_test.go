@@ -0,0 +1,73 @@
+package middleware_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	test "net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/internal/rest/middleware"
+)
+
+func TestCompress(t *testing.T) {
+	large := `{"content":"` + strings.Repeat("lorem ipsum ", 200) + `"}`
+
+	app := fiber.New()
+	app.Use(middleware.Compress(middleware.CompressConfig{MinSize: 512}))
+	app.Get("/large", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.SendString(large)
+	})
+	app.Get("/tiny", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"id": 1})
+	})
+
+	get := func(t *testing.T, target, acceptEncoding string) *http.Response {
+		t.Helper()
+		req := test.NewRequest(http.MethodGet, target, nil)
+		if acceptEncoding != "" {
+			req.Header.Set(fiber.HeaderAcceptEncoding, acceptEncoding)
+		}
+		res, err := app.Test(req)
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("large", func(t *testing.T) {
+		res := get(t, "/large", "gzip, deflate")
+
+		assert.Equal(t, "gzip", res.Header.Get(fiber.HeaderContentEncoding))
+		assert.Equal(t, fiber.HeaderAcceptEncoding, res.Header.Get(fiber.HeaderVary))
+		zr, err := gzip.NewReader(res.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		assert.Equal(t, large, string(body))
+	})
+
+	t.Run("tiny", func(t *testing.T) {
+		res := get(t, "/tiny", "gzip")
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Empty(t, res.Header.Get(fiber.HeaderContentEncoding))
+		assert.JSONEq(t, `{"id":1}`, string(body))
+	})
+
+	t.Run("not-accepted", func(t *testing.T) {
+		for _, acceptEncoding := range []string{"", "deflate", "gzip;q=0"} {
+			res := get(t, "/large", acceptEncoding)
+
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			assert.Empty(t, res.Header.Get(fiber.HeaderContentEncoding), acceptEncoding)
+			assert.Equal(t, large, string(body), acceptEncoding)
+		}
+	})
+}
@@ -1,11 +1,96 @@
 package middleware
 
-import "github.com/labstack/echo/v4"
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
-// CORS will handle the CORS middleware
-func CORS(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		c.Response().Header().Set("Access-Control-Allow-Origin", "*")
-		return next(c)
+	"github.com/gofiber/fiber/v2"
+)
+
+var defaultCORSMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+}
+
+// CORSConfig represent the settings of the CORS middleware
+type CORSConfig struct {
+	// AllowOrigins lists the origins allowed to call the API, "*" allows any origin
+	AllowOrigins []string
+	// AllowMethods lists the methods allowed on a preflight request, defaultCORSMethods when empty
+	AllowMethods []string
+	// AllowHeaders lists the request headers allowed on a preflight request,
+	// the requested headers are echoed back when empty
+	AllowHeaders []string
+	// ExposeHeaders lists the response headers the browser may read
+	ExposeHeaders []string
+	// AllowCredentials lets the browser send cookies and authorization headers,
+	// the matching origin is then echoed back instead of "*"
+	AllowCredentials bool
+	// MaxAge is how long the browser may cache the preflight response, not sent when zero
+	MaxAge time.Duration
+}
+
+// CORS will answer the preflight requests and set the CORS headers of the requests from an allowed origin,
+// a request from any other origin gets no CORS header so the browser blocks it
+func CORS(cfg CORSConfig) fiber.Handler {
+	if len(cfg.AllowMethods) == 0 {
+		cfg.AllowMethods = defaultCORSMethods
+	}
+	anyOrigin := false
+	origins := make(map[string]struct{}, len(cfg.AllowOrigins))
+	for _, origin := range cfg.AllowOrigins {
+		if origin == "*" {
+			anyOrigin = true
+		}
+		origins[strings.ToLower(origin)] = struct{}{}
+	}
+	allowMethods := strings.Join(cfg.AllowMethods, ",")
+	allowHeaders := strings.Join(cfg.AllowHeaders, ",")
+	exposeHeaders := strings.Join(cfg.ExposeHeaders, ",")
+
+	return func(c *fiber.Ctx) error {
+		origin := c.Get(fiber.HeaderOrigin)
+		preflight := c.Method() == http.MethodOptions && c.Get(fiber.HeaderAccessControlRequestMethod) != ""
+		if origin == "" {
+			return c.Next()
+		}
+
+		c.Vary(fiber.HeaderOrigin)
+		_, allowed := origins[strings.ToLower(origin)]
+		if !allowed && !anyOrigin {
+			if preflight {
+				return c.SendStatus(http.StatusNoContent)
+			}
+			return c.Next()
+		}
+
+		if anyOrigin && !cfg.AllowCredentials {
+			c.Set(fiber.HeaderAccessControlAllowOrigin, "*")
+		} else {
+			c.Set(fiber.HeaderAccessControlAllowOrigin, origin)
+		}
+		if cfg.AllowCredentials {
+			c.Set(fiber.HeaderAccessControlAllowCredentials, "true")
+		}
+
+		if !preflight {
+			if exposeHeaders != "" {
+				c.Set(fiber.HeaderAccessControlExposeHeaders, exposeHeaders)
+			}
+			return c.Next()
+		}
+
+		c.Vary(fiber.HeaderAccessControlRequestMethod, fiber.HeaderAccessControlRequestHeaders)
+		c.Set(fiber.HeaderAccessControlAllowMethods, allowMethods)
+		if allowHeaders != "" {
+			c.Set(fiber.HeaderAccessControlAllowHeaders, allowHeaders)
+		} else if requested := c.Get(fiber.HeaderAccessControlRequestHeaders); requested != "" {
+			c.Set(fiber.HeaderAccessControlAllowHeaders, requested)
+		}
+		if cfg.MaxAge > 0 {
+			c.Set(fiber.HeaderAccessControlMaxAge, strconv.Itoa(int(cfg.MaxAge.Seconds())))
+		}
+		return c.SendStatus(http.StatusNoContent)
 	}
 }
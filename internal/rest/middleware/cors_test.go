@@ -5,7 +5,7 @@ import (
 	test "net/http/httptest"
 	"testing"
 
-	"github.com/labstack/echo/v4"
+	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -13,16 +13,85 @@ import (
 )
 
 func TestCORS(t *testing.T) {
-	e := echo.New()
-	req := test.NewRequest(echo.GET, "/", nil)
-	res := test.NewRecorder()
-	c := e.NewContext(req, res)
-
-	h := middleware.CORS(echo.HandlerFunc(func(c echo.Context) error {
-		return c.NoContent(http.StatusOK)
-	}))
-
-	err := h(c)
-	require.NoError(t, err)
-	assert.Equal(t, "*", res.Header().Get("Access-Control-Allow-Origin"))
+	newApp := func(cfg middleware.CORSConfig) *fiber.App {
+		app := fiber.New()
+		app.Use(middleware.CORS(cfg))
+		app.Get("/articles", func(c *fiber.Ctx) error {
+			return c.SendStatus(http.StatusOK)
+		})
+		return app
+	}
+	send := func(t *testing.T, app *fiber.App, req *http.Request) *http.Response {
+		t.Helper()
+		res, err := app.Test(req)
+		require.NoError(t, err)
+		return res
+	}
+	cfg := middleware.CORSConfig{
+		AllowOrigins:     []string{"https://app.example.com"},
+		AllowHeaders:     []string{fiber.HeaderAuthorization, fiber.HeaderContentType},
+		ExposeHeaders:    []string{"X-Cursor"},
+		AllowCredentials: true,
+	}
+
+	t.Run("allowed-origin", func(t *testing.T) {
+		req := test.NewRequest(http.MethodGet, "/articles", nil)
+		req.Header.Set(fiber.HeaderOrigin, "https://app.example.com")
+
+		res := send(t, newApp(cfg), req)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "https://app.example.com", res.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+		assert.Equal(t, "true", res.Header.Get(fiber.HeaderAccessControlAllowCredentials))
+		assert.Equal(t, "X-Cursor", res.Header.Get(fiber.HeaderAccessControlExposeHeaders))
+		assert.Equal(t, fiber.HeaderOrigin, res.Header.Get(fiber.HeaderVary))
+	})
+
+	t.Run("wildcard-with-credentials", func(t *testing.T) {
+		req := test.NewRequest(http.MethodGet, "/articles", nil)
+		req.Header.Set(fiber.HeaderOrigin, "https://other.example.com")
+
+		res := send(t, newApp(middleware.CORSConfig{AllowOrigins: []string{"*"}, AllowCredentials: true}), req)
+		assert.Equal(t, "https://other.example.com", res.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+
+		res = send(t, newApp(middleware.CORSConfig{AllowOrigins: []string{"*"}}), req)
+		assert.Equal(t, "*", res.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+	})
+
+	t.Run("disallowed-origin", func(t *testing.T) {
+		req := test.NewRequest(http.MethodGet, "/articles", nil)
+		req.Header.Set(fiber.HeaderOrigin, "https://evil.example.com")
+
+		res := send(t, newApp(cfg), req)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Empty(t, res.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+		assert.Empty(t, res.Header.Get(fiber.HeaderAccessControlAllowCredentials))
+	})
+
+	t.Run("preflight", func(t *testing.T) {
+		req := test.NewRequest(http.MethodOptions, "/articles", nil)
+		req.Header.Set(fiber.HeaderOrigin, "https://app.example.com")
+		req.Header.Set(fiber.HeaderAccessControlRequestMethod, http.MethodPost)
+		req.Header.Set(fiber.HeaderAccessControlRequestHeaders, fiber.HeaderAuthorization)
+
+		res := send(t, newApp(cfg), req)
+
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		assert.Equal(t, "https://app.example.com", res.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+		assert.Equal(t, "GET,HEAD,POST,PUT,PATCH,DELETE", res.Header.Get(fiber.HeaderAccessControlAllowMethods))
+		assert.Equal(t, "Authorization,Content-Type", res.Header.Get(fiber.HeaderAccessControlAllowHeaders))
+	})
+
+	t.Run("preflight-disallowed-origin", func(t *testing.T) {
+		req := test.NewRequest(http.MethodOptions, "/articles", nil)
+		req.Header.Set(fiber.HeaderOrigin, "https://evil.example.com")
+		req.Header.Set(fiber.HeaderAccessControlRequestMethod, http.MethodDelete)
+
+		res := send(t, newApp(cfg), req)
+
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		assert.Empty(t, res.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+		assert.Empty(t, res.Header.Get(fiber.HeaderAccessControlAllowMethods))
+	})
 }
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type userIDKey struct{}
+
+const localsUserID = "userID"
+
+type errorResponse struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// The errors of ParseToken, their message answers the request
+var (
+	ErrTokenExpired   = errors.New("token is expired")
+	ErrTokenInvalid   = errors.New("token is invalid")
+	ErrTokenNoSubject = errors.New("token has no subject")
+)
+
+// JWT will authenticate the write requests with a HS256 signed bearer token,
+// the subject of the token is made available through UserID and UserIDFromContext.
+// Safe methods stay public, a bearer token sent on them is still authenticated so the
+// editors can read the drafts. The requests already authenticated by an api key are let through.
+func JWT(secret []byte) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, ok := APIKeyLabel(c); ok {
+			return c.Next()
+		}
+
+		raw, ok := BearerToken(c.Get(fiber.HeaderAuthorization))
+		if !ok {
+			if isSafeMethod(c.Method()) {
+				return c.Next()
+			}
+			return unauthorized(c, "missing bearer token")
+		}
+
+		userID, err := ParseToken(secret, raw)
+		if err != nil {
+			return unauthorized(c, err.Error())
+		}
+
+		c.Locals(localsUserID, userID)
+		c.SetUserContext(ContextWithUserID(c.UserContext(), userID))
+		return c.Next()
+	}
+}
+
+// ParseToken will check the HS256 signature and the expiry of the token, returning its subject
+func ParseToken(secret []byte, raw string) (string, error) {
+	keyFunc := func(*jwt.Token) (interface{}, error) {
+		return secret, nil
+	}
+	token, err := jwt.Parse(raw, keyFunc, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return "", ErrTokenExpired
+		}
+		return "", ErrTokenInvalid
+	}
+
+	userID, err := token.Claims.GetSubject()
+	if err != nil || userID == "" {
+		return "", ErrTokenNoSubject
+	}
+	return userID, nil
+}
+
+// UserID will return the id of the user authenticated by the JWT middleware
+func UserID(c *fiber.Ctx) (string, bool) {
+	userID, ok := c.Locals(localsUserID).(string)
+	return userID, ok
+}
+
+// ContextWithUserID will return a copy of ctx carrying the id of the user authenticated by a token,
+// for the servers other than fiber
+func ContextWithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFromContext will return the id of the user authenticated by the JWT middleware
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(string)
+	return userID, ok
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// BearerToken will return the token of a Bearer authorization header
+func BearerToken(header string) (string, bool) {
+	const prefix = "Bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(header[len(prefix):]), true
+}
+
+func unauthorized(c *fiber.Ctx, message string) error {
+	c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+	return c.Status(http.StatusUnauthorized).JSON(errorResponse{Message: message})
+}
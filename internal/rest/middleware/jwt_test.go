@@ -0,0 +1,85 @@
+package middleware_test
+
+import (
+	"net/http"
+	test "net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/internal/rest/middleware"
+)
+
+var jwtSecret = []byte("secret")
+
+func signToken(t *testing.T, secret []byte, claims jwt.RegisteredClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	require.NoError(t, err)
+	return token
+}
+
+func newJWTApp() *fiber.App {
+	app := fiber.New()
+	app.Use(middleware.JWT(jwtSecret))
+	handler := func(c *fiber.Ctx) error {
+		userID, _ := middleware.UserID(c)
+		ctxUserID, _ := middleware.UserIDFromContext(c.UserContext())
+		return c.SendString(userID + "|" + ctxUserID)
+	}
+	app.Get("/articles", handler)
+	app.Post("/articles", handler)
+	return app
+}
+
+func TestJWT(t *testing.T) {
+	valid := signToken(t, jwtSecret, jwt.RegisteredClaims{
+		Subject:   "42",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	})
+	expired := signToken(t, jwtSecret, jwt.RegisteredClaims{
+		Subject:   "42",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+	})
+	wrongSecret := signToken(t, []byte("other"), jwt.RegisteredClaims{Subject: "42"})
+
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		wantStatus    int
+		wantBody      string
+	}{
+		{"valid-token", http.MethodPost, "Bearer " + valid, http.StatusOK, "42|42"},
+		{"expired-token", http.MethodPost, "Bearer " + expired, http.StatusUnauthorized, `{"message":"token is expired"}`},
+		{"malformed-token", http.MethodPost, "Bearer not-a-jwt", http.StatusUnauthorized, `{"message":"token is invalid"}`},
+		{"wrong-secret", http.MethodPost, "Bearer " + wrongSecret, http.StatusUnauthorized, `{"message":"token is invalid"}`},
+		{"missing-header", http.MethodPost, "", http.StatusUnauthorized, `{"message":"missing bearer token"}`},
+		{"public-read", http.MethodGet, "", http.StatusOK, "|"},
+		{"authenticated-read", http.MethodGet, "Bearer " + valid, http.StatusOK, "42|42"},
+		{"invalid-token-read", http.MethodGet, "Bearer not-a-jwt", http.StatusUnauthorized, `{"message":"token is invalid"}`},
+	}
+
+	app := newJWTApp()
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			req := test.NewRequest(tc.method, "/articles", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+
+			res, err := app.Test(req)
+			require.NoError(t, err)
+
+			body := make([]byte, 128)
+			n, _ := res.Body.Read(body)
+			assert.Equal(t, tc.wantStatus, res.StatusCode)
+			assert.Equal(t, tc.wantBody, string(body[:n]))
+		})
+	}
+}
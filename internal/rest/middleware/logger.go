@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Logger will log one entry per request with its method, path, status, latency and the bytes written,
+// the level follows the status class: warn for 4xx and error for 5xx
+func Logger() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		// let the error handler write the response now, so the status is known when logging
+		if err := c.Next(); err != nil {
+			if errHandler := c.App().ErrorHandler(c, err); errHandler != nil {
+				_ = c.SendStatus(http.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		entry := logrus.WithContext(c.UserContext()).WithFields(logrus.Fields{
+			"method":     c.Method(),
+			"path":       c.Path(),
+			"status":     status,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"bytes":      len(c.Response().Body()),
+		})
+
+		switch {
+		case status >= http.StatusInternalServerError:
+			entry.Error("request completed")
+		case status >= http.StatusBadRequest:
+			entry.Warn("request completed")
+		default:
+			entry.Info("request completed")
+		}
+		return nil
+	}
+}
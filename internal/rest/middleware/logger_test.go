@@ -0,0 +1,66 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	test "net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/internal/rest/middleware"
+)
+
+func TestLogger(t *testing.T) {
+	var logs bytes.Buffer
+	logrus.SetOutput(&logs)
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+	defer func() {
+		logrus.SetOutput(os.Stderr)
+		logrus.SetFormatter(&logrus.TextFormatter{})
+	}()
+
+	app := fiber.New()
+	app.Use(middleware.Logger())
+	app.Get("/articles", func(c *fiber.Ctx) error {
+		return c.SendString("hello")
+	})
+	app.Get("/articles/:id", func(c *fiber.Ctx) error {
+		return c.Status(http.StatusNotFound).SendString("missing")
+	})
+	app.Get("/broken", func(c *fiber.Ctx) error {
+		return fiber.NewError(http.StatusServiceUnavailable, "unavailable")
+	})
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantLevel  string
+		wantBytes  int
+	}{
+		{"/articles", http.StatusOK, "info", 5},
+		{"/articles/7", http.StatusNotFound, "warning", 7},
+		{"/broken", http.StatusServiceUnavailable, "error", 11},
+	}
+
+	for _, tc := range tests {
+		logs.Reset()
+		res, err := app.Test(test.NewRequest(http.MethodGet, tc.path, nil))
+		require.NoError(t, err)
+		assert.Equal(t, tc.wantStatus, res.StatusCode)
+
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(logs.Bytes(), &entry), logs.String())
+		assert.Equal(t, http.MethodGet, entry["method"])
+		assert.Equal(t, tc.path, entry["path"])
+		assert.EqualValues(t, tc.wantStatus, entry["status"])
+		assert.EqualValues(t, tc.wantBytes, entry["bytes"])
+		assert.Contains(t, entry, "latency_ms")
+		assert.Equal(t, tc.wantLevel, entry["level"])
+	}
+}
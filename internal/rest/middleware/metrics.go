@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// unmatchedRoute labels the requests which matched no route, so unknown paths can't grow the label set
+const unmatchedRoute = "unmatched"
+
+// Metrics records the prometheus metrics of the HTTP layer
+type Metrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewMetrics will create the HTTP metrics and register them in the given registerer
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Number of HTTP requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Duration of the HTTP requests by method and route.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+	}
+	reg.MustRegister(m.requests, m.duration)
+	return m
+}
+
+// Handler will record every request, the route label is the route template like /articles/:id
+func (m *Metrics) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		self := c.Route()
+
+		err := c.Next()
+		if err != nil {
+			if errHandler := c.App().ErrorHandler(c, err); errHandler != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		route := unmatchedRoute
+		if matched := c.Route(); matched != self {
+			route = matched.Path
+		}
+
+		method := c.Method()
+		m.requests.WithLabelValues(method, route, strconv.Itoa(c.Response().StatusCode())).Inc()
+		m.duration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
+
+// MetricsEndpoint will expose the metrics of the given gatherer in the prometheus text format
+func MetricsEndpoint(gatherer prometheus.Gatherer) fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}))
+}
@@ -0,0 +1,43 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	test "net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/internal/rest/middleware"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics := middleware.NewMetrics(reg)
+
+	app := fiber.New()
+	app.Use(metrics.Handler())
+	app.Get("/metrics", middleware.MetricsEndpoint(reg))
+	app.Get("/articles/:id", func(c *fiber.Ctx) error {
+		return c.SendString(c.Params("id"))
+	})
+
+	for _, path := range []string{"/articles/1", "/articles/2", "/articles/3", "/unknown"} {
+		_, err := app.Test(test.NewRequest(http.MethodGet, path, nil))
+		require.NoError(t, err)
+	}
+
+	res, err := app.Test(test.NewRequest(http.MethodGet, "/metrics", nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, string(body), `http_requests_total{method="GET",route="/articles/:id",status="200"} 3`)
+	assert.Contains(t, string(body), `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, string(body), `http_request_duration_seconds_count{method="GET",route="/articles/:id"} 3`)
+	assert.NotContains(t, string(body), `route="/articles/1"`)
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// The headers the rate limiter sets on every response
+const (
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+)
+
+const rateLimitSweepInterval = time.Minute
+
+// RateLimitConfig represent the settings of the rate limiter
+type RateLimitConfig struct {
+	// Rate is the number of requests a client regains per second
+	Rate float64
+	// Burst is the number of requests a client can send at once
+	Burst int
+	// Next skips the limiter for the requests it returns true for, every request is limited when nil
+	Next func(c *fiber.Ctx) bool
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type rateLimiter struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     float64
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+	next      func(c *fiber.Ctx) bool
+}
+
+// RateLimit will limit every client with a token bucket, the client is identified by the label of its api key
+// once APIKey authenticated it, by its ip otherwise. It is mounted after APIKey, so that the key sent by the
+// client is never trusted as it is. A rejected request gets 429 with a Retry-After header.
+func RateLimit(cfg RateLimitConfig) fiber.Handler {
+	return newRateLimiter(cfg, time.Now).handle
+}
+
+func newRateLimiter(cfg RateLimitConfig, now func() time.Time) *rateLimiter {
+	if cfg.Burst < 1 {
+		cfg.Burst = 1
+	}
+	return &rateLimiter{
+		rate:      cfg.Rate,
+		burst:     float64(cfg.Burst),
+		buckets:   make(map[string]*bucket),
+		lastSweep: now(),
+		now:       now,
+		next:      cfg.Next,
+	}
+}
+
+func (l *rateLimiter) handle(c *fiber.Ctx) error {
+	if l.next != nil && l.next(c) {
+		return c.Next()
+	}
+
+	// the prefixes keep a label from sharing the bucket of an ip
+	key := "ip:" + c.IP()
+	if label, ok := APIKeyLabel(c); ok {
+		key = "key:" + label
+	}
+
+	remaining, retryAfter, ok := l.take(key)
+	c.Set(HeaderRateLimitLimit, strconv.Itoa(int(l.burst)))
+	c.Set(HeaderRateLimitRemaining, strconv.Itoa(remaining))
+	if !ok {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		return c.Status(http.StatusTooManyRequests).JSON(errorResponse{Message: "too many requests"})
+	}
+	return c.Next()
+}
+
+// take will consume a token of the client, when the bucket is empty it reports how long until the next token
+func (l *rateLimiter) take(key string) (remaining int, retryAfter time.Duration, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, found := l.buckets[key]
+	if !found {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		if l.rate <= 0 {
+			return 0, time.Second, false
+		}
+		return 0, time.Duration((1 - b.tokens) / l.rate * float64(time.Second)), false
+	}
+
+	b.tokens--
+	return int(b.tokens), 0, true
+}
+
+// sweep will drop the buckets which refilled completely, they are the same as a new bucket
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
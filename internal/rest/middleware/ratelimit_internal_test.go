@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"net/http"
+	test "net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+func newRateLimitApp(clock *fakeClock, cfg RateLimitConfig) *fiber.App {
+	app := fiber.New()
+	app.Use(APIKey(APIKeyConfig{Keys: map[string]string{"key-1": "client-1", "key-2": "client-1"}, Optional: true}))
+	app.Use(newRateLimiter(cfg, clock.Now).handle)
+	app.Get("/articles", func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+	return app
+}
+
+func sendRateLimited(t *testing.T, app *fiber.App, apiKey string) *http.Response {
+	t.Helper()
+	req := test.NewRequest(http.MethodGet, "/articles", nil)
+	if apiKey != "" {
+		req.Header.Set(HeaderAPIKey, apiKey)
+	}
+	res, err := app.Test(req)
+	require.NoError(t, err)
+	return res
+}
+
+func TestRateLimit(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}
+	app := newRateLimitApp(clock, RateLimitConfig{Rate: 0.5, Burst: 2})
+
+	res := sendRateLimited(t, app, "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "1", res.Header.Get(HeaderRateLimitRemaining))
+
+	res = sendRateLimited(t, app, "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "0", res.Header.Get(HeaderRateLimitRemaining))
+
+	res = sendRateLimited(t, app, "")
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	assert.Equal(t, "2", res.Header.Get("Retry-After"))
+
+	// another client has its own bucket
+	res = sendRateLimited(t, app, "key-1")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	clock.Advance(2 * time.Second)
+	res = sendRateLimited(t, app, "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "0", res.Header.Get(HeaderRateLimitRemaining))
+
+	res = sendRateLimited(t, app, "")
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+}
+
+func TestRateLimitKey(t *testing.T) {
+	t.Run("label", func(t *testing.T) {
+		clock := &fakeClock{now: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}
+		app := newRateLimitApp(clock, RateLimitConfig{Rate: 0.5, Burst: 2})
+
+		// the keys of a client share its bucket
+		assert.Equal(t, http.StatusOK, sendRateLimited(t, app, "key-1").StatusCode)
+		assert.Equal(t, http.StatusOK, sendRateLimited(t, app, "key-2").StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, sendRateLimited(t, app, "key-1").StatusCode)
+	})
+
+	t.Run("unvalidated-key", func(t *testing.T) {
+		clock := &fakeClock{now: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}
+		app := fiber.New()
+		app.Use(newRateLimiter(RateLimitConfig{Rate: 0.5, Burst: 2}, clock.Now).handle)
+		app.Get("/articles", func(c *fiber.Ctx) error {
+			return c.SendStatus(http.StatusOK)
+		})
+
+		// a key no middleware checked doesn't get a bucket of its own
+		assert.Equal(t, http.StatusOK, sendRateLimited(t, app, "random-1").StatusCode)
+		assert.Equal(t, http.StatusOK, sendRateLimited(t, app, "random-2").StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, sendRateLimited(t, app, "random-3").StatusCode)
+	})
+}
+
+func TestRateLimitNext(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}
+	app := newRateLimitApp(clock, RateLimitConfig{Rate: 0.5, Burst: 1, Next: func(c *fiber.Ctx) bool {
+		return c.Get(HeaderAPIKey) == ""
+	}})
+
+	// the skipped requests don't take a token
+	for i := 0; i < 3; i++ {
+		res := sendRateLimited(t, app, "")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Empty(t, res.Header.Get(HeaderRateLimitRemaining))
+	}
+	assert.Equal(t, http.StatusOK, sendRateLimited(t, app, "key-1").StatusCode)
+	assert.Equal(t, http.StatusTooManyRequests, sendRateLimited(t, app, "key-1").StatusCode)
+}
+
+func TestRateLimitSweep(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}
+	l := newRateLimiter(RateLimitConfig{Rate: 1, Burst: 2}, clock.Now)
+
+	_, _, ok := l.take("client")
+	require.True(t, ok)
+
+	clock.Advance(2 * rateLimitSweepInterval)
+	_, _, ok = l.take("other")
+	require.True(t, ok)
+	assert.NotContains(t, l.buckets, "client")
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Recover will turn a panic of the next handlers into a 500 response, the panic and its stack trace
+// are logged. The headers already set, like the request id, are kept on the response.
+func Recover() fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			fields := logrus.Fields{
+				"method": c.Method(),
+				"path":   c.Path(),
+				"stack":  string(debug.Stack()),
+			}
+			if requestID := c.GetRespHeader(fiber.HeaderXRequestID); requestID != "" {
+				fields["request_id"] = requestID
+			}
+			logrus.WithFields(fields).Error(fmt.Sprintf("panic recovered: %v", r))
+
+			err = c.Status(http.StatusInternalServerError).JSON(errorResponse{Message: "internal server error"})
+		}()
+
+		return c.Next()
+	}
+}
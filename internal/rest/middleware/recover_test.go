@@ -0,0 +1,45 @@
+package middleware_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	test "net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/internal/rest/middleware"
+)
+
+func TestRecover(t *testing.T) {
+	var logs bytes.Buffer
+	logrus.SetOutput(&logs)
+	defer logrus.SetOutput(os.Stderr)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderXRequestID, "req-1")
+		return c.Next()
+	})
+	app.Use(middleware.Recover())
+	app.Get("/articles", func(c *fiber.Ctx) error {
+		panic("boom")
+	})
+
+	res, err := app.Test(test.NewRequest(http.MethodGet, "/articles", nil))
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	assert.JSONEq(t, `{"message":"internal server error"}`, string(body))
+	assert.Equal(t, "req-1", res.Header.Get(fiber.HeaderXRequestID))
+	assert.Contains(t, logs.String(), "panic recovered: boom")
+	assert.Contains(t, logs.String(), "request_id=req-1")
+	assert.Contains(t, logs.String(), "recover_test.go")
+}
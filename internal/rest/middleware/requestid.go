@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type requestIDKey struct{}
+
+const localsRequestID = "requestID"
+
+// RequestID will correlate the request with the id sent in the X-Request-ID header, or a generated one,
+// the id is echoed back in the response and carried by the user context of the request
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := c.Get(fiber.HeaderXRequestID)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+
+		c.Set(fiber.HeaderXRequestID, requestID)
+		c.Locals(localsRequestID, requestID)
+		c.SetUserContext(ContextWithRequestID(c.UserContext(), requestID))
+		return c.Next()
+	}
+}
+
+// ContextWithRequestID will return a copy of the context carrying the request id
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext will return the request id set by the RequestID middleware
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey{}).(string)
+	return requestID, ok
+}
+
+// RequestIDHook adds the request id to the log entries made with logrus.WithContext
+type RequestIDHook struct{}
+
+func (RequestIDHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (RequestIDHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+	if requestID, ok := RequestIDFromContext(entry.Context); ok {
+		entry.Data["request_id"] = requestID
+	}
+	return nil
+}
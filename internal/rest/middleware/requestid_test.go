@@ -0,0 +1,71 @@
+package middleware_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	test "net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/internal/rest/middleware"
+)
+
+func newRequestIDApp() *fiber.App {
+	app := fiber.New()
+	app.Use(middleware.RequestID())
+	app.Get("/articles", func(c *fiber.Ctx) error {
+		requestID, _ := middleware.RequestIDFromContext(c.UserContext())
+		return c.SendString(requestID)
+	})
+	return app
+}
+
+func TestRequestID(t *testing.T) {
+	app := newRequestIDApp()
+
+	t.Run("provided", func(t *testing.T) {
+		req := test.NewRequest(http.MethodGet, "/articles", nil)
+		req.Header.Set(fiber.HeaderXRequestID, "req-1")
+		res, err := app.Test(req)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "req-1", res.Header.Get(fiber.HeaderXRequestID))
+		assert.Equal(t, "req-1", string(body))
+	})
+
+	t.Run("generated", func(t *testing.T) {
+		res, err := app.Test(test.NewRequest(http.MethodGet, "/articles", nil))
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		requestID := res.Header.Get(fiber.HeaderXRequestID)
+		_, err = uuid.Parse(requestID)
+		assert.NoError(t, err)
+		assert.Equal(t, requestID, string(body))
+	})
+}
+
+func TestRequestIDHook(t *testing.T) {
+	var logs bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&logs)
+	logger.AddHook(middleware.RequestIDHook{})
+
+	ctx := middleware.ContextWithRequestID(context.Background(), "req-1")
+	logger.WithContext(ctx).Error("failed")
+	assert.Contains(t, logs.String(), "request_id=req-1")
+
+	logs.Reset()
+	logger.WithContext(context.Background()).Error("failed")
+	assert.NotContains(t, logs.String(), "request_id")
+}
@@ -2,21 +2,27 @@ package middleware
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"time"
 
-	echo "github.com/labstack/echo/v4"
+	"github.com/gofiber/fiber/v2"
 )
 
-// SetRequestContextWithTimeout will set the request context with timeout for every incoming HTTP Request
-func SetRequestContextWithTimeout(d time.Duration) echo.MiddlewareFunc {
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			ctx, cancel := context.WithTimeout(c.Request().Context(), d)
-			defer cancel()
+// Timeout will give every request a context with the deadline of the timeout, the handlers pass it down
+// through c.UserContext. A request still served once the deadline passed is answered with 503, the headers
+// already set, like the request id, are kept on the response.
+func Timeout(d time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(c.UserContext(), d)
+		defer cancel()
+		c.SetUserContext(ctx)
 
-			newRequest := c.Request().WithContext(ctx)
-			c.SetRequest(newRequest)
-			return next(c)
+		err := c.Next()
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return err
 		}
+		c.Response().ResetBody()
+		return c.Status(http.StatusServiceUnavailable).JSON(errorResponse{Code: "TIMEOUT", Message: "request timed out"})
 	}
 }
@@ -0,0 +1,52 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	test "net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/internal/rest/middleware"
+)
+
+func TestTimeout(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.Timeout(20 * time.Millisecond))
+	app.Get("/slow", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderXRequestID, "req-1")
+		<-c.UserContext().Done()
+		return c.Status(http.StatusInternalServerError).SendString(c.UserContext().Err().Error())
+	})
+	app.Get("/fast", func(c *fiber.Ctx) error {
+		deadline, ok := c.UserContext().Deadline()
+		require.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(20*time.Millisecond), deadline, 20*time.Millisecond)
+		return c.SendString("done")
+	})
+
+	t.Run("deadline-exceeded", func(t *testing.T) {
+		res, err := app.Test(test.NewRequest(http.MethodGet, "/slow", nil), int((time.Second).Milliseconds()))
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+		assert.JSONEq(t, `{"code":"TIMEOUT","message":"request timed out"}`, string(body))
+		assert.Equal(t, "req-1", res.Header.Get(fiber.HeaderXRequestID))
+	})
+
+	t.Run("in-time", func(t *testing.T) {
+		res, err := app.Test(test.NewRequest(http.MethodGet, "/fast", nil))
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "done", string(body))
+	})
+}
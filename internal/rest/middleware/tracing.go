@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "apismrtbiz/internal/rest"
+
+// Tracing will start a server span for every request, continuing the trace of the W3C traceparent header
+// when one is sent. The span is carried by the user context so the usecase and repository spans are its children.
+func Tracing() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// the propagators look the headers up by their lower case name
+		carrier := propagation.MapCarrier{}
+		c.Request().Header.VisitAll(func(key, value []byte) {
+			carrier.Set(strings.ToLower(string(key)), string(value))
+		})
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), carrier)
+
+		ctx, span := otel.Tracer(tracerName).Start(ctx, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Method()),
+				attribute.String("url.path", c.Path()),
+			),
+		)
+		defer span.End()
+		c.SetUserContext(ctx)
+		self := c.Route()
+
+		err := c.Next()
+		if err != nil {
+			if errHandler := c.App().ErrorHandler(c, err); errHandler != nil {
+				_ = c.SendStatus(http.StatusInternalServerError)
+			}
+		}
+
+		// the route is only known once a handler matched
+		route := unmatchedRoute
+		if matched := c.Route(); matched != self {
+			route = matched.Path
+		}
+		span.SetName(c.Method() + " " + route)
+		span.SetAttributes(
+			attribute.String("http.route", route),
+			attribute.Int("http.response.status_code", c.Response().StatusCode()),
+		)
+		if c.Response().StatusCode() >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(c.Response().StatusCode()))
+		}
+		return nil
+	}
+}
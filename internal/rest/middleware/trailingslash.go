@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TrailingSlash will redirect a request whose path ends with a slash to the path without it, keeping the
+// query string. The redirect is a 308 so that the clients repeat the method and the body. It is meant for
+// an app with fiber.Config.StrictRouting, which serves /articles but not /articles/.
+func TrailingSlash() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		path := c.Path()
+		if len(path) <= 1 || !strings.HasSuffix(path, "/") {
+			return c.Next()
+		}
+
+		// the leading slashes are squashed too, //example.com would send the client to another host
+		target := "/" + strings.Trim(path, "/")
+		if query := c.Request().URI().QueryString(); len(query) > 0 {
+			target += "?" + string(query)
+		}
+		return c.Redirect(target, http.StatusPermanentRedirect)
+	}
+}
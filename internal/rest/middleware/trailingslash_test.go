@@ -0,0 +1,43 @@
+package middleware_test
+
+import (
+	"net/http"
+	test "net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/internal/rest/middleware"
+)
+
+func TestTrailingSlash(t *testing.T) {
+	app := fiber.New(fiber.Config{StrictRouting: true})
+	app.Use(middleware.TrailingSlash())
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendString("root") })
+	app.Post("/articles", func(c *fiber.Ctx) error { return c.SendString("stored") })
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		status   int
+		location string
+	}{
+		{"redirected", http.MethodPost, "/articles/", http.StatusPermanentRedirect, "/articles"},
+		{"query-kept", http.MethodPost, "/articles/?lang=fr&draft=true", http.StatusPermanentRedirect, "/articles?lang=fr&draft=true"},
+		{"same-host", http.MethodGet, "//example.com/", http.StatusPermanentRedirect, "/example.com"},
+		{"no-slash", http.MethodPost, "/articles", http.StatusOK, ""},
+		{"root", http.MethodGet, "/", http.StatusOK, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := app.Test(test.NewRequest(tc.method, tc.target, nil))
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.status, res.StatusCode)
+			assert.Equal(t, tc.location, res.Header.Get(fiber.HeaderLocation))
+		})
+	}
+}
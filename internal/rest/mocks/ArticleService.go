@@ -14,6 +14,34 @@ type ArticleService struct {
 	mock.Mock
 }
 
+// Count provides a mock function with given fields: ctx, status
+func (_m *ArticleService) Count(ctx context.Context, status domain.Status) (int64, error) {
+	ret := _m.Called(ctx, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Count")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Status) (int64, error)); ok {
+		return rf(ctx, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Status) int64); ok {
+		r0 = rf(ctx, status)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Status) error); ok {
+		r1 = rf(ctx, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx, id
 func (_m *ArticleService) Delete(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
@@ -32,9 +60,37 @@ func (_m *ArticleService) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *ArticleService) Fetch(ctx context.Context, cursor string, num int64) ([]domain.Article, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// DiffRevisions provides a mock function with given fields: ctx, id, from, to
+func (_m *ArticleService) DiffRevisions(ctx context.Context, id int64, from int64, to int64) (domain.RevisionDiff, error) {
+	ret := _m.Called(ctx, id, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DiffRevisions")
+	}
+
+	var r0 domain.RevisionDiff
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64) (domain.RevisionDiff, error)); ok {
+		return rf(ctx, id, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64) domain.RevisionDiff); ok {
+		r0 = rf(ctx, id, from, to)
+	} else {
+		r0 = ret.Get(0).(domain.RevisionDiff)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, int64) error); ok {
+		r1 = rf(ctx, id, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Fetch provides a mock function with given fields: ctx, cursor, num, filter
+func (_m *ArticleService) Fetch(ctx context.Context, cursor string, num int64, filter domain.ArticleFilter) ([]domain.Article, string, string, error) {
+	ret := _m.Called(ctx, cursor, num, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Fetch")
@@ -42,31 +98,68 @@ func (_m *ArticleService) Fetch(ctx context.Context, cursor string, num int64) (
 
 	var r0 []domain.Article
 	var r1 string
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) ([]domain.Article, string, error)); ok {
-		return rf(ctx, cursor, num)
+	var r2 string
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, domain.ArticleFilter) ([]domain.Article, string, string, error)); ok {
+		return rf(ctx, cursor, num, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []domain.Article); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, domain.ArticleFilter) []domain.Article); ok {
+		r0 = rf(ctx, cursor, num, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Article)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, domain.ArticleFilter) string); ok {
+		r1 = rf(ctx, cursor, num, filter)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(2).(func(context.Context, string, int64, domain.ArticleFilter) string); ok {
+		r2 = rf(ctx, cursor, num, filter)
 	} else {
-		r2 = ret.Error(2)
+		r2 = ret.Get(2).(string)
 	}
 
-	return r0, r1, r2
+	if rf, ok := ret.Get(3).(func(context.Context, string, int64, domain.ArticleFilter) error); ok {
+		r3 = rf(ctx, cursor, num, filter)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// FuzzySearch provides a mock function with given fields: ctx, query, num
+func (_m *ArticleService) FuzzySearch(ctx context.Context, query string, num int64) ([]domain.Article, error) {
+	ret := _m.Called(ctx, query, num)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FuzzySearch")
+	}
+
+	var r0 []domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) ([]domain.Article, error)); ok {
+		return rf(ctx, query, num)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []domain.Article); ok {
+		r0 = rf(ctx, query, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, query, num)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // GetByID provides a mock function with given fields: ctx, id
@@ -97,6 +190,64 @@ func (_m *ArticleService) GetByID(ctx context.Context, id int64) (domain.Article
 	return r0, r1
 }
 
+// GetByIDs provides a mock function with given fields: ctx, ids
+func (_m *ArticleService) GetByIDs(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByIDs")
+	}
+
+	var r0 []domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) ([]domain.Article, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []domain.Article); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBySlug provides a mock function with given fields: ctx, slug
+func (_m *ArticleService) GetBySlug(ctx context.Context, slug string) (domain.Article, error) {
+	ret := _m.Called(ctx, slug)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBySlug")
+	}
+
+	var r0 domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Article, error)); ok {
+		return rf(ctx, slug)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Article); ok {
+		r0 = rf(ctx, slug)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByTitle provides a mock function with given fields: ctx, title
 func (_m *ArticleService) GetByTitle(ctx context.Context, title string) (domain.Article, error) {
 	ret := _m.Called(ctx, title)
@@ -125,6 +276,222 @@ func (_m *ArticleService) GetByTitle(ctx context.Context, title string) (domain.
 	return r0, r1
 }
 
+// GetRelated provides a mock function with given fields: ctx, id, num
+func (_m *ArticleService) GetRelated(ctx context.Context, id int64, num int64) ([]domain.Article, error) {
+	ret := _m.Called(ctx, id, num)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRelated")
+	}
+
+	var r0 []domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) ([]domain.Article, error)); ok {
+		return rf(ctx, id, num)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []domain.Article); ok {
+		r0 = rf(ctx, id, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, num)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IncrementViews provides a mock function with given fields: ctx, id
+func (_m *ArticleService) IncrementViews(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementViews")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListRevisions provides a mock function with given fields: ctx, id
+func (_m *ArticleService) ListRevisions(ctx context.Context, id int64) ([]domain.Revision, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRevisions")
+	}
+
+	var r0 []domain.Revision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]domain.Revision, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []domain.Revision); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Revision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OffsetFetch provides a mock function with given fields: ctx, page, perPage, status
+func (_m *ArticleService) OffsetFetch(ctx context.Context, page int64, perPage int64, status domain.Status) ([]domain.Article, int64, error) {
+	ret := _m.Called(ctx, page, perPage, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OffsetFetch")
+	}
+
+	var r0 []domain.Article
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, domain.Status) ([]domain.Article, int64, error)); ok {
+		return rf(ctx, page, perPage, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, domain.Status) []domain.Article); ok {
+		r0 = rf(ctx, page, perPage, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, domain.Status) int64); ok {
+		r1 = rf(ctx, page, perPage, status)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, int64, domain.Status) error); ok {
+		r2 = rf(ctx, page, perPage, status)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Publish provides a mock function with given fields: ctx, id
+func (_m *ArticleService) Publish(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RenderHTML provides a mock function with given fields: content
+func (_m *ArticleService) RenderHTML(content string) (string, error) {
+	ret := _m.Called(content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenderHTML")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(content)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(content)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(content)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *ArticleService) Restore(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: ctx, query, num, cursor, status
+func (_m *ArticleService) Search(ctx context.Context, query string, num int64, cursor string, status domain.Status) ([]domain.Article, string, error) {
+	ret := _m.Called(ctx, query, num, cursor, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 []domain.Article
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, domain.Status) ([]domain.Article, string, error)); ok {
+		return rf(ctx, query, num, cursor, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, domain.Status) []domain.Article); ok {
+		r0 = rf(ctx, query, num, cursor, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, string, domain.Status) string); ok {
+		r1 = rf(ctx, query, num, cursor, status)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, int64, string, domain.Status) error); ok {
+		r2 = rf(ctx, query, num, cursor, status)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Store provides a mock function with given fields: _a0, _a1
 func (_m *ArticleService) Store(_a0 context.Context, _a1 *domain.Article) error {
 	ret := _m.Called(_a0, _a1)
@@ -143,6 +510,42 @@ func (_m *ArticleService) Store(_a0 context.Context, _a1 *domain.Article) error
 	return r0
 }
 
+// StoreBatch provides a mock function with given fields: ctx, list
+func (_m *ArticleService) StoreBatch(ctx context.Context, list []*domain.Article) error {
+	ret := _m.Called(ctx, list)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StoreBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*domain.Article) error); ok {
+		r0 = rf(ctx, list)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Unpublish provides a mock function with given fields: ctx, id
+func (_m *ArticleService) Unpublish(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unpublish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, ar
 func (_m *ArticleService) Update(ctx context.Context, ar *domain.Article) error {
 	ret := _m.Called(ctx, ar)
@@ -161,6 +564,34 @@ func (_m *ArticleService) Update(ctx context.Context, ar *domain.Article) error
 	return r0
 }
 
+// Upsert provides a mock function with given fields: ctx, ar
+func (_m *ArticleService) Upsert(ctx context.Context, ar *domain.Article) (bool, error) {
+	ret := _m.Called(ctx, ar)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Upsert")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Article) (bool, error)); ok {
+		return rf(ctx, ar)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Article) bool); ok {
+		r0 = rf(ctx, ar)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Article) error); ok {
+		r1 = rf(ctx, ar)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewArticleService creates a new instance of ArticleService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewArticleService(t interface {
@@ -0,0 +1,95 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "apismrtbiz/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FavoriteService is an autogenerated mock type for the FavoriteService type
+type FavoriteService struct {
+	mock.Mock
+}
+
+// Favorite provides a mock function with given fields: ctx, userID, articleID
+func (_m *FavoriteService) Favorite(ctx context.Context, userID string, articleID int64) error {
+	ret := _m.Called(ctx, userID, articleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Favorite")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = rf(ctx, userID, articleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Status provides a mock function with given fields: ctx, userID, articleIDs
+func (_m *FavoriteService) Status(ctx context.Context, userID string, articleIDs []int64) (map[int64]domain.FavoriteStatus, error) {
+	ret := _m.Called(ctx, userID, articleIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Status")
+	}
+
+	var r0 map[int64]domain.FavoriteStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []int64) (map[int64]domain.FavoriteStatus, error)); ok {
+		return rf(ctx, userID, articleIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []int64) map[int64]domain.FavoriteStatus); ok {
+		r0 = rf(ctx, userID, articleIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int64]domain.FavoriteStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []int64) error); ok {
+		r1 = rf(ctx, userID, articleIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unfavorite provides a mock function with given fields: ctx, userID, articleID
+func (_m *FavoriteService) Unfavorite(ctx context.Context, userID string, articleID int64) error {
+	ret := _m.Called(ctx, userID, articleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unfavorite")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = rf(ctx, userID, articleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewFavoriteService creates a new instance of FavoriteService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFavoriteService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FavoriteService {
+	mock := &FavoriteService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,79 @@
+package rest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/rest"
+	"apismrtbiz/internal/rest/mocks"
+)
+
+// TestMocks will fail to compile once a mock falls behind its interface, rerun go generate then
+func TestMocks(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("ArticleService", func(t *testing.T) {
+		m := new(mocks.ArticleService)
+		var svc rest.ArticleService = m
+		m.On("Count", mock.Anything, domain.StatusPublished).Return(int64(2), nil).Once()
+		m.On("Search", mock.Anything, "go", int64(10), "", domain.StatusPublished).Return([]domain.Article{{ID: 1}}, "next", nil).Once()
+		m.On("GetByIDs", mock.Anything, []int64{1, 2}).Return([]domain.Article{{ID: 1}, {ID: 2}}, nil).Once()
+		m.On("StoreBatch", mock.Anything, mock.AnythingOfType("[]*domain.Article")).Return(nil).Once()
+
+		total, err := svc.Count(ctx, domain.StatusPublished)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		list, next, err := svc.Search(ctx, "go", 10, "", domain.StatusPublished)
+		require.NoError(t, err)
+		assert.Len(t, list, 1)
+		assert.Equal(t, "next", next)
+		list, err = svc.GetByIDs(ctx, []int64{1, 2})
+		require.NoError(t, err)
+		assert.Len(t, list, 2)
+		require.NoError(t, svc.StoreBatch(ctx, []*domain.Article{{Title: "Hello"}}))
+		m.AssertExpectations(t)
+	})
+
+	t.Run("CommentService", func(t *testing.T) {
+		m := new(mocks.CommentService)
+		var svc rest.CommentService = m
+		m.On("Store", mock.Anything, mock.AnythingOfType("*domain.Comment")).Return(nil).Once()
+
+		require.NoError(t, svc.Store(ctx, &domain.Comment{ArticleID: 1}))
+		m.AssertExpectations(t)
+	})
+
+	t.Run("FavoriteService", func(t *testing.T) {
+		m := new(mocks.FavoriteService)
+		var svc rest.FavoriteService = m
+		m.On("Favorite", mock.Anything, "user", int64(1)).Return(nil).Once()
+
+		require.NoError(t, svc.Favorite(ctx, "user", 1))
+		m.AssertExpectations(t)
+	})
+
+	t.Run("CategoryService", func(t *testing.T) {
+		m := new(mocks.CategoryService)
+		var svc rest.CategoryService = m
+		m.On("GetByID", mock.Anything, int64(1)).Return(domain.Category{ID: 1}, nil).Once()
+
+		c, err := svc.GetByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), c.ID)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("HealthChecker", func(t *testing.T) {
+		m := new(mocks.HealthChecker)
+		var checker rest.HealthChecker = m
+		m.On("Ping", mock.Anything).Return(nil).Once()
+
+		require.NoError(t, checker.Ping(ctx))
+		m.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"encoding/xml"
+
+	"github.com/gofiber/fiber/v2"
+
+	"apismrtbiz/domain"
+)
+
+// articleList represent a list of articles as an XML document
+type articleList struct {
+	XMLName  xml.Name         `xml:"articles"`
+	Articles []domain.Article `xml:"article"`
+}
+
+// bulkResultList represent the results of a bulk request as an XML document
+type bulkResultList struct {
+	XMLName xml.Name     `xml:"results"`
+	Results []BulkResult `xml:"result"`
+}
+
+// revisionList represent the revisions of an article as an XML document
+type revisionList struct {
+	XMLName   xml.Name          `xml:"revisions"`
+	Revisions []domain.Revision `xml:"revision"`
+}
+
+// commentList represent the comments of an article as an XML document
+type commentList struct {
+	XMLName  xml.Name         `xml:"comments"`
+	Comments []domain.Comment `xml:"comment"`
+}
+
+// categoryList represent the categories as an XML document
+type categoryList struct {
+	XMLName    xml.Name          `xml:"categories"`
+	Categories []domain.Category `xml:"category"`
+}
+
+// wantsXML will report whether the Accept header prefers XML over JSON, JSON is the default
+func wantsXML(c *fiber.Ctx) bool {
+	switch c.Accepts(fiber.MIMEApplicationJSON, fiber.MIMEApplicationXML, fiber.MIMETextXML) {
+	case fiber.MIMEApplicationXML, fiber.MIMETextXML:
+		return true
+	default:
+		return false
+	}
+}
+
+// send will write the value in the representation negotiated from the Accept header,
+// the articles are written as JSON:API documents when the client asks for them
+func send(c *fiber.Ctx, v interface{}) error {
+	if wantsJSONAPI(c) {
+		if ok, err := sendJSONAPI(c, v); ok {
+			return err
+		}
+	}
+	if !wantsXML(c) {
+		return c.JSON(v)
+	}
+
+	// a list has no root element of its own in XML
+	switch list := v.(type) {
+	case []domain.Article:
+		v = articleList{Articles: list}
+	case []BulkResult:
+		v = bulkResultList{Results: list}
+	case []domain.Revision:
+		v = revisionList{Revisions: list}
+	case []domain.Comment:
+		v = commentList{Comments: list}
+	case []domain.Category:
+		v = categoryList{Categories: list}
+	}
+	return c.XML(v)
+}
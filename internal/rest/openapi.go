@@ -0,0 +1,387 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+	"github.com/gofiber/fiber/v2"
+
+	"apismrtbiz/domain"
+)
+
+// swaggerUI is the page of /docs, it renders /openapi.json with the Swagger UI bundle
+const swaggerUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>apismrtbiz API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>window.onload = () => { window.ui = SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"}) }</script>
+</body>
+</html>`
+
+// operation documents a route of the handlers, every route registered by NewArticleHandler
+// must be listed in articleOperations, every one of NewCommentHandler in commentOperations,
+// every one of NewFavoriteHandler in favoriteOperations and every one of NewCategoryHandler in categoryOperations
+type operation struct {
+	method  string
+	path    string
+	summary string
+	params  []*openapi3.Parameter
+	// body is the schema name of the JSON request body, if any
+	body string
+	// responses are the schema names of the JSON responses by status, an empty name has no body
+	responses map[int]string
+}
+
+// the schemas of the request and response bodies, generated from their types
+var schemaValues = map[string]interface{}{
+	"Article":         domain.Article{},
+	"ArticleList":     []domain.Article{},
+	"ListEnvelope":    ListEnvelope{},
+	"BulkResult":      []BulkResult{},
+	"ImportSummary":   ImportSummary{},
+	"Count":           CountResponse{},
+	"RevisionList":    []domain.Revision{},
+	"RevisionDiff":    domain.RevisionDiff{},
+	"ArticlePatch":    articlePatch{},
+	"Comment":         domain.Comment{},
+	"CommentList":     []domain.Comment{},
+	"Category":        domain.Category{},
+	"CategoryList":    []domain.Category{},
+	"Error":           errRep{},
+	"ValidationError": ValidationError{},
+}
+
+func pathParam(name, description string) *openapi3.Parameter {
+	return openapi3.NewPathParameter(name).WithDescription(description).WithSchema(openapi3.NewInt64Schema())
+}
+
+func queryParam(name, description string, schema *openapi3.Schema) *openapi3.Parameter {
+	return openapi3.NewQueryParameter(name).WithDescription(description).WithSchema(schema)
+}
+
+// statusSchema lists the values of the status param, all covers every status
+var statusSchema = openapi3.NewStringSchema().WithEnum(string(domain.StatusDraft), string(domain.StatusPublished), "all")
+
+var (
+	idParam         = pathParam("id", "id of the article")
+	categoryIDParam = pathParam("id", "id of the category")
+	cursorParam     = queryParam("cursor", "opaque cursor of the page, given by X-Cursor", openapi3.NewStringSchema())
+	numParam        = queryParam("num", "size of the page", openapi3.NewIntegerSchema())
+	fieldsParam     = queryParam("fields", "comma separated fields of the article to return", openapi3.NewStringSchema())
+	embedParam      = queryParam("embed", "embed the author details in the v2 articles instead of their author_id, the v1 articles always hold them", openapi3.NewStringSchema().WithEnum(embedAuthor))
+	hateoasParam    = queryParam("hateoas", "add the _links of the actions on the articles", openapi3.NewBoolSchema())
+	statusParam     = queryParam("status", "status of the articles, published unless an editor asks for the drafts or all", statusSchema)
+	dryRunParam     = queryParam("dry_run", "check the article and answer it with a 200 without saving it", openapi3.NewBoolSchema())
+	ifMatchParam    = openapi3.NewHeaderParameter(fiber.HeaderIfMatch).
+			WithDescription("ETag of the article as it was read, the update is refused with a 412 when it has changed since").
+			WithSchema(openapi3.NewStringSchema())
+)
+
+var articleOperations = []operation{
+	{
+		method: http.MethodGet, path: "/articles", summary: "List the articles a page at a time",
+		params: []*openapi3.Parameter{
+			cursorParam, numParam, fieldsParam, embedParam, hateoasParam,
+			queryParam("ids", "comma separated ids of the articles to get, the drafts are left out unless an editor asks", openapi3.NewStringSchema()),
+			queryParam("page", "page of the offset pagination", openapi3.NewIntegerSchema().WithMin(1).WithMax(maxPage)),
+			queryParam("per_page", "size of the page of the offset pagination", openapi3.NewIntegerSchema()),
+			queryParam("sort", "sort field", openapi3.NewStringSchema().WithEnum(string(domain.SortByUpdatedAt), string(domain.SortByCreatedAt), string(domain.SortByTitle))),
+			queryParam("order", "sort order", openapi3.NewStringSchema().WithEnum("asc", "desc")),
+			queryParam("author_id", "id of the author", openapi3.NewInt64Schema()),
+			queryParam("category_id", "id of the category", openapi3.NewInt64Schema()),
+			queryParam("tag", "tag of the articles", openapi3.NewStringSchema()),
+			statusParam,
+			queryParam("include_deleted", "include the soft deleted articles, for an editor only", openapi3.NewBoolSchema()),
+			queryParam("created_from", "lower bound of the creation time", openapi3.NewDateTimeSchema()),
+			queryParam("created_to", "upper bound of the creation time", openapi3.NewDateTimeSchema()),
+			queryParam("direction", "direction of the page from the cursor", openapi3.NewStringSchema().WithEnum("forward", "backward")),
+			queryParam("envelope", "wrap the page with its metadata", openapi3.NewBoolSchema()),
+		},
+		responses: map[int]string{http.StatusOK: "ArticleList", http.StatusBadRequest: "Error", http.StatusForbidden: "Error"},
+	},
+	{
+		method: http.MethodPost, path: "/articles", summary: "Create an article",
+		params:    []*openapi3.Parameter{openapi3.NewHeaderParameter(HeaderIdempotencyKey).WithSchema(openapi3.NewStringSchema()), dryRunParam},
+		body:      "Article",
+		responses: map[int]string{http.StatusOK: "Article", http.StatusCreated: "Article", http.StatusConflict: "Error", http.StatusUnprocessableEntity: "ValidationError"},
+	},
+	{
+		method: http.MethodPost, path: "/articles/bulk", summary: "Create a list of articles",
+		body:      "ArticleList",
+		responses: map[int]string{http.StatusCreated: "BulkResult", http.StatusMultiStatus: "BulkResult", http.StatusUnprocessableEntity: "BulkResult"},
+	},
+	{
+		method: http.MethodPost, path: "/articles/import", summary: "Create the articles of an uploaded CSV file",
+		responses: map[int]string{http.StatusCreated: "ImportSummary", http.StatusMultiStatus: "ImportSummary", http.StatusBadRequest: "Error"},
+	},
+	{
+		method: http.MethodGet, path: "/articles/count", summary: "Count the articles",
+		params:    []*openapi3.Parameter{statusParam},
+		responses: map[int]string{http.StatusOK: "Count", http.StatusForbidden: "Error"},
+	},
+	{
+		method: http.MethodGet, path: "/articles/export.csv", summary: "Export the articles as CSV",
+		responses: map[int]string{http.StatusOK: ""},
+	},
+	{
+		method: http.MethodGet, path: "/articles/search", summary: "Get an article by title or search the articles",
+		params: []*openapi3.Parameter{
+			queryParam("title", "title of the article", openapi3.NewStringSchema()),
+			queryParam("q", "text searched in the title and the content", openapi3.NewStringSchema()),
+			queryParam("fuzzy", "rank the approximate matches of q in the titles by their search_score, without a cursor, rate limited", openapi3.NewBoolSchema()),
+			cursorParam, numParam, statusParam, fieldsParam, embedParam, hateoasParam,
+		},
+		responses: map[int]string{
+			http.StatusOK: "Article", http.StatusBadRequest: "Error", http.StatusForbidden: "Error", http.StatusNotFound: "Error",
+			http.StatusTooManyRequests: "Error",
+		},
+	},
+	{
+		method: http.MethodGet, path: "/articles/slug/{slug}", summary: "Get an article by its slug",
+		params: []*openapi3.Parameter{
+			openapi3.NewPathParameter("slug").WithDescription("slug of the article").WithSchema(openapi3.NewStringSchema()),
+			fieldsParam, embedParam, hateoasParam,
+		},
+		responses: map[int]string{http.StatusOK: "Article", http.StatusNotFound: "Error"},
+	},
+	{
+		method: http.MethodGet, path: "/articles/{id}", summary: "Get an article",
+		params: []*openapi3.Parameter{
+			idParam, fieldsParam, embedParam, hateoasParam,
+			queryParam("format", "format of the content", openapi3.NewStringSchema().WithEnum(formatMarkdown, formatHTML)),
+		},
+		responses: map[int]string{http.StatusOK: "Article", http.StatusNotModified: "", http.StatusBadRequest: "Error", http.StatusNotFound: "Error"},
+	},
+	{
+		method: http.MethodGet, path: "/articles/{id}/related", summary: "List the articles related to an article",
+		params:    []*openapi3.Parameter{idParam, numParam, fieldsParam, embedParam, hateoasParam},
+		responses: map[int]string{http.StatusOK: "ArticleList", http.StatusNotFound: "Error"},
+	},
+	{
+		method: http.MethodGet, path: "/articles/{id}/revisions", summary: "List the revisions of an article, the newest first",
+		params:    []*openapi3.Parameter{idParam},
+		responses: map[int]string{http.StatusOK: "RevisionList", http.StatusNotFound: "Error"},
+	},
+	{
+		method: http.MethodGet, path: "/articles/{id}/revisions/diff", summary: "Compare two revisions of an article",
+		params: []*openapi3.Parameter{
+			idParam,
+			queryParam("from", "version of the first revision", openapi3.NewInt64Schema()).WithRequired(true),
+			queryParam("to", "version of the second revision", openapi3.NewInt64Schema()).WithRequired(true),
+		},
+		responses: map[int]string{http.StatusOK: "RevisionDiff", http.StatusBadRequest: "Error", http.StatusNotFound: "Error"},
+	},
+	{
+		method: http.MethodPut, path: "/articles/{id}", summary: "Update an article",
+		params: []*openapi3.Parameter{
+			idParam,
+			queryParam("upsert", "create the article under the id when it doesn't exist", openapi3.NewBoolSchema()),
+			ifMatchParam,
+			dryRunParam,
+		},
+		body: "Article",
+		responses: map[int]string{http.StatusOK: "Article", http.StatusCreated: "Article", http.StatusBadRequest: "Error", http.StatusNotFound: "Error", http.StatusConflict: "Error",
+			http.StatusPreconditionFailed: "Error", http.StatusUnprocessableEntity: "ValidationError", http.StatusPreconditionRequired: "Error"},
+	},
+	{
+		method: http.MethodPatch, path: "/articles/{id}", summary: "Update some fields of an article",
+		params: []*openapi3.Parameter{idParam, ifMatchParam, dryRunParam},
+		body:   "ArticlePatch",
+		responses: map[int]string{http.StatusOK: "Article", http.StatusNotFound: "Error", http.StatusConflict: "Error",
+			http.StatusPreconditionFailed: "Error", http.StatusUnprocessableEntity: "ValidationError", http.StatusPreconditionRequired: "Error"},
+	},
+	{
+		method: http.MethodDelete, path: "/articles/{id}", summary: "Delete an article",
+		params:    []*openapi3.Parameter{idParam},
+		responses: map[int]string{http.StatusNoContent: "", http.StatusBadRequest: "Error", http.StatusNotFound: "Error"},
+	},
+	{
+		method: http.MethodPost, path: "/articles/{id}/restore", summary: "Restore a deleted article",
+		params:    []*openapi3.Parameter{idParam},
+		responses: map[int]string{http.StatusNoContent: "", http.StatusNotFound: "Error", http.StatusConflict: "Error"},
+	},
+	{
+		method: http.MethodPost, path: "/articles/{id}/publish", summary: "Publish a draft",
+		params:    []*openapi3.Parameter{idParam},
+		responses: map[int]string{http.StatusNoContent: "", http.StatusNotFound: "Error", http.StatusConflict: "Error"},
+	},
+	{
+		method: http.MethodPost, path: "/articles/{id}/unpublish", summary: "Take a published article back to draft",
+		params:    []*openapi3.Parameter{idParam},
+		responses: map[int]string{http.StatusNoContent: "", http.StatusNotFound: "Error", http.StatusConflict: "Error"},
+	},
+	{
+		method: http.MethodPost, path: "/articles/{id}/view", summary: "Count a view of an article",
+		params:    []*openapi3.Parameter{idParam},
+		responses: map[int]string{http.StatusNoContent: "", http.StatusNotFound: "Error"},
+	},
+}
+
+var commentOperations = []operation{
+	{
+		method: http.MethodGet, path: "/articles/{id}/comments", summary: "List the comments of an article a page at a time, the oldest first",
+		params:    []*openapi3.Parameter{idParam, cursorParam, numParam},
+		responses: map[int]string{http.StatusOK: "CommentList", http.StatusBadRequest: "Error", http.StatusNotFound: "Error"},
+	},
+	{
+		method: http.MethodPost, path: "/articles/{id}/comments", summary: "Comment an article",
+		params:    []*openapi3.Parameter{idParam},
+		body:      "Comment",
+		responses: map[int]string{http.StatusCreated: "Comment", http.StatusNotFound: "Error", http.StatusUnprocessableEntity: "ValidationError"},
+	},
+}
+
+var favoriteOperations = []operation{
+	{
+		method: http.MethodPost, path: "/articles/{id}/favorite", summary: "Favorite an article as the authenticated user",
+		params:    []*openapi3.Parameter{idParam},
+		responses: map[int]string{http.StatusNoContent: "", http.StatusUnauthorized: "Error", http.StatusNotFound: "Error"},
+	},
+	{
+		method: http.MethodDelete, path: "/articles/{id}/favorite", summary: "Unfavorite an article as the authenticated user",
+		params:    []*openapi3.Parameter{idParam},
+		responses: map[int]string{http.StatusNoContent: "", http.StatusUnauthorized: "Error", http.StatusNotFound: "Error"},
+	},
+}
+
+var categoryOperations = []operation{
+	{
+		method: http.MethodGet, path: "/categories", summary: "List the categories by name",
+		responses: map[int]string{http.StatusOK: "CategoryList"},
+	},
+	{
+		method: http.MethodPost, path: "/categories", summary: "Create a category",
+		body:      "Category",
+		responses: map[int]string{http.StatusCreated: "Category", http.StatusUnprocessableEntity: "ValidationError"},
+	},
+	{
+		method: http.MethodGet, path: "/categories/{id}", summary: "Get a category by id",
+		params:    []*openapi3.Parameter{categoryIDParam},
+		responses: map[int]string{http.StatusOK: "Category", http.StatusNotFound: "Error"},
+	},
+	{
+		method: http.MethodPut, path: "/categories/{id}", summary: "Rename a category",
+		params:    []*openapi3.Parameter{categoryIDParam},
+		body:      "Category",
+		responses: map[int]string{http.StatusOK: "Category", http.StatusBadRequest: "Error", http.StatusNotFound: "Error", http.StatusUnprocessableEntity: "ValidationError"},
+	},
+	{
+		method: http.MethodDelete, path: "/categories/{id}", summary: "Delete a category no article is filed under",
+		params:    []*openapi3.Parameter{categoryIDParam},
+		responses: map[int]string{http.StatusNoContent: "", http.StatusNotFound: "Error", http.StatusConflict: "Error"},
+	},
+}
+
+// specDescription tells the versions apart, the routes are described in the v1 shape
+const specDescription = "The articles API, the routes are served under /v1 and /v2 as well. " +
+	"The /v2 articles are written as JSON only, XML and JSON:API are answered with 406 there."
+
+// NewOpenAPISpec will describe the article, comment, favorite and category routes as an OpenAPI 3 document,
+// the schemas are generated from the types of the bodies
+func NewOpenAPISpec() (*openapi3.T, error) {
+	doc := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info:    &openapi3.Info{Title: "apismrtbiz", Version: "1.0.0", Description: specDescription},
+		Paths:   openapi3.NewPaths(),
+		Components: &openapi3.Components{
+			Schemas: openapi3.Schemas{},
+		},
+	}
+
+	for name, value := range schemaValues {
+		ref, err := openapi3gen.NewSchemaRefForValue(value, nil, openapi3gen.UseAllExportedFields())
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+		doc.Components.Schemas[name] = ref
+	}
+
+	for _, op := range concatOperations(articleOperations, commentOperations, favoriteOperations, categoryOperations) {
+		o := openapi3.NewOperation()
+		o.Summary = op.summary
+		o.OperationID = operationID(op.method, op.path)
+		for _, p := range op.params {
+			o.AddParameter(p)
+		}
+		if op.body != "" {
+			o.RequestBody = &openapi3.RequestBodyRef{
+				Value: openapi3.NewRequestBody().WithRequired(true).WithJSONSchemaRef(schemaRef(op.body)),
+			}
+		}
+		for status, name := range op.responses {
+			res := openapi3.NewResponse().WithDescription(http.StatusText(status))
+			if name != "" {
+				res.WithJSONSchemaRef(schemaRef(name))
+			}
+			o.AddResponse(status, res)
+		}
+		doc.AddOperation(op.path, op.method, o)
+	}
+	return doc, nil
+}
+
+func schemaRef(name string) *openapi3.SchemaRef {
+	return openapi3.NewSchemaRef("#/components/schemas/"+name, nil)
+}
+
+// operationID will name the operation after its method and path, e.g. get-articles-id-revisions
+func operationID(method, path string) string {
+	parts := []string{strings.ToLower(method)}
+	for _, segment := range strings.Split(path, "/") {
+		if segment = strings.Trim(segment, "{}"); segment != "" {
+			parts = append(parts, strings.ReplaceAll(segment, ".", "-"))
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// DocsHandler represent the httphandler of the API description
+type DocsHandler struct {
+	spec []byte
+}
+
+// NewDocsHandler will serve the OpenAPI document on /openapi.json and the Swagger UI on /docs
+func NewDocsHandler(e *fiber.App) error {
+	doc, err := NewOpenAPISpec()
+	if err != nil {
+		return err
+	}
+	spec, err := doc.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	handler := &DocsHandler{spec: spec}
+	e.Get("/openapi.json", handler.Spec)
+	e.Get("/docs", handler.UI)
+	return nil
+}
+
+// Spec will send the OpenAPI document
+func (h *DocsHandler) Spec(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	return c.Send(h.spec)
+}
+
+// UI will send the Swagger UI page
+func (h *DocsHandler) UI(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.SendString(swaggerUI)
+}
+
+// concatOperations will join the operations of the handlers in the order they are given
+func concatOperations(groups ...[]operation) []operation {
+	var all []operation
+	for _, ops := range groups {
+		all = append(all, ops...)
+	}
+	return all
+}
@@ -0,0 +1,85 @@
+package rest_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/internal/rest"
+	"apismrtbiz/internal/rest/mocks"
+)
+
+func TestOpenAPISpec(t *testing.T) {
+	app := fiber.New()
+	require.NoError(t, rest.NewDocsHandler(app))
+
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	doc, err := openapi3.NewLoader().LoadFromData(body)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.TODO()))
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+
+	article := doc.Components.Schemas["Article"]
+	require.NotNil(t, article)
+	assert.Contains(t, article.Value.Properties, "title")
+	assert.Contains(t, article.Value.Properties, "reading_time_minutes")
+	assert.NotContains(t, article.Value.Properties, "XMLName")
+}
+
+// TestOpenAPISpecInSync fails when a route of the handlers is missing from the document, or the other way around
+func TestOpenAPISpecInSync(t *testing.T) {
+	doc, err := rest.NewOpenAPISpec()
+	require.NoError(t, err)
+	var documented []string
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			documented = append(documented, method+" "+strings.NewReplacer("{", ":", "}", "").Replace(path))
+		}
+	}
+	sort.Strings(documented)
+
+	app := fiber.New()
+	rest.NewArticleHandler(app, new(mocks.ArticleService), rest.HandlerConfig{})
+	rest.NewCommentHandler(app, new(mocks.CommentService))
+	rest.NewFavoriteHandler(app, new(mocks.FavoriteService))
+	rest.NewCategoryHandler(app, new(mocks.CategoryService))
+	var registered []string
+	for _, route := range app.GetRoutes(true) {
+		if route.Method != http.MethodHead {
+			registered = append(registered, route.Method+" "+route.Path)
+		}
+	}
+	sort.Strings(registered)
+
+	assert.Equal(t, registered, documented)
+}
+
+func TestDocsUI(t *testing.T) {
+	app := fiber.New()
+	require.NoError(t, rest.NewDocsHandler(app))
+
+	res, err := app.Test(httptest.NewRequest(http.MethodGet, "/docs", nil))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, res.Header.Get(fiber.HeaderContentType), fiber.MIMETextHTML)
+	assert.Contains(t, string(body), `url: "/openapi.json"`)
+}
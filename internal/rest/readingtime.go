@@ -0,0 +1,17 @@
+package rest
+
+import "strings"
+
+// defaultWordsPerMinute is the average reading speed of an adult
+const defaultWordsPerMinute = 200
+
+// readingTime will estimate in minutes how long the content takes to read, rounded up
+// so that any content takes at least a minute, an empty content takes none
+func (a *ArticleHandler) readingTime(content string) int {
+	wpm := a.Config.WordsPerMinute
+	if wpm <= 0 {
+		wpm = defaultWordsPerMinute
+	}
+	words := len(strings.Fields(content))
+	return (words + wpm - 1) / wpm
+}
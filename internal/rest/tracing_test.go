@@ -0,0 +1,107 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"apismrtbiz/article"
+	"apismrtbiz/domain"
+	mysqlRepo "apismrtbiz/internal/repository/mysql"
+	"apismrtbiz/internal/rest"
+	"apismrtbiz/internal/rest/middleware"
+	"apismrtbiz/internal/rest/mocks"
+)
+
+func setupTracing(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return recorder
+}
+
+func TestTracingGetByID(t *testing.T) {
+	recorder := setupTracing(t)
+
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+	dbMock.ExpectQuery("SELECT (.+) FROM article WHERE ID = \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "deleted_at", "version", "status", "publish_at", "view_count", "slug", "category_id", "tags"}).
+			AddRow(7, "Title", "Content", 1, time.Now(), time.Now(), nil, 1, "published", nil, 0, "title", nil, nil))
+	dbMock.ExpectPrepare("SELECT id, name, created_at, updated_at FROM author WHERE id=\\?").
+		ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).
+		AddRow(1, "Iman Tumorang", time.Now(), time.Now()))
+
+	svc := article.NewService(mysqlRepo.NewArticleRepository(db), mysqlRepo.NewAuthorRepository(db))
+	app := fiber.New()
+	app.Use(middleware.Tracing())
+	rest.NewArticleHandler(app, svc, rest.HandlerConfig{})
+
+	req := httptest.NewRequest(http.MethodGet, "/articles/7", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	res, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	require.Len(t, spans, 4)
+
+	server := spans["GET /articles/:id"]
+	require.NotNil(t, server)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+
+	usecase := spans["Service.GetByID"]
+	require.NotNil(t, usecase)
+	assert.Equal(t, server.SpanContext().SpanID(), usecase.Parent().SpanID())
+
+	for _, name := range []string{"ArticleRepository.GetByID", "AuthorRepository.GetByID"} {
+		repo := spans[name]
+		require.NotNil(t, repo, name)
+		assert.Equal(t, usecase.SpanContext().SpanID(), repo.Parent().SpanID(), name)
+		assert.Contains(t, repo.Attributes(), attribute.String("db.operation", "SELECT"), name)
+	}
+}
+
+func TestTracingFetchArticleAttributes(t *testing.T) {
+	recorder := setupTracing(t)
+
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("Fetch", mock.Anything, "abc", int64(5), mock.Anything).Return([]domain.Article{}, "", "", nil).Once()
+
+	app := fiber.New()
+	app.Use(middleware.Tracing())
+	rest.NewArticleHandler(app, mockUCase, rest.HandlerConfig{})
+
+	res := sendJSON(t, app, http.MethodGet, "/articles?cursor=abc&num=5", "")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /articles", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("article.cursor", "abc"))
+	assert.Contains(t, spans[0].Attributes(), attribute.Int("article.num", 5))
+}
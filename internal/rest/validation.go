@@ -0,0 +1,83 @@
+package rest
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"apismrtbiz/domain"
+)
+
+const validationErrMessage = "request body is not valid"
+
+var validate = newValidator()
+
+// FieldError represent a single invalid field of the request body
+type FieldError struct {
+	Field   string `json:"field" xml:"field"`
+	Tag     string `json:"tag" xml:"tag"`
+	Message string `json:"message" xml:"message"`
+}
+
+// ValidationError represent the response error of a request body that failed the validation
+type ValidationError struct {
+	XMLName xml.Name     `json:"-" xml:"error"`
+	Message string       `json:"message" xml:"message"`
+	Errors  []FieldError `json:"errors,omitempty" xml:"errors>error,omitempty"`
+}
+
+// NewValidationError will translate the error returned by the validator into a ValidationError,
+// an error which is not a validator.ValidationErrors results in the generic message only
+func NewValidationError(err error) ValidationError {
+	resp := ValidationError{Message: validationErrMessage}
+
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return resp
+	}
+
+	for _, fe := range errs {
+		resp.Errors = append(resp.Errors, FieldError{
+			Field:   fe.Field(),
+			Tag:     fe.Tag(),
+			Message: fieldErrMessage(fe),
+		})
+	}
+	return resp
+}
+
+func fieldErrMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", fe.Field())
+	case "max":
+		return fmt.Sprintf("%s must be at most %s characters long", fe.Field(), fe.Param())
+	default:
+		return fmt.Sprintf("%s failed on the %s rule", fe.Field(), fe.Tag())
+	}
+}
+
+// newValidator will create the validator which reports the fields by their json name
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+func isRequestValid(m *domain.Article) (bool, error) {
+	err := validate.Struct(m)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
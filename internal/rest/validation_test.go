@@ -0,0 +1,17 @@
+package rest_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"apismrtbiz/internal/rest"
+)
+
+func TestNewValidationErrorFallback(t *testing.T) {
+	got := rest.NewValidationError(errors.New("something else"))
+
+	assert.Equal(t, "request body is not valid", got.Message)
+	assert.Empty(t, got.Errors)
+}
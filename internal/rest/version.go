@@ -0,0 +1,141 @@
+package rest
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"apismrtbiz/domain"
+)
+
+// APIVersion is the shape the articles are written in, the requests are read in the same shape by every version
+type APIVersion string
+
+// The versions of the API
+const (
+	// APIVersion1 is the shape of domain.Article, the default one
+	APIVersion1 APIVersion = "v1"
+	// APIVersion2 renames the content to body and the publish time to published_at, groups the
+	// counters under stats and references the author by author_id unless ?embed=author is given
+	APIVersion2 APIVersion = "v2"
+)
+
+// errV2Representation answers the v2 requests asking for an other representation than JSON
+var errV2Representation = errors.New("the v2 articles are only served as JSON")
+
+// localsAPIVersion is the key of the version the routes of the articles are mounted for
+const localsAPIVersion = "apiVersion"
+
+// ArticleV2 represent an article in the v2 shape
+type ArticleV2 struct {
+	ID          int64          `json:"id"`
+	Title       string         `json:"title"`
+	Slug        string         `json:"slug"`
+	Body        string         `json:"body"`
+	AuthorID    int64          `json:"author_id"`
+	Author      *domain.Author `json:"author,omitempty"`
+	Status      domain.Status  `json:"status"`
+	Tags        []string       `json:"tags"`
+	CategoryID  *int64         `json:"category_id"`
+	PublishedAt *time.Time     `json:"published_at"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   *time.Time     `json:"deleted_at,omitempty"`
+	Version     int64          `json:"version"`
+	Stats       ArticleStats   `json:"stats"`
+	Favorited   *bool          `json:"favorited,omitempty"`
+	SearchScore *float64       `json:"search_score,omitempty"`
+	Highlight   string         `json:"highlight,omitempty"`
+	Links       *ArticleLinks  `json:"_links,omitempty"`
+}
+
+// ArticleStats represent the counters of an article in the v2 shape
+type ArticleStats struct {
+	Views              int64  `json:"views"`
+	ReadingTimeMinutes int    `json:"reading_time_minutes"`
+	Favorites          *int64 `json:"favorites,omitempty"`
+}
+
+// newArticleV2 will map the article to the v2 shape, the author details are kept when they are embedded
+func newArticleV2(ar domain.Article, embedded map[string]bool) ArticleV2 {
+	v2 := ArticleV2{
+		ID:          ar.ID,
+		Title:       ar.Title,
+		Slug:        ar.Slug,
+		Body:        ar.Content,
+		AuthorID:    ar.Author.ID,
+		Status:      ar.Status,
+		Tags:        ar.Tags,
+		PublishedAt: ar.PublishAt,
+		CreatedAt:   ar.CreatedAt,
+		UpdatedAt:   ar.UpdatedAt,
+		DeletedAt:   ar.DeletedAt,
+		Version:     ar.Version,
+		Stats: ArticleStats{
+			Views:              ar.ViewCount,
+			ReadingTimeMinutes: ar.ReadingTimeMinutes,
+			Favorites:          ar.FavoritesCount,
+		},
+		Favorited:   ar.Favorited,
+		SearchScore: ar.SearchScore,
+		Highlight:   ar.Highlight,
+	}
+	if v2.Tags == nil {
+		v2.Tags = []string{}
+	}
+	if ar.CategoryID != 0 {
+		v2.CategoryID = &ar.CategoryID
+	}
+	if embedded[embedAuthor] {
+		author := ar.Author
+		v2.Author = &author
+	}
+	return v2
+}
+
+// apiVersion is the version of the routes the request was routed to
+func apiVersion(c *fiber.Ctx) APIVersion {
+	if version, ok := c.Locals(localsAPIVersion).(APIVersion); ok {
+		return version
+	}
+	return APIVersion1
+}
+
+// acceptsVersion will report whether the representation asked by the Accept header has a mapper in
+// the version of the request. The v2 shape is written as JSON only, the XML and JSON:API documents
+// are the v1 ones and would silently answer in the wrong shape.
+func acceptsVersion(c *fiber.Ctx) bool {
+	return apiVersion(c) != APIVersion2 || !(wantsXML(c) || wantsJSONAPI(c))
+}
+
+// present will map an article or every article of a list to the shape of the version of the request,
+// along their links when the request asks for them. Any other value is returned as is.
+func present(c *fiber.Ctx, v interface{}) interface{} {
+	if apiVersion(c) != APIVersion2 {
+		return withLinks(c, v)
+	}
+
+	linked := wantsLinks(c)
+	// the embed param is checked before the articles are presented
+	embedded, _ := parseEmbed(c.Query("embed"))
+	toV2 := func(ar domain.Article) ArticleV2 {
+		v2 := newArticleV2(ar, embedded)
+		if linked {
+			links := newArticleLinks(c, ar.ID)
+			v2.Links = &links
+		}
+		return v2
+	}
+	switch t := v.(type) {
+	case domain.Article:
+		return toV2(t)
+	case []domain.Article:
+		list := make([]ArticleV2, len(t))
+		for i, ar := range t {
+			list[i] = toV2(ar)
+		}
+		return list
+	}
+	return v
+}
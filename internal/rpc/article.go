@@ -0,0 +1,212 @@
+// Package rpc serves the article usecases over gRPC, see articlepb/article.proto
+package rpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/rest/middleware"
+	"apismrtbiz/internal/rpc/articlepb"
+)
+
+const (
+	defaultNum = 10
+	maxNum     = 100
+)
+
+var validate = validator.New()
+
+// ArticleService represent the article's usecases served over gRPC
+//
+//go:generate mockery --name ArticleService
+type ArticleService interface {
+	Fetch(ctx context.Context, cursor string, num int64, filter domain.ArticleFilter) ([]domain.Article, string, string, error)
+	GetByID(ctx context.Context, id int64) (domain.Article, error)
+	Store(context.Context, *domain.Article) error
+	Update(ctx context.Context, ar *domain.Article) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// ArticleServer represent the gRPC handler for article
+type ArticleServer struct {
+	articlepb.UnimplementedArticleServiceServer
+	Service ArticleService
+}
+
+// NewArticleServer will register the article service on the gRPC server
+func NewArticleServer(s grpc.ServiceRegistrar, svc ArticleService) {
+	articlepb.RegisterArticleServiceServer(s, &ArticleServer{Service: svc})
+}
+
+func (a *ArticleServer) Fetch(ctx context.Context, req *articlepb.FetchRequest) (*articlepb.FetchResponse, error) {
+	num := req.GetNum()
+	switch {
+	case num < 0:
+		return nil, status.Error(codes.InvalidArgument, "num must not be negative")
+	case num == 0:
+		num = defaultNum
+	case num > maxNum:
+		num = maxNum
+	}
+
+	filter := domain.ArticleFilter{Status: domain.Status(req.GetStatus())}
+	if filter.Status == "" {
+		filter.Status = domain.StatusPublished
+	}
+	if !filter.Status.Valid() {
+		return nil, status.Error(codes.InvalidArgument, "status must be one of draft, published")
+	}
+	if filter.Status != domain.StatusPublished && !isEditor(ctx) {
+		return nil, status.Error(codes.PermissionDenied, "only an authenticated editor can list the drafts")
+	}
+
+	list, nextCursor, prevCursor, err := a.Service.Fetch(ctx, req.GetCursor(), num, filter)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	res := &articlepb.FetchResponse{NextCursor: nextCursor, PrevCursor: prevCursor}
+	for _, ar := range list {
+		res.Articles = append(res.Articles, toProto(ar))
+	}
+	return res, nil
+}
+
+func (a *ArticleServer) GetByID(ctx context.Context, req *articlepb.GetByIDRequest) (*articlepb.Article, error) {
+	ar, err := a.Service.GetByID(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	// the drafts are hidden from the public like over REST
+	if ar.Status != domain.StatusPublished && !isEditor(ctx) {
+		return nil, status.Error(codes.NotFound, domain.ErrNotFound.Error())
+	}
+	return toProto(ar), nil
+}
+
+func (a *ArticleServer) Store(ctx context.Context, req *articlepb.StoreRequest) (*articlepb.Article, error) {
+	ar := domain.Article{
+		Title:     req.GetTitle(),
+		Content:   req.GetContent(),
+		Author:    domain.Author{ID: req.GetAuthorId()},
+		Status:    domain.Status(req.GetStatus()),
+		PublishAt: fromTimestamp(req.GetPublishAt()),
+		Tags:      req.GetTags(),
+	}
+	if ar.Status != "" && !ar.Status.Valid() {
+		return nil, status.Error(codes.InvalidArgument, "status must be one of draft, published")
+	}
+	if err := validate.Struct(ar); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := a.Service.Store(ctx, &ar); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toProto(ar), nil
+}
+
+func (a *ArticleServer) Update(ctx context.Context, req *articlepb.UpdateRequest) (*articlepb.Article, error) {
+	ar := domain.Article{
+		ID:      req.GetId(),
+		Title:   req.GetTitle(),
+		Content: req.GetContent(),
+		Tags:    req.GetTags(),
+		Version: req.GetVersion(),
+	}
+	if err := validate.Struct(ar); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if ar.Version == 0 {
+		return nil, status.Error(codes.InvalidArgument, "version is required")
+	}
+
+	if err := a.Service.Update(ctx, &ar); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return toProto(ar), nil
+}
+
+func (a *ArticleServer) Delete(ctx context.Context, req *articlepb.DeleteRequest) (*articlepb.DeleteResponse, error) {
+	if err := a.Service.Delete(ctx, req.GetId()); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &articlepb.DeleteResponse{}, nil
+}
+
+// isEditor will report whether the call is authenticated, by a bearer token or an api key
+func isEditor(ctx context.Context) bool {
+	if _, ok := middleware.UserIDFromContext(ctx); ok {
+		return true
+	}
+	_, ok := middleware.APIKeyLabelFromContext(ctx)
+	return ok
+}
+
+// toStatus will translate the domain errors to the gRPC status codes, the unexpected
+// errors are logged and reported as internal without leaking their message
+func toStatus(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return status.Error(codes.NotFound, domain.ErrNotFound.Error())
+	case errors.Is(err, domain.ErrConflict):
+		return status.Error(codes.AlreadyExists, domain.ErrConflict.Error())
+	case errors.Is(err, domain.ErrBadParamInput):
+		return status.Error(codes.InvalidArgument, domain.ErrBadParamInput.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		logrus.WithContext(ctx).Error(err)
+		return status.Error(codes.Internal, domain.ErrInternalServerError.Error())
+	}
+}
+
+func toProto(ar domain.Article) *articlepb.Article {
+	return &articlepb.Article{
+		Id:        ar.ID,
+		Title:     ar.Title,
+		Content:   ar.Content,
+		Author:    &articlepb.Author{Id: ar.Author.ID, Name: ar.Author.Name},
+		UpdatedAt: toTimestamp(ar.UpdatedAt),
+		CreatedAt: toTimestamp(ar.CreatedAt),
+		Version:   ar.Version,
+		Status:    string(ar.Status),
+		PublishAt: toTimestampPtr(ar.PublishAt),
+		ViewCount: ar.ViewCount,
+		Tags:      ar.Tags,
+	}
+}
+
+// toTimestamp will leave a zero time unset
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func toTimestampPtr(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return toTimestamp(*t)
+}
+
+func fromTimestamp(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
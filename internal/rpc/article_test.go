@@ -0,0 +1,142 @@
+package rpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/rpc"
+	"apismrtbiz/internal/rpc/articlepb"
+	"apismrtbiz/internal/rpc/mocks"
+)
+
+// newClient will serve the article service in process and connect a client to it
+func newClient(t *testing.T, svc rpc.ArticleService, opts ...grpc.ServerOption) articlepb.ArticleServiceClient {
+	t.Helper()
+	ln := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(opts...)
+	rpc.NewArticleServer(s, svc)
+	go func() { _ = s.Serve(ln) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return articlepb.NewArticleServiceClient(conn)
+}
+
+func TestFetch(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	updatedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	mockUCase.On("Fetch", mock.Anything, "cursor", int64(2), domain.ArticleFilter{Status: domain.StatusPublished}).
+		Return([]domain.Article{{ID: 1, Title: "Hello", UpdatedAt: updatedAt}, {ID: 2, Title: "World"}}, "next", "prev", nil).Once()
+	client := newClient(t, mockUCase)
+
+	res, err := client.Fetch(context.TODO(), &articlepb.FetchRequest{Cursor: "cursor", Num: 2})
+	require.NoError(t, err)
+	require.Len(t, res.GetArticles(), 2)
+	assert.Equal(t, "Hello", res.GetArticles()[0].GetTitle())
+	assert.True(t, updatedAt.Equal(res.GetArticles()[0].GetUpdatedAt().AsTime()))
+	assert.Nil(t, res.GetArticles()[1].GetUpdatedAt())
+	assert.Equal(t, "next", res.GetNextCursor())
+	assert.Equal(t, "prev", res.GetPrevCursor())
+
+	_, err = client.Fetch(context.TODO(), &articlepb.FetchRequest{Status: "archived"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	mockUCase.AssertExpectations(t)
+}
+
+func TestGetByID(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("GetByID", mock.Anything, int64(1)).
+		Return(domain.Article{ID: 1, Title: "Hello", Author: domain.Author{ID: 3, Name: "Iman"}, Tags: []string{"golang"}, Status: domain.StatusPublished}, nil).Once()
+	mockUCase.On("GetByID", mock.Anything, int64(404)).Return(domain.Article{}, domain.ErrNotFound).Once()
+	client := newClient(t, mockUCase)
+
+	ar, err := client.GetByID(context.TODO(), &articlepb.GetByIDRequest{Id: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", ar.GetTitle())
+	assert.Equal(t, "Iman", ar.GetAuthor().GetName())
+	assert.Equal(t, []string{"golang"}, ar.GetTags())
+
+	_, err = client.GetByID(context.TODO(), &articlepb.GetByIDRequest{Id: 404})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	mockUCase.AssertExpectations(t)
+}
+
+func TestStore(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("Store", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool { return ar.Title == "Hello" })).
+		Run(func(args mock.Arguments) {
+			ar := args.Get(1).(*domain.Article)
+			ar.ID = 7
+			ar.Status = domain.StatusDraft
+		}).Return(nil).Once()
+	mockUCase.On("Store", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool { return ar.Title == "Taken" })).
+		Return(domain.ErrConflict).Once()
+	client := newClient(t, mockUCase)
+
+	ar, err := client.Store(context.TODO(), &articlepb.StoreRequest{Title: "Hello", Content: "Content", AuthorId: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), ar.GetId())
+	assert.Equal(t, string(domain.StatusDraft), ar.GetStatus())
+
+	_, err = client.Store(context.TODO(), &articlepb.StoreRequest{Title: "Taken", Content: "Content"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = client.Store(context.TODO(), &articlepb.StoreRequest{Title: "Hello"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	mockUCase.AssertExpectations(t)
+}
+
+func TestUpdate(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool { return ar.ID == 1 })).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Article).Version++ }).Return(nil).Once()
+	mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(ar *domain.Article) bool { return ar.ID == 404 })).
+		Return(domain.ErrNotFound).Once()
+	client := newClient(t, mockUCase)
+
+	ar, err := client.Update(context.TODO(), &articlepb.UpdateRequest{Id: 1, Title: "Hello", Content: "Content", Version: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), ar.GetVersion())
+
+	_, err = client.Update(context.TODO(), &articlepb.UpdateRequest{Id: 404, Title: "Hello", Content: "Content", Version: 1})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.Update(context.TODO(), &articlepb.UpdateRequest{Id: 1, Title: "Hello", Content: "Content"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	mockUCase.AssertExpectations(t)
+}
+
+func TestDelete(t *testing.T) {
+	mockUCase := new(mocks.ArticleService)
+	mockUCase.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+	mockUCase.On("Delete", mock.Anything, int64(404)).Return(domain.ErrNotFound).Once()
+	mockUCase.On("Delete", mock.Anything, int64(500)).Return(context.DeadlineExceeded).Once()
+	client := newClient(t, mockUCase)
+
+	_, err := client.Delete(context.TODO(), &articlepb.DeleteRequest{Id: 1})
+	require.NoError(t, err)
+
+	_, err = client.Delete(context.TODO(), &articlepb.DeleteRequest{Id: 404})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.Delete(context.TODO(), &articlepb.DeleteRequest{Id: 500})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	mockUCase.AssertExpectations(t)
+}
@@ -0,0 +1,87 @@
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"apismrtbiz/internal/rest/middleware"
+	"apismrtbiz/internal/rpc/articlepb"
+)
+
+// The metadata carrying the credentials, the lower cased headers of the REST API
+const (
+	metadataAPIKey        = "x-api-key"
+	metadataAuthorization = "authorization"
+)
+
+// readMethods stay public like the safe methods of the REST API, the drafts are left to the editors
+var readMethods = map[string]bool{
+	articlepb.ArticleService_Fetch_FullMethodName:   true,
+	articlepb.ArticleService_GetByID_FullMethodName: true,
+}
+
+// AuthConfig represent the credentials the calls are authenticated with, the ones of the REST API
+type AuthConfig struct {
+	// Keys maps every accepted api key to the label of its client
+	Keys map[string]string
+	// JWTSecret is the secret the bearer tokens are signed with
+	JWTSecret []byte
+}
+
+func (cfg AuthConfig) enabled() bool {
+	return len(cfg.Keys) > 0 || len(cfg.JWTSecret) > 0
+}
+
+// UnaryAuth will authenticate the write calls with the api key of the x-api-key metadata or the bearer
+// token of the authorization metadata, the label or the user is found in the context of the call as over
+// REST. The reads stay public, but invalid credentials are rejected on every call. The calls aren't
+// authenticated when neither keys nor a secret are given.
+func UnaryAuth(cfg AuthConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !cfg.enabled() {
+			return handler(ctx, req)
+		}
+
+		ctx, authenticated, err := authenticate(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if !authenticated && !readMethods[info.FullMethod] {
+			return nil, status.Error(codes.Unauthenticated, "missing api key or bearer token")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authenticate will check the credentials of the metadata, the api key wins over the bearer token
+func authenticate(ctx context.Context, cfg AuthConfig) (context.Context, bool, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if key := firstValue(md, metadataAPIKey); key != "" && len(cfg.Keys) > 0 {
+		label, ok := middleware.LookupAPIKey(cfg.Keys, key)
+		if !ok {
+			return ctx, false, status.Error(codes.Unauthenticated, "api key is invalid")
+		}
+		return middleware.ContextWithAPIKeyLabel(ctx, label), true, nil
+	}
+
+	if raw, ok := middleware.BearerToken(firstValue(md, metadataAuthorization)); ok && len(cfg.JWTSecret) > 0 {
+		userID, err := middleware.ParseToken(cfg.JWTSecret, raw)
+		if err != nil {
+			return ctx, false, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return middleware.ContextWithUserID(ctx, userID), true, nil
+	}
+	return ctx, false, nil
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
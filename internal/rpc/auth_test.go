@@ -0,0 +1,140 @@
+package rpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/rest/middleware"
+	"apismrtbiz/internal/rpc"
+	"apismrtbiz/internal/rpc/articlepb"
+	"apismrtbiz/internal/rpc/mocks"
+)
+
+var testSecret = []byte("secret")
+
+func signToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testSecret)
+	require.NoError(t, err)
+	return token
+}
+
+func TestUnaryAuth(t *testing.T) {
+	auth := grpc.UnaryInterceptor(rpc.UnaryAuth(rpc.AuthConfig{Keys: map[string]string{"key-1": "client-1"}, JWTSecret: testSecret}))
+	withMetadata := func(kv ...string) context.Context {
+		return metadata.NewOutgoingContext(context.TODO(), metadata.Pairs(kv...))
+	}
+
+	t.Run("missing-credentials", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		client := newClient(t, mockUCase, auth)
+
+		_, err := client.Delete(context.TODO(), &articlepb.DeleteRequest{Id: 1})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		mockUCase.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("api-key", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Delete", mock.MatchedBy(func(ctx context.Context) bool {
+			label, ok := middleware.APIKeyLabelFromContext(ctx)
+			return ok && label == "client-1"
+		}), int64(1)).Return(nil).Once()
+		client := newClient(t, mockUCase, auth)
+
+		_, err := client.Delete(withMetadata("x-api-key", "key-1"), &articlepb.DeleteRequest{Id: 1})
+		require.NoError(t, err)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("bearer-token", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Delete", mock.MatchedBy(func(ctx context.Context) bool {
+			userID, ok := middleware.UserIDFromContext(ctx)
+			return ok && userID == "user-1"
+		}), int64(1)).Return(nil).Once()
+		client := newClient(t, mockUCase, auth)
+
+		token := signToken(t, jwt.MapClaims{"sub": "user-1", "exp": time.Now().Add(time.Hour).Unix()})
+		_, err := client.Delete(withMetadata("authorization", "Bearer "+token), &articlepb.DeleteRequest{Id: 1})
+		require.NoError(t, err)
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("invalid-credentials", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		client := newClient(t, mockUCase, auth)
+
+		expired := signToken(t, jwt.MapClaims{"sub": "user-1", "exp": time.Now().Add(-time.Hour).Unix()})
+		for _, ctx := range []context.Context{
+			withMetadata("x-api-key", "wrong"),
+			withMetadata("authorization", "Bearer "+expired),
+		} {
+			// the reads are public, but not with invalid credentials
+			_, err := client.GetByID(ctx, &articlepb.GetByIDRequest{Id: 1})
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		}
+		mockUCase.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("public-read", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(1)).Return(domain.Article{ID: 1, Title: "Hello", Status: domain.StatusPublished}, nil).Once()
+		client := newClient(t, mockUCase, auth)
+
+		ar, err := client.GetByID(context.TODO(), &articlepb.GetByIDRequest{Id: 1})
+		require.NoError(t, err)
+		assert.Equal(t, "Hello", ar.GetTitle())
+	})
+
+	t.Run("anonymous-drafts", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("GetByID", mock.Anything, int64(2)).Return(domain.Article{ID: 2, Title: "Draft", Status: domain.StatusDraft}, nil).Once()
+		client := newClient(t, mockUCase, auth)
+
+		_, err := client.Fetch(context.TODO(), &articlepb.FetchRequest{Status: string(domain.StatusDraft)})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockUCase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+		_, err = client.GetByID(context.TODO(), &articlepb.GetByIDRequest{Id: 2})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("editor-drafts", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Fetch", mock.Anything, "", int64(10), domain.ArticleFilter{Status: domain.StatusDraft}).
+			Return([]domain.Article{{ID: 2, Title: "Draft", Status: domain.StatusDraft}}, "", "", nil).Once()
+		mockUCase.On("GetByID", mock.Anything, int64(2)).Return(domain.Article{ID: 2, Title: "Draft", Status: domain.StatusDraft}, nil).Once()
+		client := newClient(t, mockUCase, auth)
+
+		res, err := client.Fetch(withMetadata("x-api-key", "key-1"), &articlepb.FetchRequest{Status: string(domain.StatusDraft)})
+		require.NoError(t, err)
+		require.Len(t, res.GetArticles(), 1)
+
+		ar, err := client.GetByID(withMetadata("x-api-key", "key-1"), &articlepb.GetByIDRequest{Id: 2})
+		require.NoError(t, err)
+		assert.Equal(t, "Draft", ar.GetTitle())
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("not-configured", func(t *testing.T) {
+		mockUCase := new(mocks.ArticleService)
+		mockUCase.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+		client := newClient(t, mockUCase, grpc.UnaryInterceptor(rpc.UnaryAuth(rpc.AuthConfig{})))
+
+		_, err := client.Delete(context.TODO(), &articlepb.DeleteRequest{Id: 1})
+		require.NoError(t, err)
+	})
+}
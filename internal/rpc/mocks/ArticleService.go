@@ -0,0 +1,155 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "apismrtbiz/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ArticleService is an autogenerated mock type for the ArticleService type
+type ArticleService struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *ArticleService) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Fetch provides a mock function with given fields: ctx, cursor, num, filter
+func (_m *ArticleService) Fetch(ctx context.Context, cursor string, num int64, filter domain.ArticleFilter) ([]domain.Article, string, string, error) {
+	ret := _m.Called(ctx, cursor, num, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fetch")
+	}
+
+	var r0 []domain.Article
+	var r1 string
+	var r2 string
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, domain.ArticleFilter) ([]domain.Article, string, string, error)); ok {
+		return rf(ctx, cursor, num, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, domain.ArticleFilter) []domain.Article); ok {
+		r0 = rf(ctx, cursor, num, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, domain.ArticleFilter) string); ok {
+		r1 = rf(ctx, cursor, num, filter)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, int64, domain.ArticleFilter) string); ok {
+		r2 = rf(ctx, cursor, num, filter)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, string, int64, domain.ArticleFilter) error); ok {
+		r3 = rf(ctx, cursor, num, filter)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *ArticleService) GetByID(ctx context.Context, id int64) (domain.Article, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 domain.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (domain.Article, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) domain.Article); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.Article)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Store provides a mock function with given fields: _a0, _a1
+func (_m *ArticleService) Store(_a0 context.Context, _a1 *domain.Article) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Store")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Article) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, ar
+func (_m *ArticleService) Update(ctx context.Context, ar *domain.Article) error {
+	ret := _m.Called(ctx, ar)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Article) error); ok {
+		r0 = rf(ctx, ar)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewArticleService creates a new instance of ArticleService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewArticleService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ArticleService {
+	mock := &ArticleService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
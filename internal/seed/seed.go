@@ -0,0 +1,89 @@
+// Package seed fills a development database with sample articles
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"apismrtbiz/domain"
+)
+
+// Repository is the part of article.ArticleRepository the seeder stores with
+type Repository interface {
+	GetByTitle(ctx context.Context, title string) (domain.Article, error)
+	Store(ctx context.Context, a *domain.Article) error
+}
+
+var (
+	subjects = []string{"Go", "Postgres", "Docker", "Fiber", "Redis", "MySQL", "Kubernetes", "SQLite", "gRPC", "OpenTelemetry"}
+	angles   = []string{"Getting Started with", "Testing", "Scaling", "Debugging", "Profiling", "Securing", "Deploying", "Monitoring"}
+	tags     = map[string][]string{
+		"Go":            {"golang"},
+		"Postgres":      {"database", "postgres"},
+		"Docker":        {"containers", "devops"},
+		"Fiber":         {"golang", "web"},
+		"Redis":         {"cache", "database"},
+		"MySQL":         {"database", "mysql"},
+		"Kubernetes":    {"containers", "devops"},
+		"SQLite":        {"database", "sqlite"},
+		"gRPC":          {"api", "golang"},
+		"OpenTelemetry": {"observability"},
+	}
+	paragraphs = []string{
+		"%s is a topic every backend team meets sooner or later, and the first steps decide how painful the rest will be.",
+		"Start small: a single service, a handful of endpoints and a test for each of them. Measure before changing anything.",
+		"Most of the problems come from the defaults. Read them once, write down the ones you rely on and revisit them every release.",
+		"Once the basics hold, automate them. A pipeline that runs the same checks on every change beats a checklist nobody reads.",
+	}
+)
+
+// Article will build the sample article of the index, the same index always builds the same article
+// so a seeded database is seeded again without duplicates
+func Article(i int) domain.Article {
+	subject := subjects[i%len(subjects)]
+	angle := angles[(i/len(subjects))%len(angles)]
+	title := angle + " " + subject
+	if round := i / (len(subjects) * len(angles)); round > 0 {
+		title = fmt.Sprintf("%s, Part %d", title, round+1)
+	}
+
+	content := make([]string, len(paragraphs))
+	for j, p := range paragraphs {
+		if strings.Contains(p, "%s") {
+			p = fmt.Sprintf(p, subject)
+		}
+		content[j] = p
+	}
+
+	return domain.Article{
+		Title:   title,
+		Slug:    domain.Slugify(title),
+		Content: strings.Join(content, "\n\n"),
+		Author:  domain.Author{ID: 1},
+		Status:  domain.StatusPublished,
+		Tags:    tags[subject],
+	}
+}
+
+// Run will store the first num sample articles, the ones already stored are skipped.
+// It returns how many articles were stored.
+func Run(ctx context.Context, repo Repository, num int) (stored int, err error) {
+	for i := 0; i < num; i++ {
+		ar := Article(i)
+		_, err = repo.GetByTitle(ctx, ar.Title)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			return stored, err
+		}
+
+		if err = repo.Store(ctx, &ar); err != nil {
+			return stored, err
+		}
+		stored++
+	}
+	return stored, nil
+}
@@ -0,0 +1,51 @@
+package seed_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sqliteMigrations "apismrtbiz/database/sqlite/migrations"
+	"apismrtbiz/internal/database"
+	sqliteRepo "apismrtbiz/internal/repository/sqlite"
+	"apismrtbiz/internal/seed"
+)
+
+func TestRun(t *testing.T) {
+	db, err := sql.Open("sqlite", sqliteRepo.DSN(":memory:"))
+	require.NoError(t, err)
+	// every connection would open its own in-memory database
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+	require.NoError(t, database.Migrate(context.TODO(), db, database.DriverSQLite, sqliteMigrations.FS))
+	repo := sqliteRepo.NewArticleRepository(db)
+
+	stored, err := seed.Run(context.TODO(), repo, 25)
+	require.NoError(t, err)
+	assert.Equal(t, 25, stored)
+
+	// seeding again only adds the missing articles
+	stored, err = seed.Run(context.TODO(), repo, 30)
+	require.NoError(t, err)
+	assert.Equal(t, 5, stored)
+
+	total, err := repo.Count(context.TODO(), "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(30), total)
+}
+
+func TestArticle(t *testing.T) {
+	titles := make(map[string]struct{})
+	for i := 0; i < 500; i++ {
+		ar := seed.Article(i)
+		assert.NotEmpty(t, ar.Content)
+		// the title column of MySQL holds 45 characters
+		assert.LessOrEqual(t, len(ar.Title), 45, ar.Title)
+		assert.NotContains(t, titles, ar.Title)
+		titles[ar.Title] = struct{}{}
+	}
+	assert.Equal(t, seed.Article(7), seed.Article(7))
+}
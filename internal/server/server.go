@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Run will serve the app on the given address until the context is done, see Serve
+func Run(ctx context.Context, app *fiber.App, address string, shutdownTimeout time.Duration, closers ...io.Closer) error {
+	ln, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	return Serve(ctx, app, ln, shutdownTimeout, closers...)
+}
+
+// Serve will serve the app on the listener until the context is done, then stop accepting connections
+// and wait up to the shutdown timeout for the in-flight requests before closing the given closers
+func Serve(ctx context.Context, app *fiber.App, ln net.Listener, shutdownTimeout time.Duration, closers ...io.Closer) (err error) {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- app.Listener(ln)
+	}()
+
+	select {
+	case err = <-serveErr:
+		// the server stopped by itself, there is nothing left to drain
+	case <-ctx.Done():
+		logrus.Info("shutting down, draining the in-flight requests")
+		err = app.ShutdownWithTimeout(shutdownTimeout)
+		if errServe := <-serveErr; err == nil {
+			err = errServe
+		}
+	}
+
+	for _, closer := range closers {
+		if errClose := closer.Close(); errClose != nil {
+			logrus.Error(errClose)
+			err = errors.Join(err, errClose)
+		}
+	}
+	return err
+}
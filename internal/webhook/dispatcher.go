@@ -0,0 +1,225 @@
+// Package webhook posts the lifecycle events of the articles to the configured URLs
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"apismrtbiz/domain"
+)
+
+const (
+	defaultMaxRetries = 3
+	defaultBackoff    = 500 * time.Millisecond
+	defaultTimeout    = 5 * time.Second
+	defaultQueueSize  = 100
+	// defaultDrainTimeout leaves the queued events some time to be delivered, within the shutdown
+	// timeout of the server
+	defaultDrainTimeout = 10 * time.Second
+)
+
+// ErrDrainTimeout is returned by Close when the queued events weren't all delivered in time
+var ErrDrainTimeout = errors.New("webhook: drain timed out, the undelivered events were dropped")
+
+// Config represent the tunable settings of the dispatcher
+type Config struct {
+	// URLs are the endpoints every event is posted to
+	URLs []string
+	// MaxRetries is how many times a failed delivery is retried, defaultMaxRetries when zero
+	MaxRetries int
+	// Backoff is the wait before the first retry, doubled on every other one, defaultBackoff when zero
+	Backoff time.Duration
+	// Timeout bounds a single delivery, defaultTimeout when zero
+	Timeout time.Duration
+	// QueueSize is how many events wait for their delivery to a URL before the new ones are dropped,
+	// defaultQueueSize when zero
+	QueueSize int
+	// DrainTimeout bounds how long Close waits for the queued events, defaultDrainTimeout when zero
+	DrainTimeout time.Duration
+}
+
+// Dispatcher delivers the events in the background, so that the writes are not held by the webhooks.
+// Every URL has a queue and a worker of its own, a slow or down endpoint doesn't delay the others.
+type Dispatcher struct {
+	client *http.Client
+	cfg    Config
+	queues []chan queued
+	wg     sync.WaitGroup
+
+	// stop is cancelled once the drain timed out, aborting the deliveries in flight
+	stop   context.Context
+	cancel context.CancelFunc
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// queued is an event waiting for its delivery, along the context it was notified with
+type queued struct {
+	ctx  context.Context
+	ev   domain.Event
+	body []byte
+}
+
+// NewDispatcher will create a dispatcher posting the events with the client and start its delivery
+func NewDispatcher(client *http.Client, cfg Config) *Dispatcher {
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = defaultBackoff
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	if cfg.DrainTimeout <= 0 {
+		cfg.DrainTimeout = defaultDrainTimeout
+	}
+	d := &Dispatcher{
+		client: client,
+		cfg:    cfg,
+		queues: make([]chan queued, len(cfg.URLs)),
+	}
+	d.stop, d.cancel = context.WithCancel(context.Background())
+	for i, url := range cfg.URLs {
+		d.queues[i] = make(chan queued, cfg.QueueSize)
+		d.wg.Add(1)
+		go d.run(url, d.queues[i])
+	}
+	return d
+}
+
+// Notify will queue the event for its delivery to every URL, the event is dropped for a URL whose queue
+// is full and when the dispatcher is closed. The delivery outlives the context, keeping its values only.
+func (d *Dispatcher) Notify(ctx context.Context, ev domain.Event) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		return
+	}
+	body, err := json.Marshal(ev)
+	if err != nil {
+		logrus.WithContext(ctx).Error(err)
+		return
+	}
+	q := queued{ctx: context.WithoutCancel(ctx), ev: ev, body: body}
+	for i, queue := range d.queues {
+		select {
+		case queue <- q:
+		default:
+			logrus.WithContext(ctx).Warnf("webhook %s: queue is full, dropping the %s event of article %d", d.cfg.URLs[i], ev.Type, ev.ArticleID)
+		}
+	}
+}
+
+// Close will stop accepting the events and wait up to the drain timeout for the queued ones to be
+// delivered. The deliveries still running then are aborted, the events left are dropped and
+// ErrDrainTimeout is returned.
+func (d *Dispatcher) Close() error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		for _, queue := range d.queues {
+			close(queue)
+		}
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(d.cfg.DrainTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		d.cancel()
+		return nil
+	case <-timer.C:
+		d.cancel()
+		<-done
+		return ErrDrainTimeout
+	}
+}
+
+// run will deliver the queued events to the url one after the other, until the queue is closed
+func (d *Dispatcher) run(url string, queue <-chan queued) {
+	defer d.wg.Done()
+	dropped := 0
+	for q := range queue {
+		if d.stop.Err() != nil {
+			dropped++
+			continue
+		}
+		if err := d.deliver(q.ctx, url, q.body); err != nil {
+			logrus.WithContext(q.ctx).Errorf("webhook %s: %s", url, err)
+		}
+	}
+	if dropped > 0 {
+		logrus.Warnf("webhook %s: drain timed out, dropping %d events", url, dropped)
+	}
+}
+
+// deliver will post the event to the url, retrying with an exponential backoff
+// while the delivery fails or the endpoint answers with a server error.
+// The delivery is aborted once the drain timed out.
+func (d *Dispatcher) deliver(ctx context.Context, url string, body []byte) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(d.stop, cancel)
+	defer stop()
+
+	backoff := d.cfg.Backoff
+	for attempt := 0; ; attempt++ {
+		status, err := d.post(ctx, url, body)
+		if err == nil && status < http.StatusMultipleChoices {
+			return nil
+		}
+		if err == nil {
+			err = fmt.Errorf("unexpected status %d", status)
+		}
+		retryable := status == 0 || status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+		if !retryable || attempt == d.cfg.MaxRetries {
+			return err
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// post will send the event once, the status is zero when no response was received
+func (d *Dispatcher) post(ctx context.Context, url string, body []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	return res.StatusCode, nil
+}
@@ -0,0 +1,178 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"apismrtbiz/domain"
+	"apismrtbiz/internal/webhook"
+)
+
+func TestDispatcherPayload(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []map[string]interface{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		mu.Lock()
+		received = append(received, body)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	d := webhook.NewDispatcher(srv.Client(), webhook.Config{URLs: []string{srv.URL}})
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	d.Notify(context.TODO(), domain.Event{Type: domain.EventArticleCreated, ArticleID: 7, Version: 1, Timestamp: at})
+	require.NoError(t, d.Close())
+
+	require.Len(t, received, 1)
+	assert.Equal(t, map[string]interface{}{
+		"type":       "article.created",
+		"article_id": float64(7),
+		"version":    float64(1),
+		"timestamp":  "2024-05-01T10:00:00Z",
+	}, received[0])
+}
+
+func TestDispatcherRetry(t *testing.T) {
+	t.Run("server-error", func(t *testing.T) {
+		var attempts atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) < 3 {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}))
+		defer srv.Close()
+
+		d := webhook.NewDispatcher(srv.Client(), webhook.Config{URLs: []string{srv.URL}, Backoff: time.Millisecond})
+		d.Notify(context.TODO(), domain.Event{Type: domain.EventArticleUpdated, ArticleID: 7})
+		require.NoError(t, d.Close())
+
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("gives-up", func(t *testing.T) {
+		var attempts atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		d := webhook.NewDispatcher(srv.Client(), webhook.Config{URLs: []string{srv.URL}, MaxRetries: 2, Backoff: time.Millisecond})
+		d.Notify(context.TODO(), domain.Event{Type: domain.EventArticleDeleted, ArticleID: 7})
+		require.NoError(t, d.Close())
+
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("client-error", func(t *testing.T) {
+		var attempts atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer srv.Close()
+
+		d := webhook.NewDispatcher(srv.Client(), webhook.Config{URLs: []string{srv.URL}, Backoff: time.Millisecond})
+		d.Notify(context.TODO(), domain.Event{Type: domain.EventArticleDeleted, ArticleID: 7})
+		require.NoError(t, d.Close())
+
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+}
+
+func TestDispatcherAsync(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+
+	d := webhook.NewDispatcher(srv.Client(), webhook.Config{URLs: []string{srv.URL}})
+	notified := make(chan struct{})
+	go func() {
+		d.Notify(context.TODO(), domain.Event{Type: domain.EventArticleCreated, ArticleID: 7})
+		close(notified)
+	}()
+
+	select {
+	case <-notified:
+	case <-time.After(time.Second):
+		t.Fatal("Notify is blocked by the delivery")
+	}
+	close(release)
+	require.NoError(t, d.Close())
+}
+
+func TestDispatcherClose(t *testing.T) {
+	t.Run("drain-timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer srv.Close()
+		defer close(release)
+
+		d := webhook.NewDispatcher(srv.Client(), webhook.Config{URLs: []string{srv.URL}, DrainTimeout: 50 * time.Millisecond})
+		d.Notify(context.TODO(), domain.Event{Type: domain.EventArticleCreated, ArticleID: 7})
+		d.Notify(context.TODO(), domain.Event{Type: domain.EventArticleUpdated, ArticleID: 7})
+
+		start := time.Now()
+		assert.ErrorIs(t, d.Close(), webhook.ErrDrainTimeout)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		var attempts atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+		}))
+		defer srv.Close()
+
+		d := webhook.NewDispatcher(srv.Client(), webhook.Config{URLs: []string{srv.URL}})
+		require.NoError(t, d.Close())
+		d.Notify(context.TODO(), domain.Event{Type: domain.EventArticleCreated, ArticleID: 7})
+		require.NoError(t, d.Close())
+
+		assert.Zero(t, attempts.Load())
+	})
+}
+
+func TestDispatcherConcurrentURLs(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+
+	delivered := make(chan struct{}, 1)
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered <- struct{}{}
+	}))
+	defer fast.Close()
+
+	d := webhook.NewDispatcher(http.DefaultClient, webhook.Config{URLs: []string{slow.URL, fast.URL}})
+	d.Notify(context.TODO(), domain.Event{Type: domain.EventArticleCreated, ArticleID: 7})
+
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		t.Fatal("the delivery is held by the slow url")
+	}
+	close(release)
+	require.NoError(t, d.Close())
+}
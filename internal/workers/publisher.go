@@ -0,0 +1,55 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const defaultPublishInterval = time.Minute
+
+// DuePublisher represent the usecase publishing the scheduled drafts
+type DuePublisher interface {
+	PublishDue(ctx context.Context) (int, error)
+}
+
+// Publisher periodically publishes the drafts whose publish_at has passed
+type Publisher struct {
+	svc      DuePublisher
+	interval time.Duration
+}
+
+// NewPublisher will create a publisher running every interval, a non positive interval falls back to a minute
+func NewPublisher(svc DuePublisher, interval time.Duration) *Publisher {
+	if interval <= 0 {
+		interval = defaultPublishInterval
+	}
+	return &Publisher{
+		svc:      svc,
+		interval: interval,
+	}
+}
+
+// Run will publish the due drafts on every tick until the context is done,
+// a failed run is only logged and retried on the next tick
+func (p *Publisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			published, err := p.svc.PublishDue(ctx)
+			if err != nil {
+				logrus.WithContext(ctx).Error(err)
+				continue
+			}
+			if published > 0 {
+				logrus.WithContext(ctx).Infof("published %d scheduled articles", published)
+			}
+		}
+	}
+}
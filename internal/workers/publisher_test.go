@@ -0,0 +1,46 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"apismrtbiz/internal/workers"
+)
+
+type publisherFunc func(ctx context.Context) (int, error)
+
+func (f publisherFunc) PublishDue(ctx context.Context) (int, error) {
+	return f(ctx)
+}
+
+func TestPublisherRun(t *testing.T) {
+	var runs atomic.Int32
+	svc := publisherFunc(func(context.Context) (int, error) {
+		if runs.Add(1) == 1 {
+			return 0, errors.New("deadlock")
+		}
+		return 1, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		workers.NewPublisher(svc, 10*time.Millisecond).Run(ctx)
+		close(done)
+	}()
+
+	// a failed run doesn't stop the worker
+	assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the publisher didn't stop once the context was done")
+	}
+}